@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/orzazade/gitch/internal/config"
 	gitpkg "github.com/orzazade/gitch/internal/git"
@@ -25,6 +26,19 @@ var (
 	addGenerateGPG bool
 	addGPGKey      string
 	addForce       bool
+	addHosts       []string
+	addHookMode    string
+	addLifetime    int
+	addGPGExpiry   string
+	addSSHPort     int
+	addProxyJump   string
+	addHTTPSUser   string
+	addExtraKeys   []string
+	addSignWith    string
+	addFromGit     bool
+	addNoAgent     bool
+	addDesc        string
+	addTags        []string
 )
 
 var addCmd = &cobra.Command{
@@ -34,12 +48,39 @@ var addCmd = &cobra.Command{
 
 The name is used to reference the identity in other commands.
 The email is the git user.email that will be used when this identity is active.
+With --from-git, the email is taken from your current global git user.email,
+so an existing git setup can be adopted without retyping it.
+Use --desc to add a note, shown in 'gitch list' and the selector, for
+telling similar identities apart, and --tag (repeatable) to group identities
+for filtering with 'gitch list --tag', 'gitch use --tag' and
+'gitch export --tag'.
 
 SSH Key Options:
   --generate-ssh (-s)  Generate a new SSH keypair for this identity
-  --key-type           SSH key type: ed25519 (default) or rsa
+  --key-type           SSH key type: ed25519 (default), rsa, ecdsa, or ecdsa-p384
   --ssh-key            Link an existing SSH private key to this identity
+  --extra-ssh-key      Link an additional SSH private key, e.g. a separate
+                       signing key (repeatable); offered after the main key
   --force              Overwrite existing SSH key if it exists
+  --host               Custom SSH host for self-hosted Git (repeatable);
+                       replaces the default github/gitlab/bitbucket/azure hosts
+                       in 'gitch ssh-config generate'
+  --agent-lifetime     Seconds ssh-agent keeps the key after 'gitch use'
+                       (0 = no expiry). Uses the agent's own timeout
+                       (ssh-add -t); gitch runs no background process
+  --no-agent           Never add or remove this identity's keys in ssh-agent
+                       when switching (set GITCH_NO_AGENT=1 to do this for
+                       every identity)
+  --ssh-port           SSH port for generated Host blocks (default 22)
+  --proxy-jump         Bastion host(s) for generated Host blocks, e.g.
+                       user@bastion:2222
+
+HTTPS Options:
+  --https-user         Username returned by 'gitch git-credential' for HTTPS
+                       remotes; set a token with 'gitch config https-token'
+
+Hook Options:
+  --mode               Pre-commit hook mode: allow, warn (default), or block
 
 Key Type Auto-Detection:
   When --key-type is not specified, gitch automatically detects Azure DevOps
@@ -48,16 +89,36 @@ Key Type Auto-Detection:
 
 GPG Key Options:
   --generate-gpg       Generate a new Ed25519 GPG key for commit signing
+  --gpg-expiry         Expiry for a generated GPG key: e.g. 2y, 6m, 30d,
+                       or never (default)
   --gpg-key            Link an existing GPG key ID for commit signing
+  --signing-key        Alias for --gpg-key. Switching to the identity sets
+                       user.signingkey and commit.gpgsign; switching to one
+                       without a key clears them
+
+Signing Options:
+  --sign-with          Commit signing method: gpg, ssh, or none. Defaults to
+                       gpg when a GPG key is set. ssh signs with the
+                       identity's SSH key (gpg.format=ssh); verifying SSH
+                       signatures locally needs gpg.ssh.allowedSignersFile
 
 Examples:
   gitch add --name work --email work@company.com
   gitch add -n personal -e me@example.com --default
+  gitch add --name work --from-git
+  gitch add --name work-contract --email me@acme.com --desc "ACME contractor account"
+  gitch add --name acme --email me@acme.com --tag client-a --tag contract
   gitch add --name github --email me@github.com --generate-ssh
   gitch add --name azuredev --email work@company.com --generate-ssh --key-type rsa
   gitch add --name work --email work@co.com --ssh-key ~/.ssh/id_ed25519
+  gitch add --name work --email work@co.com --generate-ssh --host git.company.internal
   gitch add --name work --email work@co.com --generate-gpg
-  gitch add --name work --email work@co.com --gpg-key ABCD1234EFGH5678`,
+  gitch add --name work --email work@co.com --generate-gpg --gpg-expiry 2y
+  gitch add --name work --email work@co.com --gpg-key ABCD1234EFGH5678
+  gitch add --name work --email work@co.com --ssh-key ~/.ssh/id_work --sign-with ssh
+  gitch add --name work --email work@co.com --mode block
+  gitch add --name work --email work@co.com --ssh-key ~/.ssh/id_work --agent-lifetime 3600
+  gitch add --name corp --email me@corp.com --ssh-key ~/.ssh/id_corp --host git.corp.internal --ssh-port 2222 --proxy-jump bastion.corp.internal`,
 	RunE: runAdd,
 }
 
@@ -65,20 +126,57 @@ func init() {
 	rootCmd.AddCommand(addCmd)
 
 	addCmd.Flags().StringVarP(&addName, "name", "n", "", "Identity name (required)")
-	addCmd.Flags().StringVarP(&addEmail, "email", "e", "", "Email address (required)")
+	addCmd.Flags().StringVarP(&addEmail, "email", "e", "", "Email address (required unless --from-git)")
+	addCmd.Flags().StringVar(&addDesc, "desc", "", "Description of the identity, e.g. \"ACME contractor account\"")
+	addCmd.Flags().StringArrayVar(&addTags, "tag", nil, "Tag for grouping identities (repeatable)")
+	_ = addCmd.RegisterFlagCompletionFunc("tag", tagCompletionFunc)
+	addCmd.Flags().BoolVar(&addFromGit, "from-git", false, "Use the current global git user.email as the email")
 	addCmd.Flags().BoolVarP(&addDefault, "default", "d", false, "Set as default identity")
 	addCmd.Flags().BoolVarP(&addGenerateSSH, "generate-ssh", "s", false, "Generate new SSH keypair")
 	addCmd.Flags().StringVar(&addSSHKey, "ssh-key", "", "Path to existing SSH private key")
-	addCmd.Flags().StringVar(&addKeyType, "key-type", "", "SSH key type: ed25519 (default) or rsa")
+	addCmd.Flags().StringArrayVar(&addExtraKeys, "extra-ssh-key", nil, "Path to an additional SSH private key (repeatable)")
+	addCmd.Flags().StringVar(&addKeyType, "key-type", "", "SSH key type: ed25519 (default), rsa, ecdsa, or ecdsa-p384")
 	addCmd.Flags().BoolVar(&addGenerateGPG, "generate-gpg", false, "Generate new GPG key for signing")
 	addCmd.Flags().StringVar(&addGPGKey, "gpg-key", "", "GPG key ID to use for signing")
+	addCmd.Flags().StringVar(&addGPGKey, "signing-key", "", "Alias for --gpg-key")
+	addCmd.Flags().StringVar(&addSignWith, "sign-with", "", "Commit signing method: gpg, ssh, or none")
+	addCmd.Flags().StringVar(&addGPGExpiry, "gpg-expiry", "", "Expiry for a generated GPG key, e.g. 2y, 6m, 30d, or never (default)")
 	addCmd.Flags().BoolVar(&addForce, "force", false, "Overwrite existing SSH key if it exists")
+	addCmd.Flags().StringArrayVar(&addHosts, "host", nil, "Custom SSH host for self-hosted Git (repeatable)")
+	addCmd.Flags().StringVar(&addHookMode, "mode", "", "Pre-commit hook mode: allow, warn (default), or block")
+	addCmd.Flags().IntVar(&addLifetime, "agent-lifetime", 0, "Seconds ssh-agent keeps the SSH key loaded (0 = no expiry)")
+	addCmd.Flags().BoolVar(&addNoAgent, "no-agent", false, "Don't load the SSH keys into ssh-agent when switching")
+	addCmd.Flags().IntVar(&addSSHPort, "ssh-port", 0, "SSH port for generated Host blocks (default 22)")
+	addCmd.Flags().StringVar(&addProxyJump, "proxy-jump", "", "ProxyJump bastion for generated Host blocks")
+	addCmd.Flags().StringVar(&addHTTPSUser, "https-user", "", "Username for HTTPS remotes (see 'gitch git-credential')")
+	_ = addCmd.RegisterFlagCompletionFunc("mode", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return hookModeCompletions, cobra.ShellCompDirectiveNoFileComp
+	})
+	_ = addCmd.RegisterFlagCompletionFunc("sign-with", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{config.SigningGPG, config.SigningSSH, config.SigningNone}, cobra.ShellCompDirectiveNoFileComp
+	})
 
 	_ = addCmd.MarkFlagRequired("name")
-	_ = addCmd.MarkFlagRequired("email")
+	addCmd.MarkFlagsMutuallyExclusive("email", "from-git")
+	addCmd.MarkFlagsMutuallyExclusive("gpg-key", "signing-key")
 }
 
 func runAdd(cmd *cobra.Command, args []string) error {
+	// Adopt the existing git email, or require --email
+	if addFromGit {
+		_, gitEmail, err := gitpkg.GetCurrentIdentity()
+		if err != nil {
+			return fmt.Errorf("failed to read git identity: %w", err)
+		}
+		if gitEmail == "" {
+			return errors.New("git user.email is not set; use --email instead of --from-git")
+		}
+		addEmail = gitEmail
+	} else if addEmail == "" {
+		return errors.New(`required flag "email" not set (or use --from-git)`)
+	}
+	addEmail = config.NormalizeEmail(addEmail)
+
 	// Validate SSH flags are mutually exclusive
 	if addGenerateSSH && addSSHKey != "" {
 		return errors.New("cannot use both --generate-ssh and --ssh-key")
@@ -89,72 +187,114 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return errors.New("cannot use both --generate-gpg and --gpg-key")
 	}
 
-	// Load config
-	cfg, err := config.Load()
+	// Validate hook mode and custom hosts before generating any keys
+	if err := config.ValidateDescription(addDesc); err != nil {
+		return fmt.Errorf("invalid --desc: %w", err)
+	}
+	for _, tag := range addTags {
+		if err := config.ValidateTag(tag); err != nil {
+			return fmt.Errorf("invalid --tag: %w", err)
+		}
+	}
+	if err := config.ValidateHookMode(addHookMode); err != nil {
+		return fmt.Errorf("invalid --mode: %w", err)
+	}
+	for _, host := range addHosts {
+		if err := config.ValidateHost(host); err != nil {
+			return fmt.Errorf("invalid --host: %w", err)
+		}
+	}
+	if err := config.ValidateAgentLifetime(addLifetime); err != nil {
+		return fmt.Errorf("invalid --agent-lifetime: %w", err)
+	}
+	if err := config.ValidateSSHPort(addSSHPort); err != nil {
+		return fmt.Errorf("invalid --ssh-port: %w", err)
+	}
+	if err := config.ValidateProxyJump(addProxyJump); err != nil {
+		return fmt.Errorf("invalid --proxy-jump: %w", err)
+	}
+	if err := config.ValidateHTTPSCredential("HTTPS user", addHTTPSUser); err != nil {
+		return fmt.Errorf("invalid --https-user: %w", err)
+	}
+	if len(addExtraKeys) > 0 && addSSHKey == "" && !addGenerateSSH {
+		return errors.New("--extra-ssh-key requires --ssh-key or --generate-ssh")
+	}
+	if err := config.ValidateSigningMethod(addSignWith); err != nil {
+		return fmt.Errorf("invalid --sign-with: %w", err)
+	}
+	if addSignWith == config.SigningSSH && addSSHKey == "" && !addGenerateSSH {
+		return errors.New("--sign-with ssh requires --ssh-key or --generate-ssh")
+	}
+	if addSignWith == config.SigningGPG && addGPGKey == "" && !addGenerateGPG {
+		return errors.New("--sign-with gpg requires --gpg-key or --generate-gpg")
+	}
+	if addGPGExpiry != "" && !addGenerateGPG {
+		return errors.New("--gpg-expiry requires --generate-gpg")
+	}
+	gpgLifetime, err := gpgpkg.ParseExpiry(addGPGExpiry)
 	if err != nil {
+		return fmt.Errorf("invalid --gpg-expiry: %w", err)
+	}
+
+	// Make sure the config loads before generating any keys
+	if _, err := config.Load(); err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
 	// Create identity
 	identity := config.Identity{
-		Name:  addName,
-		Email: addEmail,
+		Name:          addName,
+		Email:         addEmail,
+		Hosts:         addHosts,
+		HookMode:      addHookMode,
+		Description:   addDesc,
+		AgentLifetime: addLifetime,
+		SSHPort:       addSSHPort,
+		ProxyJump:     addProxyJump,
+		HTTPSUser:     addHTTPSUser,
+		SigningMethod: addSignWith,
+		NoAgent:       addNoAgent,
+	}
+	for _, tag := range addTags {
+		identity.AddTag(tag)
 	}
 
 	// Handle SSH key linking
 	if addSSHKey != "" {
-		expandedPath, err := sshpkg.ExpandPath(addSSHKey)
+		expandedPath, err := linkSSHKey(addSSHKey)
 		if err != nil {
-			return fmt.Errorf("invalid SSH key path: %w", err)
+			return err
 		}
+		identity.SSHKeyPath = expandedPath
 
-		if err := sshpkg.ValidateKeyPath(expandedPath); err != nil {
-			return fmt.Errorf("SSH key validation failed: %w", err)
+		// SSH signing reads the public key
+		if addSignWith == config.SigningSSH {
+			if _, err := os.Stat(identity.SigningKey()); err != nil {
+				return fmt.Errorf("--sign-with ssh needs the public key %s", identity.SigningKey())
+			}
 		}
+	}
 
-		identity.SSHKeyPath = expandedPath
+	// Validate extra keys before generating anything
+	var extraKeys []string
+	for _, keyPath := range addExtraKeys {
+		expandedPath, err := linkSSHKey(keyPath)
+		if err != nil {
+			return err
+		}
+		extraKeys = append(extraKeys, expandedPath)
 	}
 
 	// Handle SSH key generation
 	if addGenerateSSH {
-		keyPath := sshpkg.DefaultSSHKeyPath(addName)
-		if keyPath == "" {
-			return errors.New("failed to determine SSH key path")
-		}
-
-		// Check if key already exists
-		if _, err := os.Stat(keyPath); err == nil {
-			if !addForce {
-				return fmt.Errorf("SSH key already exists at %s; use --force to overwrite", keyPath)
-			}
+		keyPath, err := checkSSHKeyPath(addName, addForce)
+		if err != nil {
+			return err
 		}
 
-		// Determine key type
-		var keyType sshpkg.KeyType
-		isAzureDevOps, _ := gitpkg.GetCurrentRemoteType()
-
-		if addKeyType != "" {
-			// User explicitly specified key type
-			var err error
-			keyType, err = sshpkg.ParseKeyType(addKeyType)
-			if err != nil {
-				return fmt.Errorf("invalid --key-type: %w", err)
-			}
-
-			// Warn if using Ed25519 with Azure DevOps
-			if keyType == sshpkg.KeyTypeEd25519 && isAzureDevOps {
-				fmt.Println(ui.WarningStyle.Render("Warning: Ed25519 keys may not work with Azure DevOps. Consider using --key-type rsa"))
-				fmt.Println()
-			}
-		} else {
-			// Auto-detect based on remote
-			if isAzureDevOps {
-				keyType = sshpkg.KeyTypeRSA
-				fmt.Println(ui.DimStyle.Render("Using RSA key (Azure DevOps detected)"))
-				fmt.Println()
-			} else {
-				keyType = sshpkg.KeyTypeEd25519
-			}
+		keyType, err := resolveSSHKeyType(addKeyType)
+		if err != nil {
+			return err
 		}
 
 		// Prompt for passphrase
@@ -163,38 +303,14 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to read passphrase: %w", err)
 		}
 
-		// Generate keypair with specified type
-		privateKey, publicKey, err := sshpkg.GenerateKeyPairWithType(keyType, addEmail, passphrase)
-		if err != nil {
-			return fmt.Errorf("failed to generate SSH keypair: %w", err)
-		}
-
-		// Write key files
-		if err := sshpkg.WriteKeyFiles(keyPath, privateKey, publicKey); err != nil {
-			return fmt.Errorf("failed to write SSH key files: %w", err)
+		if err := generateSSHKey(keyPath, addEmail, keyType, passphrase); err != nil {
+			return err
 		}
-
-		// Get fingerprint for display
-		fingerprint, err := sshpkg.GetFingerprint(publicKey)
-		if err != nil {
-			return fmt.Errorf("failed to get key fingerprint: %w", err)
-		}
-
 		identity.SSHKeyPath = keyPath
+	}
 
-		// Print key generation success info with key type
-		keyTypeLabel := "Ed25519"
-		if keyType == sshpkg.KeyTypeRSA {
-			keyTypeLabel = "RSA 4096-bit"
-		}
-		fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("Generated %s SSH key:", keyTypeLabel)))
-		fmt.Printf("  Path: %s\n", keyPath)
-		fmt.Printf("  Fingerprint: %s\n", fingerprint)
-		fmt.Println()
-		fmt.Println("Public key (add to GitHub/GitLab):")
-		fmt.Print(strings.TrimSuffix(string(publicKey), "\n"))
-		fmt.Println()
-		fmt.Println()
+	if len(extraKeys) > 0 {
+		identity.SSHKeyPaths = append([]string{identity.SSHKeyPath}, extraKeys...)
 	}
 
 	// Handle GPG key linking (existing key)
@@ -218,63 +334,199 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to read passphrase: %w", err)
 		}
 
-		// Generate GPG key
-		keyInfo, err := gpgpkg.GenerateKey(addName, addEmail, passphrase)
+		keyID, err := generateGPGKey(addName, addEmail, passphrase, gpgLifetime)
 		if err != nil {
-			return fmt.Errorf("failed to generate GPG key: %w", err)
+			return err
 		}
+		identity.GPGKeyID = keyID
+	}
 
-		identity.GPGKeyID = keyInfo.ID
-
-		// Export public key for display
-		publicKey, err := gpgpkg.ExportPublicKey(keyInfo.ID)
-		if err != nil {
-			// Key was generated but export failed - warn but continue
-			fmt.Fprintf(os.Stderr, "Warning: failed to export public key: %v\n", err)
+	// Add identity (handles validation and duplicate checks) to the config as
+	// it is now, since key generation can take a while
+	first := false
+	err = updateConfig(func(cfg *config.Config) error {
+		if err := cfg.AddIdentity(identity); err != nil {
+			return err
 		}
 
-		// Print key generation success info
-		fmt.Println(ui.SuccessStyle.Render("Generated GPG key:"))
-		fmt.Printf("  Key ID: %s\n", keyInfo.ID)
-		fmt.Printf("  Fingerprint: %s\n", keyInfo.Fingerprint)
-		fmt.Println()
-		if publicKey != "" {
-			fmt.Println("Public key (add to GitHub/GitLab):")
-			fmt.Print(strings.TrimSuffix(publicKey, "\n"))
-			fmt.Println()
-			fmt.Println()
+		// Set as default if requested
+		if addDefault {
+			if err := cfg.SetDefault(addName); err != nil {
+				return fmt.Errorf("failed to set default: %w", err)
+			}
 		}
+		first = len(cfg.Identities) == 1
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Add identity (handles validation and duplicate checks)
-	if err := cfg.AddIdentity(identity); err != nil {
-		return err
+	// If this is the first identity, update prompt cache (it becomes implicitly active)
+	if first {
+		_ = prompt.UpdateCache(identity.Name) // Best effort
 	}
 
-	// Set as default if requested
+	// Print success
+	printSuccess(fmt.Sprintf("Added identity '%s' (%s)", addName, addEmail))
+
 	if addDefault {
-		if err := cfg.SetDefault(addName); err != nil {
-			return fmt.Errorf("failed to set default: %w", err)
+		printInfo("Set as default identity")
+	}
+
+	return nil
+}
+
+// linkSSHKey expands and validates an existing SSH private key path,
+// warning if its permissions would make ssh refuse it
+func linkSSHKey(keyPath string) (string, error) {
+	expandedPath, err := expandPath(keyPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid SSH key path: %w", err)
+	}
+
+	if err := sshpkg.ValidateKeyPath(expandedPath); err != nil {
+		return "", fmt.Errorf("SSH key validation failed: %w", err)
+	}
+
+	// ssh refuses keys other users can read
+	if err := sshpkg.CheckKeyPermissions(expandedPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		fmt.Fprintf(os.Stderr, "  Fix with: chmod 600 %s (or run 'gitch doctor')\n", expandedPath)
+	}
+
+	return expandedPath, nil
+}
+
+// checkSSHKeyPath returns the default SSH key path for an identity name.
+// It fails if a key already exists there, unless force is set.
+func checkSSHKeyPath(name string, force bool) (string, error) {
+	keyPath := sshpkg.DefaultSSHKeyPath(name)
+	if keyPath == "" {
+		return "", errors.New("failed to determine SSH key path")
+	}
+
+	// Check if key already exists
+	if _, err := os.Stat(keyPath); err == nil && !force {
+		return "", fmt.Errorf("SSH key already exists at %s; use --force to overwrite", keyPath)
+	}
+
+	return keyPath, nil
+}
+
+// resolveSSHKeyType parses a --key-type value. An empty value auto-detects:
+// RSA for Azure DevOps remotes, Ed25519 otherwise.
+func resolveSSHKeyType(flag string) (sshpkg.KeyType, error) {
+	isAzureDevOps := gitpkg.IsAzureDevOps()
+
+	if flag != "" {
+		// User explicitly specified key type
+		keyType, err := sshpkg.ParseKeyType(flag)
+		if err != nil {
+			return "", fmt.Errorf("invalid --key-type: %w", err)
 		}
+
+		// Warn if using Ed25519 with Azure DevOps
+		if keyType == sshpkg.KeyTypeEd25519 && isAzureDevOps {
+			fmt.Println(ui.WarningStyle.Render("Warning: Ed25519 keys may not work with Azure DevOps. Consider using --key-type rsa"))
+			fmt.Println()
+		}
+		return keyType, nil
 	}
 
-	// Save config
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	// Auto-detect based on remote
+	if isAzureDevOps {
+		fmt.Println(ui.DimStyle.Render("Using RSA key (Azure DevOps detected)"))
+		fmt.Println()
+		return sshpkg.KeyTypeRSA, nil
 	}
+	return sshpkg.KeyTypeEd25519, nil
+}
 
-	// If this is the first identity, update prompt cache (it becomes implicitly active)
-	if len(cfg.Identities) == 1 {
-		_ = prompt.UpdateCache(identity.Name) // Best effort
+// generateSSHKey generates a keypair, writes it to keyPath, and prints the
+// fingerprint and public key.
+func generateSSHKey(keyPath, email string, keyType sshpkg.KeyType, passphrase []byte) error {
+	// Generate keypair with specified type
+	privateKey, publicKey, err := sshpkg.GenerateKeyPairWithType(keyType, email, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to generate SSH keypair: %w", err)
 	}
 
-	// Print success
-	msg := fmt.Sprintf("Added identity '%s' (%s)", addName, addEmail)
-	fmt.Println(ui.SuccessStyle.Render(msg))
+	// Write key files
+	if err := sshpkg.WriteKeyFiles(keyPath, privateKey, publicKey); err != nil {
+		return fmt.Errorf("failed to write SSH key files: %w", err)
+	}
 
-	if addDefault {
-		fmt.Println("Set as default identity")
+	// Get fingerprint for display
+	fingerprint, err := sshpkg.GetFingerprint(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to get key fingerprint: %w", err)
 	}
 
+	if quiet {
+		return nil
+	}
+
+	// Print key generation success info with key type
+	fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("Generated %s SSH key:", sshKeyTypeLabel(keyType))))
+	fmt.Printf("  Path: %s\n", keyPath)
+	fmt.Printf("  Fingerprint: %s\n", fingerprint)
+	fmt.Println()
+	fmt.Println("Public key (add to GitHub/GitLab):")
+	fmt.Print(strings.TrimSuffix(string(publicKey), "\n"))
+	fmt.Println()
+	fmt.Println()
+
 	return nil
 }
+
+// generateGPGKey generates a GPG key, prints its details and public key,
+// and returns the key ID.
+func generateGPGKey(name, email string, passphrase []byte, lifetime time.Duration) (string, error) {
+	keyInfo, err := gpgpkg.GenerateKeyWithExpiry(name, email, passphrase, lifetime)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate GPG key: %w", err)
+	}
+
+	// Export public key for display
+	publicKey, err := gpgpkg.ExportPublicKey(keyInfo.ID)
+	if err != nil {
+		// Key was generated but export failed - warn but continue
+		fmt.Fprintf(os.Stderr, "Warning: failed to export public key: %v\n", err)
+	}
+
+	if quiet {
+		return keyInfo.ID, nil
+	}
+
+	// Print key generation success info
+	fmt.Println(ui.SuccessStyle.Render("Generated GPG key:"))
+	fmt.Printf("  Key ID: %s\n", keyInfo.ID)
+	fmt.Printf("  Fingerprint: %s\n", keyInfo.Fingerprint)
+	if keyInfo.Expires != nil {
+		fmt.Printf("  Expires: %s\n", keyInfo.Expires.Format("2006-01-02"))
+	}
+	fmt.Println()
+	if publicKey != "" {
+		fmt.Println("Public key (add to GitHub/GitLab):")
+		fmt.Print(strings.TrimSuffix(publicKey, "\n"))
+		fmt.Println()
+		fmt.Println()
+	}
+
+	return keyInfo.ID, nil
+}
+
+// sshKeyTypeLabel returns a human-readable label for a generated SSH key type
+func sshKeyTypeLabel(keyType sshpkg.KeyType) string {
+	switch keyType {
+	case sshpkg.KeyTypeRSA:
+		return "RSA 4096-bit"
+	case sshpkg.KeyTypeECDSA:
+		return "ECDSA P-256"
+	case sshpkg.KeyTypeECDSAP384:
+		return "ECDSA P-384"
+	default:
+		return "Ed25519"
+	}
+}
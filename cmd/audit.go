@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"text/tabwriter"
+	"time"
 
 	"github.com/orzazade/gitch/internal/audit"
 	"github.com/orzazade/gitch/internal/ui"
@@ -15,6 +18,13 @@ var (
 	auditAll     bool
 	auditShowAll bool
 	auditFix     bool
+	auditJSON    bool
+	auditSince   string
+	auditUntil   string
+	auditVerify  bool
+	auditAuthor  string
+	auditDryRun  bool
+	auditBackup  string
 )
 
 var auditCmd = &cobra.Command{
@@ -23,18 +33,51 @@ var auditCmd = &cobra.Command{
 	Long: `Scan git history for commits made with an identity that doesn't match
 the expected identity for this repository.
 
-The audit compares each commit's author email against the identity that
-gitch's rules indicate should be used for this repository.
+The audit compares each commit's author and committer emails against the
+identity that gitch's rules indicate should be used for this repository.
+Rebases and cherry-picks can leave a wrong committer even when the author
+is correct; such commits are marked with "(committer)".
 
 By default, scans the last 1000 commits. Use --limit to change this,
-or --all to scan the entire history.
+or --all to scan the entire history. Use --since/--until to restrict the
+scan to a date range (YYYY-MM-DD or relative, e.g. "2 weeks ago").
+
+In shared repositories, use --author to audit only one person's commits.
+The pattern is passed to 'git log --author', so it matches part of the
+author's name or email (e.g. your email or name).
 
 Examples:
   gitch audit                    # Scan last 1000 commits
   gitch audit --limit 100        # Scan last 100 commits
   gitch audit --all              # Scan entire history
   gitch audit --show-all         # Include matching commits in output
-  gitch audit --fix              # Fix mismatched commits (destructive!)`,
+  gitch audit --since 2024-01-01 # Commits since a date
+  gitch audit --since "3 months ago" --until "1 month ago"
+  gitch audit --author me@example.com # Only my commits
+  gitch audit --json             # Machine-readable output for CI
+  gitch audit --verify-signatures # Also check GPG signatures
+  gitch audit --fix              # Fix mismatched commits (destructive!)
+  gitch audit --fix --dry-run    # Preview the rewrite without changing anything
+  gitch audit --fix --backup-dir ~/backups
+
+With --verify-signatures, each commit's GPG signature is checked and the
+signing key is compared against the expected identity's GPG key. Signature
+checks are skipped with a warning if gpg is not installed.
+
+With --json, the exit code is 1 when any mismatched commits are found,
+so CI pipelines can fail the build.
+
+With --fix, mismatched commits are rewritten to the expected email using
+git-filter-repo. Before rewriting, gitch creates a mirror backup and asks
+you to type "I UNDERSTAND"; afterwards it removes remotes to prevent an
+accidental force-push. --fix and --json cannot be used together.
+
+The mirror backup is written to the system temp directory unless
+--backup-dir names another (existing, writable) directory.
+
+Add --dry-run to --fix to print the commits that would be rewritten, the
+exact mailmap, and the backup location, without creating a backup,
+rewriting history, or removing remotes.`,
 	Args: cobra.NoArgs,
 	RunE: runAudit,
 }
@@ -44,10 +87,27 @@ func init() {
 	auditCmd.Flags().IntVar(&auditLimit, "limit", 0, "Maximum commits to scan (default 1000, 0 for default)")
 	auditCmd.Flags().BoolVar(&auditAll, "all", false, "Scan entire history (ignores --limit)")
 	auditCmd.Flags().BoolVar(&auditShowAll, "show-all", false, "Show all commits, not just mismatches")
-	auditCmd.Flags().BoolVar(&auditFix, "fix", false, "Rewrite mismatched commits with correct identity")
+	auditCmd.Flags().BoolVar(&auditFix, "fix", false, "Rewrite mismatched commits with correct identity (not with --json)")
+	auditCmd.Flags().StringVar(&auditSince, "since", "", "Only scan commits after this date (YYYY-MM-DD or relative, e.g. \"2 weeks ago\")")
+	auditCmd.Flags().StringVar(&auditUntil, "until", "", "Only scan commits up to this date (inclusive)")
+	auditCmd.Flags().StringVar(&auditAuthor, "author", "", "Only scan commits whose author name or email matches this pattern")
+	auditCmd.Flags().BoolVar(&auditVerify, "verify-signatures", false, "Verify GPG signatures against the expected identity's key")
+	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Output in JSON format (exits 1 on mismatches)")
+	auditCmd.Flags().BoolVar(&auditDryRun, "dry-run", false, "With --fix, preview the rewrite without changing anything")
+	auditCmd.Flags().StringVar(&auditBackup, "backup-dir", "", "With --fix, directory for the mirror backup (default: system temp dir)")
 }
 
 func runAudit(cmd *cobra.Command, args []string) error {
+	if auditJSON && auditFix {
+		return errors.New("cannot use both --fix and --json")
+	}
+	if auditDryRun && !auditFix {
+		return errors.New("--dry-run can only be used with --fix")
+	}
+	if auditBackup != "" && !auditFix {
+		return errors.New("--backup-dir can only be used with --fix")
+	}
+
 	// Check if we're in a git repo
 	if !audit.IsGitRepo() {
 		return fmt.Errorf("not in a git repository")
@@ -61,14 +121,41 @@ func runAudit(cmd *cobra.Command, args []string) error {
 
 	// Run scan
 	opts := audit.ScanOptions{
-		Limit:   limit,
-		ShowAll: auditShowAll,
+		Limit:            limit,
+		ShowAll:          auditShowAll,
+		VerifySignatures: auditVerify,
+		Author:           auditAuthor,
 	}
+
+	// Parse date range
+	now := time.Now()
+	if auditSince != "" {
+		since, err := audit.ParseDate(auditSince, now, false)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		opts.Since = since
+	}
+	if auditUntil != "" {
+		until, err := audit.ParseDate(auditUntil, now, true)
+		if err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+		opts.Until = until
+	}
+	if !opts.Since.IsZero() && !opts.Until.IsZero() && opts.Until.Before(opts.Since) {
+		return fmt.Errorf("--until must not be before --since")
+	}
+
 	result, err := audit.Scan(opts)
 	if err != nil {
 		return fmt.Errorf("audit failed: %w", err)
 	}
 
+	if auditVerify && result.MatchedRule != nil && !result.SignaturesVerified {
+		fmt.Fprintln(os.Stderr, "Warning: gpg not found, skipping signature verification")
+	}
+
 	// If --fix flag, run fix workflow instead of just printing
 	if auditFix {
 		// Ensure we have mismatches to fix
@@ -77,8 +164,38 @@ func runAudit(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
+		fixOpts := audit.FixOptions{}
+		if auditBackup != "" {
+			backupDir, err := expandPath(auditBackup)
+			if err != nil {
+				return fmt.Errorf("invalid --backup-dir: %w", err)
+			}
+			if fixOpts.BackupDir, err = filepath.Abs(backupDir); err != nil {
+				return fmt.Errorf("invalid --backup-dir: %w", err)
+			}
+		}
+
+		if auditDryRun {
+			return audit.FixDryRun(result, fixOpts)
+		}
+
 		// Run fix workflow
-		return audit.Fix(result)
+		return audit.Fix(result, fixOpts)
+	}
+
+	// JSON output format
+	if auditJSON {
+		jsonBytes, err := audit.MarshalJSONReport(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+
+		// Non-zero exit so scripts can fail the build
+		if result.MismatchCount > 0 || result.BadSignatureCount > 0 {
+			os.Exit(1)
+		}
+		return nil
 	}
 
 	// Handle output
@@ -100,25 +217,32 @@ func printAuditResults(result *audit.ScanResult) error {
 	fmt.Printf("Commits scanned: %d\n\n", result.TotalScanned)
 
 	// Handle no mismatches
-	if result.MismatchCount == 0 {
+	if result.MismatchCount == 0 && result.BadSignatureCount == 0 {
 		fmt.Println(ui.SuccessStyle.Render("All commits match the expected identity."))
 		return nil
 	}
 
-	// Print results table
+	// Print results table (with a signature column when verifying)
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "STATUS\tHASH\tAUTHOR\tDATE\tSUBJECT")
+	if result.SignaturesVerified {
+		fmt.Fprintln(w, "STATUS\tSIG\tHASH\tAUTHOR\tDATE\tSUBJECT")
+	} else {
+		fmt.Fprintln(w, "STATUS\tHASH\tAUTHOR\tDATE\tSUBJECT")
+	}
 
 	for _, r := range result.Results {
-		if !r.IsMismatched && !auditShowAll {
+		if !r.IsMismatched && r.SignatureStatus != audit.SignatureBad && !auditShowAll {
 			continue
 		}
 
 		status := formatStatus(r)
 		subject := truncateSubject(r.Commit.Subject, 50)
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-			status,
+		fmt.Fprintf(w, "%s\t", status)
+		if result.SignaturesVerified {
+			fmt.Fprintf(w, "%s\t", formatSignature(r.SignatureStatus))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
 			r.Commit.Hash[:8],
 			r.Commit.AuthorEmail,
 			r.Commit.Date.Format("2006-01-02"),
@@ -137,10 +261,34 @@ func formatStatus(r audit.Result) string {
 	if !r.IsMismatched {
 		return ui.SuccessStyle.Render("OK")
 	}
+
+	// Show which field is wrong unless it's the common author-only case
+	label := "LOCAL"
+	if r.IsPushed {
+		label = "PUSHED"
+	}
+	switch r.MismatchKind {
+	case audit.CommitterMismatch:
+		label += " (committer)"
+	case audit.BothMismatch:
+		label += " (both)"
+	}
+
 	if r.IsPushed {
-		return ui.ErrorStyle.Render("PUSHED")
+		return ui.ErrorStyle.Render(label)
+	}
+	return ui.WarningStyle.Render(label)
+}
+
+func formatSignature(status audit.SignatureStatus) string {
+	switch status {
+	case audit.SignatureGood:
+		return ui.SuccessStyle.Render("good")
+	case audit.SignatureBad:
+		return ui.ErrorStyle.Render("bad")
+	default:
+		return ui.DimStyle.Render(string(status))
 	}
-	return ui.WarningStyle.Render("LOCAL")
 }
 
 func truncateSubject(subject string, maxLen int) string {
@@ -151,6 +299,11 @@ func truncateSubject(subject string, maxLen int) string {
 }
 
 func printSummary(result *audit.ScanResult) {
+	if result.BadSignatureCount > 0 {
+		msg := fmt.Sprintf("Found %d commit(s) with bad or unexpected signatures", result.BadSignatureCount)
+		fmt.Println(ui.ErrorStyle.Render(msg))
+	}
+
 	if result.MismatchCount == 0 {
 		return
 	}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/orzazade/gitch/internal/rules"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	autoGlobal    bool
+	autoNoDefault bool
+)
+
+var autoCmd = &cobra.Command{
+	Use:   "auto",
+	Short: "Apply the identity selected by rules for the current directory",
+	Long: `Apply the identity selected by your rules for the current directory and remote.
+
+The best matching rule decides the identity. It is written to the current
+repository's git config, or to the global config with --global. If no rule
+matches, the default identity is used; pass --no-default to do nothing
+instead.
+
+Nothing is printed when the identity already matches, so this is safe to
+run from a shell 'cd' hook or on every prompt. Outside a git repository,
+'gitch auto' does nothing unless --global is set.
+
+Examples:
+  gitch auto
+  gitch auto --global
+  gitch auto --no-default`,
+	Args: cobra.NoArgs,
+	RunE: runAuto,
+}
+
+func init() {
+	rootCmd.AddCommand(autoCmd)
+	autoCmd.Flags().BoolVar(&autoGlobal, "global", false, "Apply the identity to the global git config")
+	autoCmd.Flags().BoolVar(&autoNoDefault, "no-default", false, "Do nothing when no rule matches instead of using the default identity")
+}
+
+func runAuto(cmd *cobra.Command, args []string) error {
+	// Local scope needs a repository; stay silent so prompt hooks don't spam
+	if !autoGlobal && git.RepoRoot() == "" {
+		return nil
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	remoteURL, _ := rules.GetGitRemoteURL()
+
+	identityName := ""
+	if matchedRule := rules.FindBestMatch(cfg.Rules, cwd, remoteURL); matchedRule != nil {
+		identityName = matchedRule.Identity
+	} else if !autoNoDefault {
+		identityName = cfg.Default
+	}
+	if identityName == "" {
+		return nil
+	}
+
+	identity, err := cfg.GetIdentity(identityName)
+	if err != nil {
+		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", identityName)
+	}
+
+	// Idempotent: nothing to do if git already uses this identity
+	currentName, _ := git.GetConfig("user.name", autoGlobal)
+	currentEmail, _ := git.GetConfig("user.email", autoGlobal)
+	if currentName == identity.Name && strings.EqualFold(currentEmail, identity.Email) {
+		return nil
+	}
+
+	if err := applyIdentity(identity, autoGlobal); err != nil {
+		return fmt.Errorf("failed to switch identity: %w", err)
+	}
+
+	// Load SSH keys if present (best effort)
+	addAgentKeys(identity)
+
+	// The prompt cache tracks the global identity
+	if autoGlobal {
+		if err := prompt.UpdateCache(identity.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update prompt cache: %v\n", err)
+		}
+	}
+
+	msg := fmt.Sprintf("Switched to '%s' (%s)", identity.Name, identity.Email)
+	if !autoGlobal {
+		msg += " in this repository"
+	}
+	fmt.Println(ui.SuccessStyle.Render(msg))
+
+	return nil
+}
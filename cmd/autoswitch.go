@@ -7,6 +7,7 @@ import (
 
 	"github.com/orzazade/gitch/internal/config"
 	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/prompt"
 	"github.com/orzazade/gitch/internal/rules"
 	sshpkg "github.com/orzazade/gitch/internal/ssh"
 )
@@ -66,22 +67,22 @@ func TryAutoSwitch(cfg *config.Config) (*AutoSwitchResult, error) {
 	}
 
 	// 6. Perform the switch
-	// Set git config
-	if err := git.SetConfig("user.name", expectedIdentity.Name, true); err != nil {
-		return nil, err
-	}
-	if err := git.SetConfig("user.email", expectedIdentity.Email, true); err != nil {
+	// Set git config, including signing
+	if err := applyIdentity(expectedIdentity, true); err != nil {
 		return nil, err
 	}
 
-	// Load SSH key if present (silently ignore errors)
-	if expectedIdentity.SSHKeyPath != "" {
-		_ = sshpkg.AddKeyToAgent(expectedIdentity.SSHKeyPath)
+	// Load SSH keys if present (silently ignore errors)
+	for _, keyPath := range expectedIdentity.AgentKeys() {
+		_ = sshpkg.AddKeyToAgent(keyPath, expectedIdentity.AgentLifetime)
 	}
 
 	// Update default in config
-	cfg.Default = expectedIdentity.Name
-	_ = cfg.Save()
+	_ = updateConfig(func(cfg *config.Config) error {
+		return cfg.SetDefault(expectedIdentity.Name)
+	})
+
+	_ = prompt.UpdateCache(expectedIdentity.Name) // Best effort
 
 	return &AutoSwitchResult{
 		Switched:     true,
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/orzazade/gitch/internal/hooks"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of 'gitch check'
+const (
+	checkExitMatch    = 0 // the active identity is the one the matching rule expects
+	checkExitMismatch = 1 // a rule matches but expects another identity
+	checkExitNoRule   = 2 // no rule matches
+	checkExitError    = 3 // the check could not run, e.g. unreadable config
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the active identity against the rules, for scripts and CI",
+	Long: `Check whether the active git identity is the one the rules expect for the
+current directory, print the result on one line and exit with a code
+scripts can act on:
+
+  0  match     - the active identity is the one the matching rule expects
+  1  mismatch  - a rule matches but expects another identity
+  2  no-rule   - no rule matches
+  3  error     - the check could not run (e.g. unreadable config)
+
+Unlike the pre-commit hook, the check ignores GITCH_BYPASS, .gitch-ignore,
+gitch.bypass and require_rule. Use --quiet to print nothing.
+
+Examples:
+  gitch check
+  gitch check -q || echo "wrong identity"`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	result, err := hooks.Check()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(checkExitError)
+	}
+
+	if result.MatchedRule == nil {
+		cwd, _ := os.Getwd()
+		printInfo("no-rule: no rule matches %s", cwd)
+		os.Exit(checkExitNoRule)
+	}
+
+	rule := fmt.Sprintf("%s (%s)", result.MatchedRule.Pattern, result.MatchedRule.Type)
+	if result.Match {
+		printInfo("match: '%s' (%s) is active, as rule %s expects", result.ExpectedName, result.ExpectedEmail, rule)
+		return nil
+	}
+
+	current := result.CurrentEmail
+	if current == "" {
+		current = "(not set)"
+	}
+	printInfo("mismatch: rule %s expects '%s' (%s), but git uses %s", rule, result.ExpectedName, result.ExpectedEmail, current)
+	os.Exit(checkExitMismatch)
+	return nil
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestRunCheckProcess runs 'gitch check' when started by TestRunCheck, so the
+// exit code runCheck ends the process with can be inspected
+func TestRunCheckProcess(t *testing.T) {
+	if os.Getenv("GITCH_TEST_RUN_CHECK") != "1" {
+		t.Skip("run by TestRunCheck")
+	}
+	if err := runCheck(checkCmd, nil); err != nil {
+		os.Exit(100)
+	}
+	os.Exit(checkExitMatch)
+}
+
+func TestRunCheck(t *testing.T) {
+	const rules = `identities:
+  - name: work
+    email: me@company.com
+rules:
+  - type: directory
+    pattern: {dir}/**
+    identity: work
+`
+
+	tests := []struct {
+		name     string
+		config   string
+		email    string
+		wantCode int
+		wantOut  string
+	}{
+		{"match", rules, "me@company.com", checkExitMatch, "match: 'work' (me@company.com) is active"},
+		{"mismatch", rules, "me@example.com", checkExitMismatch, "mismatch: rule"},
+		{"no email set", rules, "", checkExitMismatch, "git uses (not set)"},
+		{"no rule", "identities: []\n", "me@company.com", checkExitNoRule, "no-rule: no rule matches"},
+		{"unreadable config", "identities: [", "me@company.com", checkExitError, "Error: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempHome(t)
+			dir := t.TempDir()
+			useTestConfig(t, strings.ReplaceAll(tt.config, "{dir}", dir))
+			if tt.email != "" {
+				if err := exec.Command("git", "config", "--global", "user.email", tt.email).Run(); err != nil {
+					t.Fatalf("failed to set user.email: %v", err)
+				}
+			}
+
+			cmd := exec.Command(os.Args[0], "-test.run=^TestRunCheckProcess$")
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GITCH_TEST_RUN_CHECK=1")
+			out, err := cmd.CombinedOutput()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("failed to run check: %v", err)
+			}
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d; output:\n%s", code, tt.wantCode, out)
+			}
+			if !strings.Contains(string(out), tt.wantOut) {
+				t.Errorf("output missing %q:\n%s", tt.wantOut, out)
+			}
+		})
+	}
+}
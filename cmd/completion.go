@@ -1,13 +1,13 @@
 package cmd
 
 import (
-	"os"
-
 	"github.com/spf13/cobra"
 )
 
+var completionNoDescriptions bool
+
 var completionCmd = &cobra.Command{
-	Use:   "completion [bash|zsh|fish]",
+	Use:   "completion [bash|zsh|fish|powershell]",
 	Short: "Generate shell completion script",
 	Long: `Generate shell completion script for gitch.
 
@@ -41,18 +41,35 @@ Fish:
 
   # Current session only:
   $ gitch completion fish | source
+
+PowerShell:
+  PS> gitch completion powershell | Out-String | Invoke-Expression
+
+Completions show a description next to each candidate, e.g. the email of
+each identity. Use --no-descriptions for shells or setups that render them
+badly.
 `,
 	DisableFlagsInUseLine: true,
-	ValidArgs:             []string{"bash", "zsh", "fish"},
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
 	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		descriptions := !completionNoDescriptions
 		switch args[0] {
 		case "bash":
-			return cmd.Root().GenBashCompletionV2(os.Stdout, true)
+			return cmd.Root().GenBashCompletionV2(out, descriptions)
 		case "zsh":
-			return cmd.Root().GenZshCompletion(os.Stdout)
+			if descriptions {
+				return cmd.Root().GenZshCompletion(out)
+			}
+			return cmd.Root().GenZshCompletionNoDesc(out)
 		case "fish":
-			return cmd.Root().GenFishCompletion(os.Stdout, true)
+			return cmd.Root().GenFishCompletion(out, descriptions)
+		case "powershell":
+			if descriptions {
+				return cmd.Root().GenPowerShellCompletionWithDesc(out)
+			}
+			return cmd.Root().GenPowerShellCompletion(out)
 		}
 		return nil
 	},
@@ -60,4 +77,7 @@ Fish:
 
 func init() {
 	rootCmd.AddCommand(completionCmd)
+	completionCmd.Flags().BoolVar(&completionNoDescriptions, "no-descriptions", false, "Leave out completion descriptions")
+	// Replaced by completionCmd; also hides cobra's default command
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// useTestConfig points GITCH_CONFIG at a temp config with the given YAML
+func useTestConfig(t *testing.T, yaml string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(yaml), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("GITCH_CONFIG", path)
+}
+
+const completionTestConfig = `identities:
+  - name: work
+    email: me@company.com
+  - name: personal
+    email: me@example.com
+    tags: [oss, client-a]
+rules:
+  - type: directory
+    pattern: ~/work/**
+    identity: work
+  - type: remote
+    pattern: github.com/me/*
+    identity: personal
+`
+
+func TestIdentityCompletionFunc(t *testing.T) {
+	useTestConfig(t, completionTestConfig)
+
+	completions, directive := identityCompletionFunc(useCmd, nil, "")
+	want := []string{"work\tme@company.com", "personal\tme@example.com"}
+	if !slices.Equal(completions, want) {
+		t.Errorf("completions = %q, want %q", completions, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+
+	// Only the first argument is an identity
+	completions, directive = identityCompletionFunc(useCmd, []string{"work"}, "")
+	if completions != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("second argument: got %q, %v; want no completions and NoFileComp", completions, directive)
+	}
+
+	// Flags complete identities whatever the positional args
+	completions, _ = identityFlagCompletionFunc(ruleAddCmd, []string{"~/work/**"}, "")
+	if !slices.Equal(completions, want) {
+		t.Errorf("flag completions = %q, want %q", completions, want)
+	}
+}
+
+func TestIdentityCompletionFunc_BadConfig(t *testing.T) {
+	useTestConfig(t, "identities: [")
+
+	completions, directive := identityCompletionFunc(useCmd, nil, "")
+	if completions != nil || directive != cobra.ShellCompDirectiveError {
+		t.Errorf("got %q, %v; want no completions and Error", completions, directive)
+	}
+}
+
+func TestRulePatternCompletionFunc(t *testing.T) {
+	useTestConfig(t, completionTestConfig)
+
+	completions, directive := rulePatternCompletionFunc(ruleRemoveCmd, nil, "")
+	want := []string{"~/work/**\tdirectory rule -> work", "github.com/me/*\tremote rule -> personal"}
+	if !slices.Equal(completions, want) {
+		t.Errorf("completions = %q, want %q", completions, want)
+	}
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v, want NoFileComp", directive)
+	}
+}
+
+func TestDirectoryCompletionFunc(t *testing.T) {
+	if _, directive := directoryCompletionFunc(ruleAddCmd, nil, ""); directive != cobra.ShellCompDirectiveFilterDirs {
+		t.Errorf("directive = %v, want FilterDirs", directive)
+	}
+	if _, directive := directoryCompletionFunc(ruleAddCmd, []string{"~/work"}, ""); directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("second argument directive = %v, want NoFileComp", directive)
+	}
+}
+
+// runComplete runs cobra's hidden __complete command, as the generated shell
+// scripts do, and returns the candidate lines and the directive line
+func runComplete(t *testing.T, args ...string) ([]string, string) {
+	t.Helper()
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, args...))
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+	})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("__complete %v: %v", args, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[len(lines)-1], ":") {
+		t.Fatalf("__complete %v: missing directive in %q", args, out.String())
+	}
+	return lines[:len(lines)-1], lines[len(lines)-1]
+}
+
+func TestCompletionWiring(t *testing.T) {
+	useTestConfig(t, completionTestConfig)
+
+	noFile := fmt.Sprintf(":%d", cobra.ShellCompDirectiveNoFileComp)
+	identities := []string{"work\tme@company.com", "personal\tme@example.com"}
+
+	tests := []struct {
+		args          []string
+		want          []string
+		wantDirective string
+	}{
+		{[]string{"use", ""}, identities, noFile},
+		{[]string{"show", ""}, identities, noFile},
+		{[]string{"rule", "add", "~/work/**", "--use", ""}, identities, noFile},
+		{[]string{"rule", "remove", ""}, []string{"~/work/**\tdirectory rule -> work", "github.com/me/*\tremote rule -> personal"}, noFile},
+		{[]string{"completion", ""}, []string{"bash", "zsh", "fish", "powershell"}, noFile},
+		{[]string{"list", "--tag", ""}, []string{"client-a", "oss"}, noFile},
+		{[]string{"tag", "work", ""}, []string{"+client-a", "+oss"}, noFile},
+		{[]string{"tag", "personal", ""}, []string{"-oss", "-client-a"}, noFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			got, directive := runComplete(t, tt.args...)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+			if directive != tt.wantDirective {
+				t.Errorf("directive = %s, want %s", directive, tt.wantDirective)
+			}
+		})
+	}
+}
+
+func TestCompletionCmd_Descriptions(t *testing.T) {
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	t.Cleanup(func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetArgs(nil)
+		completionNoDescriptions = false
+	})
+
+	rootCmd.SetArgs([]string{"completion", "zsh"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("completion zsh: %v", err)
+	}
+	if !strings.Contains(out.String(), "#compdef gitch") {
+		t.Fatal("expected a zsh completion script")
+	}
+	if strings.Contains(out.String(), cobra.ShellCompNoDescRequestCmd) {
+		t.Error("zsh script should request completions with descriptions")
+	}
+
+	out.Reset()
+	rootCmd.SetArgs([]string{"completion", "zsh", "--no-descriptions"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("completion zsh --no-descriptions: %v", err)
+	}
+	if !strings.Contains(out.String(), cobra.ShellCompNoDescRequestCmd) {
+		t.Error("zsh script with --no-descriptions should request completions without descriptions")
+	}
+}
@@ -1,9 +1,14 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/hooks"
 	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -16,7 +21,12 @@ var configCmd = &cobra.Command{
 Subcommands allow you to configure various aspects of gitch behavior.
 
 Examples:
-  gitch config hook-mode work block`,
+  gitch config hook-mode work block
+  gitch config description work "ACME contractor account"
+  gitch config require-rule warn
+  gitch config https-user work octocat
+  gh auth token | gitch config https-token work
+  gitch config backup`,
 }
 
 var configHookModeCmd = &cobra.Command{
@@ -37,9 +47,156 @@ Example:
 	RunE:              runConfigHookMode,
 }
 
+// setModeCmd is a top-level shortcut for 'gitch config hook-mode'
+var setModeCmd = &cobra.Command{
+	Use:   "set-mode <identity> <mode>",
+	Short: "Set hook behavior for an identity",
+	Long: `Set how the pre-commit hook behaves for a specific identity.
+This is a shortcut for 'gitch config hook-mode'.
+
+Modes:
+  allow - Always allow commits (no warning)
+  warn  - Show warning but allow commit (default)
+  block - Block commits until identity matches
+
+Example:
+  gitch set-mode work block`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: configHookModeCompletionFunc,
+	RunE:              runConfigHookMode,
+}
+
+var configDescriptionCmd = &cobra.Command{
+	Use:   "description <identity> [text]",
+	Short: "Set the description of an identity",
+	Long: `Set the free-form note shown under an identity in 'gitch list' and the
+selector, for telling similar identities apart. Omit the text to clear it.
+
+Example:
+  gitch config description work-contract "ACME contractor account"`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runConfigDescription,
+}
+
+var configHTTPSUserCmd = &cobra.Command{
+	Use:   "https-user <identity> [username]",
+	Short: "Set the HTTPS username for an identity",
+	Long: `Set the username 'gitch git-credential' returns for HTTPS remotes
+that resolve to this identity. Omit the username to clear it.
+
+Example:
+  gitch config https-user work octocat`,
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runConfigHTTPSUser,
+}
+
+var configHTTPSTokenCmd = &cobra.Command{
+	Use:   "https-token <identity>",
+	Short: "Set the HTTPS access token for an identity",
+	Long: `Set the access token 'gitch git-credential' returns as the password for
+HTTPS remotes that resolve to this identity.
+
+The token is read without echo in a terminal, or from the first line of
+stdin otherwise. An empty token clears it. Tokens are stored in the gitch
+config file and are never included in 'gitch export' or 'gitch list --json'.
+
+Examples:
+  gitch config https-token work
+  gh auth token | gitch config https-token work`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runConfigHTTPSToken,
+}
+
+var configAgentCmd = &cobra.Command{
+	Use:   "agent <identity> <on|off>",
+	Short: "Turn ssh-agent key loading on or off for an identity",
+	Long: `Choose whether switching to an identity adds its SSH keys to ssh-agent
+(and switching away removes them). Turn it off on machines without an agent,
+or when keys are managed by another tool, to skip the agent and its warnings.
+
+Set GITCH_NO_AGENT=1 to turn it off for every identity.
+
+Examples:
+  gitch config agent work off
+  gitch config agent work on`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: configAgentCompletionFunc,
+	RunE:              runConfigAgent,
+}
+
+var configRequireRuleCmd = &cobra.Command{
+	Use:   "require-rule [off|warn|block]",
+	Short: "Set what the hook does in repositories no rule matches",
+	Long: `Set what the pre-commit hook does when no rule matches the repository,
+so commits don't silently use whatever global identity is active.
+
+Modes:
+  off   - Allow the commit silently (default)
+  warn  - Allow the commit but print a warning
+  block - Block the commit until a rule is added with 'gitch rule add'
+
+Without a mode, prints the current setting.
+
+Examples:
+  gitch config require-rule warn
+  gitch config require-rule`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"off\tAllow commits silently", "warn\tWarn but allow commits", "block\tBlock commits until a rule matches"},
+	RunE:      runConfigRequireRule,
+}
+
+var configBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Save a timestamped copy of the config file",
+	Long: `Copy the gitch config file to a timestamped file next to it, e.g.
+config.yaml.20260102-150405.bak, and print its path.
+
+'gitch import' makes the same backup automatically before overwriting
+existing identities or rules (disable with --no-backup).
+
+Example:
+  gitch config backup`,
+	Args: cobra.NoArgs,
+	RunE: runConfigBackup,
+}
+
+var configRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Replace the config file with a backup",
+	Long: `Replace the gitch config file with a backup made by 'gitch config backup'
+or by 'gitch import'.
+
+The backup is checked first: it must parse, its identities must be valid, and
+its rules and default must refer to existing identities. The current config
+is backed up before it is replaced, so a restore can itself be undone.
+
+Example:
+  gitch config restore ~/.config/gitch/config.yaml.20260102-150405.bak`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigRestore,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configHookModeCmd)
+	configCmd.AddCommand(configDescriptionCmd)
+	configCmd.AddCommand(configHTTPSUserCmd)
+	configCmd.AddCommand(configHTTPSTokenCmd)
+	configCmd.AddCommand(configAgentCmd)
+	configCmd.AddCommand(configRequireRuleCmd)
+	configCmd.AddCommand(configBackupCmd)
+	configCmd.AddCommand(configRestoreCmd)
+	rootCmd.AddCommand(setModeCmd)
+}
+
+// hookModeCompletions lists hook modes with descriptions for tab completion
+var hookModeCompletions = []string{
+	"allow\tAlways allow commits",
+	"warn\tShow warning but allow",
+	"block\tBlock commits until identity matches",
 }
 
 // configHookModeCompletionFunc provides tab completion for config hook-mode command
@@ -59,11 +216,7 @@ func configHookModeCompletionFunc(cmd *cobra.Command, args []string, toComplete
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	case 1:
 		// Second arg: mode values
-		return []string{
-			"allow\tAlways allow commits",
-			"warn\tShow warning but allow",
-			"block\tBlock commits until identity matches",
-		}, cobra.ShellCompDirectiveNoFileComp
+		return hookModeCompletions, cobra.ShellCompDirectiveNoFileComp
 	default:
 		return nil, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -78,28 +231,282 @@ func runConfigHookMode(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	// Load config
-	cfg, err := config.Load()
+	var storedName string
+
+	// Hold the config lock across load-modify-save
+	err := config.WithLock(func() error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Find the identity
+		identity, err := cfg.GetIdentity(identityName)
+		if err != nil {
+			return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", identityName)
+		}
+		storedName = identity.Name
+
+		// Update the hook mode
+		identity.HookMode = mode
+
+		// Save config
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+		return err
 	}
 
-	// Find the identity
-	identity, err := cfg.GetIdentity(identityName)
+	msg := fmt.Sprintf("Hook mode for '%s' set to '%s'", storedName, mode)
+	fmt.Println(ui.SuccessStyle.Render(msg))
+
+	return nil
+}
+
+func runConfigDescription(cmd *cobra.Command, args []string) error {
+	description := ""
+	if len(args) == 2 {
+		description = strings.TrimSpace(args[1])
+	}
+	if err := config.ValidateDescription(description); err != nil {
+		return err
+	}
+
+	storedName, err := updateIdentityField(args[0], func(identity *config.Identity) {
+		identity.Description = description
+	})
 	if err != nil {
-		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", identityName)
+		return err
 	}
 
-	// Update the hook mode
-	identity.HookMode = mode
+	if description == "" {
+		printSuccess(fmt.Sprintf("Description for '%s' cleared", storedName))
+	} else {
+		printSuccess(fmt.Sprintf("Description for '%s' set to '%s'", storedName, description))
+	}
+	return nil
+}
 
-	// Save config
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+func runConfigHTTPSUser(cmd *cobra.Command, args []string) error {
+	username := ""
+	if len(args) == 2 {
+		username = args[1]
+	}
+	if err := config.ValidateHTTPSCredential("HTTPS user", username); err != nil {
+		return err
 	}
 
-	msg := fmt.Sprintf("Hook mode for '%s' set to '%s'", identity.Name, mode)
-	fmt.Println(ui.SuccessStyle.Render(msg))
+	storedName, err := updateIdentityField(args[0], func(identity *config.Identity) {
+		identity.HTTPSUser = username
+	})
+	if err != nil {
+		return err
+	}
 
+	if username == "" {
+		printSuccess(fmt.Sprintf("HTTPS user for '%s' cleared", storedName))
+	} else {
+		printSuccess(fmt.Sprintf("HTTPS user for '%s' set to '%s'", storedName, username))
+	}
 	return nil
 }
+
+func runConfigHTTPSToken(cmd *cobra.Command, args []string) error {
+	token, err := readHTTPSToken()
+	if err != nil {
+		return err
+	}
+	if err := config.ValidateHTTPSCredential("HTTPS token", token); err != nil {
+		return err
+	}
+
+	storedName, err := updateIdentityField(args[0], func(identity *config.Identity) {
+		identity.HTTPSToken = token
+	})
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		printSuccess(fmt.Sprintf("HTTPS token for '%s' cleared", storedName))
+	} else {
+		printSuccess(fmt.Sprintf("HTTPS token for '%s' saved", storedName))
+	}
+	return nil
+}
+
+// readHTTPSToken reads a token without echo from a terminal, or the first
+// line of stdin when piped
+func readHTTPSToken() (string, error) {
+	token, err := ui.ReadPassphrase("HTTPS token (empty to clear): ")
+	if err == nil {
+		return strings.TrimSpace(string(token)), nil
+	}
+	if !errors.Is(err, ui.ErrNotInteractive) {
+		return "", err
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", nil
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func runConfigBackup(cmd *cobra.Command, args []string) error {
+	backupPath, err := config.Backup()
+	if err != nil {
+		return err
+	}
+	printSuccess(fmt.Sprintf("Backed up config to %s", backupPath))
+	return nil
+}
+
+func runConfigRestore(cmd *cobra.Command, args []string) error {
+	path, err := expandPath(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	// Keep the current config so the restore can be undone
+	previous, err := config.Backup()
+	if err != nil && !errors.Is(err, config.ErrNoConfigFile) {
+		return fmt.Errorf("failed to back up current config: %w", err)
+	}
+
+	if err := config.Restore(path); err != nil {
+		if previous != "" {
+			os.Remove(previous)
+		}
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Restored config from %s", path))
+	if previous != "" {
+		printInfo("Previous config saved to %s", previous)
+	}
+	return nil
+}
+
+// configAgentCompletionFunc completes identity names, then on or off
+func configAgentCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 1 {
+		return []string{"on\tAdd SSH keys to ssh-agent when switching", "off\tLeave ssh-agent alone"}, cobra.ShellCompDirectiveNoFileComp
+	}
+	return identityCompletionFunc(cmd, args, toComplete)
+}
+
+func runConfigAgent(cmd *cobra.Command, args []string) error {
+	var noAgent bool
+	switch strings.ToLower(args[1]) {
+	case "on":
+		noAgent = false
+	case "off":
+		noAgent = true
+	default:
+		return fmt.Errorf("invalid value %q: must be on or off", args[1])
+	}
+
+	storedName, err := updateIdentityField(args[0], func(identity *config.Identity) {
+		identity.NoAgent = noAgent
+	})
+	if err != nil {
+		return err
+	}
+
+	if noAgent {
+		printSuccess(fmt.Sprintf("'%s' will no longer use ssh-agent when switching", storedName))
+	} else {
+		printSuccess(fmt.Sprintf("'%s' will add its SSH keys to ssh-agent when switching", storedName))
+	}
+	return nil
+}
+
+func runConfigRequireRule(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		fmt.Println(cfg.GetRequireRule())
+		return nil
+	}
+
+	mode := strings.ToLower(args[0])
+	if err := config.ValidateRequireRule(mode); err != nil || mode == "" {
+		return fmt.Errorf("invalid mode %q: must be one of: off, warn, block", args[0])
+	}
+
+	err := updateConfig(func(cfg *config.Config) error {
+		cfg.RequireRule = mode
+		if mode == config.RequireRuleOff {
+			cfg.RequireRule = ""
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	switch mode {
+	case config.RequireRuleWarn:
+		printSuccess("The pre-commit hook will warn in repositories no rule matches")
+	case config.RequireRuleBlock:
+		printSuccess("The pre-commit hook will block commits in repositories no rule matches")
+	default:
+		printSuccess("The pre-commit hook will allow commits in repositories no rule matches")
+	}
+
+	// Hooks written before require_rule treat a block as an identity mismatch
+	if mode != config.RequireRuleOff {
+		if status, err := hooks.IsInstalled(); err == nil && (status.GlobalOutdated || status.LocalOutdated) {
+			fmt.Fprintln(os.Stderr, "Warning: the installed gitch hooks are outdated; run 'gitch hook install' again so they handle require_rule")
+		}
+	}
+	return nil
+}
+
+// updateConfig loads the config, applies update and saves it, all under the
+// config lock so concurrent gitch processes don't lose each other's changes.
+// Nothing is saved if update returns an error.
+func updateConfig(update func(cfg *config.Config) error) error {
+	return config.WithLock(func() error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		if err := update(cfg); err != nil {
+			return err
+		}
+
+		// Save config
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	})
+}
+
+// updateIdentityField applies update to the named identity under the config
+// lock and saves. Returns the identity's stored name.
+func updateIdentityField(name string, update func(identity *config.Identity)) (string, error) {
+	var storedName string
+	err := updateConfig(func(cfg *config.Config) error {
+		identity, err := cfg.GetIdentity(name)
+		if err != nil {
+			return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", name)
+		}
+		storedName = identity.Name
+		update(identity)
+		return nil
+	})
+	return storedName, err
+}
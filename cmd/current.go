@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/rules"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var currentJSON bool
+
+// currentOutput represents the JSON output structure for gitch current --json
+type currentOutput struct {
+	Name     string       `json:"name"`
+	Email    string       `json:"email"`
+	Managed  bool         `json:"managed"`
+	FromRule bool         `json:"from_rule"`
+	Rule     *currentRule `json:"rule,omitempty"`
+}
+
+// currentRule describes the rule that selected the active identity
+type currentRule struct {
+	Type    string `json:"type"`
+	Pattern string `json:"pattern"`
+}
+
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the active gitch identity",
+	Long: `Print the gitch identity that is active in the current repository.
+
+The active git email is matched against configured identities. If a rule
+for the current directory or remote selects that identity, the rule is shown.
+
+Unlike 'gitch status', this command never switches identities.
+
+Examples:
+  gitch current
+  gitch current --json`,
+	RunE: runCurrent,
+}
+
+func init() {
+	rootCmd.AddCommand(currentCmd)
+	currentCmd.Flags().BoolVar(&currentJSON, "json", false, "Output in JSON format")
+}
+
+func runCurrent(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Get current git identity
+	name, email, err := git.GetCurrentIdentity()
+	if err != nil {
+		return fmt.Errorf("failed to get current identity: %w", err)
+	}
+
+	output := currentOutput{Name: name, Email: email}
+
+	// Match email against configured identities (case-insensitive)
+	var identity *config.Identity
+	identities := cfg.ListIdentities()
+	for i := range identities {
+		if email != "" && strings.EqualFold(identities[i].Email, email) {
+			identity = &identities[i]
+			break
+		}
+	}
+
+	if identity != nil {
+		output.Name = identity.Name
+		output.Managed = true
+
+		// Check whether a rule for this location selects the identity
+		cwd, _ := os.Getwd()
+		remoteURL, _ := rules.GetGitRemoteURL()
+		if matchedRule := rules.FindBestMatch(cfg.Rules, cwd, remoteURL); matchedRule != nil &&
+			strings.EqualFold(matchedRule.Identity, identity.Name) {
+			output.FromRule = true
+			output.Rule = &currentRule{
+				Type:    string(matchedRule.Type),
+				Pattern: matchedRule.Pattern,
+			}
+		}
+	}
+
+	// JSON output format
+	if currentJSON {
+		jsonBytes, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		if email != "" && !output.Managed {
+			fmt.Fprintln(os.Stderr, "Warning: active git email does not match any gitch identity; run 'gitch use <name>' to switch")
+		}
+		return nil
+	}
+
+	if email == "" {
+		fmt.Println("No active identity. Use 'gitch use <name>' to set one.")
+		return nil
+	}
+
+	if !output.Managed {
+		if name != "" {
+			fmt.Printf("%s (%s)\n", name, email)
+		} else {
+			fmt.Printf("(%s)\n", email)
+		}
+		fmt.Println(ui.WarningStyle.Render("Warning: this email does not match any gitch identity. Run 'gitch use <name>' to switch."))
+		return nil
+	}
+
+	fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("%s (%s)", output.Name, output.Email)))
+	if output.Rule != nil {
+		fmt.Println(ui.DimStyle.Render(fmt.Sprintf("Selected by %s rule: %s", output.Rule.Type, output.Rule.Pattern)))
+	} else {
+		fmt.Println(ui.DimStyle.Render("Not selected by any rule"))
+	}
+
+	return nil
+}
@@ -0,0 +1,313 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/gpg"
+	sshpkg "github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// defaultExpiryWindowDays is how far ahead GPG key expiry is reported
+const defaultExpiryWindowDays = 30
+
+var (
+	doctorExpiryWindow int
+	doctorFix          bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the gitch config for problems",
+	Long: `Check the gitch config for problems.
+
+Reports rules that reference identities which no longer exist and a default
+identity that has been removed. These otherwise surface later as errors in
+'gitch audit' or the pre-commit hook.
+
+Identities that share an email are reported as warnings, since switching
+between them is ambiguous.
+
+Also checks each identity's GPG key: expired keys are reported as problems,
+and keys expiring within --expiry-window days (default 30) as warnings, so
+commits don't suddenly show up as signed with an expired key.
+
+SSH private keys readable by group or others are reported as warnings, since
+ssh refuses to use them. In a terminal, doctor offers to restrict each one
+to 0600; --fix does so without asking.
+
+For identities that sign commits with their SSH key, a missing public key is
+reported as a problem, and a missing gpg.ssh.allowedSignersFile as a warning,
+since git can't verify SSH signatures without it.
+
+Exits with status 1 if any problems are found.
+
+Examples:
+  gitch doctor
+  gitch doctor --expiry-window 90
+  gitch doctor --fix`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().IntVar(&doctorExpiryWindow, "expiry-window", defaultExpiryWindowDays, "Warn about GPG keys expiring within this many days")
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Fix SSH key permissions without prompting")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if doctorExpiryWindow < 0 {
+		return fmt.Errorf("invalid --expiry-window %d: must not be negative", doctorExpiryWindow)
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if configPath, err := config.ConfigPath(); err == nil {
+		fmt.Println(ui.DimStyle.Render("Config: " + configPath))
+	}
+
+	problems := cfg.Validate()
+	configProblems := len(problems)
+
+	duplicates := duplicateEmailWarnings(cfg)
+	warnings := append([]string{}, duplicates...)
+
+	expiries := checkGPGExpiry(cfg.ListIdentities(), doctorExpiryWindow)
+	for _, expiry := range expiries {
+		if expiry.Expired {
+			problems = append(problems, errors.New(expiry.Message()))
+		} else {
+			warnings = append(warnings, expiry.Message())
+		}
+	}
+
+	loosePerms := checkKeyPermissions(cfg.ListIdentities())
+	for _, perm := range loosePerms {
+		warnings = append(warnings, perm.Message())
+	}
+
+	signingProblems, signingWarnings := checkSSHSigning(cfg.ListIdentities())
+	problems = append(problems, signingProblems...)
+	warnings = append(warnings, signingWarnings...)
+
+	if len(warnings) > 0 {
+		fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("Found %d warning(s):", len(warnings))))
+		for _, warning := range warnings {
+			fmt.Printf("  - %s\n", warning)
+		}
+	}
+	if len(duplicates) > 0 {
+		fmt.Println(ui.DimStyle.Render("Give each identity its own email, or remove redundant ones with 'gitch remove <name>'."))
+	}
+	if len(expiries) > 0 {
+		fmt.Println(ui.DimStyle.Render("Extend a GPG key with 'gpg --quick-set-expire <key-id> 1y', then re-upload the public key."))
+	}
+
+	if len(signingWarnings) > 0 {
+		fmt.Println(ui.DimStyle.Render("List '<email> <public key>' lines in ~/.ssh/allowed_signers, then run 'git config --global gpg.ssh.allowedSignersFile ~/.ssh/allowed_signers'."))
+	}
+
+	if len(loosePerms) > 0 {
+		fixKeyPermissions(loosePerms, doctorFix)
+	}
+
+	if len(problems) == 0 {
+		fmt.Println(ui.SuccessStyle.Render("No problems found."))
+		return nil
+	}
+
+	fmt.Println(ui.ErrorStyle.Render(fmt.Sprintf("Found %d problem(s):", len(problems))))
+	for _, problem := range problems {
+		fmt.Printf("  - %v\n", problem)
+	}
+	if configProblems > 0 {
+		fmt.Println()
+		fmt.Println("Remove dangling rules with 'gitch rule remove <pattern>', or edit the config file directly.")
+	}
+
+	os.Exit(1)
+	return nil
+}
+
+// duplicateEmailWarnings describes each email shared by several identities,
+// sorted by email
+func duplicateEmailWarnings(cfg *config.Config) []string {
+	duplicates := cfg.DuplicateEmails()
+	emails := make([]string, 0, len(duplicates))
+	for email := range duplicates {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+
+	warnings := make([]string, 0, len(emails))
+	for _, email := range emails {
+		warnings = append(warnings, fmt.Sprintf("email %q is shared by identities %s", email, quoteNames(duplicates[email])))
+	}
+	return warnings
+}
+
+// quoteNames formats names as 'a', 'b'
+func quoteNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// gpgExpiry describes an identity whose GPG key has expired or expires soon
+type gpgExpiry struct {
+	Identity string
+	KeyID    string
+	Expires  time.Time
+	Expired  bool
+}
+
+// Message formats the expiry for display
+func (e gpgExpiry) Message() string {
+	date := e.Expires.Format("2006-01-02")
+	if e.Expired {
+		return fmt.Sprintf("GPG key %s for '%s' expired on %s", e.KeyID, e.Identity, date)
+	}
+	days := int(math.Ceil(time.Until(e.Expires).Hours() / 24))
+	return fmt.Sprintf("GPG key %s for '%s' expires on %s (in %d day(s))", e.KeyID, e.Identity, date, days)
+}
+
+// checkGPGExpiry returns identities whose GPG key has expired or expires
+// within windowDays. Keys that can't be looked up are skipped.
+func checkGPGExpiry(identities []config.Identity, windowDays int) []gpgExpiry {
+	if !gpg.IsGPGAvailable() {
+		return nil
+	}
+
+	now := time.Now()
+	window := time.Duration(windowDays) * 24 * time.Hour
+
+	var expiring []gpgExpiry
+	for _, id := range identities {
+		if id.GPGKeyID == "" {
+			continue
+		}
+		info, err := gpg.GetKeyInfo(id.GPGKeyID)
+		if err != nil || !info.ExpiresWithin(now, window) {
+			continue
+		}
+		expiring = append(expiring, gpgExpiry{
+			Identity: id.Name,
+			KeyID:    id.GPGKeyID,
+			Expires:  *info.Expires,
+			Expired:  info.IsExpired(now),
+		})
+	}
+	return expiring
+}
+
+// printGPGExpiryWarnings prints expired and soon-to-expire GPG keys to stderr
+func printGPGExpiryWarnings(identities []config.Identity, windowDays int) {
+	for _, expiry := range checkGPGExpiry(identities, windowDays) {
+		style := ui.WarningStyle
+		if expiry.Expired {
+			style = ui.ErrorStyle
+		}
+		fmt.Fprintln(os.Stderr, style.Render("Warning: "+expiry.Message()))
+	}
+}
+
+// keyPermission describes an identity whose SSH private key is readable by
+// group or others
+type keyPermission struct {
+	Identity string
+	KeyPath  string
+	Err      error
+}
+
+// Message formats the permission problem for display
+func (k keyPermission) Message() string {
+	return fmt.Sprintf("SSH key for '%s': %v", k.Identity, k.Err)
+}
+
+// checkKeyPermissions returns identities whose SSH key has loose permissions.
+// Missing keys are skipped; they aren't a permissions problem.
+func checkKeyPermissions(identities []config.Identity) []keyPermission {
+	var loose []keyPermission
+	for _, id := range identities {
+		for _, keyPath := range id.SSHKeys() {
+			err := sshpkg.CheckKeyPermissions(keyPath)
+			if errors.Is(err, sshpkg.ErrKeyPermissions) {
+				loose = append(loose, keyPermission{Identity: id.Name, KeyPath: keyPath, Err: err})
+			}
+		}
+	}
+	return loose
+}
+
+// fixKeyPermissions offers to chmod each key to 0600, or does so directly
+// when force is set. Without a terminal it only prints the command to run.
+func fixKeyPermissions(loose []keyPermission, force bool) {
+	for _, perm := range loose {
+		confirmed, err := ui.ConfirmPrompt(fmt.Sprintf("Restrict %s to 0600?", perm.KeyPath), force)
+		if err != nil {
+			fmt.Println(ui.DimStyle.Render("Fix with 'chmod 600 " + perm.KeyPath + "' or 'gitch doctor --fix'."))
+			continue
+		}
+		if !confirmed {
+			continue
+		}
+		if err := sshpkg.FixKeyPermissions(perm.KeyPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		fmt.Println(ui.SuccessStyle.Render("Fixed permissions on " + perm.KeyPath))
+	}
+}
+
+// checkSSHSigning checks identities that sign commits with their SSH key:
+// a missing public key is a problem, and a missing allowed signers file
+// (needed to verify SSH signatures) a warning.
+func checkSSHSigning(identities []config.Identity) ([]error, []string) {
+	var problems []error
+	signsWithSSH := false
+	for _, id := range identities {
+		if id.GetSigningMethod() != config.SigningSSH {
+			continue
+		}
+		signsWithSSH = true
+		path, err := expandPath(id.SigningKey())
+		if err != nil {
+			path = id.SigningKey()
+		}
+		if _, err := os.Stat(path); err != nil {
+			problems = append(problems, fmt.Errorf("SSH signing key %s for '%s' not found", id.SigningKey(), id.Name))
+		}
+	}
+	if !signsWithSSH {
+		return problems, nil
+	}
+
+	signersFile, _ := git.GetConfig("gpg.ssh.allowedSignersFile", true)
+	if signersFile == "" {
+		return problems, []string{"gpg.ssh.allowedSignersFile is not set; git can't verify SSH-signed commits"}
+	}
+	path, err := expandPath(signersFile)
+	if err != nil {
+		path = signersFile
+	}
+	if _, err := os.Stat(path); err != nil {
+		return problems, []string{fmt.Sprintf("allowed signers file %s does not exist", signersFile)}
+	}
+	return problems, nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/config"
+)
+
+func TestCheckSSHSigning_TildeKeyPath(t *testing.T) {
+	home := useTempHome(t)
+	writeTestSSHKey(t, filepath.Join(home, ".ssh", "id_work"))
+
+	identities := []config.Identity{
+		{Name: "work", Email: "me@company.com", SSHKeyPath: "~/.ssh/id_work", SigningMethod: config.SigningSSH},
+	}
+	if problems, _ := checkSSHSigning(identities); len(problems) != 0 {
+		t.Errorf("checkSSHSigning() = %v, want the ~ key path expanded and found", problems)
+	}
+
+	if err := os.Remove(filepath.Join(home, ".ssh", "id_work.pub")); err != nil {
+		t.Fatal(err)
+	}
+	if problems, _ := checkSSHSigning(identities); len(problems) != 1 {
+		t.Errorf("checkSSHSigning() = %v, want the missing public key reported", problems)
+	}
+}
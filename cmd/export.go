@@ -4,43 +4,126 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/orzazade/gitch/internal/config"
 	"github.com/orzazade/gitch/internal/portability"
-	"github.com/orzazade/gitch/internal/ssh"
 	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-var exportEncrypt bool
+var (
+	exportEncrypt    bool
+	exportFormat     string
+	exportIdentities []string
+	exportTags       []string
+	exportGPG        bool
+	exportGPGSecret  bool
+	exportStdout     bool
+	exportRecipient  string
+)
 
 var exportCmd = &cobra.Command{
-	Use:   "export <file>",
-	Short: "Export identities and rules to a YAML file",
-	Long: `Export all gitch identities and rules to a YAML file for backup or migration.
+	Use:   "export [file]",
+	Short: "Export identities and rules to a YAML or JSON file",
+	Long: `Export all gitch identities and rules to a YAML or JSON file for backup or migration.
+
+The format is inferred from the file extension (.json for JSON, YAML otherwise)
+and can be overridden with --format.
 
 The exported file includes:
 - All identity names, emails, SSH key paths, GPG key IDs
 - All auto-switch rules (directory and remote patterns)
 - Export metadata (timestamp, version)
 
+Use --stdout (or the path '-') to write the export to stdout instead, e.g.
+to pipe it into gpg or a clipboard tool. Stdout output is YAML unless
+--format json is given, has no header comment, and can't be combined with
+--encrypt.
+
+Use --identity (repeatable) to export only specific identities, together with
+the rules that reference them. This is handy for sharing a config with a teammate.
+--tag (repeatable) does the same for every identity with one of the tags, and
+can be combined with --identity.
+
 Note: By default, only SSH key paths are exported, not the keys themselves.
 Use --encrypt to include encrypted SSH private keys in the export.
 
+Keys are encrypted with a passphrase you are prompted for. For
+non-interactive backups, pass --age-recipient with an age public key
+(age1..., from age-keygen) instead; 'gitch import --age-identity' then
+decrypts with the matching private key file.
+
+GPG keys are referenced by ID only. With --encrypt, add --include-gpg to embed
+each identity's GPG public key, or --include-gpg-secret to also embed the GPG
+secret key, encrypted with the export passphrase. Exporting secret keys asks
+for confirmation. 'gitch import' offers to load embedded keys into gpg.
+
 Examples:
   gitch export backup.yaml
   gitch export ~/gitch-backup.yaml
-  gitch export --encrypt backup.yaml  # Include encrypted SSH keys`,
-	Args: cobra.ExactArgs(1),
+  gitch export backup.json            # Export as JSON
+  gitch export --format json dotfiles/gitch.conf
+  gitch export --identity work team-config.yaml
+  gitch export --tag client-a client-a.yaml
+  gitch export --stdout | gpg --encrypt -r me@example.com > gitch.yaml.gpg
+  gitch export --encrypt backup.yaml  # Include encrypted SSH keys
+  gitch export --encrypt --include-gpg-secret backup.yaml  # Full backup
+  gitch export --encrypt --age-recipient age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p backup.yaml`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: runExport,
 }
 
 func init() {
 	rootCmd.AddCommand(exportCmd)
 	exportCmd.Flags().BoolVarP(&exportEncrypt, "encrypt", "e", false, "Include encrypted SSH private keys in export")
+	exportCmd.Flags().BoolVar(&exportGPG, "include-gpg", false, "Embed GPG public keys (requires --encrypt)")
+	exportCmd.Flags().BoolVar(&exportGPGSecret, "include-gpg-secret", false, "Embed encrypted GPG secret keys (requires --encrypt)")
+	exportCmd.Flags().StringVar(&exportRecipient, "age-recipient", "", "Encrypt to this age public key instead of a passphrase (requires --encrypt)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Export format: yaml or json (default: from file extension)")
+	exportCmd.Flags().BoolVar(&exportStdout, "stdout", false, "Write the export to stdout instead of a file")
+	exportCmd.Flags().StringArrayVar(&exportIdentities, "identity", nil, "Export only this identity and its rules (repeatable)")
+	_ = exportCmd.RegisterFlagCompletionFunc("identity", identityFlagCompletionFunc)
+	exportCmd.Flags().StringArrayVar(&exportTags, "tag", nil, "Export only identities with this tag and their rules (repeatable)")
+	_ = exportCmd.RegisterFlagCompletionFunc("tag", tagCompletionFunc)
 }
 
 func runExport(cmd *cobra.Command, args []string) error {
+	toStdout := exportStdout || (len(args) == 1 && args[0] == "-")
+	if exportStdout && len(args) == 1 && args[0] != "-" {
+		return errors.New("cannot use both --stdout and an output file")
+	}
+	if !toStdout && len(args) == 0 {
+		return errors.New("an output file is required (or use --stdout)")
+	}
+	if toStdout && exportEncrypt {
+		return errors.New("--encrypt cannot write to stdout; export to a file instead")
+	}
+
+	format, err := portability.ParseFormat(exportFormat)
+	if err != nil {
+		return err
+	}
+
+	gpgKeys := portability.GPGExportNone
+	if exportGPG {
+		gpgKeys = portability.GPGExportPublic
+	}
+	if exportGPGSecret {
+		gpgKeys = portability.GPGExportSecret
+	}
+	if gpgKeys != portability.GPGExportNone && !exportEncrypt {
+		return errors.New("--include-gpg and --include-gpg-secret require --encrypt")
+	}
+	if exportRecipient != "" {
+		if !exportEncrypt {
+			return errors.New("--age-recipient requires --encrypt")
+		}
+		if err := portability.ValidateRecipient(exportRecipient); err != nil {
+			return err
+		}
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -49,34 +132,80 @@ func runExport(cmd *cobra.Command, args []string) error {
 
 	// Check if config has identities
 	if len(cfg.Identities) == 0 {
-		fmt.Println(ui.WarningStyle.Render("Warning: No identities to export"))
+		fmt.Fprintln(os.Stderr, ui.WarningStyle.Render("Warning: No identities to export"))
 		return errors.New("no identities configured")
 	}
 
+	// Restrict to selected identities and their rules
+	names := exportIdentities
+	if len(exportTags) > 0 {
+		tagged := cfg.IdentitiesWithTag(exportTags...)
+		if len(tagged) == 0 {
+			return fmt.Errorf("no identities tagged %s", strings.Join(exportTags, " or "))
+		}
+		for _, identity := range tagged {
+			names = append(names, identity.Name)
+		}
+	}
+	if len(names) > 0 {
+		export, err := portability.BuildExportConfigFiltered(cfg, names)
+		if err != nil {
+			return err
+		}
+		cfg = &config.Config{
+			Default:    export.Default,
+			Identities: export.Identities,
+			Rules:      export.Rules,
+		}
+	}
+
+	if toStdout {
+		data, err := portability.BuildExportBytes(cfg, format)
+		if err != nil {
+			return fmt.Errorf("failed to export: %w", err)
+		}
+		if _, err := os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("failed to write export: %w", err)
+		}
+		return nil
+	}
+
 	outputPath := args[0]
 
 	// Check if file already exists and warn
-	if expandedPath, err := ssh.ExpandPath(outputPath); err == nil {
+	if expandedPath, err := expandPath(outputPath); err == nil {
 		if _, statErr := os.Stat(expandedPath); statErr == nil {
 			fmt.Fprintf(os.Stderr, "Warning: Overwriting existing file: %s\n", outputPath)
 		}
 	}
 
 	if exportEncrypt {
-		// Prompt for passphrase with confirmation
-		passphrase, err := ui.ReadPassphraseWithConfirm()
-		if err != nil {
-			return fmt.Errorf("failed to read passphrase: %w", err)
+		// Secret keys leave the keyring: require explicit confirmation
+		if gpgKeys == portability.GPGExportSecret {
+			fmt.Println(ui.WarningStyle.Render("Warning: --include-gpg-secret embeds your GPG secret keys in the export file."))
+			fmt.Println(ui.DimStyle.Render("Anyone with the file and its passphrase can sign commits as you."))
+			confirmed, err := ui.ConfirmPrompt("Export GPG secret keys?", false)
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Println("Export cancelled.")
+				return nil
+			}
 		}
-		if passphrase == nil || len(passphrase) == 0 {
-			return errors.New("passphrase required for encrypted export")
+
+		cipher, err := exportCipher()
+		if err != nil {
+			return err
 		}
 
-		// Count identities with SSH keys
+		// Count the SSH keys (all of each identity's) and the GPG keys
 		keysToEncrypt := 0
+		gpgKeysToExport := 0
 		for _, id := range cfg.Identities {
-			if id.SSHKeyPath != "" {
-				keysToEncrypt++
+			keysToEncrypt += len(id.SSHKeys())
+			if id.GPGKeyID != "" {
+				gpgKeysToExport++
 			}
 		}
 
@@ -84,7 +213,7 @@ func runExport(cmd *cobra.Command, args []string) error {
 			fmt.Println(ui.WarningStyle.Render("Warning: No SSH keys to encrypt"))
 		}
 
-		if err := portability.ExportToFileEncrypted(cfg, outputPath, passphrase); err != nil {
+		if err := portability.ExportToFileEncrypted(cfg, outputPath, cipher, format, gpgKeys); err != nil {
 			return fmt.Errorf("failed to export: %w", err)
 		}
 
@@ -95,12 +224,18 @@ func runExport(cmd *cobra.Command, args []string) error {
 		if keysToEncrypt > 0 {
 			fmt.Printf("  SSH keys encrypted: %d\n", keysToEncrypt)
 		}
+		if gpgKeys == portability.GPGExportPublic && gpgKeysToExport > 0 {
+			fmt.Printf("  GPG public keys: %d\n", gpgKeysToExport)
+		}
+		if gpgKeys == portability.GPGExportSecret && gpgKeysToExport > 0 {
+			fmt.Printf("  GPG secret keys encrypted: %d\n", gpgKeysToExport)
+		}
 		if len(cfg.Rules) > 0 {
 			fmt.Printf("  Rules: %d\n", len(cfg.Rules))
 		}
 	} else {
 		// Original non-encrypted export
-		if err := portability.ExportToFile(cfg, outputPath); err != nil {
+		if err := portability.ExportToFile(cfg, outputPath, format); err != nil {
 			return fmt.Errorf("failed to export: %w", err)
 		}
 
@@ -115,3 +250,21 @@ func runExport(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// exportCipher returns the cipher for an encrypted export: the age recipient
+// if given, otherwise a passphrase prompted for with confirmation
+func exportCipher() (portability.KeyCipher, error) {
+	if exportRecipient != "" {
+		return portability.RecipientCipher{Recipient: exportRecipient}, nil
+	}
+
+	// Prompt for passphrase with confirmation
+	passphrase, err := ui.ReadPassphraseWithConfirm()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(passphrase) == 0 {
+		return nil, errors.New("passphrase required for encrypted export")
+	}
+	return portability.PassphraseCipher(passphrase), nil
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/credential"
+	"github.com/orzazade/gitch/internal/rules"
+	"github.com/spf13/cobra"
+)
+
+var gitCredentialCmd = &cobra.Command{
+	Use:   "git-credential <get|store|erase>",
+	Short: "Git credential helper for HTTPS remotes",
+	Long: `Act as a git credential helper, answering HTTPS credential requests with
+the username and token of the identity your rules select.
+
+Git passes the protocol, host and (optionally) path on stdin. gitch matches
+your rules against that remote and the current directory, and returns the
+matching identity's HTTPS user and token. When no rule matches, or the
+identity has no HTTPS credentials, nothing is returned and git falls back to
+its other helpers or prompts.
+
+Credentials are only sent over https, and only to the identity's own hosts
+(set with 'gitch add --host') or the hosting providers gitch knows:
+github.com, gitlab.com, bitbucket.org and Azure DevOps. A directory rule
+therefore never hands a token to another server.
+
+Only 'get' is answered; 'store' and 'erase' are accepted and ignored, since
+credentials are managed with 'gitch config https-user' and
+'gitch config https-token'.
+
+Setup:
+  git config --global credential.helper '!gitch git-credential'
+
+  Remote rules such as github.com/company/* need the repository path, which
+  git only sends when credential.useHttpPath is enabled:
+
+  git config --global credential.useHttpPath true
+
+Examples:
+  gitch config https-user work octocat
+  gh auth token | gitch config https-token work
+  printf 'protocol=https\nhost=github.com\npath=company/app.git\n' | gitch git-credential get`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"get", "store", "erase"},
+	RunE:      runGitCredential,
+}
+
+func init() {
+	rootCmd.AddCommand(gitCredentialCmd)
+}
+
+func runGitCredential(cmd *cobra.Command, args []string) error {
+	switch args[0] {
+	case "get":
+	case "store", "erase":
+		// Read-only helper: drain the request so git doesn't see a broken pipe
+		_, _ = io.Copy(io.Discard, os.Stdin)
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q: must be get, store, or erase", args[0])
+	}
+
+	req, err := credential.ReadRequest(os.Stdin)
+	if err != nil {
+		return err
+	}
+
+	// Only answer for HTTPS; never send a token in the clear
+	if req.Protocol != "https" {
+		return nil
+	}
+
+	identity, err := credentialIdentity(req)
+	if err != nil || identity == nil {
+		return err
+	}
+
+	// A username supplied by git (e.g. from the remote URL) wins
+	username := identity.HTTPSUser
+	if req.Username != "" {
+		username = req.Username
+	}
+
+	return credential.WriteResponse(os.Stdout, username, identity.HTTPSToken)
+}
+
+// credentialIdentity returns the identity whose rule best matches the
+// request's remote and the current directory, or nil when none matches or
+// the request's host isn't one the identity's token may be sent to
+func credentialIdentity(req *credential.Request) (*config.Identity, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Git runs helpers in the repository, so directory rules apply too
+	cwd, err := os.Getwd()
+	if err != nil {
+		cwd = ""
+	}
+
+	rule := rules.FindBestMatch(cfg.Rules, cwd, req.RemoteURL())
+	if rule == nil {
+		return nil, nil
+	}
+
+	identity, err := cfg.GetIdentity(rule.Identity)
+	if err != nil {
+		// Dangling rule; let git fall back rather than failing the fetch
+		return nil, nil
+	}
+	if !credentialHostAllowed(identity, req.Host) {
+		return nil, nil
+	}
+	return identity, nil
+}
+
+// credentialHosts are the hosting providers any identity's token may be sent
+// to, besides its own custom hosts
+var credentialHosts = []string{"github.com", "gitlab.com", "bitbucket.org", "dev.azure.com"}
+
+// credentialHostAllowed reports whether host (as git sends it, possibly with
+// a port) is one of the identity's custom hosts or a known hosting provider
+func credentialHostAllowed(identity *config.Identity, host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+	hostname = strings.ToLower(hostname)
+	if hostname == "" {
+		return false
+	}
+
+	for _, h := range identity.Hosts {
+		if strings.EqualFold(h, hostname) {
+			return true
+		}
+	}
+	// Legacy Azure DevOps URLs are <organization>.visualstudio.com
+	return slices.Contains(credentialHosts, hostname) || strings.HasSuffix(hostname, ".visualstudio.com")
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// runGitCredentialGet runs 'gitch git-credential get' with input on stdin in
+// dir, returning what it wrote to stdout
+func runGitCredentialGet(t *testing.T, dir, input string) string {
+	t.Helper()
+	t.Chdir(dir)
+
+	stdin, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.WriteString(input); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stdin.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin, os.Stdout = stdin, stdout
+	defer func() {
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		stdin.Close()
+		stdout.Close()
+	}()
+
+	if err := runGitCredential(gitCredentialCmd, []string{"get"}); err != nil {
+		t.Fatalf("runGitCredential() error = %v", err)
+	}
+
+	data, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRunGitCredential(t *testing.T) {
+	workDir := t.TempDir()
+	repoDir := filepath.Join(workDir, "app")
+	if err := os.MkdirAll(repoDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	useTestConfig(t, fmt.Sprintf(`identities:
+  - name: work
+    email: me@company.com
+    https_user: octocat
+    https_token: ghp_work
+  - name: gitea
+    email: me@company.com
+    hosts: [git.company.com]
+    https_user: me
+    https_token: gitea_token
+rules:
+  - type: directory
+    pattern: %s/**
+    identity: work
+  - type: remote
+    pattern: git.company.com/team/*
+    identity: gitea
+`, workDir))
+
+	otherDir := t.TempDir()
+
+	tests := []struct {
+		name  string
+		dir   string
+		input string
+		want  string
+	}{
+		{"provider host", repoDir, "protocol=https\nhost=github.com\n", "username=octocat\npassword=ghp_work\n"},
+		{"foreign host", repoDir, "protocol=https\nhost=evil.example.com\n", ""},
+		{"provider host over http", repoDir, "protocol=http\nhost=github.com\n", ""},
+		{"lookalike host", repoDir, "protocol=https\nhost=github.com.evil.example.com\n", ""},
+		{"custom host", otherDir, "protocol=https\nhost=git.company.com\npath=team/app.git\n", "username=me\npassword=gitea_token\n"},
+		{"custom host with port", otherDir, "protocol=https\nhost=git.company.com:8443\npath=team/app.git\n", "username=me\npassword=gitea_token\n"},
+		{"custom host of another identity", repoDir, "protocol=https\nhost=git.company.com\n", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runGitCredentialGet(t, tt.dir, tt.input); got != tt.want {
+				t.Errorf("git-credential get = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	gpgBackupOut         string
+	gpgImportName        string
+	gpgImportUpdateEmail bool
+)
+
+var gpgCmd = &cobra.Command{
+	Use:   "gpg",
+	Short: "Manage identity GPG keys",
+	Long: `Manage the GPG keys linked to gitch identities.
+
+Examples:
+  gitch gpg backup work
+  gitch gpg import ~/keys/work.asc --name work`,
+}
+
+var gpgBackupCmd = &cobra.Command{
+	Use:   "backup <identity>",
+	Short: "Write an identity's GPG key to armored backup files",
+	Long: `Export an identity's GPG key from the gpg keyring to armored files.
+
+The secret key is written to ~/.gnupg/gitch-<identity>.asc (mode 0600) and
+the public key next to it as gitch-<identity>.pub.asc. Use --out to choose
+the secret key path; the public key is written alongside it.
+
+Keep the secret key file somewhere safe. Restore it on another machine with
+'gpg --import'.
+
+Examples:
+  gitch gpg backup work
+  gitch gpg backup work --out /media/usb/work-gpg.asc`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runGPGBackup,
+}
+
+var gpgImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import an existing armored GPG secret key",
+	Long: `Import an armored GPG secret key (for example from 'gitch gpg backup' or
+'gpg --armor --export-secret-keys') into the gpg keyring.
+
+With --name, the key is linked to that identity: an existing identity gets
+the key ID, and a missing one is created with the key ID and the key's email
+address. If an existing identity has another email address, you are asked
+before it is changed to the key's; --update-email changes it without asking.
+Without --name, the key is only imported and its ID is printed.
+
+Examples:
+  gitch gpg import ~/keys/work.asc
+  gitch gpg import /media/usb/work-gpg.asc --name work
+  gitch gpg import /media/usb/work-gpg.asc --name work --update-email`,
+	Args: cobra.ExactArgs(1),
+	RunE: runGPGImport,
+}
+
+func init() {
+	rootCmd.AddCommand(gpgCmd)
+	gpgCmd.AddCommand(gpgBackupCmd)
+	gpgCmd.AddCommand(gpgImportCmd)
+
+	gpgBackupCmd.Flags().StringVarP(&gpgBackupOut, "out", "o", "", "Secret key backup path (default: ~/.gnupg/gitch-<identity>.asc)")
+
+	gpgImportCmd.Flags().StringVarP(&gpgImportName, "name", "n", "", "Identity to link the key to (created if missing)")
+	_ = gpgImportCmd.RegisterFlagCompletionFunc("name", identityFlagCompletionFunc)
+	gpgImportCmd.Flags().BoolVar(&gpgImportUpdateEmail, "update-email", false, "Change an existing identity's email to the key's without asking")
+}
+
+func runGPGBackup(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	identity, err := cfg.GetIdentity(args[0])
+	if err != nil {
+		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", args[0])
+	}
+
+	if identity.GPGKeyID == "" {
+		return fmt.Errorf("identity '%s' has no GPG key; use 'gitch add --generate-gpg' to create one", identity.Name)
+	}
+
+	if !gpgpkg.IsGPGAvailable() {
+		return errors.New("gpg command not found - install GPG to use signing features")
+	}
+
+	outPath := gpgpkg.DefaultKeyPath(identity.Name)
+	if gpgBackupOut != "" {
+		outPath, err = expandPath(gpgBackupOut)
+		if err != nil {
+			return fmt.Errorf("invalid --out path: %w", err)
+		}
+	}
+	if outPath == "" {
+		return errors.New("failed to determine backup path")
+	}
+
+	// WriteKeyBackup appends .asc and .pub.asc to the base path
+	basePath := strings.TrimSuffix(outPath, ".asc")
+	if err := gpgpkg.WriteKeyBackup(identity.GPGKeyID, basePath); err != nil {
+		return err
+	}
+
+	fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("Backed up GPG key %s for '%s':", identity.GPGKeyID, identity.Name)))
+	fmt.Printf("  Secret key: %s\n", basePath+".asc")
+	fmt.Printf("  Public key: %s\n", basePath+".pub.asc")
+	fmt.Println()
+	fmt.Println(ui.WarningStyle.Render("The secret key file is readable only by you (0600). Store it somewhere safe."))
+
+	return nil
+}
+
+func runGPGImport(cmd *cobra.Command, args []string) error {
+	if !gpgpkg.IsGPGAvailable() {
+		return errors.New("gpg command not found - install GPG to use signing features")
+	}
+
+	path, err := expandPath(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+
+	keys, err := gpgpkg.ImportKeyFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		printSuccess(fmt.Sprintf("Imported GPG key %s (%s <%s>)", key.ID, key.Name, key.Email))
+	}
+
+	name := gpgImportName
+	if name == "" {
+		printInfo("Link it to an identity with: gitch gpg import %s --name <identity>", args[0])
+		return nil
+	}
+
+	if len(keys) > 1 {
+		return fmt.Errorf("%s contains %d secret keys; import a file with a single key to link it to '%s'", args[0], len(keys), name)
+	}
+	key := keys[0]
+	if err := config.ValidateEmail(key.Email); err != nil {
+		return fmt.Errorf("GPG key %s has no usable email address: %w", key.ID, err)
+	}
+	email := config.NormalizeEmail(key.Email)
+
+	// Changing an existing identity's email needs confirmation
+	emailMismatch := func(identity *config.Identity) error {
+		return fmt.Errorf("GPG key %s is for %s but '%s' uses %s; pass --update-email to change the identity's email", key.ID, email, identity.Name, identity.Email)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	updateEmail := gpgImportUpdateEmail
+	if identity, err := cfg.GetIdentity(name); err == nil && !strings.EqualFold(identity.Email, email) {
+		message := fmt.Sprintf("GPG key %s is for %s. Change the email of '%s' from %s to match it?", key.ID, email, identity.Name, identity.Email)
+		confirmed, err := ui.ConfirmPrompt(message, updateEmail)
+		if errors.Is(err, ui.ErrNotInteractive) {
+			return emailMismatch(identity)
+		}
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			fmt.Println("Cancelled. The key stays in the gpg keyring but isn't linked.")
+			return nil
+		}
+		updateEmail = true
+	}
+
+	var created bool
+	var previousEmail, signingMethod string
+	err = updateConfig(func(cfg *config.Config) error {
+		identity, err := cfg.GetIdentity(name)
+		if err != nil {
+			created = true
+			return cfg.AddIdentity(config.Identity{
+				Name:     name,
+				Email:    email,
+				GPGKeyID: key.ID,
+			})
+		}
+
+		name = identity.Name
+		if !strings.EqualFold(identity.Email, email) {
+			if !updateEmail {
+				return emailMismatch(identity)
+			}
+			previousEmail = identity.Email
+			identity.Email = email
+		}
+		identity.GPGKeyID = key.ID
+		// An identity that didn't sign now signs with the imported key
+		if identity.SigningMethod == config.SigningNone {
+			identity.SigningMethod = ""
+		}
+		signingMethod = identity.GetSigningMethod()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if created {
+		printSuccess(fmt.Sprintf("Created identity '%s' (%s) with GPG key %s", name, email, key.ID))
+		return nil
+	}
+
+	printSuccess(fmt.Sprintf("Linked GPG key %s to '%s'", key.ID, name))
+	if previousEmail != "" {
+		printInfo("Email changed from %s to %s to match the key", previousEmail, email)
+	}
+	if signingMethod == config.SigningSSH {
+		fmt.Fprintf(os.Stderr, "Warning: '%s' signs commits with SSH, so the GPG key won't be used for signing\n", name)
+	}
+	return nil
+}
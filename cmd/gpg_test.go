@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/config"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/gpg/gpgtest"
+)
+
+// armoredTestKey generates a GPG key for email in a temp keyring and
+// returns its ID and the path of its armored secret key
+func armoredTestKey(t *testing.T, email string) (string, string) {
+	t.Helper()
+	gpgtest.UseTempKeyring(t)
+
+	key, err := gpgpkg.GenerateKey("Work", email, nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	basePath := filepath.Join(t.TempDir(), "work")
+	if err := gpgpkg.WriteKeyBackup(key.ID, basePath); err != nil {
+		t.Fatalf("WriteKeyBackup() error = %v", err)
+	}
+	return key.ID, basePath + ".asc"
+}
+
+// runGPGImportWith runs 'gitch gpg import' with stdin not a terminal
+func runGPGImportWith(t *testing.T, path, name string, updateEmail bool) error {
+	t.Helper()
+	gpgImportName, gpgImportUpdateEmail = name, updateEmail
+	t.Cleanup(func() { gpgImportName, gpgImportUpdateEmail = "", false })
+
+	stdin, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	oldStdin := os.Stdin
+	os.Stdin = stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	var runErr error
+	captureStdout(t, func() {
+		runErr = runGPGImport(gpgImportCmd, []string{path})
+	})
+	return runErr
+}
+
+// loadTestIdentity returns the named identity from the test config
+func loadTestIdentity(t *testing.T, name string) *config.Identity {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+	identity, err := cfg.GetIdentity(name)
+	if err != nil {
+		t.Fatalf("GetIdentity(%q) error = %v", name, err)
+	}
+	return identity
+}
+
+func TestRunGPGImport_CreatesIdentity(t *testing.T) {
+	keyID, path := armoredTestKey(t, "me@company.com")
+	useTestConfig(t, "identities: []\n")
+
+	if err := runGPGImportWith(t, path, "work", false); err != nil {
+		t.Fatalf("runGPGImport() error = %v", err)
+	}
+	identity := loadTestIdentity(t, "work")
+	if identity.Email != "me@company.com" || identity.GPGKeyID != keyID {
+		t.Errorf("identity = %+v, want the key's email and ID", identity)
+	}
+}
+
+func TestRunGPGImport_KeepsEmailWithoutConfirmation(t *testing.T) {
+	keyID, path := armoredTestKey(t, "me@company.com")
+	useTestConfig(t, "identities:\n  - name: work\n    email: me@example.com\n")
+
+	err := runGPGImportWith(t, path, "WORK", false)
+	if err == nil || !strings.Contains(err.Error(), "--update-email") {
+		t.Fatalf("runGPGImport() error = %v, want a hint to pass --update-email", err)
+	}
+	if identity := loadTestIdentity(t, "work"); identity.Email != "me@example.com" || identity.GPGKeyID != "" {
+		t.Errorf("identity = %+v, want it unchanged", identity)
+	}
+
+	if err := runGPGImportWith(t, path, "WORK", true); err != nil {
+		t.Fatalf("runGPGImport() with --update-email error = %v", err)
+	}
+	if identity := loadTestIdentity(t, "work"); identity.Email != "me@company.com" || identity.GPGKeyID != keyID {
+		t.Errorf("identity = %+v, want the key's email and ID", identity)
+	}
+	if gpgImportName != "WORK" {
+		t.Errorf("--name changed to %q, want it left as given", gpgImportName)
+	}
+}
+
+func TestRunGPGImport_SameEmail(t *testing.T) {
+	keyID, path := armoredTestKey(t, "me@company.com")
+	useTestConfig(t, "identities:\n  - name: work\n    email: Me@Company.com\n")
+
+	if err := runGPGImportWith(t, path, "work", false); err != nil {
+		t.Fatalf("runGPGImport() error = %v", err)
+	}
+	if identity := loadTestIdentity(t, "work"); identity.GPGKeyID != keyID {
+		t.Errorf("identity = %+v, want GPG key %s", identity, keyID)
+	}
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/history"
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var historyLimit int
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent identity switches",
+	Long: `Show recent identity switches made with 'gitch use', 'gitch undo' and the
+pre-commit hook's [S]witch option, newest first.
+
+The log keeps the last 100 switches and is stored in the gitch cache
+directory alongside the prompt cache.
+
+Examples:
+  gitch history
+  gitch history -n 5`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last identity switch",
+	Long: `Switch back to the identity that was active before the last switch
+recorded in 'gitch history', in the same scope (global, or the repository
+for 'gitch use --local'). As with 'gitch use', the SSH keys of the identity
+being left are removed from ssh-agent and those of the restored one added.
+
+Undo is itself recorded as a switch, so running it twice switches back again.
+
+Example:
+  gitch undo`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	rootCmd.AddCommand(undoCmd)
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Number of switches to show (0 for all)")
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if historyLimit < 0 {
+		return fmt.Errorf("invalid --limit %d: must not be negative", historyLimit)
+	}
+
+	entries, err := history.Load()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Println("No identity switches recorded yet.")
+		return nil
+	}
+
+	if historyLimit > 0 && len(entries) > historyLimit {
+		entries = entries[len(entries)-historyLimit:]
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		from := entry.From
+		if from == "" {
+			from = "(unknown)"
+		}
+		line := fmt.Sprintf("%s  %-6s  %s -> %s",
+			entry.Time.Local().Format("2006-01-02 15:04"), entry.Scope, from, entry.To)
+		if entry.Scope == history.ScopeLocal && entry.Repo != "" {
+			line += ui.DimStyle.Render("  " + entry.Repo)
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	last, err := history.Last()
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		return errors.New("no identity switches recorded; nothing to undo")
+	}
+	if last.From == "" {
+		return fmt.Errorf("the identity before '%s' is unknown; use 'gitch use <name>' instead", last.To)
+	}
+
+	global := last.Scope != history.ScopeLocal
+	if !global && git.RepoRoot() != last.Repo {
+		return fmt.Errorf("the last switch was in %s; run 'gitch undo' from that repository", last.Repo)
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	previous, err := cfg.GetIdentity(last.From)
+	if err != nil {
+		return fmt.Errorf("identity '%s' no longer exists", last.From)
+	}
+	current, _ := cfg.GetIdentity(last.To)
+
+	if err := applyIdentity(previous, global); err != nil {
+		return fmt.Errorf("failed to switch identity: %w", err)
+	}
+	recordSwitch(current, previous, global)
+
+	// Swap the undone identity's SSH keys in ssh-agent, like 'gitch use'
+	if current != nil {
+		removePreviousSSHKey(cfg, current.Name, current.Email, previous)
+	}
+	addAgentKeys(previous)
+
+	if global {
+		// Update prompt cache (best effort - don't fail the switch)
+		if err := prompt.UpdateCache(previous.Name); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update prompt cache: %v\n", err)
+		}
+	}
+
+	printSuccess(fmt.Sprintf("Switched back to '%s' (%s)", previous.Name, previous.Email))
+	return nil
+}
+
+// recordSwitch adds a switch from one identity (nil if unknown) to another
+// to the history log. Failures are printed as warnings.
+func recordSwitch(from, to *config.Identity, global bool) {
+	entry := history.Entry{
+		Time:  time.Now().UTC(),
+		To:    to.Name,
+		Scope: history.ScopeGlobal,
+	}
+	if from != nil {
+		entry.From = from.Name
+	}
+	if !global {
+		entry.Scope = history.ScopeLocal
+	}
+	entry.Repo = git.RepoRoot()
+
+	if err := history.Record(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to record switch in history: %v\n", err)
+	}
+}
@@ -1,17 +1,48 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/orzazade/gitch/internal/config"
 	"github.com/orzazade/gitch/internal/git"
 	"github.com/orzazade/gitch/internal/hooks"
-	sshpkg "github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/prompt"
 	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-var hookGlobal bool
+var (
+	hookGlobal     bool
+	hookLocal      bool
+	hookSignCheck  bool
+	hookStatusJSON bool
+)
+
+// hookStatusOutput represents the JSON output structure for gitch hook status --json
+type hookStatusOutput struct {
+	GitchHooksDir   string `json:"gitch_hooks_dir"`
+	HooksPath       string `json:"hooks_path,omitempty"`
+	Global          bool   `json:"global"`
+	GlobalSignCheck bool   `json:"global_sign_check"`
+	InRepo          bool   `json:"in_repo"`
+	Local           bool   `json:"local"`
+	LocalSignCheck  bool   `json:"local_sign_check"`
+	GlobalOutdated  bool   `json:"global_outdated"`
+	LocalOutdated   bool   `json:"local_outdated"`
+	// Active is the gitch hook git runs here: global, local or none
+	Active           string `json:"active"`
+	Mode             string `json:"mode"`
+	RuleMatched      bool   `json:"rule_matched"`
+	ExpectedIdentity string `json:"expected_identity,omitempty"`
+	Bypass           bool   `json:"bypass"`
+	// RepoBypass is the .gitch-ignore path or gitch.bypass when the
+	// repository opts out of the hooks
+	RepoBypass string `json:"repo_bypass,omitempty"`
+}
 
 var hookCmd = &cobra.Command{
 	Use:   "hook",
@@ -21,8 +52,15 @@ var hookCmd = &cobra.Command{
 The hook will detect identity mismatches and prompt you to switch, continue, or abort.
 Use GITCH_BYPASS=1 environment variable to skip the hook.
 
+To skip the hooks in one repository for good (e.g. a throwaway sandbox),
+create a .gitch-ignore file at its root or run 'git config gitch.bypass true'
+in it. GITCH_BYPASS=1 takes precedence over both, which take precedence over
+normal validation.
+
 Examples:
   gitch hook install --global
+  gitch hook install --local
+  gitch hook status
   gitch hook uninstall --global`,
 }
 
@@ -31,14 +69,28 @@ var hookInstallCmd = &cobra.Command{
 	Short: "Install the gitch pre-commit hook",
 	Long: `Install the gitch pre-commit hook to validate identity before commits.
 
-Currently only global installation via core.hooksPath is supported.
-This sets up a pre-commit hook that runs 'gitch hook validate' before each commit.
+Use --global to install for all repositories via core.hooksPath, or --local
+to install into the current repository's .git/hooks. A local install keeps
+any existing pre-commit hook (backed up as pre-commit.gitch-backup) and runs
+it before gitch's check.
+
+The hook runs 'gitch hook validate' before each commit.
 
 If the current identity doesn't match the expected identity for the repository,
-the hook will prompt you to [S]witch, [C]ontinue, or [A]bort.
+the hook will prompt you to [S]witch, [C]ontinue, or [A]bort. Repositories no
+rule matches pass unless 'gitch config require-rule' is set to warn or block.
+
+Running install again updates hooks written by an older gitch version;
+'gitch hook status' reports them as outdated.
+
+With --sign-check, a commit-msg hook is also installed. When the expected
+identity signs commits (with GPG or SSH), it blocks commits unless
+commit.gpgsign is enabled and user.signingkey and gpg.format match it.
 
 Examples:
-  gitch hook install --global`,
+  gitch hook install --global
+  gitch hook install --local
+  gitch hook install --global --sign-check`,
 	RunE: runHookInstall,
 }
 
@@ -47,13 +99,33 @@ var hookUninstallCmd = &cobra.Command{
 	Short: "Uninstall the gitch pre-commit hook",
 	Long: `Remove the gitch pre-commit hook.
 
-This removes the core.hooksPath configuration and deletes the hooks directory.
+With --global, this removes the core.hooksPath configuration and deletes the
+hooks directory. With --local, this removes the current repository's hook
+and restores any pre-commit hook that was backed up on install.
 
 Examples:
-  gitch hook uninstall --global`,
+  gitch hook uninstall --global
+  gitch hook uninstall --local`,
 	RunE: runHookUninstall,
 }
 
+var hookStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where gitch hooks are installed and whether they run here",
+	Long: `Show the state of the gitch hooks, to debug a hook that isn't running.
+
+Reports whether the global hooks (core.hooksPath) and the current
+repository's hooks are installed, which of them git runs here, the hook mode
+for this repository, and whether GITCH_BYPASS is set in the environment or
+the repository opts out with .gitch-ignore or gitch.bypass.
+
+Examples:
+  gitch hook status
+  gitch hook status --json`,
+	Args: cobra.NoArgs,
+	RunE: runHookStatus,
+}
+
 // hookValidateCmd is called by the pre-commit script
 var hookValidateCmd = &cobra.Command{
 	Use:    "validate",
@@ -62,6 +134,14 @@ var hookValidateCmd = &cobra.Command{
 	RunE:   runHookValidate,
 }
 
+// hookValidateSigningCmd is called by the commit-msg script
+var hookValidateSigningCmd = &cobra.Command{
+	Use:    "validate-signing",
+	Short:  "Validate commit signing config (used by commit-msg hook)",
+	Hidden: true,
+	RunE:   runHookValidateSigning,
+}
+
 // hookSwitchCmd is called by the pre-commit script
 var hookSwitchCmd = &cobra.Command{
 	Use:    "switch",
@@ -82,61 +162,155 @@ func init() {
 	rootCmd.AddCommand(hookCmd)
 	hookCmd.AddCommand(hookInstallCmd)
 	hookCmd.AddCommand(hookUninstallCmd)
+	hookCmd.AddCommand(hookStatusCmd)
 	hookCmd.AddCommand(hookValidateCmd)
+	hookCmd.AddCommand(hookValidateSigningCmd)
 	hookCmd.AddCommand(hookSwitchCmd)
 	hookCmd.AddCommand(hookModeCmd)
 
 	// Flags
-	hookInstallCmd.Flags().BoolVar(&hookGlobal, "global", false, "Install hooks globally (required)")
-	_ = hookInstallCmd.MarkFlagRequired("global")
+	hookInstallCmd.Flags().BoolVar(&hookGlobal, "global", false, "Install hooks globally via core.hooksPath")
+	hookInstallCmd.Flags().BoolVar(&hookLocal, "local", false, "Install hook in the current repository only")
+	hookInstallCmd.Flags().BoolVar(&hookSignCheck, "sign-check", false, "Also install a commit-msg hook that enforces commit signing")
 
-	hookUninstallCmd.Flags().BoolVar(&hookGlobal, "global", false, "Uninstall global hooks (required)")
-	_ = hookUninstallCmd.MarkFlagRequired("global")
+	hookUninstallCmd.Flags().BoolVar(&hookGlobal, "global", false, "Uninstall global hooks")
+	hookUninstallCmd.Flags().BoolVar(&hookLocal, "local", false, "Uninstall the current repository's hook")
+
+	hookStatusCmd.Flags().BoolVar(&hookStatusJSON, "json", false, "Output in JSON format")
+}
+
+// validateHookScope ensures exactly one of --global or --local is set
+func validateHookScope() error {
+	if hookGlobal == hookLocal {
+		return errors.New("specify exactly one of --global or --local")
+	}
+	if hookLocal && git.RepoRoot() == "" {
+		return errors.New("not in a git repository")
+	}
+	return nil
 }
 
 func runHookInstall(cmd *cobra.Command, args []string) error {
-	if !hookGlobal {
-		return fmt.Errorf("only --global installation is currently supported")
+	if err := validateHookScope(); err != nil {
+		return err
 	}
 
 	// Check if already installed
-	installed, err := hooks.IsInstalled()
+	status, err := hooks.IsInstalled()
 	if err != nil {
 		return fmt.Errorf("failed to check hook status: %w", err)
 	}
 
-	if installed {
-		fmt.Println("Gitch hooks are already installed.")
+	if hookLocal {
+		if status.Local && !status.LocalOutdated && (!hookSignCheck || status.LocalSignCheck) {
+			fmt.Println("Gitch hook is already installed in this repository.")
+			return nil
+		}
+
+		hooksDir, _ := hooks.LocalHooksDir()
+
+		if !status.Local {
+			if err := hooks.InstallLocal(); err != nil {
+				return fmt.Errorf("failed to install hook: %w", err)
+			}
+			fmt.Println(ui.SuccessStyle.Render("Local hook installed at " + hooksDir))
+			if _, err := os.Stat(filepath.Join(hooksDir, hooks.LocalBackupName)); err == nil {
+				fmt.Println(ui.DimStyle.Render("Existing pre-commit hook preserved as " + hooks.LocalBackupName + " and will run first."))
+			}
+		} else if status.LocalOutdated {
+			if err := hooks.InstallLocal(); err != nil {
+				return fmt.Errorf("failed to update hook: %w", err)
+			}
+			if status.LocalSignCheck {
+				if err := hooks.InstallSignCheckLocal(); err != nil {
+					return fmt.Errorf("failed to update signing check: %w", err)
+				}
+			}
+			fmt.Println(ui.SuccessStyle.Render("Local hooks updated in " + hooksDir))
+		}
+
+		if hookSignCheck && !status.LocalSignCheck {
+			if err := hooks.InstallSignCheckLocal(); err != nil {
+				return fmt.Errorf("failed to install signing check: %w", err)
+			}
+			fmt.Println(ui.SuccessStyle.Render("Signing check installed"))
+			if _, err := os.Stat(filepath.Join(hooksDir, hooks.LocalCommitMsgBackupName)); err == nil {
+				fmt.Println(ui.DimStyle.Render("Existing commit-msg hook preserved as " + hooks.LocalCommitMsgBackupName + " and will run first."))
+			}
+		}
+		if status.Global {
+			fmt.Println(ui.WarningStyle.Render("Note: global hooks are installed; git uses core.hooksPath and ignores this local hook."))
+		}
+		fmt.Println(ui.DimStyle.Render("Use GITCH_BYPASS=1 to skip validation."))
 		return nil
 	}
 
-	// Install hooks
-	if err := hooks.InstallGlobal(); err != nil {
-		return fmt.Errorf("failed to install hooks: %w", err)
+	if status.Global && !status.GlobalOutdated && (!hookSignCheck || status.GlobalSignCheck) {
+		fmt.Println("Gitch hooks are already installed.")
+		return nil
 	}
 
 	// Get hooks dir for display
 	hooksDir, _ := hooks.HooksDir()
 
-	fmt.Println(ui.SuccessStyle.Render("Global hooks installed at " + hooksDir))
-	fmt.Println(ui.DimStyle.Render("Git will now validate identity before each commit."))
+	// Install hooks
+	if !status.Global {
+		if err := hooks.InstallGlobal(); err != nil {
+			return fmt.Errorf("failed to install hooks: %w", err)
+		}
+		fmt.Println(ui.SuccessStyle.Render("Global hooks installed at " + hooksDir))
+		fmt.Println(ui.DimStyle.Render("Git will now validate identity before each commit."))
+	} else if status.GlobalOutdated {
+		if err := hooks.InstallGlobal(); err != nil {
+			return fmt.Errorf("failed to update hooks: %w", err)
+		}
+		if status.GlobalSignCheck {
+			if err := hooks.InstallSignCheckGlobal(); err != nil {
+				return fmt.Errorf("failed to update signing check: %w", err)
+			}
+		}
+		fmt.Println(ui.SuccessStyle.Render("Global hooks updated in " + hooksDir))
+	}
+
+	if hookSignCheck && !status.GlobalSignCheck {
+		if err := hooks.InstallSignCheckGlobal(); err != nil {
+			return fmt.Errorf("failed to install signing check: %w", err)
+		}
+		fmt.Println(ui.SuccessStyle.Render("Signing check installed"))
+		fmt.Println(ui.DimStyle.Render("Commits will be blocked unless they are signed with the identity's GPG key."))
+	}
+
 	fmt.Println(ui.DimStyle.Render("Use GITCH_BYPASS=1 to skip validation."))
 
 	return nil
 }
 
 func runHookUninstall(cmd *cobra.Command, args []string) error {
-	if !hookGlobal {
-		return fmt.Errorf("only --global uninstallation is currently supported")
+	if err := validateHookScope(); err != nil {
+		return err
 	}
 
 	// Check if installed
-	installed, err := hooks.IsInstalled()
+	status, err := hooks.IsInstalled()
 	if err != nil {
 		return fmt.Errorf("failed to check hook status: %w", err)
 	}
 
-	if !installed {
+	if hookLocal {
+		if !status.Local {
+			fmt.Println("Gitch hook is not installed in this repository.")
+			return nil
+		}
+
+		if err := hooks.UninstallLocal(); err != nil {
+			return fmt.Errorf("failed to uninstall hook: %w", err)
+		}
+
+		fmt.Println(ui.SuccessStyle.Render("Local hook removed"))
+		return nil
+	}
+
+	if !status.Global {
 		fmt.Println("Gitch hooks are not installed.")
 		return nil
 	}
@@ -151,12 +325,179 @@ func runHookUninstall(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runHookStatus(cmd *cobra.Command, args []string) error {
+	status, err := hooks.IsInstalled()
+	if err != nil {
+		return fmt.Errorf("failed to check hook status: %w", err)
+	}
+
+	output := hookStatusOutput{
+		Global:          status.Global,
+		GlobalSignCheck: status.GlobalSignCheck,
+		InRepo:          git.RepoRoot() != "",
+		Local:           status.Local,
+		LocalSignCheck:  status.LocalSignCheck,
+		GlobalOutdated:  status.GlobalOutdated,
+		LocalOutdated:   status.LocalOutdated,
+		Bypass:          os.Getenv(hooks.BypassEnvVar) == "1",
+		RepoBypass:      hooks.RepoBypass(),
+	}
+	output.GitchHooksDir, _ = hooks.HooksDir()
+
+	// The effective core.hooksPath: a repository setting overrides the global one
+	globalHooksPath, _ := git.GetConfig("core.hooksPath", true)
+	output.HooksPath = globalHooksPath
+	if output.InRepo {
+		output.HooksPath, _ = git.GetConfig("core.hooksPath", false)
+	}
+
+	switch {
+	case output.HooksPath == "":
+		output.Active = "none"
+		if status.Local {
+			output.Active = "local"
+		}
+	case filepath.Clean(output.HooksPath) == filepath.Clean(output.GitchHooksDir):
+		output.Active = "global"
+	default:
+		output.Active = "none"
+	}
+
+	result, validateErr := hooks.Validate()
+	if validateErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", validateErr)
+	}
+	output.Mode, output.ExpectedIdentity = hookStatusMode(result, validateErr)
+	output.RuleMatched = validateErr == nil && result.MatchedRule != nil
+
+	if hookStatusJSON {
+		jsonBytes, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	hooksPath := output.HooksPath
+	if hooksPath == "" {
+		hooksPath = ui.DimStyle.Render("not set")
+	}
+
+	printHookStatusLine("Global hook", installedLabel(status.Global, status.GlobalSignCheck, status.GlobalOutdated))
+	printHookStatusLine("core.hooksPath", hooksPath)
+	if output.InRepo {
+		printHookStatusLine("Local hook", installedLabel(status.Local, status.LocalSignCheck, status.LocalOutdated))
+	} else {
+		printHookStatusLine("Local hook", ui.DimStyle.Render("not in a git repository"))
+	}
+
+	switch output.Active {
+	case "global":
+		printHookStatusLine("Runs here", "global hook")
+	case "local":
+		printHookStatusLine("Runs here", "local hook")
+	default:
+		printHookStatusLine("Runs here", ui.WarningStyle.Render("no gitch hook"))
+		switch {
+		case output.HooksPath != "" && (status.Global || status.Local):
+			fmt.Println(ui.DimStyle.Render("  core.hooksPath points to another directory, so git doesn't run the gitch hook."))
+		case !status.Global && !status.Local:
+			fmt.Println(ui.DimStyle.Render("  Install one with 'gitch hook install --global' or 'gitch hook install --local'."))
+		}
+	}
+
+	mode := output.Mode
+	if output.ExpectedIdentity != "" {
+		mode += ui.DimStyle.Render(fmt.Sprintf(" (expects '%s')", output.ExpectedIdentity))
+	} else if validateErr == nil {
+		mode += ui.DimStyle.Render(" (no rule matches)")
+	}
+	printHookStatusLine("Mode", mode)
+
+	if output.Bypass {
+		printHookStatusLine(hooks.BypassEnvVar, ui.WarningStyle.Render("set - hooks are skipped"))
+	} else {
+		printHookStatusLine(hooks.BypassEnvVar, "not set")
+	}
+	if output.RepoBypass != "" {
+		printHookStatusLine("Repo bypass", ui.WarningStyle.Render(output.RepoBypass+" - hooks are skipped in this repository"))
+	}
+	if status.GlobalOutdated || status.LocalOutdated {
+		fmt.Println(ui.DimStyle.Render("  Outdated hooks don't know newer checks such as require_rule; run 'gitch hook install' again to update them."))
+	}
+
+	return nil
+}
+
+// hookStatusMode returns what the pre-commit hook does on a mismatch, given
+// the validation result for this repository, and the identity it expects
+// (empty when no rule matches)
+func hookStatusMode(result *hooks.ValidationResult, validateErr error) (mode, expected string) {
+	mode = effectiveHookMode(result, validateErr)
+	if validateErr == nil && result.ExpectedIdentity != nil {
+		expected = result.ExpectedIdentity.Name
+	}
+	return mode, expected
+}
+
+// effectiveHookMode returns the mode the installed hook runs in, as printed
+// by 'gitch hook mode'. Validation errors, such as a rule naming a missing
+// identity, and an invalid identity hook mode fall back to warn.
+func effectiveHookMode(result *hooks.ValidationResult, validateErr error) string {
+	switch {
+	case validateErr != nil:
+		return config.HookModeWarn
+	case result.NoRule && !result.Match:
+		// No rule matched and require_rule is block
+		return config.HookModeBlock
+	case result.ExpectedIdentity == nil:
+		return config.HookModeWarn
+	}
+	mode := result.ExpectedIdentity.GetHookMode()
+	if config.ValidateHookMode(mode) != nil {
+		return config.HookModeWarn
+	}
+	return mode
+}
+
+// installedLabel describes whether a hook and its signing check are
+// installed, and whether they are outdated
+func installedLabel(installed, signCheck, outdated bool) string {
+	if !installed {
+		return ui.DimStyle.Render("not installed")
+	}
+	label := ui.SuccessStyle.Render("installed")
+	if signCheck {
+		label += " (with signing check)"
+	}
+	if outdated {
+		label += " " + ui.WarningStyle.Render("outdated")
+	}
+	return label
+}
+
+// printHookStatusLine prints a labeled line of 'gitch hook status'
+func printHookStatusLine(label, value string) {
+	fmt.Printf("%-16s %s\n", label+":", value)
+}
+
 func runHookValidate(cmd *cobra.Command, args []string) error {
 	result, err := hooks.Validate()
 	if err != nil {
 		return err
 	}
 
+	if result.NoRule {
+		// No rule matches and require_rule is set - there is no identity to
+		// switch to, so the hook shows the message and, for block, aborts
+		fmt.Println(result.FormatMismatch())
+		if !result.Match {
+			os.Exit(2)
+		}
+		return nil
+	}
+
 	if result.Match {
 		// Identity matches or no rule applies - exit silently
 		return nil
@@ -168,6 +509,22 @@ func runHookValidate(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runHookValidateSigning(cmd *cobra.Command, args []string) error {
+	result, err := hooks.ValidateSigning()
+	if err != nil {
+		return err
+	}
+
+	if result.OK {
+		return nil
+	}
+
+	// Signing misconfigured - print message and exit with error
+	fmt.Println(result.FormatProblem())
+	os.Exit(1)
+	return nil
+}
+
 func runHookSwitch(cmd *cobra.Command, args []string) error {
 	// Get the expected identity from validation
 	result, err := hooks.Validate()
@@ -182,16 +539,20 @@ func runHookSwitch(cmd *cobra.Command, args []string) error {
 	identity := result.ExpectedIdentity
 
 	// Apply identity to git config
-	if err := git.ApplyIdentity(identity.Name, identity.Email); err != nil {
+	if err := applyIdentity(identity, true); err != nil {
 		return fmt.Errorf("failed to switch identity: %w", err)
 	}
 
-	// Add SSH key to agent if configured
-	if identity.SSHKeyPath != "" {
-		if err := addSSHKeyForHook(identity.SSHKeyPath); err != nil {
-			// Print warning but don't fail the switch
-			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
-		}
+	if cfg, err := config.Load(); err == nil {
+		recordSwitch(findGitIdentity(cfg, result.CurrentName, result.CurrentEmail), identity, true)
+	}
+
+	// Add SSH keys to agent if configured
+	addAgentKeys(identity)
+
+	// Update prompt cache (best effort - don't fail the switch)
+	if err := prompt.UpdateCache(identity.Name); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to update prompt cache: %v\n", err)
 	}
 
 	// Print success
@@ -204,36 +565,11 @@ func runHookSwitch(cmd *cobra.Command, args []string) error {
 func runHookMode(cmd *cobra.Command, args []string) error {
 	// Get validation result to find expected identity
 	result, err := hooks.Validate()
-	if err != nil {
-		// Default to warn on error
-		fmt.Print("warn")
-		return nil
-	}
-
-	// If no expected identity (no rule matched), default to warn
-	if result.ExpectedIdentity == nil {
-		fmt.Print("warn")
-		return nil
-	}
-
-	// Get the hook mode for this identity
-	mode := result.ExpectedIdentity.GetHookMode()
-	fmt.Print(mode)
-	return nil
-}
-
-// addSSHKeyForHook adds an SSH key to the ssh-agent
-// Duplicated from use.go to avoid circular dependencies in cmd package
-func addSSHKeyForHook(keyPath string) error {
-	// Check if key file exists
-	if _, err := os.Stat(keyPath); os.IsNotExist(err) {
-		return fmt.Errorf("SSH key not found: %s", keyPath)
-	}
-
-	// Add to agent (will prompt for passphrase if needed)
-	if err := sshpkg.AddKeyToAgent(keyPath); err != nil {
-		return fmt.Errorf("failed to add SSH key to agent: %w", err)
+	if err == nil && result.ExpectedIdentity != nil {
+		if err := config.ValidateHookMode(result.ExpectedIdentity.GetHookMode()); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v; using 'warn'\n", err)
+		}
 	}
-
+	fmt.Print(effectiveHookMode(result, err))
 	return nil
 }
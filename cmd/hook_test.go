@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// hookModeTests are configs and the mode the installed hook runs in for a
+// directory {dir} the rules can match
+var hookModeTests = []struct {
+	name   string
+	config string
+	want   string
+}{
+	{"no rule", "identities: []\n", "warn"},
+	{"no rule, require_rule warn", "require_rule: warn\n", "warn"},
+	{"no rule, require_rule block", "require_rule: block\n", "block"},
+	{"identity hook mode", `identities:
+  - name: work
+    email: me@company.com
+    hook_mode: block
+rules:
+  - type: directory
+    pattern: {dir}/**
+    identity: work
+`, "block"},
+	{"invalid identity hook mode", `identities:
+  - name: work
+    email: me@company.com
+    hook_mode: sometimes
+rules:
+  - type: directory
+    pattern: {dir}/**
+    identity: work
+`, "warn"},
+	{"rule for a missing identity", `identities: []
+rules:
+  - type: directory
+    pattern: {dir}/**
+    identity: gone
+`, "warn"},
+}
+
+func TestRunHookMode(t *testing.T) {
+	for _, tt := range hookModeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempHome(t)
+			dir := t.TempDir()
+			t.Chdir(dir)
+			useTestConfig(t, strings.ReplaceAll(tt.config, "{dir}", dir))
+
+			got := captureStdout(t, func() {
+				if err := runHookMode(hookModeCmd, nil); err != nil {
+					t.Fatalf("runHookMode() error = %v", err)
+				}
+			})
+			if got != tt.want {
+				t.Errorf("hook mode = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunHookStatus_ModeMatchesHook(t *testing.T) {
+	for _, tt := range hookModeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempHome(t)
+			dir := t.TempDir()
+			t.Chdir(dir)
+			useTestConfig(t, strings.ReplaceAll(tt.config, "{dir}", dir))
+			hookStatusJSON = true
+			t.Cleanup(func() { hookStatusJSON = false })
+
+			out := captureStdout(t, func() {
+				if err := runHookStatus(hookStatusCmd, nil); err != nil {
+					t.Fatalf("runHookStatus() error = %v", err)
+				}
+			})
+			var status hookStatusOutput
+			if err := json.Unmarshal([]byte(out), &status); err != nil {
+				t.Fatalf("invalid JSON %q: %v", out, err)
+			}
+			if status.Mode != tt.want {
+				t.Errorf("hook status mode = %q, want %q as 'gitch hook mode' reports", status.Mode, tt.want)
+			}
+		})
+	}
+}
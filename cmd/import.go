@@ -2,38 +2,66 @@ package cmd
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/orzazade/gitch/internal/config"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
 	"github.com/orzazade/gitch/internal/portability"
-	"github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/rules"
 	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-var importForce bool
+var (
+	importForce         bool
+	importMergeStrategy string
+	importAgeIdentity   string
+	importNoBackup      bool
+)
 
 var importCmd = &cobra.Command{
 	Use:   "import <file>",
-	Short: "Import identities and rules from a YAML file",
-	Long: `Import gitch identities and rules from a YAML file.
+	Short: "Import identities and rules from a YAML or JSON file",
+	Long: `Import gitch identities and rules from a YAML or JSON file.
+
+JSON files are detected automatically from the .json extension or content.
 
 When importing, if an identity or rule already exists:
 - You will be prompted to overwrite, skip, or abort
 - Use --force to overwrite all conflicts without prompting
+- Use --merge-strategy to resolve all conflicts without prompting:
+    skip       keep existing identities, rules and SSH key files
+    overwrite  replace them (same as --force)
+    newer      overwrite if the import file was exported after your
+               config file was last modified, otherwise skip
 
 If the import file contains encrypted SSH keys:
-- You will be prompted for the decryption passphrase
+- You will be prompted for the decryption passphrase, which is checked
+  before any key is written (3 attempts), or, for exports made with
+  --age-recipient, pass the age private key file with --age-identity
 - Keys are written to their original paths with secure permissions (0600)
 - Existing key files prompt for overwrite confirmation
 
+If the import file contains GPG keys (gitch export --include-gpg or
+--include-gpg-secret), you will be asked whether to import them into your gpg
+keyring. --force imports them without asking.
+
+Before overwriting any existing identity or rule, the current config file is
+copied to a timestamped backup next to it (see 'gitch config restore').
+Use --no-backup to skip this.
+
 Note: SSH key files must exist at the referenced paths for SSH features to work.
 
 Examples:
   gitch import backup.yaml
-  gitch import ~/gitch-backup.yaml --force`,
+  gitch import backup.json
+  gitch import ~/gitch-backup.yaml --force
+  gitch import team.yaml --merge-strategy skip
+  gitch import backup.yaml --merge-strategy newer
+  gitch import backup.yaml --age-identity ~/.config/age/key.txt`,
 	Args: cobra.ExactArgs(1),
 	RunE: runImport,
 }
@@ -41,9 +69,25 @@ Examples:
 func init() {
 	rootCmd.AddCommand(importCmd)
 	importCmd.Flags().BoolVarP(&importForce, "force", "f", false, "Overwrite all conflicts without prompting")
+	importCmd.Flags().StringVar(&importMergeStrategy, "merge-strategy", "", "Resolve conflicts without prompting: skip, overwrite or newer")
+	importCmd.Flags().StringVar(&importAgeIdentity, "age-identity", "", "age private key file to decrypt keys exported with --age-recipient")
+	importCmd.Flags().BoolVar(&importNoBackup, "no-backup", false, "Don't back up the config before overwriting identities or rules")
+	_ = importCmd.RegisterFlagCompletionFunc("merge-strategy", cobra.FixedCompletions(
+		[]string{"skip", "overwrite", "newer"}, cobra.ShellCompDirectiveNoFileComp))
 }
 
 func runImport(cmd *cobra.Command, args []string) error {
+	strategy, err := portability.ParseMergeStrategy(importMergeStrategy)
+	if err != nil {
+		return err
+	}
+	if importForce {
+		if strategy != "" && strategy != portability.MergeOverwrite {
+			return fmt.Errorf("--force cannot be combined with --merge-strategy %s", strategy)
+		}
+		strategy = portability.MergeOverwrite
+	}
+
 	// Load current config
 	cfg, err := config.Load()
 	if err != nil {
@@ -63,28 +107,44 @@ func runImport(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("invalid identity %q in import file: %w", id.Name, err)
 		}
 
-		// Warn if SSH key path doesn't exist (but continue import)
-		if id.SSHKeyPath != "" {
-			expanded, err := ssh.ExpandPath(id.SSHKeyPath)
+		// Warn if an SSH key path doesn't exist (but continue import)
+		for _, keyPath := range id.SSHKeys() {
+			expanded, err := expandPath(keyPath)
 			if err == nil {
 				if _, statErr := os.Stat(expanded); os.IsNotExist(statErr) {
-					fmt.Fprintf(os.Stderr, "Warning: SSH key not found: %s (identity: %s)\n", id.SSHKeyPath, id.Name)
+					fmt.Fprintf(os.Stderr, "Warning: SSH key not found: %s (identity: %s)\n", keyPath, id.Name)
 				}
 			}
 		}
 	}
 
+	// Warn about rules that would point at a missing identity (they are skipped)
+	for _, r := range portability.DanglingRules(cfg, export) {
+		fmt.Fprintf(os.Stderr, "Warning: skipping rule %q: identity %q not found in config or import file\n", r.Pattern, r.Identity)
+	}
+
 	// Detect conflicts
 	conflicts := portability.DetectConflicts(cfg, export)
 
 	// Build overwrite map
 	overwrite := make(map[string]bool)
+	hints := portability.TimestampHints{Incoming: export.ExportedAt}
+	if configPath, err := config.ConfigPath(); err == nil {
+		if info, err := os.Stat(configPath); err == nil {
+			hints.Existing = info.ModTime()
+		}
+	}
 
 	if len(conflicts) > 0 {
-		if importForce {
-			// Force mode: overwrite all conflicts
-			for _, c := range conflicts {
-				overwrite[c.Key] = true
+		if strategy != "" {
+			// Non-interactive: resolve every conflict the same way
+			overwrite = portability.ResolveConflicts(conflicts, strategy, hints)
+			if strategy == portability.MergeNewer && !quiet {
+				if hints.IncomingNewer() {
+					fmt.Println(ui.DimStyle.Render("Import file is newer than your config; overwriting conflicts."))
+				} else {
+					fmt.Println(ui.DimStyle.Render("Your config is newer than the import file; keeping existing entries."))
+				}
 			}
 		} else {
 			// Interactive mode: prompt for each conflict
@@ -103,30 +163,22 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Merge config
-	result, err := portability.MergeConfig(cfg, export, overwrite)
+	// Merge config, checking it merges cleanly before any keys are written
+	result, err := mergeImport(cfg, export, overwrite)
 	if err != nil {
-		return fmt.Errorf("failed to merge config: %w", err)
-	}
-
-	// Handle default identity from import
-	if export.Default != "" && cfg.Default == "" {
-		// Check if the default identity exists in the merged config
-		if _, err := cfg.GetIdentity(export.Default); err == nil {
-			cfg.Default = export.Default
-		}
+		return err
 	}
 
 	// Handle encrypted SSH keys
 	var keyResult *portability.KeyExtractionResult
+	var cipher portability.KeyCipher
 	if portability.HasEncryptedKeys(export) {
 		fmt.Println()
 		fmt.Println("Encrypted SSH keys detected in import file.")
 
-		// Prompt for passphrase
-		passphrase, err := ui.ReadPassphrase("Enter passphrase to decrypt SSH keys: ")
+		cipher, err = importCipher(export, "Enter passphrase to decrypt SSH keys: ")
 		if err != nil {
-			return fmt.Errorf("failed to read passphrase: %w", err)
+			return err
 		}
 
 		// Check which key files already exist
@@ -136,8 +188,8 @@ func runImport(cmd *cobra.Command, args []string) error {
 		for _, keyPath := range keyPaths {
 			if _, err := os.Stat(keyPath); err == nil {
 				// File exists, prompt for overwrite
-				if importForce {
-					overwriteKeys[keyPath] = true
+				if strategy != "" {
+					overwriteKeys[keyPath] = strategy.Overwrites(hints)
 				} else {
 					fmt.Printf("\nSSH key file already exists: %s\n", keyPath)
 					fmt.Print("  [o]verwrite / [s]kip? ")
@@ -154,7 +206,7 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 
 		// Extract keys
-		keyResult, err = portability.ExtractEncryptedKeys(export, passphrase, overwriteKeys)
+		keyResult, err = portability.ExtractEncryptedKeys(export, cipher, overwriteKeys)
 		if err != nil {
 			return fmt.Errorf("failed to extract SSH keys: %w", err)
 		}
@@ -167,17 +219,146 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Save config
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	// Handle embedded GPG keys
+	var gpgResult *portability.GPGImportResult
+	if portability.HasGPGKeys(export) {
+		gpgResult, err = importGPGKeys(export, cipher)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Merge again into the config as it is now, under the config lock, so
+	// changes saved by another gitch while prompting aren't lost
+	err = updateConfig(func(cfg *config.Config) error {
+		result, err = mergeImport(cfg, export, overwrite)
+		if err != nil {
+			return err
+		}
+
+		// Back up the config before overwriting anything in it
+		if !importNoBackup && (len(result.UpdatedIdentities) > 0 || len(result.UpdatedRules) > 0) {
+			backupPath, err := config.Backup()
+			if err != nil {
+				return fmt.Errorf("failed to back up config (use --no-backup to skip): %w", err)
+			}
+			printInfo("Backed up config to %s", backupPath)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Print summary
-	printImportSummary(inputPath, result, keyResult)
+	if !quiet {
+		printImportSummary(inputPath, result, keyResult, gpgResult)
+	}
 
 	return nil
 }
 
+// mergeImport merges export into cfg, resolving conflicts per overwrite, and
+// adopts the export's default identity if cfg has none
+func mergeImport(cfg *config.Config, export *portability.ExportConfig, overwrite map[string]bool) (*portability.ImportResult, error) {
+	result, err := portability.MergeConfig(cfg, export, overwrite)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge config: %w", err)
+	}
+
+	// Handle default identity from import
+	if export.Default != "" && cfg.Default == "" {
+		// Check if the default identity exists in the merged config
+		if _, err := cfg.GetIdentity(export.Default); err == nil {
+			cfg.Default = export.Default
+		}
+	}
+	return result, nil
+}
+
+// importGPGKeys asks whether to load embedded GPG keys into the gpg keyring and
+// imports them. The cipher used for SSH keys is reused for secret keys when
+// already set up. Returns nil if the user declines, or with a warning if
+// there is no terminal to ask on, so the rest of the import still completes.
+func importGPGKeys(export *portability.ExportConfig, cipher portability.KeyCipher) (*portability.GPGImportResult, error) {
+	fmt.Println()
+	fmt.Println("GPG keys detected in import file.")
+
+	if !gpgpkg.IsGPGAvailable() {
+		fmt.Fprintln(os.Stderr, "Warning: gpg not found; skipping GPG key import")
+		return nil, nil
+	}
+
+	confirmed, err := ui.ConfirmPrompt("Import them into your gpg keyring?", importForce)
+	if errors.Is(err, ui.ErrNotInteractive) {
+		fmt.Fprintln(os.Stderr, "Warning: stdin is not a terminal; skipping GPG key import (use --force to import them)")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !confirmed {
+		return nil, nil
+	}
+
+	if portability.HasGPGSecretKeys(export) && cipher == nil {
+		cipher, err = importCipher(export, "Enter passphrase to decrypt GPG keys: ")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := portability.ImportGPGKeys(export, cipher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import GPG keys: %w", err)
+	}
+
+	for _, errMsg := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  ! %s\n", errMsg)
+	}
+
+	return result, nil
+}
+
+// maxPassphraseAttempts is how many times import asks for the passphrase
+// before giving up
+const maxPassphraseAttempts = 3
+
+// importCipher returns the cipher for decrypting the export's keys: the
+// --age-identity file for exports encrypted to an age recipient, otherwise a
+// passphrase read with prompt. The passphrase is checked against one
+// encrypted key and asked for again, up to maxPassphraseAttempts times.
+func importCipher(export *portability.ExportConfig, prompt string) (portability.KeyCipher, error) {
+	if export.Encryption != nil && export.Encryption.Method == portability.EncryptionX25519 {
+		if importAgeIdentity == "" {
+			return nil, errors.New("import file is encrypted to an age recipient; pass its private key file with --age-identity")
+		}
+		identities, err := portability.LoadIdentityFile(importAgeIdentity)
+		if err != nil {
+			return nil, err
+		}
+		cipher := portability.RecipientCipher{Identities: identities}
+		if !portability.VerifyCipher(export, cipher) {
+			return nil, fmt.Errorf("%s cannot decrypt the keys in this import file", importAgeIdentity)
+		}
+		return cipher, nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		passphrase, err := ui.ReadPassphrase(prompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if portability.VerifyPassphrase(export, passphrase) {
+			return portability.PassphraseCipher(passphrase), nil
+		}
+		if attempt == maxPassphraseAttempts {
+			return nil, portability.ErrDecryptionFailed
+		}
+		fmt.Fprintln(os.Stderr, ui.WarningStyle.Render("Wrong passphrase, try again."))
+	}
+}
+
 func promptConflict(reader *bufio.Reader, c portability.Conflict) (overwrite bool, abort bool, err error) {
 	switch c.Type {
 	case portability.IdentityConflict:
@@ -205,8 +386,13 @@ func promptConflict(reader *bufio.Reader, c portability.Conflict) (overwrite boo
 		fmt.Println()
 
 	case portability.RuleConflict:
+		existing := c.Existing.(rules.Rule)
+		incoming := c.Incoming.(rules.Rule)
+
 		fmt.Println()
-		fmt.Printf("Rule %q already exists with different identity\n", c.Key)
+		fmt.Printf("Rule %q already exists:\n", c.Key)
+		fmt.Printf("  Existing: %s\n", formatConflictRule(existing))
+		fmt.Printf("  Incoming: %s\n", formatConflictRule(incoming))
 	}
 
 	fmt.Print("  [o]verwrite / [s]kip / [a]bort? ")
@@ -228,7 +414,36 @@ func promptConflict(reader *bufio.Reader, c portability.Conflict) (overwrite boo
 	}
 }
 
-func printImportSummary(path string, result *portability.ImportResult, keyResult *portability.KeyExtractionResult) {
+// formatConflictRule formats a rule as "work (directory, priority 10)"
+func formatConflictRule(rule rules.Rule) string {
+	details := string(rule.Type)
+	if rule.Priority != 0 {
+		details += fmt.Sprintf(", priority %d", rule.Priority)
+	}
+	if rule.IgnoreCase {
+		details += ", ignore case"
+	}
+	return fmt.Sprintf("%s (%s)", rule.Identity, details)
+}
+
+// formatFieldChanges formats changes as "email old -> new, hook_mode old -> new"
+func formatFieldChanges(changes []portability.FieldChange) string {
+	parts := make([]string, len(changes))
+	for i, c := range changes {
+		parts[i] = fmt.Sprintf("%s %s -> %s", c.Field, valueOrNone(c.Old), valueOrNone(c.New))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// valueOrNone returns s, or "(none)" if it is empty
+func valueOrNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+func printImportSummary(path string, result *portability.ImportResult, keyResult *portability.KeyExtractionResult, gpgResult *portability.GPGImportResult) {
 	fmt.Println()
 	fmt.Println(ui.SuccessStyle.Render("Import complete!"))
 	fmt.Printf("  File: %s\n", path)
@@ -241,6 +456,11 @@ func printImportSummary(path string, result *portability.ImportResult, keyResult
 	}
 	if len(result.UpdatedIdentities) > 0 {
 		fmt.Printf("  ~ %d identities updated\n", len(result.UpdatedIdentities))
+		for _, name := range result.UpdatedIdentities {
+			if changes := result.Changes[name]; len(changes) > 0 {
+				fmt.Printf("      %s: %s\n", name, formatFieldChanges(changes))
+			}
+		}
 		hasOutput = true
 	}
 	if len(result.AddedRules) > 0 {
@@ -284,6 +504,11 @@ func printImportSummary(path string, result *portability.ImportResult, keyResult
 		}
 	}
 
+	if gpgResult != nil && len(gpgResult.Imported) > 0 {
+		fmt.Printf("  + %d GPG keys imported\n", len(gpgResult.Imported))
+		hasOutput = true
+	}
+
 	if !hasOutput {
 		fmt.Println("  No changes (config already up to date)")
 	}
@@ -0,0 +1,244 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	sshpkg "github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	keyRotateForce bool
+	keyShowSSH     bool
+	keyShowGPG     bool
+)
+
+var keyCmd = &cobra.Command{
+	Use:   "key",
+	Short: "Manage identity keys",
+	Long: `Show and manage the SSH and GPG keys linked to gitch identities.
+
+Examples:
+  gitch key show work
+  gitch key rotate work --force`,
+}
+
+var keyShowCmd = &cobra.Command{
+	Use:   "show <identity>",
+	Short: "Print an identity's public keys",
+	Long: `Print the public keys linked to an identity, ready to paste into your
+Git host: each SSH public key (<key>.pub) with its SHA256 fingerprint, and
+the armored GPG public key.
+
+Both are shown by default; use --ssh or --gpg to show only one. Only the
+keys go to stdout, so the output can be piped or redirected; the headers
+naming each key go to stderr.
+
+Examples:
+  gitch key show work
+  gitch key show work --ssh
+  gitch key show work --gpg`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runKeyShow,
+}
+
+var keyRotateCmd = &cobra.Command{
+	Use:   "rotate <identity>",
+	Short: "Regenerate an identity's SSH key in place",
+	Long: `Replace an identity's SSH key with a freshly generated keypair.
+
+Use this when a key may be compromised. The new key has the same type as
+the old one and is written to the same path, so the identity and any SSH
+config Host aliases keep working once the new public key is added to your
+Git host.
+
+The old key files are kept as <key>.bak and <key>.pub.bak (<key>-N.bak and
+<key>.pub-N.bak if earlier backups exist), and the old key is removed from
+ssh-agent. If 'gitch ssh-config update' has been run before,
+the managed block in ~/.ssh/config is refreshed.
+
+--force is required because the existing key is replaced.
+
+Examples:
+  gitch key rotate work --force`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runKeyRotate,
+}
+
+func init() {
+	rootCmd.AddCommand(keyCmd)
+	keyCmd.AddCommand(keyShowCmd)
+	keyCmd.AddCommand(keyRotateCmd)
+
+	keyShowCmd.Flags().BoolVar(&keyShowSSH, "ssh", false, "Show only the SSH public keys")
+	keyShowCmd.Flags().BoolVar(&keyShowGPG, "gpg", false, "Show only the GPG public key")
+	keyRotateCmd.Flags().BoolVar(&keyRotateForce, "force", false, "Confirm replacing the existing SSH key")
+}
+
+func runKeyShow(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	identity, err := cfg.GetIdentity(args[0])
+	if err != nil {
+		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", args[0])
+	}
+
+	// Without --ssh or --gpg, show whichever keys the identity has
+	showSSH, showGPG := keyShowSSH, keyShowGPG
+	if !showSSH && !showGPG {
+		showSSH = identity.SSHKeyPath != ""
+		showGPG = identity.GPGKeyID != ""
+		if !showSSH && !showGPG {
+			return fmt.Errorf("identity '%s' has no SSH or GPG key", identity.Name)
+		}
+	}
+	if showSSH && identity.SSHKeyPath == "" {
+		return fmt.Errorf("identity '%s' has no SSH key", identity.Name)
+	}
+	if showGPG && identity.GPGKeyID == "" {
+		return fmt.Errorf("identity '%s' has no GPG key", identity.Name)
+	}
+
+	first := true
+	if showSSH {
+		for _, keyPath := range identity.SSHKeys() {
+			publicKeyPath := keyPath + ".pub"
+			publicKey, err := os.ReadFile(publicKeyPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return fmt.Errorf("SSH public key not found: %s", publicKeyPath)
+				}
+				return fmt.Errorf("failed to read SSH public key: %w", err)
+			}
+			fingerprint, err := sshpkg.GetFingerprint(publicKey)
+			if err != nil {
+				return fmt.Errorf("failed to get fingerprint of %s: %w", publicKeyPath, err)
+			}
+
+			if !first {
+				fmt.Fprintln(os.Stderr)
+			}
+			first = false
+			fmt.Fprintln(os.Stderr, ui.DimStyle.Render(fmt.Sprintf("SSH key %s (%s)", publicKeyPath, fingerprint)))
+			fmt.Println(strings.TrimSpace(string(publicKey)))
+		}
+	}
+
+	if showGPG {
+		publicKey, err := gpgpkg.ExportPublicKey(identity.GPGKeyID)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			fmt.Fprintln(os.Stderr)
+		}
+		fmt.Fprintln(os.Stderr, ui.DimStyle.Render("GPG key "+identity.GPGKeyID))
+		fmt.Println(strings.TrimSpace(publicKey))
+	}
+
+	return nil
+}
+
+func runKeyRotate(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	identity, err := cfg.GetIdentity(args[0])
+	if err != nil {
+		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", args[0])
+	}
+
+	if identity.SSHKeyPath == "" {
+		return fmt.Errorf("identity '%s' has no SSH key; use 'gitch add --generate-ssh' to create one", identity.Name)
+	}
+	keyPath, err := expandPath(identity.SSHKeyPath)
+	if err != nil {
+		return fmt.Errorf("invalid SSH key path: %w", err)
+	}
+
+	// Determine the type of the existing key
+	pemData, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read SSH key: %w", err)
+	}
+	keyType, err := sshpkg.GetKeyType(pemData)
+	if err != nil {
+		return fmt.Errorf("failed to determine SSH key type: %w", err)
+	}
+
+	if !keyRotateForce {
+		return fmt.Errorf("rotating replaces the SSH key at %s; re-run with --force to confirm", keyPath)
+	}
+
+	// Prompt for passphrase
+	passphrase, err := ui.ReadPassphraseWithConfirm()
+	if err != nil {
+		return fmt.Errorf("failed to read passphrase: %w", err)
+	}
+
+	// Generate before touching the old key so a failure leaves it in place
+	privateKey, publicKey, err := sshpkg.GenerateKeyPairWithType(keyType, identity.Email, passphrase)
+	if err != nil {
+		return fmt.Errorf("failed to generate SSH keypair: %w", err)
+	}
+
+	// Unload the old key while its files are still in place (best effort)
+	if err := sshpkg.RemoveKeyFromAgent(keyPath); err != nil && !errors.Is(err, sshpkg.ErrAgentNotRunning) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to remove old SSH key from agent: %v\n", err)
+	}
+
+	backupPath, err := sshpkg.BackupKeyFiles(keyPath)
+	if err != nil {
+		return err
+	}
+
+	if err := sshpkg.WriteKeyFiles(keyPath, privateKey, publicKey); err != nil {
+		// Put the old key back so the identity keeps working
+		if restoreErr := sshpkg.RestoreKeyFiles(keyPath, backupPath); restoreErr != nil {
+			return fmt.Errorf("failed to write SSH key files: %w; the old key is still at %s (%v)", err, backupPath, restoreErr)
+		}
+		return fmt.Errorf("failed to write SSH key files: %w; the old key was restored", err)
+	}
+
+	// Get fingerprint for display
+	fingerprint, err := sshpkg.GetFingerprint(publicKey)
+	if err != nil {
+		return fmt.Errorf("failed to get key fingerprint: %w", err)
+	}
+
+	fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("Rotated %s SSH key for '%s':", sshKeyTypeLabel(keyType), identity.Name)))
+	fmt.Printf("  Path: %s\n", keyPath)
+	fmt.Printf("  Fingerprint: %s\n", fingerprint)
+	fmt.Printf("  Old key: %s\n", backupPath)
+	fmt.Println()
+	fmt.Println("Public key (add to GitHub/GitLab, then remove the old one):")
+	fmt.Print(strings.TrimSuffix(string(publicKey), "\n"))
+	fmt.Println()
+	fmt.Println()
+
+	// Refresh the managed SSH config block
+	updatedPath, err := refreshSSHConfig(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if updatedPath != "" {
+		fmt.Println(ui.DimStyle.Render("Updated " + updatedPath))
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	sshpkg "github.com/orzazade/gitch/internal/ssh"
+)
+
+// writeTestSSHKey writes an Ed25519 keypair to path and returns the public key
+func writeTestSSHKey(t *testing.T, path string) string {
+	t.Helper()
+	privateKey, publicKey, err := sshpkg.GenerateKeyPair("me@company.com", nil)
+	if err != nil {
+		t.Fatalf("GenerateKeyPair() error = %v", err)
+	}
+	if err := sshpkg.WriteKeyFiles(path, privateKey, publicKey); err != nil {
+		t.Fatalf("WriteKeyFiles() error = %v", err)
+	}
+	return strings.TrimSpace(string(publicKey))
+}
+
+func TestRunKeyShow_OnlyKeysOnStdout(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_work")
+	publicKey := writeTestSSHKey(t, keyPath)
+	useTestConfig(t, "identities:\n  - name: work\n    email: me@company.com\n    ssh_key_path: "+keyPath+"\n")
+
+	stderr, err := os.CreateTemp(t.TempDir(), "stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stderr.Close()
+	oldStderr := os.Stderr
+	os.Stderr = stderr
+	defer func() { os.Stderr = oldStderr }()
+
+	out := captureStdout(t, func() {
+		if err := runKeyShow(keyShowCmd, []string{"work"}); err != nil {
+			t.Fatalf("runKeyShow() error = %v", err)
+		}
+	})
+	if out != publicKey+"\n" {
+		t.Errorf("stdout = %q, want only the public key", out)
+	}
+
+	header, err := os.ReadFile(stderr.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(header), "SSH key "+keyPath+".pub (SHA256:") {
+		t.Errorf("stderr = %q, want the key header", header)
+	}
+}
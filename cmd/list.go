@@ -17,11 +17,22 @@ type listOutputItem struct {
 	Email      string `json:"email"`
 	SSHKeyPath string `json:"ssh_key_path,omitempty"`
 	GPGKeyID   string `json:"gpg_key_id,omitempty"`
+	HookMode   string `json:"hook_mode"`
 	IsActive   bool   `json:"is_active"`
 	IsDefault  bool   `json:"is_default"`
+	// SSHKeyPaths is set only for identities with more than one SSH key
+	SSHKeyPaths []string `json:"ssh_key_paths,omitempty"`
+	// SigningMethod is the effective signing method: gpg, ssh or none
+	SigningMethod string   `json:"signing_method"`
+	Description   string   `json:"description,omitempty"`
+	Tags          []string `json:"tags,omitempty"`
 }
 
-var listJSON bool
+var (
+	listJSON         bool
+	listExpiryWindow int
+	listTags         []string
+)
 
 var listCmd = &cobra.Command{
 	Use:     "list",
@@ -32,18 +43,36 @@ var listCmd = &cobra.Command{
 The currently active identity is highlighted with a checkmark and green border.
 The default identity is marked with "(default)".
 
+Warns on stderr when an identity's GPG key has expired or expires within
+--expiry-window days (default 30).
+
+With --json, prints a JSON array for editor plugins and scripts. Each entry
+includes the identity's email, keys, effective hook mode, and whether it is
+active or the default.
+
+Use --tag (repeatable) to list only identities with one of the tags.
+
 Examples:
   gitch list
-  gitch ls`,
+  gitch ls
+  gitch list --tag client-a
+  gitch list --json`,
 	RunE: runList,
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output in JSON format")
+	listCmd.Flags().StringArrayVar(&listTags, "tag", nil, "List only identities with this tag (repeatable)")
+	_ = listCmd.RegisterFlagCompletionFunc("tag", tagCompletionFunc)
+	listCmd.Flags().IntVar(&listExpiryWindow, "expiry-window", defaultExpiryWindowDays, "Warn about GPG keys expiring within this many days")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if listExpiryWindow < 0 {
+		return fmt.Errorf("invalid --expiry-window %d: must not be negative", listExpiryWindow)
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -52,10 +81,17 @@ func runList(cmd *cobra.Command, args []string) error {
 
 	// Get all identities
 	identities := cfg.ListIdentities()
-	if len(identities) == 0 {
+	if len(identities) == 0 && !listJSON {
 		fmt.Println("No identities configured. Use 'gitch add' to create one.")
 		return nil
 	}
+	if len(listTags) > 0 {
+		identities = cfg.IdentitiesWithTag(listTags...)
+		if len(identities) == 0 && !listJSON {
+			fmt.Printf("No identities tagged %s.\n", strings.Join(listTags, " or "))
+			return nil
+		}
+	}
 
 	// Get current git identity to determine which is active
 	_, activeEmail, err := git.GetCurrentIdentity()
@@ -69,12 +105,17 @@ func runList(cmd *cobra.Command, args []string) error {
 		items := make([]listOutputItem, len(identities))
 		for i, id := range identities {
 			items[i] = listOutputItem{
-				Name:       id.Name,
-				Email:      id.Email,
-				SSHKeyPath: id.SSHKeyPath,
-				GPGKeyID:   id.GPGKeyID,
-				IsActive:   strings.EqualFold(id.Email, activeEmail),
-				IsDefault:  strings.EqualFold(id.Name, cfg.Default),
+				Name:          id.Name,
+				Email:         id.Email,
+				SSHKeyPath:    id.SSHKeyPath,
+				GPGKeyID:      id.GPGKeyID,
+				HookMode:      id.GetHookMode(),
+				IsActive:      activeEmail != "" && strings.EqualFold(id.Email, activeEmail),
+				IsDefault:     strings.EqualFold(id.Name, cfg.Default),
+				SSHKeyPaths:   id.SSHKeyPaths,
+				SigningMethod: id.GetSigningMethod(),
+				Description:   id.Description,
+				Tags:          id.Tags,
 			}
 		}
 		jsonBytes, err := json.MarshalIndent(items, "", "  ")
@@ -89,5 +130,7 @@ func runList(cmd *cobra.Command, args []string) error {
 	output := ui.RenderIdentityList(identities, activeEmail, cfg.Default)
 	fmt.Println(output)
 
+	printGPGExpiryWarnings(identities, listExpiryWindow)
+
 	return nil
 }
@@ -0,0 +1,9 @@
+package cmd
+
+import sshpkg "github.com/orzazade/gitch/internal/ssh"
+
+// expandPath expands ~, ~username and environment variables in a path given
+// on the command line or in the config, returning the cleaned absolute path
+func expandPath(path string) (string, error) {
+	return sshpkg.ExpandPath(path)
+}
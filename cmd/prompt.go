@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var promptFormat string
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the active identity for use in a shell prompt",
+	Long: `Print the name of the active identity for use in a shell prompt.
+
+The name is read from gitch's prompt cache, which is updated whenever the
+identity changes through gitch (use, add, the pre-commit hook, auto-switch),
+so this is fast enough to run on every prompt. Nothing is printed when no
+identity is cached.
+
+Use --format to wrap the name; %s is replaced with the identity name.
+
+For a ready-made prompt setup, see 'gitch init'.
+
+Examples:
+  gitch prompt
+  gitch prompt --format "(%s) "
+
+  # Bash (~/.bashrc)
+  PS1='$(gitch prompt --format "[%s] ")'"$PS1"
+
+  # Zsh (~/.zshrc)
+  setopt PROMPT_SUBST
+  PROMPT='$(gitch prompt --format "[%s] ")'"$PROMPT"`,
+	Args: cobra.NoArgs,
+	RunE: runPrompt,
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.Flags().StringVar(&promptFormat, "format", prompt.DefaultFormat, "Output format; %s is replaced with the identity name")
+}
+
+func runPrompt(cmd *cobra.Command, args []string) error {
+	if err := prompt.ValidateFormat(promptFormat); err != nil {
+		return err
+	}
+
+	name, err := prompt.ReadCache()
+	if err != nil {
+		// Never break the user's prompt over an unreadable cache
+		return nil
+	}
+
+	// No trailing newline so the output can be embedded in PS1
+	fmt.Print(prompt.FormatIdentity(promptFormat, name))
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	removeYes       bool
+	removeForce     bool
+	removeKeepRules bool
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove <identity-name>",
+	Aliases: []string{"rm", "delete"},
+	Short:   "Remove a git identity",
+	Long: `Remove a git identity by name.
+
+Prompts for confirmation unless --yes or --force is specified.
+If the removed identity is the default, the default is cleared.
+
+If any rules reference the identity, they are listed and you are
+offered to remove them too. Use --force to remove them without asking,
+or --keep-rules to leave them in place.
+
+Examples:
+  gitch remove work
+  gitch rm personal --yes
+  gitch remove work --force
+  gitch remove work --keep-rules`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runRemove,
+}
+
+func init() {
+	rootCmd.AddCommand(removeCmd)
+
+	removeCmd.Flags().BoolVarP(&removeYes, "yes", "y", false, "Skip confirmation prompt")
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Skip all prompts and remove referencing rules")
+	removeCmd.Flags().BoolVar(&removeKeepRules, "keep-rules", false, "Keep rules that reference the identity")
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	skipConfirm := removeYes || removeForce
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Verify identity exists
+	identity, err := cfg.GetIdentity(name)
+	if err != nil {
+		fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("Identity '%s' not found, nothing to remove.", name)))
+		return nil
+	}
+	// Copy before the slice is modified by DeleteIdentity
+	removed := *identity
+
+	// Check if this is the currently active identity
+	_, activeEmail, _ := git.GetCurrentIdentity()
+	isActive := strings.EqualFold(removed.Email, activeEmail)
+
+	// Confirm removal
+	message := fmt.Sprintf("Remove identity '%s'? This cannot be undone.", removed.Name)
+	confirmed, err := ui.ConfirmPrompt(message, skipConfirm)
+	if err != nil {
+		return err
+	}
+
+	if !confirmed {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Handle rules that reference this identity
+	removeRules := false
+	referencing := cfg.RulesForIdentity(removed.Name)
+	if len(referencing) > 0 && !removeKeepRules {
+		fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("%d rule(s) reference '%s':", len(referencing), removed.Name)))
+		for _, rule := range referencing {
+			fmt.Printf("  %s: %s -> %s\n", rule.Type, rule.Pattern, rule.Identity)
+		}
+
+		removeRules, err = ui.ConfirmPrompt("Remove these rules too?", removeForce)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Remove identity, re-reading the config under the lock
+	rulesRemoved := 0
+	remaining := 0
+	err = updateConfig(func(cfg *config.Config) error {
+		if err := cfg.DeleteIdentity(removed.Name); err != nil {
+			return fmt.Errorf("failed to remove identity: %w", err)
+		}
+		if removeRules {
+			rulesRemoved = cfg.RemoveRulesForIdentity(removed.Name)
+		}
+		remaining = len(cfg.Identities)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Handle prompt cache if we removed the active identity
+	if isActive {
+		// If no identities left, clear cache
+		if remaining == 0 {
+			_ = prompt.ClearCache() // Best effort
+		}
+		// If other identities exist, leave cache as-is (user will switch)
+	}
+
+	// Print success
+	msg := fmt.Sprintf("Removed identity '%s'", removed.Name)
+	fmt.Println(ui.SuccessStyle.Render(msg))
+
+	if rulesRemoved > 0 {
+		fmt.Printf("Removed %d rule(s)\n", rulesRemoved)
+	} else if len(referencing) > 0 {
+		fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("Note: %d rule(s) still reference '%s'.", len(referencing), removed.Name)))
+	}
+
+	// Warn if this was the active identity
+	if isActive {
+		fmt.Println(ui.WarningStyle.Render("Note: This was the active identity. Git config is unchanged."))
+	}
+
+	return nil
+}
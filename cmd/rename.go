@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <old-name> <new-name>",
+	Short: "Rename a git identity",
+	Long: `Rename a git identity and update everything that references it.
+
+Rules that point at the old name are rewritten to the new name, and the
+default identity is updated if it matched. Renaming only changes the
+gitch identity name; git user.name is left unchanged.
+
+Examples:
+  gitch rename work company
+  gitch rename personal me`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runRename,
+}
+
+func init() {
+	rootCmd.AddCommand(renameCmd)
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	oldName := args[0]
+	newName := args[1]
+
+	var storedName, email string
+	var rulesUpdated int
+
+	// Hold the config lock across load-modify-save
+	err := config.WithLock(func() error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Verify identity exists
+		identity, err := cfg.GetIdentity(oldName)
+		if err != nil {
+			return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", oldName)
+		}
+		storedName = identity.Name
+		email = identity.Email
+
+		// Rename identity and update references
+		rulesUpdated, err = cfg.RenameIdentity(oldName, newName)
+		if err != nil {
+			return err
+		}
+
+		// Save config
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Update prompt cache if the renamed identity is active (best effort)
+	_, activeEmail, _ := git.GetCurrentIdentity()
+	if strings.EqualFold(email, activeEmail) {
+		_ = prompt.UpdateCache(newName)
+	}
+
+	// Print success
+	msg := fmt.Sprintf("Renamed identity '%s' to '%s'", storedName, newName)
+	fmt.Println(ui.SuccessStyle.Render(msg))
+	if rulesUpdated > 0 {
+		fmt.Printf("Updated %d rule(s)\n", rulesUpdated)
+	}
+
+	return nil
+}
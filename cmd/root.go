@@ -4,7 +4,8 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/adrg/xdg"
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -12,6 +13,14 @@ import (
 // Version is the current version of gitch
 var Version = "0.1.0"
 
+// quiet suppresses success and informational output (--quiet). Errors,
+// warnings and requested output such as dry runs are still printed.
+var quiet bool
+
+// noColor disables colored output (--no-color). Color is also off when
+// NO_COLOR is non-empty or stdout is not a terminal.
+var noColor bool
+
 var rootCmd = &cobra.Command{
 	Use:   "gitch",
 	Short: "A git identity manager",
@@ -34,12 +43,34 @@ func Execute() error {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initColor, initConfig)
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Suppress success and informational output")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output (also set by NO_COLOR)")
+}
+
+// printSuccess prints msg in the success style unless --quiet is set
+func printSuccess(msg string) {
+	if quiet {
+		return
+	}
+	fmt.Println(ui.SuccessStyle.Render(msg))
+}
+
+// printInfo prints an informational line unless --quiet is set
+func printInfo(format string, a ...any) {
+	if quiet {
+		return
+	}
+	fmt.Printf(format+"\n", a...)
+}
+
+func initColor() {
+	ui.ConfigureColor(noColor)
 }
 
 func initConfig() {
-	// XDG config path: ~/.config/gitch/config.yaml
-	configPath, err := xdg.ConfigFile("gitch/config.yaml")
+	// $GITCH_CONFIG or XDG config path: ~/.config/gitch/config.yaml
+	configPath, err := config.ConfigPath()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not determine config path: %v\n", err)
 		return
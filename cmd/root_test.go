@@ -0,0 +1,37 @@
+package cmd
+
+import "testing"
+
+func TestQuietSuppressesOutput(t *testing.T) {
+	t.Cleanup(func() { quiet = false })
+
+	emit := func() {
+		printSuccess("Switched to 'work'")
+		printInfo("Rule %s added", "~/work/**")
+	}
+
+	if out := captureStdout(t, emit); out == "" {
+		t.Error("printSuccess and printInfo printed nothing without --quiet")
+	}
+
+	quiet = true
+	if out := captureStdout(t, emit); out != "" {
+		t.Errorf("output with --quiet = %q, want none", out)
+	}
+}
+
+func TestQuietFlag(t *testing.T) {
+	useTestConfig(t, "identities:\n  - name: work\n    email: me@company.com\n")
+	t.Cleanup(func() { quiet, ruleUse = false, "" })
+
+	out := captureStdout(t, func() {
+		rootCmd.SetArgs([]string{"rule", "add", "/srv/work/**", "--use", "work", "-q"})
+		defer rootCmd.SetArgs(nil)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+	})
+	if !quiet || out != "" {
+		t.Errorf("quiet = %v, output %q; want --quiet to silence the command", quiet, out)
+	}
+}
@@ -1,19 +1,40 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
 	"github.com/orzazade/gitch/internal/rules"
 	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	ruleUse    string
-	ruleRemote string
+	ruleUse        string
+	ruleRemote     string
+	ruleBranch     string
+	ruleEmail      string
+	ruleTestRemote string
+	ruleListSort   string
+
+	ruleAddTest       string
+	ruleAddTestRemote string
+	ruleAddForce      bool
+	ruleAddIgnoreCase bool
+
+	ruleImportRoot   string
+	ruleImportUse    string
+	ruleImportDryRun bool
 )
 
 var ruleCmd = &cobra.Command{
@@ -24,18 +45,25 @@ var ruleCmd = &cobra.Command{
 Rules allow gitch to automatically determine which identity to use based on:
 - Directory patterns: Match the current working directory
 - Remote patterns: Match the git remote URL
+- Branch patterns: Match the current git branch
+- Email patterns: Match the domain of the current git user.email
 
 Examples:
   gitch rule add ~/work/** --use work
   gitch rule add --remote "github.com/company/*" --use work
+  gitch rule add --branch "release/*" --use work
+  gitch rule add --email "*@company.com" --use work
   gitch rule list
-  gitch rule remove "~/work/**"`,
+  gitch rule test ~/work/project
+  gitch rule priority "~/work/**" 10
+  gitch rule remove "~/work/**"
+  gitch rule import-from-remotes --root ~/src`,
 }
 
 var ruleAddCmd = &cobra.Command{
 	Use:   "add [directory-pattern]",
 	Short: "Add a new identity rule",
-	Long: `Add a new rule that maps a directory or remote pattern to an identity.
+	Long: `Add a new rule that maps a directory, remote, branch, or email pattern to an identity.
 
 For directory rules, provide the pattern as a positional argument:
   gitch rule add ~/work/** --use work
@@ -45,15 +73,45 @@ For remote rules, use the --remote flag:
   gitch rule add --remote "github.com/company/*" --use work
   gitch rule add --remote "github.com/personal/*" --use personal
 
+For branch rules, use the --branch flag:
+  gitch rule add --branch "release/*" --use work
+
+For email rules, use the --email flag. These match the current git
+user.email and are useful when there is no directory convention:
+  gitch rule add --email "*@company.com" --use work
+
+Branch rules take precedence over directory and remote rules. Email rules
+have the lowest precedence and only apply when nothing else matches.
+Use 'gitch rule priority' to override this ordering.
+
 Patterns support glob syntax:
   * matches any single path segment
-  ** matches any number of path segments
+  ** matches any number of path segments, anywhere in the pattern:
+     ~/projects/**/frontend matches every frontend directory under
+     ~/projects; add /** to also match the directories inside them
+
+Azure DevOps remotes are matched as host/org/project/repo:
+dev.azure.com/org/project/* matches every repository in a project, and
+dev.azure.com/org/* or dev.azure.com/org/project still match as before.
+
+Directory patterns ignore case on macOS and Windows, whose file systems are
+case-insensitive by default. Use --ignore-case to do the same elsewhere, e.g.
+for a case-insensitive volume on Linux.
+
+To catch a typo'd pattern before it is saved, pass a directory that should
+match with --test (directory rules) or a remote URL with --test-remote
+(remote rules). The rule is not added if it doesn't match, unless --force
+is given.
 
 Examples:
   gitch rule add ~/work/** --use work
-  gitch rule add --remote "github.com/myorg/*" --use work`,
-	Args: cobra.MaximumNArgs(1),
-	RunE: runRuleAdd,
+  gitch rule add "~/work/**" --use work --test ~/work/api
+  gitch rule add --remote "github.com/myorg/*" --use work --test-remote git@github.com:myorg/app.git
+  gitch rule add --remote "github.com/myorg/*" --use work
+  gitch rule add --branch "release/**" --use work`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: directoryCompletionFunc,
+	RunE:              runRuleAdd,
 }
 
 var ruleListCmd = &cobra.Command{
@@ -61,7 +119,17 @@ var ruleListCmd = &cobra.Command{
 	Short: "List all configured rules",
 	Long: `Display all configured identity rules in a table format.
 
-Shows the rule type (directory or remote), the pattern, and the associated identity.`,
+Shows the rule type, the pattern, the associated identity, the priority and
+the specificity score. The rule that matches the current directory (and its
+remote, branch and git email) is marked with a checkmark.
+
+Rules are listed in config order. With --sort specificity they are listed in
+precedence order instead: highest priority first, then highest specificity,
+so the first matching rule in the list is the one that wins.
+
+Examples:
+  gitch rule list
+  gitch rule list --sort specificity`,
 	Args: cobra.NoArgs,
 	RunE: runRuleList,
 }
@@ -76,8 +144,67 @@ The pattern must match exactly as it was specified when the rule was created.
 Examples:
   gitch rule remove "~/work/**"
   gitch rule remove "github.com/company/*"`,
-	Args: cobra.ExactArgs(1),
-	RunE: runRuleRemove,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: rulePatternCompletionFunc,
+	RunE:              runRuleRemove,
+}
+
+var rulePriorityCmd = &cobra.Command{
+	Use:   "priority <pattern> <n>",
+	Short: "Set a rule's priority",
+	Long: `Set the priority of a rule, identified by its exact pattern.
+
+When several rules match, the rule with the highest priority wins, and
+specificity breaks ties between rules of equal priority. Rules default to
+priority 0; use a negative priority to make a rule a fallback.
+
+Examples:
+  gitch rule priority "~/work/**" 10
+  gitch rule priority "github.com/company/*" 0`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: rulePatternCompletionFunc,
+	RunE:              runRulePriority,
+}
+
+var ruleTestCmd = &cobra.Command{
+	Use:   "test [path]",
+	Short: "Show which rule matches a path",
+	Long: `Explain which rule selects the identity for a path.
+
+Evaluates all rules against the path (default: current directory), its
+git remote, its current branch, and the current git email, then prints the winning rule and its specificity score along
+with any other matching rules that lost. Use --remote to test against a
+different remote URL.
+
+Examples:
+  gitch rule test
+  gitch rule test ~/work/project
+  gitch rule test --remote git@github.com:company/repo.git`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: directoryCompletionFunc,
+	RunE:              runRuleTest,
+}
+
+var ruleImportRemotesCmd = &cobra.Command{
+	Use:   "import-from-remotes",
+	Short: "Create remote rules from the repositories you have cloned",
+	Long: `Scan a directory tree for git repositories and create remote rules from
+their origin remotes.
+
+Repositories are grouped by host and organization (e.g. github.com/company/*),
+and you are asked which identity each group should use. Press Enter to skip a
+group. Groups that already have a rule with the same pattern are skipped, and
+overlaps with existing rules are reported as with 'gitch rule add'.
+
+Hidden directories and repositories nested inside other repositories are not
+scanned. Use --use to assign every group to one identity without prompting.
+
+Examples:
+  gitch rule import-from-remotes --root ~/src
+  gitch rule import-from-remotes --root ~/work --use work
+  gitch rule import-from-remotes --root ~/src --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: runRuleImportRemotes,
 }
 
 func init() {
@@ -85,35 +212,59 @@ func init() {
 	ruleCmd.AddCommand(ruleAddCmd)
 	ruleCmd.AddCommand(ruleListCmd)
 	ruleCmd.AddCommand(ruleRemoveCmd)
+	ruleCmd.AddCommand(ruleTestCmd)
+	ruleCmd.AddCommand(rulePriorityCmd)
+	ruleCmd.AddCommand(ruleImportRemotesCmd)
 
 	// Flags for ruleAddCmd
 	ruleAddCmd.Flags().StringVar(&ruleUse, "use", "", "Identity to use when rule matches (required)")
 	ruleAddCmd.Flags().StringVar(&ruleRemote, "remote", "", "Remote pattern (mutually exclusive with positional arg)")
+	ruleAddCmd.Flags().StringVar(&ruleBranch, "branch", "", "Branch pattern, e.g. \"release/*\" (mutually exclusive with positional arg)")
+	ruleAddCmd.Flags().StringVar(&ruleEmail, "email", "", "Email pattern, e.g. \"*@company.com\" (mutually exclusive with positional arg)")
+	ruleAddCmd.Flags().StringVar(&ruleAddTest, "test", "", "Directory the pattern must match before the rule is added")
+	ruleAddCmd.Flags().StringVar(&ruleAddTestRemote, "test-remote", "", "Remote URL the --remote pattern must match before the rule is added")
+	ruleAddCmd.Flags().BoolVar(&ruleAddForce, "force", false, "Add the rule even if --test or --test-remote doesn't match")
+	ruleAddCmd.Flags().BoolVar(&ruleAddIgnoreCase, "ignore-case", false, "Match the directory pattern regardless of case (always on for macOS and Windows)")
 	_ = ruleAddCmd.MarkFlagRequired("use")
+	_ = ruleAddCmd.RegisterFlagCompletionFunc("use", identityFlagCompletionFunc)
+	_ = ruleAddCmd.MarkFlagDirname("test")
+
+	// Flags for ruleListCmd
+	ruleListCmd.Flags().StringVar(&ruleListSort, "sort", "order", "Sort rules by config order or by specificity (precedence)")
+	_ = ruleListCmd.RegisterFlagCompletionFunc("sort", cobra.FixedCompletions(
+		[]string{"order", "specificity"}, cobra.ShellCompDirectiveNoFileComp))
+
+	// Flags for ruleImportRemotesCmd
+	ruleImportRemotesCmd.Flags().StringVar(&ruleImportRoot, "root", ".", "Directory to scan for git repositories")
+	ruleImportRemotesCmd.Flags().StringVar(&ruleImportUse, "use", "", "Identity for every group (skips prompts)")
+	_ = ruleImportRemotesCmd.RegisterFlagCompletionFunc("use", identityFlagCompletionFunc)
+	_ = ruleImportRemotesCmd.MarkFlagDirname("root")
+	ruleImportRemotesCmd.Flags().BoolVar(&ruleImportDryRun, "dry-run", false, "Show the groups found without creating rules")
+
+	// Flags for ruleTestCmd
+	ruleTestCmd.Flags().StringVar(&ruleTestRemote, "remote", "", "Remote URL to test (default: origin of the path's repository)")
 }
 
 func runRuleAdd(cmd *cobra.Command, args []string) error {
-	// Validate that exactly one of positional arg or --remote is provided
+	// Validate that exactly one of positional arg, --remote, --branch, or --email is provided
 	hasPositional := len(args) > 0
 	hasRemote := ruleRemote != ""
+	hasBranch := ruleBranch != ""
+	hasEmail := ruleEmail != ""
 
-	if hasPositional && hasRemote {
-		return fmt.Errorf("cannot specify both a directory pattern and --remote; use one or the other")
-	}
-
-	if !hasPositional && !hasRemote {
-		return fmt.Errorf("must specify either a directory pattern or --remote")
+	specified := 0
+	for _, has := range []bool{hasPositional, hasRemote, hasBranch, hasEmail} {
+		if has {
+			specified++
+		}
 	}
 
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	if specified > 1 {
+		return fmt.Errorf("specify only one of a directory pattern, --remote, --branch, or --email")
 	}
 
-	// Validate identity exists
-	if _, err := cfg.GetIdentity(ruleUse); err != nil {
-		return fmt.Errorf("identity %q not found; use 'gitch list' to see available identities", ruleUse)
+	if specified == 0 {
+		return fmt.Errorf("must specify either a directory pattern, --remote, --branch, or --email")
 	}
 
 	// Build the rule
@@ -124,12 +275,28 @@ func runRuleAdd(cmd *cobra.Command, args []string) error {
 			Pattern:  ruleRemote,
 			Identity: ruleUse,
 		}
-	} else {
+	} else if hasBranch {
+		rule = rules.Rule{
+			Type:     rules.BranchRule,
+			Pattern:  ruleBranch,
+			Identity: ruleUse,
+		}
+	} else if hasEmail {
 		rule = rules.Rule{
-			Type:     rules.DirectoryRule,
-			Pattern:  args[0],
+			Type:     rules.EmailRule,
+			Pattern:  ruleEmail,
 			Identity: ruleUse,
 		}
+	} else {
+		rule = rules.Rule{
+			Type:       rules.DirectoryRule,
+			Pattern:    args[0],
+			Identity:   ruleUse,
+			IgnoreCase: ruleAddIgnoreCase,
+		}
+	}
+	if ruleAddIgnoreCase && rule.Type != rules.DirectoryRule {
+		return errors.New("--ignore-case only applies to directory rules")
 	}
 
 	// Validate pattern
@@ -137,30 +304,98 @@ func runRuleAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid pattern: %w", err)
 	}
 
-	// Check for overlapping rules and warn
-	overlapping := cfg.FindOverlappingRules(rule)
-	if len(overlapping) > 0 {
-		fmt.Println(ui.WarningStyle.Render("Warning: This rule may overlap with existing rules:"))
-		for _, overlap := range overlapping {
-			fmt.Printf("  %s: %s -> %s\n", overlap.Type, overlap.Pattern, overlap.Identity)
-		}
-		fmt.Println()
+	if err := testNewRule(rule); err != nil {
+		return err
 	}
 
-	// Add the rule
-	if err := cfg.AddRule(rule); err != nil {
+	// Hold the config lock across load-modify-save
+	err := config.WithLock(func() error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Validate identity exists
+		if _, err := cfg.GetIdentity(ruleUse); err != nil {
+			return fmt.Errorf("identity %q not found; use 'gitch list' to see available identities", ruleUse)
+		}
+
+		// Check for overlapping rules and warn
+		overlapping := cfg.FindOverlappingRules(rule)
+		if len(overlapping) > 0 {
+			fmt.Println(ui.WarningStyle.Render("Warning: This rule may overlap with existing rules:"))
+			for _, overlap := range overlapping {
+				fmt.Printf("  %s: %s -> %s\n", overlap.Type, overlap.Pattern, overlap.Identity)
+			}
+			fmt.Println()
+		}
+
+		// Add the rule
+		if err := cfg.AddRule(rule); err != nil {
+			return err
+		}
+
+		// Save config
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	// Save config
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	// Print success
+	printSuccess(fmt.Sprintf("Rule added: %s -> %s", rule.Pattern, rule.Identity))
+
+	return nil
+}
+
+// testNewRule checks the rule against --test or --test-remote before it is
+// added. A mismatch is an error unless --force is set, which downgrades it to
+// a warning.
+func testNewRule(rule rules.Rule) error {
+	if ruleAddTest != "" && rule.Type != rules.DirectoryRule {
+		return errors.New("--test only applies to directory rules; use --test-remote for --remote")
+	}
+	if ruleAddTestRemote != "" && rule.Type != rules.RemoteRule {
+		return errors.New("--test-remote only applies to --remote rules")
 	}
 
-	// Print success
-	msg := fmt.Sprintf("Rule added: %s -> %s", rule.Pattern, rule.Identity)
-	fmt.Println(ui.SuccessStyle.Render(msg))
+	var target string
+	var matched bool
+	switch {
+	case ruleAddTest != "":
+		path, err := expandPath(ruleAddTest)
+		if err != nil {
+			return fmt.Errorf("invalid --test path: %w", err)
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("invalid --test path: %w", err)
+		}
+		target = path
+		matched = rule.Matches(path, "")
+	case ruleAddTestRemote != "":
+		parsed, err := rules.ParseRemote(ruleAddTestRemote)
+		if err != nil {
+			return fmt.Errorf("invalid --test-remote URL: %w", err)
+		}
+		target = ruleAddTestRemote
+		matched = rules.MatchRemote(rule.Pattern, parsed)
+	default:
+		return nil
+	}
 
+	if matched {
+		printInfo("Pattern %s matches %s", rule.Pattern, target)
+		return nil
+	}
+	if !ruleAddForce {
+		return fmt.Errorf("pattern %s does not match %s; fix the pattern or use --force to add it anyway", rule.Pattern, target)
+	}
+	fmt.Fprintf(os.Stderr, "Warning: pattern %s does not match %s\n", rule.Pattern, target)
 	return nil
 }
 
@@ -171,46 +406,320 @@ func runRuleList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	rules := cfg.ListRules()
-	if len(rules) == 0 {
+	ruleList := cfg.ListRules()
+	if len(ruleList) == 0 {
 		fmt.Println("No rules configured. Use 'gitch rule add' to create one.")
 		return nil
 	}
 
+	switch ruleListSort {
+	case "order":
+	case "specificity":
+		ruleList = slices.Clone(ruleList)
+		sort.SliceStable(ruleList, func(i, j int) bool {
+			return ruleList[i].Outranks(ruleList[j])
+		})
+	default:
+		return fmt.Errorf("invalid --sort %q: must be order or specificity", ruleListSort)
+	}
+
+	// Mark the rule that applies here
+	var best *rules.Rule
+	if cwd, err := os.Getwd(); err == nil {
+		remoteURL, _ := rules.GetGitRemoteURL()
+		best = rules.FindBestMatch(cfg.Rules, cwd, remoteURL)
+	}
+
 	// Create tabwriter for aligned output
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "TYPE\tPATTERN\tIDENTITY")
+	fmt.Fprintln(w, "TYPE\tPATTERN\tIDENTITY\tPRIORITY\tSPECIFICITY\tMATCH")
 
-	for _, rule := range rules {
-		fmt.Fprintf(w, "%s\t%s\t%s\n", rule.Type, rule.Pattern, rule.Identity)
+	for _, rule := range ruleList {
+		match := ""
+		if best != nil && *best == rule {
+			match = "✓"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", rule.Type, rule.Pattern, rule.Identity, rule.Priority, rule.Specificity(), match)
 	}
 
 	w.Flush()
 	return nil
 }
 
+// rulePatternCompletionFunc returns completions for existing rule patterns,
+// described by the rule type and identity.
+func rulePatternCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// Only complete first argument
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		// Format: "pattern\tdescription" - tab separates pattern from description
+		completions = append(completions, fmt.Sprintf("%s\t%s rule -> %s", r.Pattern, r.Type, r.Identity))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// directoryCompletionFunc completes the first argument with directory names
+func directoryCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
 func runRuleRemove(cmd *cobra.Command, args []string) error {
 	pattern := args[0]
 
+	// Hold the config lock across load-modify-save
+	err := config.WithLock(func() error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		// Remove the rule
+		if err := cfg.RemoveRule(pattern); err != nil {
+			return fmt.Errorf("rule with pattern %q not found", pattern)
+		}
+
+		// Save config
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Print success
+	printSuccess(fmt.Sprintf("Rule removed: %s", pattern))
+
+	return nil
+}
+
+func runRulePriority(cmd *cobra.Command, args []string) error {
+	pattern := args[0]
+	priority, err := strconv.Atoi(args[1])
+	if err != nil {
+		return fmt.Errorf("invalid priority %q: must be an integer", args[1])
+	}
+
+	err = updateConfig(func(cfg *config.Config) error {
+		return cfg.SetRulePriority(pattern, priority)
+	})
+	if err != nil {
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("Rule %s: priority %d", pattern, priority))
+
+	return nil
+}
+
+func runRuleTest(cmd *cobra.Command, args []string) error {
+	// Resolve the path to test
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+
+	// Use the path's origin remote unless one was given
+	remoteURL := ruleTestRemote
+	if remoteURL == "" {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			remoteURL, _ = rules.GetGitRemoteURLIn(path)
+		}
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Remove the rule
-	if err := cfg.RemoveRule(pattern); err != nil {
-		return fmt.Errorf("rule with pattern %q not found", pattern)
+	fmt.Printf("Path:   %s\n", path)
+	if remoteURL != "" {
+		fmt.Printf("Remote: %s\n", remoteURL)
+	} else {
+		fmt.Println("Remote: (none)")
+	}
+	if branch, _ := rules.GetCurrentBranchIn(path); branch != "" {
+		fmt.Printf("Branch: %s\n", branch)
+	} else {
+		fmt.Println("Branch: (none)")
+	}
+	if _, email, _ := git.GetCurrentIdentity(); email != "" {
+		fmt.Printf("Email:  %s\n", email)
+	} else {
+		fmt.Println("Email:  (none)")
 	}
+	fmt.Println()
 
-	// Save config
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	best := rules.FindBestMatch(cfg.Rules, path, remoteURL)
+	if best == nil {
+		fmt.Println("No identity rule matches this path.")
+		fmt.Println("Use 'gitch rule add' to create a rule for this directory or remote.")
+		return nil
 	}
 
-	// Print success
-	msg := fmt.Sprintf("Rule removed: %s", pattern)
+	msg := fmt.Sprintf("Matched: %s -> %s (%s, priority %d, specificity %d)",
+		best.Pattern, best.Identity, best.Type, best.Priority, best.Specificity())
 	fmt.Println(ui.SuccessStyle.Render(msg))
 
+	// Show the other matching rules that lost on priority or specificity
+	matches := rules.FindMatches(cfg.Rules, path, remoteURL)
+	if len(matches) <= 1 {
+		return nil
+	}
+
+	fmt.Println()
+	fmt.Println("Also matched (lower or equal priority and specificity):")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  PRIORITY\tSCORE\tTYPE\tPATTERN\tIDENTITY")
+	for _, rule := range matches[1:] {
+		fmt.Fprintf(w, "  %d\t%d\t%s\t%s\t%s\n", rule.Priority, rule.Specificity(), rule.Type, rule.Pattern, rule.Identity)
+	}
+	w.Flush()
+
+	return nil
+}
+
+func runRuleImportRemotes(cmd *cobra.Command, args []string) error {
+	root, err := expandPath(ruleImportRoot)
+	if err != nil {
+		return fmt.Errorf("invalid --root: %w", err)
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if ruleImportUse != "" {
+		if _, err := cfg.GetIdentity(ruleImportUse); err != nil {
+			return fmt.Errorf("identity %q not found; use 'gitch list' to see available identities", ruleImportUse)
+		}
+	}
+
+	repos, err := rules.FindRepoRemotes(root)
+	if err != nil {
+		return fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	groups := rules.GroupByOrg(repos)
+	if len(groups) == 0 {
+		fmt.Printf("No repositories with an origin remote found under %s\n", root)
+		return nil
+	}
+
+	fmt.Printf("Found %d repositories in %d group(s) under %s\n", len(repos), len(groups), root)
+
+	// Decide an identity per group before taking the config lock
+	reader := bufio.NewReader(os.Stdin)
+	var proposed []rules.Rule
+	for _, group := range groups {
+		fmt.Println()
+		fmt.Printf("%s (%d repo(s))\n", group.Pattern, len(group.Dirs))
+		for _, dir := range group.Dirs {
+			fmt.Println(ui.DimStyle.Render("  " + dir))
+		}
+
+		if existing := findRuleByPattern(cfg.Rules, group.Pattern); existing != nil {
+			fmt.Println(ui.DimStyle.Render(fmt.Sprintf("  Already has a rule -> %s, skipping", existing.Identity)))
+			continue
+		}
+		if ruleImportDryRun {
+			continue
+		}
+
+		identity := ruleImportUse
+		if identity == "" {
+			fmt.Print("  Identity (Enter to skip): ")
+			input, err := reader.ReadString('\n')
+			identity = strings.TrimSpace(input)
+			if err != nil && identity == "" {
+				break // stdin closed: skip the rest
+			}
+		}
+		if identity == "" {
+			continue
+		}
+
+		found, err := cfg.GetIdentity(identity)
+		if err != nil {
+			fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("  Identity %q not found, skipping", identity)))
+			continue
+		}
+
+		proposed = append(proposed, rules.Rule{
+			Type:     rules.RemoteRule,
+			Pattern:  group.Pattern,
+			Identity: found.Name,
+		})
+	}
+
+	if len(proposed) == 0 {
+		fmt.Println()
+		fmt.Println("No rules created.")
+		return nil
+	}
+
+	var created []rules.Rule
+	// Reload: the config may have changed while prompting
+	err = updateConfig(func(cfg *config.Config) error {
+		for _, rule := range proposed {
+			if overlapping := cfg.FindOverlappingRules(rule); len(overlapping) > 0 {
+				fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("Warning: %s may overlap with existing rules:", rule.Pattern)))
+				for _, overlap := range overlapping {
+					fmt.Printf("  %s: %s -> %s\n", overlap.Type, overlap.Pattern, overlap.Identity)
+				}
+			}
+			if err := cfg.AddRule(rule); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipped %s: %v\n", rule.Pattern, err)
+				continue
+			}
+			created = append(created, rule)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if quiet {
+		return nil
+	}
+	fmt.Println()
+	fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("Created %d rule(s):", len(created))))
+	for _, rule := range created {
+		fmt.Printf("  %s -> %s\n", rule.Pattern, rule.Identity)
+	}
+	return nil
+}
+
+// findRuleByPattern returns the rule with exactly pattern, or nil
+func findRuleByPattern(ruleList []rules.Rule, pattern string) *rules.Rule {
+	for i := range ruleList {
+		if ruleList[i].Pattern == pattern {
+			return &ruleList[i]
+		}
+	}
 	return nil
 }
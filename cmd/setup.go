@@ -1,16 +1,32 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/orzazade/gitch/internal/config"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
 	"github.com/orzazade/gitch/internal/prompt"
 	"github.com/orzazade/gitch/internal/ui"
 	"github.com/orzazade/gitch/internal/ui/wizard"
 	"github.com/spf13/cobra"
 )
 
+var (
+	setupName           string
+	setupEmail          string
+	setupDefault        bool
+	setupGenerateSSH    bool
+	setupKeyType        string
+	setupGenerateGPG    bool
+	setupForce          bool
+	setupPassphraseFile string
+	setupNonInteractive bool
+)
+
 var setupCmd = &cobra.Command{
 	Use:   "setup",
 	Short: "Interactive setup wizard for creating identities",
@@ -21,17 +37,43 @@ The wizard guides you through:
   2. Setting the email address
   3. Optionally generating an SSH key
   4. Optionally generating a GPG key for commit signing
+  5. Optionally creating a rule for the current directory or remote
+
+Non-interactive mode:
+  Without a terminal (or with --non-interactive), setup creates the identity
+  from flags instead, for dotfile bootstrap scripts. --name and --email are
+  required. Key passphrases are read from --passphrase-file, or from the
+  GITCH_SSH_PASSPHRASE and GITCH_GPG_PASSPHRASE environment variables;
+  when none is given the keys have no passphrase.
 
 Examples:
-  gitch setup`,
+  gitch setup
+  gitch setup --non-interactive --name work --email work@co.com --generate-ssh
+  GITCH_SSH_PASSPHRASE=secret gitch setup -n work -e work@co.com -s --generate-gpg
+  gitch setup -n work -e work@co.com -s --passphrase-file ~/.config/gitch/pass`,
 	RunE: runSetup,
 }
 
 func init() {
 	rootCmd.AddCommand(setupCmd)
+
+	setupCmd.Flags().StringVarP(&setupName, "name", "n", "", "Identity name (non-interactive mode)")
+	setupCmd.Flags().StringVarP(&setupEmail, "email", "e", "", "Email address (non-interactive mode)")
+	setupCmd.Flags().BoolVarP(&setupDefault, "default", "d", false, "Set as default identity (non-interactive mode)")
+	setupCmd.Flags().BoolVarP(&setupGenerateSSH, "generate-ssh", "s", false, "Generate new SSH keypair (non-interactive mode)")
+	setupCmd.Flags().StringVar(&setupKeyType, "key-type", "", "SSH key type: ed25519 (default), rsa, ecdsa, or ecdsa-p384")
+	setupCmd.Flags().BoolVar(&setupGenerateGPG, "generate-gpg", false, "Generate new GPG key for signing (non-interactive mode)")
+	setupCmd.Flags().BoolVar(&setupForce, "force", false, "Overwrite existing SSH key if it exists")
+	setupCmd.Flags().StringVar(&setupPassphraseFile, "passphrase-file", "", "Read the key passphrase from this file")
+	setupCmd.Flags().BoolVar(&setupNonInteractive, "non-interactive", false, "Create the identity from flags without the wizard")
 }
 
 func runSetup(cmd *cobra.Command, args []string) error {
+	// Scripts have no terminal for the wizard
+	if setupNonInteractive || !ui.IsInteractive() {
+		return runSetupNonInteractive()
+	}
+
 	m := wizard.New()
 	p := tea.NewProgram(m)
 
@@ -54,12 +96,6 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Load config
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
 	// Create identity
 	identity := config.Identity{
 		Name:       data.Name,
@@ -68,14 +104,25 @@ func runSetup(cmd *cobra.Command, args []string) error {
 		GPGKeyID:   data.GPGKeyID,
 	}
 
-	// Add identity
-	if err := cfg.AddIdentity(identity); err != nil {
-		return err
-	}
+	ruleAdded := false
+	err = updateConfig(func(cfg *config.Config) error {
+		// Add identity
+		if err := cfg.AddIdentity(identity); err != nil {
+			return err
+		}
 
-	// Save config
-	if err := cfg.Save(); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+		// Add rule (best effort, the identity is still saved)
+		if data.Rule != nil {
+			if err := cfg.AddRule(*data.Rule); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: rule not created: %v\n", err)
+			} else {
+				ruleAdded = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Update prompt cache (wizard creates active identity)
@@ -92,6 +139,9 @@ func runSetup(cmd *cobra.Command, args []string) error {
 	if data.GPGKeyID != "" {
 		fmt.Printf("GPG key: %s\n", data.GPGKeyID)
 	}
+	if ruleAdded {
+		fmt.Printf("Rule: %s (%s)\n", data.Rule.Pattern, data.Rule.Type)
+	}
 
 	// Suggest next steps
 	fmt.Println()
@@ -100,3 +150,122 @@ func runSetup(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// runSetupNonInteractive creates an identity from flags, generating keys
+// without prompting.
+func runSetupNonInteractive() error {
+	if setupName == "" || setupEmail == "" {
+		return errors.New("--name and --email are required when setup runs without a terminal")
+	}
+	if err := config.ValidateName(setupName); err != nil {
+		return err
+	}
+	setupEmail = config.NormalizeEmail(setupEmail)
+	if err := config.ValidateEmail(setupEmail); err != nil {
+		return err
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Fail before generating keys that would be left unused
+	if _, err := cfg.GetIdentity(setupName); err == nil {
+		return fmt.Errorf("identity '%s' already exists", setupName)
+	}
+
+	identity := config.Identity{
+		Name:  setupName,
+		Email: setupEmail,
+	}
+
+	if setupGenerateSSH {
+		keyPath, err := checkSSHKeyPath(setupName, setupForce)
+		if err != nil {
+			return err
+		}
+
+		keyType, err := resolveSSHKeyType(setupKeyType)
+		if err != nil {
+			return err
+		}
+
+		passphrase, err := setupPassphrase("GITCH_SSH_PASSPHRASE")
+		if err != nil {
+			return err
+		}
+
+		if err := generateSSHKey(keyPath, setupEmail, keyType, passphrase); err != nil {
+			return err
+		}
+		identity.SSHKeyPath = keyPath
+	}
+
+	if setupGenerateGPG {
+		if !gpgpkg.IsGPGAvailable() {
+			return errors.New("gpg command not found - install GPG to use signing features")
+		}
+
+		passphrase, err := setupPassphrase("GITCH_GPG_PASSPHRASE")
+		if err != nil {
+			return err
+		}
+
+		keyID, err := generateGPGKey(setupName, setupEmail, passphrase, 0)
+		if err != nil {
+			return err
+		}
+		identity.GPGKeyID = keyID
+	}
+
+	err = updateConfig(func(cfg *config.Config) error {
+		if err := cfg.AddIdentity(identity); err != nil {
+			return err
+		}
+
+		if setupDefault {
+			if err := cfg.SetDefault(setupName); err != nil {
+				return fmt.Errorf("failed to set default: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Same as the wizard: the new identity becomes the prompt's identity
+	_ = prompt.UpdateCache(setupName) // Best effort
+
+	msg := fmt.Sprintf("Created identity '%s' (%s)", setupName, setupEmail)
+	fmt.Println(ui.SuccessStyle.Render(msg))
+	if setupDefault {
+		fmt.Println("Set as default identity")
+	}
+
+	return nil
+}
+
+// setupPassphrase returns the key passphrase for non-interactive setup:
+// the contents of --passphrase-file, else the envVar environment variable.
+// Returns nil (no passphrase) when neither is set.
+func setupPassphrase(envVar string) ([]byte, error) {
+	if setupPassphraseFile != "" {
+		data, err := os.ReadFile(setupPassphraseFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read passphrase file: %w", err)
+		}
+		passphrase := strings.TrimRight(string(data), "\r\n")
+		if passphrase == "" {
+			return nil, nil
+		}
+		return []byte(passphrase), nil
+	}
+
+	if passphrase := os.Getenv(envVar); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/config"
+	"golang.org/x/crypto/ssh"
+)
+
+// setSetupFlags sets the non-interactive setup flags for the test
+func setSetupFlags(t *testing.T, name, email string, generateSSH, isDefault bool, passphraseFile string) {
+	t.Helper()
+	setupName, setupEmail = name, email
+	setupGenerateSSH, setupDefault = generateSSH, isDefault
+	setupKeyType, setupPassphraseFile = "ed25519", passphraseFile
+	t.Cleanup(func() {
+		setupName, setupEmail = "", ""
+		setupGenerateSSH, setupDefault = false, false
+		setupKeyType, setupPassphraseFile = "", ""
+	})
+}
+
+func TestRunSetupNonInteractive_RequiresNameAndEmail(t *testing.T) {
+	useTempHome(t)
+	setSetupFlags(t, "work", "", false, false, "")
+
+	err := runSetupNonInteractive()
+	if err == nil || !strings.Contains(err.Error(), "--name and --email are required") {
+		t.Errorf("runSetupNonInteractive() error = %v, want the missing flags", err)
+	}
+}
+
+func TestRunSetupNonInteractive_CreatesIdentity(t *testing.T) {
+	home := useTempHome(t)
+	passphraseFile := filepath.Join(t.TempDir(), "pass")
+	if err := os.WriteFile(passphraseFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	setSetupFlags(t, "work", " me@Company.com ", true, true, passphraseFile)
+
+	captureStdout(t, func() {
+		if err := runSetupNonInteractive(); err != nil {
+			t.Fatalf("runSetupNonInteractive() error = %v", err)
+		}
+	})
+
+	identity := loadTestIdentity(t, "work")
+	if identity.Email != "me@company.com" {
+		t.Errorf("Email = %q, want the normalized email", identity.Email)
+	}
+	if cfg, err := config.Load(); err != nil || cfg.Default != "work" {
+		t.Errorf("default identity not set: %v", err)
+	}
+	wantKeyPath := filepath.Join(home, ".ssh", "gitch_work_ed25519")
+	if identity.SSHKeyPath != wantKeyPath {
+		t.Errorf("SSHKeyPath = %q, want %q", identity.SSHKeyPath, wantKeyPath)
+	}
+
+	// The key is encrypted with the passphrase from the file, newline trimmed
+	data, err := os.ReadFile(wantKeyPath)
+	if err != nil {
+		t.Fatalf("SSH key not written: %v", err)
+	}
+	var missing *ssh.PassphraseMissingError
+	if _, err := ssh.ParseRawPrivateKey(data); !errors.As(err, &missing) {
+		t.Errorf("ParseRawPrivateKey() error = %v, want the key to need a passphrase", err)
+	}
+	if _, err := ssh.ParseRawPrivateKeyWithPassphrase(data, []byte("secret")); err != nil {
+		t.Errorf("key doesn't open with the passphrase: %v", err)
+	}
+}
+
+func TestRunSetupNonInteractive_ExistingIdentity(t *testing.T) {
+	home := useTempHome(t)
+	configPath := os.Getenv("GITCH_CONFIG")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "identities:\n  - name: work\n    email: me@company.com\n"
+	if err := os.WriteFile(configPath, []byte(existing), 0600); err != nil {
+		t.Fatal(err)
+	}
+	setSetupFlags(t, "work", "other@company.com", true, false, "")
+
+	err := runSetupNonInteractive()
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("runSetupNonInteractive() error = %v, want the identity to exist", err)
+	}
+	if fileExists(filepath.Join(home, ".ssh", "gitch_work_ed25519")) {
+		t.Error("SSH key generated for an identity that can't be added")
+	}
+}
+
+func TestSetupPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	withNewline := writeFile("pass", "from-file\r\n")
+	empty := writeFile("empty", "\n")
+
+	tests := []struct {
+		name    string
+		file    string
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{"nothing set", "", "", "", false},
+		{"env var", "", "from-env", "from-env", false},
+		{"file trims the newline", withNewline, "", "from-file", false},
+		{"file wins over env var", withNewline, "from-env", "from-file", false},
+		{"empty file", empty, "from-env", "", false},
+		{"missing file", filepath.Join(dir, "missing"), "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupPassphraseFile = tt.file
+			t.Cleanup(func() { setupPassphraseFile = "" })
+			t.Setenv("GITCH_SSH_PASSPHRASE", tt.env)
+
+			got, err := setupPassphrase("GITCH_SSH_PASSPHRASE")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setupPassphrase() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("setupPassphrase() = %q, want %q", got, tt.want)
+			}
+			if tt.want == "" && got != nil {
+				t.Errorf("setupPassphrase() = %q, want nil for no passphrase", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/spf13/cobra"
+)
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init <bash|zsh|fish>",
+	Short: "Print shell code that auto-switches identity on directory change",
+	Long: `Print shell code that runs 'gitch auto' whenever you change directory.
+
+With it, entering a directory or repository covered by a rule applies the
+matching identity to that repository. See 'gitch help auto'.
+
+Add the output to your shell configuration file:
+
+Bash (~/.bashrc):
+  eval "$(gitch shell-init bash)"
+
+Zsh (~/.zshrc):
+  eval "$(gitch shell-init zsh)"
+
+Fish (~/.config/fish/config.fish):
+  gitch shell-init fish | source
+
+The generated code calls gitch by the path it was run with, so it also
+works for a binary that is not on PATH. This can be combined with
+'gitch init' for the prompt.`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	RunE:                  runShellInit,
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
+
+func runShellInit(cmd *cobra.Command, args []string) error {
+	script, err := prompt.AutoSwitchInit(args[0], gitchBinaryPath())
+	if err != nil {
+		return err
+	}
+	fmt.Print(script)
+	return nil
+}
+
+// gitchBinaryPath returns the absolute path gitch was invoked as, falling
+// back to os.Args[0] when it can't be resolved
+func gitchBinaryPath() string {
+	bin := os.Args[0]
+	if !strings.ContainsRune(bin, filepath.Separator) {
+		// Invoked via PATH lookup
+		if path, err := exec.LookPath(bin); err == nil {
+			bin = path
+		}
+	}
+	if abs, err := filepath.Abs(bin); err == nil {
+		return abs
+	}
+	return bin
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/orzazade/gitch/internal/config"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	sshpkg "github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <identity>",
+	Short: "Show an identity's details and keys",
+	Long: `Show everything gitch knows about an identity: its email, signing and hook
+settings, and details of its keys.
+
+Each SSH key is shown with its type and fingerprint. The GPG key is looked up
+in the gpg keyring and shown with its algorithm, fingerprint, creation and
+expiry dates. Keys missing from disk or from the keyring are marked instead
+of failing the command.
+
+Examples:
+  gitch show work`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runShow,
+}
+
+func init() {
+	rootCmd.AddCommand(showCmd)
+}
+
+func runShow(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	identity, err := cfg.GetIdentity(args[0])
+	if err != nil {
+		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", args[0])
+	}
+
+	title := identity.Name
+	if strings.EqualFold(identity.Name, cfg.Default) {
+		title += " (default)"
+	}
+	fmt.Println(ui.SuccessStyle.Render(title))
+
+	printShowField("Email", identity.Email)
+	if identity.Description != "" {
+		printShowField("Description", identity.Description)
+	}
+	if len(identity.Tags) > 0 {
+		printShowField("Tags", strings.Join(identity.Tags, ", "))
+	}
+	printShowField("Signing", identity.GetSigningMethod())
+	printShowField("Hook mode", identity.GetHookMode())
+	if len(identity.Hosts) > 0 {
+		printShowField("Hosts", strings.Join(identity.Hosts, ", "))
+	}
+	if identity.HTTPSUser != "" {
+		printShowField("HTTPS user", identity.HTTPSUser)
+	}
+
+	keys := identity.SSHKeys()
+	if len(keys) == 0 {
+		printShowField("SSH key", ui.DimStyle.Render("none"))
+	}
+	for _, keyPath := range keys {
+		printShowField("SSH key", describeSSHKey(keyPath))
+	}
+	if len(keys) > 0 && identity.AgentKeys() == nil {
+		printShowField("ssh-agent", "not used")
+	} else if identity.AgentLifetime > 0 {
+		printShowField("ssh-agent", fmt.Sprintf("keys expire after %s", time.Duration(identity.AgentLifetime)*time.Second))
+	}
+
+	if identity.GPGKeyID == "" {
+		printShowField("GPG key", ui.DimStyle.Render("none"))
+		return nil
+	}
+	printShowField("GPG key", identity.GPGKeyID)
+	if !gpgpkg.IsGPGAvailable() {
+		fmt.Println(ui.WarningStyle.Render("    gpg not installed; key details unavailable"))
+		return nil
+	}
+	info, err := gpgpkg.GetKeyInfo(identity.GPGKeyID)
+	if err != nil {
+		fmt.Println(ui.WarningStyle.Render("    not found in the gpg keyring; import it with 'gitch gpg import <file>'"))
+		return nil
+	}
+	printShowDetail("Algorithm", info.Algorithm)
+	printShowDetail("Fingerprint", info.Fingerprint)
+	if info.Name != "" || info.Email != "" {
+		printShowDetail("User ID", fmt.Sprintf("%s <%s>", info.Name, info.Email))
+	}
+	printShowDetail("Created", info.Created.Format("2006-01-02"))
+	switch {
+	case info.Expires == nil:
+		printShowDetail("Expires", "never")
+	case info.IsExpired(time.Now()):
+		printShowDetail("Expires", ui.WarningStyle.Render(info.Expires.Format("2006-01-02")+" (expired)"))
+	default:
+		printShowDetail("Expires", info.Expires.Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+// describeSSHKey returns the key path with its type and fingerprint, or a
+// note when the key can't be read
+func describeSSHKey(keyPath string) string {
+	expandedPath, err := expandPath(keyPath)
+	if err != nil {
+		return keyPath + ui.WarningStyle.Render(" (invalid path)")
+	}
+
+	data, err := os.ReadFile(expandedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return keyPath + ui.WarningStyle.Render(" (not found)")
+		}
+		return keyPath + ui.WarningStyle.Render(" (unreadable)")
+	}
+
+	var details []string
+	if keyType, err := sshpkg.GetKeyType(data); err == nil {
+		details = append(details, keyType.String())
+	}
+	if fingerprint, err := sshpkg.KeyFingerprint(keyPath); err == nil {
+		details = append(details, fingerprint)
+	}
+	if len(details) == 0 {
+		return keyPath
+	}
+	return fmt.Sprintf("%s (%s)", keyPath, strings.Join(details, ", "))
+}
+
+// printShowField prints a top-level field of 'gitch show'
+func printShowField(label, value string) {
+	fmt.Printf("  %-12s %s\n", label+":", value)
+}
+
+// printShowDetail prints a key detail, indented under its key
+func printShowDetail(label, value string) {
+	fmt.Printf("    %-12s %s\n", label+":", value)
+}
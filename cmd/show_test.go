@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/gpg/gpgtest"
+)
+
+func TestDescribeSSHKey(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_work")
+	writeTestSSHKey(t, keyPath)
+
+	got := describeSSHKey(keyPath)
+	if !strings.HasPrefix(got, keyPath+" (ed25519, SHA256:") {
+		t.Errorf("describeSSHKey() = %q, want the key type and fingerprint", got)
+	}
+
+	missingPath := filepath.Join(dir, "id_missing")
+	if got := describeSSHKey(missingPath); !strings.Contains(got, missingPath) || !strings.Contains(got, "(not found)") {
+		t.Errorf("describeSSHKey() = %q, want the missing key marked", got)
+	}
+}
+
+// runShowWith runs 'gitch show name' and returns its output
+func runShowWith(t *testing.T, name string) string {
+	t.Helper()
+	return captureStdout(t, func() {
+		if err := runShow(showCmd, []string{name}); err != nil {
+			t.Fatalf("runShow() error = %v", err)
+		}
+	})
+}
+
+func TestRunShow(t *testing.T) {
+	keyID, _ := armoredTestKey(t, "me@company.com")
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "id_work")
+	writeTestSSHKey(t, keyPath)
+	useTestConfig(t, `default: work
+identities:
+  - name: work
+    email: me@company.com
+    tags: [client-a, oss]
+    ssh_key_path: `+keyPath+`
+    gpg_key_id: `+keyID+`
+`)
+
+	out := runShowWith(t, "work")
+	for _, want := range []string{
+		"work (default)",
+		"me@company.com",
+		"client-a, oss",
+		keyPath + " (ed25519, SHA256:",
+		"GPG key:     " + keyID,
+		"Algorithm:   ed25519",
+		"Fingerprint: ",
+		"<me@company.com>",
+		"Expires:     never",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunShow_MissingKeys(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+	keyPath := filepath.Join(t.TempDir(), "id_gone")
+	useTestConfig(t, `identities:
+  - name: work
+    email: me@company.com
+    ssh_key_path: `+keyPath+`
+    gpg_key_id: 0123456789ABCDEF
+`)
+
+	out := runShowWith(t, "work")
+	for _, want := range []string{
+		keyPath + " (not found)",
+		"GPG key:     0123456789ABCDEF",
+		"not found in the gpg keyring",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Fingerprint:") {
+		t.Errorf("output shows key details for a missing key:\n%s", out)
+	}
+}
+
+func TestRunShow_UnknownIdentity(t *testing.T) {
+	useTestConfig(t, "identities: []\n")
+
+	err := runShow(showCmd, []string{"work"})
+	if err == nil || !strings.Contains(err.Error(), "identity 'work' not found") {
+		t.Errorf("runShow() error = %v, want the identity not found", err)
+	}
+}
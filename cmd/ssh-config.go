@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/orzazade/gitch/internal/config"
 	"github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -14,8 +19,9 @@ var sshConfigCmd = &cobra.Command{
 	Long: `Generate and manage SSH config Host blocks for your identities.
 
 gitch can generate SSH config Host aliases that allow you to use different
-SSH keys for different GitHub/GitLab accounts. Each identity with an SSH key
-will get Host aliases like "github-<name>" and "gitlab-<name>".
+SSH keys for different GitHub/GitLab/Bitbucket/Azure DevOps accounts. Each
+identity with an SSH key will get Host aliases like "github-<name>",
+"gitlab-<name>", "bitbucket-<name>" and "azure-<name>".
 
 This enables you to clone repositories using the identity-specific host alias:
   git clone git@github-work:company/repo.git
@@ -23,14 +29,22 @@ This enables you to clone repositories using the identity-specific host alias:
 Commands:
   generate    Print SSH config Host blocks to stdout
   update      Write Host blocks to ~/.ssh/config with backup
+  remove      Remove the gitch-managed block from ~/.ssh/config
+  status      Check whether the managed block matches your identities
 
 Examples:
   gitch ssh-config generate
   gitch ssh-config update
-  gitch ssh-config update --dry-run`,
+  gitch ssh-config update --dry-run
+  gitch ssh-config update --include-file ~/.ssh/config.d/gitch
+  gitch ssh-config remove
+  gitch ssh-config status`,
 }
 
-var sshConfigDryRun bool
+var (
+	sshConfigDryRun      bool
+	sshConfigIncludeFile string
+)
 
 var sshConfigGenerateCmd = &cobra.Command{
 	Use:   "generate",
@@ -40,8 +54,10 @@ var sshConfigGenerateCmd = &cobra.Command{
 The output can be manually added to ~/.ssh/config, or you can use the
 'update' command to automatically apply the changes with a backup.
 
-Each identity with an SSH key gets Host aliases for github.com and gitlab.com,
-allowing you to use different SSH keys for different accounts.
+Each identity with an SSH key gets Host aliases for github.com, gitlab.com,
+bitbucket.org and ssh.dev.azure.com, allowing you to use different SSH keys
+for different accounts. Identities with custom hosts (gitch add --host) get
+one alias per host instead, e.g. "git.company-<name>" for git.company.internal.
 
 Example output:
   Host github-work
@@ -62,26 +78,80 @@ This command safely modifies ~/.ssh/config by:
 2. Removing any existing gitch-managed block
 3. Appending the new Host blocks wrapped in markers
 
+If the managed block is already up to date, nothing is written and no
+backup is made. Otherwise a diff of the managed section is printed.
+
 The gitch-managed section is identified by markers:
   # gitch:start - MANAGED BY GITCH, DO NOT EDIT
   ... host blocks ...
   # gitch:end
 
+With --include-file, the block is written to a file of its own instead, and
+an "Include <file>" line is added at the top of ~/.ssh/config unless an
+existing top-level Include (such as "Include config.d/*") already covers it.
+Any inline block is removed. gitch remembers the file, so later updates
+write to it without the flag; 'gitch ssh-config remove' switches back.
+An existing include file is only changed between the gitch markers; one
+without them is left alone and the update fails.
+
 Use --dry-run to preview changes without modifying files.
 
 Examples:
   gitch ssh-config update              # Apply changes
-  gitch ssh-config update --dry-run    # Preview only`,
+  gitch ssh-config update --dry-run    # Preview only
+  gitch ssh-config update --include-file ~/.ssh/config.d/gitch`,
 	RunE: runSSHConfigUpdate,
 }
 
+var sshConfigRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove the gitch-managed block from ~/.ssh/config",
+	Long: `Remove the gitch-managed Host blocks from your SSH config file.
+
+Everything between the gitch markers is removed; the rest of the file is
+left as is. A backup is saved to ~/.ssh/config.gitch.backup first.
+
+If the block was written with --include-file, the block is removed from
+the include file. If nothing else is left in it, the file is deleted and the
+Include line gitch added is removed from ~/.ssh/config.
+
+If the start marker has no matching end marker, nothing is changed and you
+need to fix the file by hand.
+
+Examples:
+  gitch ssh-config remove`,
+	Args: cobra.NoArgs,
+	RunE: runSSHConfigRemove,
+}
+
+var sshConfigStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check whether the managed block matches your identities",
+	Long: `Check whether the gitch-managed block in ~/.ssh/config, or in the file set
+with 'update --include-file', still matches the Host blocks your identities
+would generate. Manual edits, restored backups or identities changed without
+a refresh all make the two drift apart.
+
+If they differ, a diff from the current block to the expected one is shown;
+run 'gitch ssh-config update' to apply it. Nothing is modified.
+
+Examples:
+  gitch ssh-config status`,
+	Args: cobra.NoArgs,
+	RunE: runSSHConfigStatus,
+}
+
 func init() {
 	rootCmd.AddCommand(sshConfigCmd)
 	sshConfigCmd.AddCommand(sshConfigGenerateCmd)
 	sshConfigCmd.AddCommand(sshConfigUpdateCmd)
+	sshConfigCmd.AddCommand(sshConfigRemoveCmd)
+	sshConfigCmd.AddCommand(sshConfigStatusCmd)
 
 	// Flags for update command
 	sshConfigUpdateCmd.Flags().BoolVar(&sshConfigDryRun, "dry-run", false, "Show what would be written without modifying files")
+	sshConfigUpdateCmd.Flags().StringVar(&sshConfigIncludeFile, "include-file", "", "Write the block to this file and Include it from ~/.ssh/config")
+	_ = sshConfigUpdateCmd.MarkFlagFilename("include-file")
 }
 
 // collectHosts gathers HostConfigs from all identities with SSH keys
@@ -148,17 +218,52 @@ func runSSHConfigUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to determine SSH config path: %w", err)
 	}
 
+	// An explicit --include-file wins over the one remembered in config
+	includePath := cfg.SSHConfigInclude
+	if sshConfigIncludeFile != "" {
+		includePath, err = ssh.NormalizeIncludePath(sshConfigIncludeFile)
+		if err != nil {
+			return fmt.Errorf("invalid --include-file: %w", err)
+		}
+	}
+
 	// Handle dry-run
 	if sshConfigDryRun {
-		fmt.Printf("Would write to: %s\n\n", configPath)
+		fmt.Printf("Would write to: %s\n", cmp.Or(includePath, configPath))
+		if includePath != "" {
+			fmt.Printf("Included from %s with: Include %s\n", configPath, includePath)
+		}
+		fmt.Println()
 		fmt.Print(block)
 		return nil
 	}
 
+	if includePath != "" {
+		return updateSSHConfigInclude(cfg, includePath, block)
+	}
+
+	// Keep the current block to show what changed
+	oldBlock, err := ssh.ReadManagedBlock()
+	if err != nil {
+		return err
+	}
+
 	// Update the SSH config
-	if err := ssh.UpdateSSHConfig(block); err != nil {
+	changed, err := ssh.UpdateSSHConfig(block)
+	if err != nil {
 		return fmt.Errorf("failed to update SSH config: %w", err)
 	}
+	if !changed {
+		printInfo("%s is already up to date", configPath)
+		return nil
+	}
+	if quiet {
+		return nil
+	}
+
+	// Show the change to the managed section
+	printDiff(ssh.UnifiedDiff(configPath+" (before)", configPath+" (after)", oldBlock, block))
+	fmt.Println()
 
 	// Print success
 	fmt.Printf("Updated %s\n", configPath)
@@ -166,3 +271,258 @@ func runSSHConfigUpdate(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// updateSSHConfigInclude writes the managed block to includePath, makes sure
+// ~/.ssh/config includes it and remembers the file in the gitch config
+func updateSSHConfigInclude(cfg *config.Config, includePath, block string) error {
+	// Keep the current block to show what changed; when switching from an
+	// inline block, that's the one in ~/.ssh/config
+	oldBlock, err := ssh.ReadManagedBlockFile(includePath)
+	if err != nil {
+		return err
+	}
+	if oldBlock == "" {
+		if oldBlock, err = ssh.ReadManagedBlock(); err != nil {
+			return err
+		}
+	}
+
+	// Don't leave a block behind in a previous include file
+	if cfg.SSHConfigInclude != "" && cfg.SSHConfigInclude != includePath {
+		if _, err := ssh.RemoveSSHConfigInclude(cfg.SSHConfigInclude); err != nil {
+			return fmt.Errorf("failed to remove previous include file: %w", err)
+		}
+	}
+
+	changed, err := ssh.UpdateSSHConfigInclude(includePath, block)
+	if err != nil {
+		return fmt.Errorf("failed to update SSH config: %w", err)
+	}
+
+	if cfg.SSHConfigInclude != includePath {
+		if err := setSSHConfigInclude(includePath); err != nil {
+			return err
+		}
+	}
+
+	if !changed {
+		printInfo("%s is already up to date", includePath)
+		return nil
+	}
+	if quiet {
+		return nil
+	}
+
+	// Show the change to the managed section
+	printDiff(ssh.UnifiedDiff(includePath+" (before)", includePath+" (after)", oldBlock, block))
+	fmt.Println()
+
+	configPath, _ := ssh.SSHConfigPath()
+	fmt.Printf("Updated %s\n", includePath)
+	fmt.Printf("Included from: %s\n", configPath)
+
+	return nil
+}
+
+// describeIncludeRemoval describes what ssh.RemoveSSHConfigInclude did with
+// includePath: deleted it, or kept it because the user has other hosts in it
+func describeIncludeRemoval(includePath, configPath string) string {
+	if includeFile, err := expandPath(includePath); err == nil && fileExists(includeFile) {
+		return fmt.Sprintf("Removed the gitch-managed block from %s (the rest of the file is kept)", includePath)
+	}
+	return fmt.Sprintf("Removed %s and its Include line from %s", includePath, configPath)
+}
+
+// setSSHConfigInclude saves the include file the managed block lives in,
+// or "" for an inline block
+func setSSHConfigInclude(includePath string) error {
+	return config.WithLock(func() error {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		cfg.SSHConfigInclude = includePath
+
+		// Save config
+		if err := cfg.Save(); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		return nil
+	})
+}
+
+func runSSHConfigRemove(cmd *cobra.Command, args []string) error {
+	configPath, err := ssh.SSHConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine SSH config path: %w", err)
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	includeRemoved := false
+	if cfg.SSHConfigInclude != "" {
+		includeRemoved, err = ssh.RemoveSSHConfigInclude(cfg.SSHConfigInclude)
+		if err != nil {
+			return fmt.Errorf("failed to remove gitch include file: %w", err)
+		}
+		if err := setSSHConfigInclude(""); err != nil {
+			return err
+		}
+		if includeRemoved {
+			printSuccess(describeIncludeRemoval(cfg.SSHConfigInclude, configPath))
+		}
+	}
+
+	removed, err := ssh.RemoveSSHConfigBlock()
+	if errors.Is(err, ssh.ErrMalformedBlock) {
+		fmt.Fprintf(os.Stderr, "Warning: %s contains %q but no %q after it\n", configPath, ssh.MarkerStart, ssh.MarkerEnd)
+		return fmt.Errorf("refusing to modify %s; remove the gitch lines by hand", configPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove gitch block: %w", err)
+	}
+
+	if !removed {
+		if !includeRemoved {
+			printInfo("No gitch-managed block found in %s", configPath)
+		}
+		return nil
+	}
+
+	printSuccess(fmt.Sprintf("Removed gitch-managed block from %s", configPath))
+	printInfo("Backup saved to: %s.gitch.backup", configPath)
+
+	return nil
+}
+
+func runSSHConfigStatus(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	configPath, err := ssh.SSHConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine SSH config path: %w", err)
+	}
+
+	// Find the current block, inline or in the include file
+	path := configPath
+	var current string
+	if cfg.SSHConfigInclude != "" {
+		path = cfg.SSHConfigInclude
+		if current, err = ssh.ReadManagedBlockFile(path); err != nil {
+			return err
+		}
+	} else {
+		if current, err = ssh.ReadManagedBlock(); err != nil {
+			return err
+		}
+		if managed, err := ssh.HasManagedBlock(); err != nil {
+			return err
+		} else if managed && current == "" {
+			return fmt.Errorf("%s contains %q but no %q after it; fix the file by hand or run 'gitch ssh-config remove'", configPath, ssh.MarkerStart, ssh.MarkerEnd)
+		}
+	}
+
+	var expected string
+	if hosts := collectHosts(cfg); len(hosts) > 0 {
+		expected = ssh.GenerateConfigBlock(hosts)
+	}
+
+	switch {
+	case current == "" && expected == "":
+		printInfo("No identities with SSH keys and no gitch-managed block in %s", path)
+		return nil
+	case current == "":
+		printInfo("No gitch-managed block in %s. Run 'gitch ssh-config update' to add one.", path)
+		return nil
+	case current == expected:
+		printSuccess(fmt.Sprintf("%s is up to date", path))
+	default:
+		fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("%s is out of date:", path)))
+		fmt.Println()
+		printDiff(ssh.UnifiedDiff(path+" (current)", path+" (expected)", current, expected))
+		fmt.Println()
+		if expected == "" {
+			fmt.Println("No identities have SSH keys; run 'gitch ssh-config remove' to remove the block.")
+		} else {
+			fmt.Println("Run 'gitch ssh-config update' to apply the changes.")
+		}
+	}
+
+	if cfg.SSHConfigInclude != "" {
+		included, err := ssh.IsIncluded(cfg.SSHConfigInclude)
+		if err != nil {
+			return err
+		}
+		if !included {
+			fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("%s is not included from %s. Run 'gitch ssh-config update' to add the Include line.", cfg.SSHConfigInclude, configPath)))
+		}
+	}
+
+	return nil
+}
+
+// refreshSSHConfig rewrites the gitch-managed block in ~/.ssh/config, or in
+// the include file it was moved to, from the current identities. It does
+// nothing unless 'gitch ssh-config update' has been run before, so commands
+// never add a block the user didn't ask for. Returns the path of the file
+// that was updated, or "" if nothing changed.
+func refreshSSHConfig(cfg *config.Config) (string, error) {
+	hosts := collectHosts(cfg)
+
+	if cfg.SSHConfigInclude != "" {
+		if len(hosts) == 0 {
+			return "", nil
+		}
+		changed, err := ssh.UpdateSSHConfigInclude(cfg.SSHConfigInclude, ssh.GenerateConfigBlock(hosts))
+		if err != nil {
+			return "", fmt.Errorf("failed to update SSH config: %w", err)
+		}
+		if !changed {
+			return "", nil
+		}
+		return cfg.SSHConfigInclude, nil
+	}
+
+	managed, err := ssh.HasManagedBlock()
+	if err != nil || !managed {
+		return "", err
+	}
+	if len(hosts) == 0 {
+		return "", nil
+	}
+
+	changed, err := ssh.UpdateSSHConfig(ssh.GenerateConfigBlock(hosts))
+	if err != nil {
+		return "", fmt.Errorf("failed to update SSH config: %w", err)
+	}
+	if !changed {
+		return "", nil
+	}
+	return ssh.SSHConfigPath()
+}
+
+// printDiff prints a unified diff, coloring added and removed lines
+func printDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"), strings.HasPrefix(line, "@@"):
+			fmt.Println(ui.DimStyle.Render(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Println(ui.SuccessStyle.Render(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Println(ui.ErrorStyle.Render(line))
+		default:
+			fmt.Println(line)
+		}
+	}
+}
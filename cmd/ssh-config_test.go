@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/ssh"
+)
+
+func TestRunSSHConfigStatus(t *testing.T) {
+	const identities = `identities:
+  - name: work
+    email: me@company.com
+    ssh_key_path: ~/.ssh/id_work
+`
+	staleBlock := ssh.MarkerStart + "\nHost github.com-old\n  HostName github.com\n" + ssh.MarkerEnd + "\n"
+
+	tests := []struct {
+		name      string
+		config    string
+		sshConfig func(expected string) string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "nothing to manage",
+			config:    "identities: []\n",
+			sshConfig: func(string) string { return "Host example\n" },
+			want:      []string{"No identities with SSH keys and no gitch-managed block"},
+		},
+		{
+			name:      "missing block",
+			config:    identities,
+			sshConfig: func(string) string { return "Host example\n" },
+			want:      []string{"No gitch-managed block", "gitch ssh-config update"},
+		},
+		{
+			name:      "up to date",
+			config:    identities,
+			sshConfig: func(expected string) string { return "Host example\n\n" + expected },
+			want:      []string{"is up to date"},
+		},
+		{
+			name:      "drift",
+			config:    identities,
+			sshConfig: func(string) string { return "Host example\n\n" + staleBlock },
+			want:      []string{"is out of date", "-Host github.com-old", "+Host github-work", "Run 'gitch ssh-config update'"},
+		},
+		{
+			name:      "block left after the keys are gone",
+			config:    "identities: []\n",
+			sshConfig: func(string) string { return staleBlock },
+			want:      []string{"is out of date", "run 'gitch ssh-config remove'"},
+		},
+		{
+			name:      "malformed block",
+			config:    identities,
+			sshConfig: func(string) string { return "Host example\n" + ssh.MarkerStart + "\nHost github.com-work\n" },
+			wantErr:   "but no",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := useTempHome(t)
+			configPath := os.Getenv("GITCH_CONFIG")
+			if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(configPath, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				t.Fatalf("config.Load() error = %v", err)
+			}
+			var expected string
+			if hosts := collectHosts(cfg); len(hosts) > 0 {
+				expected = ssh.GenerateConfigBlock(hosts)
+			}
+
+			sshConfig := filepath.Join(home, ".ssh", "config")
+			if err := os.MkdirAll(filepath.Dir(sshConfig), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(sshConfig, []byte(tt.sshConfig(expected)), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			var runErr error
+			out := captureStdout(t, func() {
+				runErr = runSSHConfigStatus(sshConfigStatusCmd, nil)
+			})
+			if tt.wantErr != "" {
+				if runErr == nil || !strings.Contains(runErr.Error(), tt.wantErr) {
+					t.Errorf("runSSHConfigStatus() error = %v, want %q", runErr, tt.wantErr)
+				}
+				return
+			}
+			if runErr != nil {
+				t.Fatalf("runSSHConfigStatus() error = %v", runErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output missing %q:\n%s", want, out)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var sshCmd = &cobra.Command{
+	Use:   "ssh",
+	Short: "Inspect SSH keys and the ssh-agent",
+	Long: `Inspect SSH keys used by gitch identities and the running ssh-agent.
+
+Examples:
+  gitch ssh list-agent`,
+}
+
+var sshListAgentCmd = &cobra.Command{
+	Use:   "list-agent",
+	Short: "List keys loaded in ssh-agent",
+	Long: `List the keys currently loaded in ssh-agent.
+
+Keys belonging to a gitch identity are marked with the identity name,
+matched by fingerprint. Use this to diagnose "wrong key offered" problems:
+SSH tries agent keys in order, so an unrelated key loaded first may be
+accepted by the server for the wrong account.
+
+Examples:
+  gitch ssh list-agent`,
+	Args: cobra.NoArgs,
+	RunE: runSSHListAgent,
+}
+
+func init() {
+	rootCmd.AddCommand(sshCmd)
+	sshCmd.AddCommand(sshListAgentCmd)
+}
+
+func runSSHListAgent(cmd *cobra.Command, args []string) error {
+	keys, err := ssh.ListAgentKeys()
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		fmt.Println("No keys loaded in ssh-agent.")
+		return nil
+	}
+
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Map identity key fingerprints to identity names
+	owners := make(map[string]string)
+	for _, id := range cfg.ListIdentities() {
+		for _, keyPath := range id.SSHKeys() {
+			fingerprint, err := ssh.KeyFingerprint(keyPath)
+			if err != nil {
+				// Encrypted key without .pub file, or missing key
+				continue
+			}
+			owners[fingerprint] = id.Name
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FINGERPRINT\tTYPE\tCOMMENT\tIDENTITY")
+	for _, key := range keys {
+		owner := ui.DimStyle.Render("-")
+		if name, ok := owners[key.Fingerprint]; ok {
+			owner = ui.SuccessStyle.Render(name)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.Fingerprint, key.Type, key.Comment, owner)
+	}
+	w.Flush()
+
+	return nil
+}
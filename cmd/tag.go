@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <identity> [+tag|-tag]...",
+	Short: "Add or remove tags on an identity",
+	Long: `Add or remove the tags used to group identities, e.g. by client.
+
+Each argument after the identity edits its tags: +tag (or just tag) adds one,
+-tag removes one. With no edits, the identity's tags are printed.
+
+Tags filter 'gitch list --tag', the selector of 'gitch use --tag' and
+'gitch export --tag'. They contain letters, digits, '.', '_' and '-', and
+are matched case-insensitively.
+
+Examples:
+  gitch tag acme +client-a +contract
+  gitch tag acme -contract
+  gitch tag acme`,
+	// Flag parsing is off so -tag isn't taken for a flag; runTag parses the
+	// known flags itself
+	DisableFlagParsing: true,
+	ValidArgsFunction:  tagArgsCompletionFunc,
+	RunE:               runTag,
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	// Parse the flags cobra skipped; other -tag arguments are edits
+	flags, edits := splitTagArgs(cmd, args)
+	cmd.Flags().AddFlagSet(cmd.InheritedFlags())
+	if err := cmd.Flags().Parse(flags); err != nil {
+		return err
+	}
+	if help, _ := cmd.Flags().GetBool("help"); help {
+		return cmd.Help()
+	}
+	initColor()
+	if len(edits) == 0 {
+		return errors.New("an identity name is required; see 'gitch tag --help'")
+	}
+	name, edits := edits[0], edits[1:]
+
+	if len(edits) == 0 {
+		// Load config
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		identity, err := cfg.GetIdentity(name)
+		if err != nil {
+			return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", name)
+		}
+		if len(identity.Tags) == 0 {
+			printInfo("'%s' has no tags", identity.Name)
+			return nil
+		}
+		fmt.Println(strings.Join(identity.Tags, "\n"))
+		return nil
+	}
+
+	// Validate all edits before changing anything
+	for _, edit := range edits {
+		tag, _ := parseTagEdit(edit)
+		if err := config.ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	var added, removed []string
+	storedName, err := updateIdentityField(name, func(identity *config.Identity) {
+		for _, edit := range edits {
+			tag, remove := parseTagEdit(edit)
+			if remove && identity.RemoveTag(tag) {
+				removed = append(removed, tag)
+			} else if !remove && identity.AddTag(tag) {
+				added = append(added, tag)
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		printInfo("Tags of '%s' unchanged", storedName)
+		return nil
+	}
+
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, "added "+strings.Join(added, ", "))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, "removed "+strings.Join(removed, ", "))
+	}
+	printSuccess(fmt.Sprintf("Tags of '%s': %s", storedName, strings.Join(changes, "; ")))
+	return nil
+}
+
+// splitTagArgs separates the flags in the arguments of 'gitch tag' from the
+// identity and tag edits. An argument is a flag if it starts with "--" or is
+// a known shorthand such as -q; everything after "--" is an edit.
+func splitTagArgs(cmd *cobra.Command, args []string) (flags, edits []string) {
+	for i, arg := range args {
+		if arg == "--" {
+			return flags, append(edits, args[i+1:]...)
+		}
+		if strings.HasPrefix(arg, "--") || isShorthandFlag(cmd, arg) {
+			flags = append(flags, arg)
+		} else {
+			edits = append(edits, arg)
+		}
+	}
+	return flags, edits
+}
+
+// isShorthandFlag reports whether arg is a shorthand flag of cmd, like -q
+// or -q=true, rather than a -tag edit
+func isShorthandFlag(cmd *cobra.Command, arg string) bool {
+	name, ok := strings.CutPrefix(arg, "-")
+	if !ok {
+		return false
+	}
+	name, _, _ = strings.Cut(name, "=")
+	if len(name) != 1 {
+		return false
+	}
+	return cmd.Flags().ShorthandLookup(name) != nil || cmd.InheritedFlags().ShorthandLookup(name) != nil
+}
+
+// parseTagEdit splits a 'gitch tag' argument into the tag and whether it
+// is removed (-tag) rather than added (+tag or tag)
+func parseTagEdit(edit string) (string, bool) {
+	if tag, ok := strings.CutPrefix(edit, "-"); ok {
+		return tag, true
+	}
+	return strings.TrimPrefix(edit, "+"), false
+}
+
+// knownTags returns every tag used by an identity, sorted and without
+// case-insensitive duplicates
+func knownTags(cfg *config.Config) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, identity := range cfg.Identities {
+		for _, tag := range identity.Tags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// tagCompletionFunc completes the tags in use for --tag flags
+func tagCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return knownTags(cfg), cobra.ShellCompDirectiveNoFileComp
+}
+
+// tagArgsCompletionFunc completes 'gitch tag': the identity first, then
+// +tag for tags it doesn't have and -tag for the ones it has
+func tagArgsCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return identityCompletionFunc(cmd, args, toComplete)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	identity, err := cfg.GetIdentity(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, tag := range knownTags(cfg) {
+		if !identity.HasTag(tag) {
+			completions = append(completions, "+"+tag)
+		}
+	}
+	for _, tag := range identity.Tags {
+		completions = append(completions, "-"+tag)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
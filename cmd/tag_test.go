@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitTagArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantFlags []string
+		wantEdits []string
+	}{
+		{"edits only", []string{"work", "+oss", "-client-a"}, nil, []string{"work", "+oss", "-client-a"}},
+		{"long flags", []string{"work", "--quiet=true", "-oss", "--no-color"}, []string{"--quiet=true", "--no-color"}, []string{"work", "-oss"}},
+		{"shorthand flag", []string{"-q", "work", "-oss"}, []string{"-q"}, []string{"work", "-oss"}},
+		{"shorthand with value", []string{"work", "-q=false"}, []string{"-q=false"}, []string{"work"}},
+		{"edits after --", []string{"--quiet", "work", "--", "-q"}, []string{"--quiet"}, []string{"work", "-q"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, edits := splitTagArgs(tagCmd, tt.args)
+			if !slices.Equal(flags, tt.wantFlags) || !slices.Equal(edits, tt.wantEdits) {
+				t.Errorf("splitTagArgs() = %q, %q; want %q, %q", flags, edits, tt.wantFlags, tt.wantEdits)
+			}
+		})
+	}
+}
+
+func TestRunTag_QuietFlagWithValue(t *testing.T) {
+	useTestConfig(t, "identities:\n  - name: work\n    email: me@company.com\n    tags: [oss]\n")
+	t.Cleanup(func() { quiet = false })
+
+	out := captureStdout(t, func() {
+		if err := runTag(tagCmd, []string{"work", "--quiet=true", "+client-a", "-oss"}); err != nil {
+			t.Fatalf("runTag() error = %v", err)
+		}
+	})
+	if !quiet || out != "" {
+		t.Errorf("quiet = %v, output %q; want --quiet=true to silence the command", quiet, out)
+	}
+	if tags := loadTestIdentity(t, "work").Tags; !slices.Equal(tags, []string{"client-a"}) {
+		t.Errorf("Tags = %q, want [client-a]", tags)
+	}
+}
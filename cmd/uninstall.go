@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/history"
+	"github.com/orzazade/gitch/internal/hooks"
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	uninstallYes          bool
+	uninstallDeleteConfig bool
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:     "uninstall",
+	Aliases: []string{"purge"},
+	Short:   "Remove gitch's hooks, SSH config block and cached state",
+	Long: `Remove what gitch has set up outside its config, so gitch can be deleted
+cleanly:
+
+  - the global hooks (core.hooksPath and the gitch hooks directory)
+  - the hooks in the current repository, restoring any hooks they replaced
+  - the gitch-managed block in ~/.ssh/config, or its include file
+  - the prompt cache and the switch history
+
+Steps with nothing to remove are skipped. The config file is kept unless
+--delete-config is given, which also deletes its backups (config.yaml.*.bak)
+and lock file. SSH and GPG keys are never deleted, and neither is
+the git identity currently set in ~/.gitconfig.
+
+Lines added to shell rc files for 'gitch shell-init' or 'gitch prompt', and
+a credential.helper set for 'gitch git-credential', must be removed by hand.
+
+Prompts for confirmation unless --yes is given.
+
+Examples:
+  gitch uninstall
+  gitch uninstall --delete-config --yes`,
+	Args: cobra.NoArgs,
+	RunE: runUninstall,
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Skip confirmation prompt")
+	uninstallCmd.Flags().BoolVar(&uninstallDeleteConfig, "delete-config", false, "Also delete the gitch config file")
+}
+
+func runUninstall(cmd *cobra.Command, args []string) error {
+	message := "Remove gitch hooks, its SSH config block and cached state?"
+	if uninstallDeleteConfig {
+		message = "Remove gitch hooks, its SSH config block, cached state and the config file?"
+	}
+	confirmed, err := ui.ConfirmPrompt(message+" Keys are not touched.", uninstallYes)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+
+	// Load config (for the SSH include file); a broken config doesn't stop
+	// the rest of the cleanup
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
+		cfg = &config.Config{}
+	}
+
+	failed := 0
+	fail := func(step string, err error) {
+		fmt.Fprintf(os.Stderr, "Warning: failed to %s: %v\n", step, err)
+		failed++
+	}
+
+	// Hooks
+	if status, err := hooks.IsInstalled(); err != nil {
+		fail("check hook status", err)
+	} else {
+		if status.Global {
+			if err := hooks.UninstallGlobal(); err != nil {
+				fail("remove global hooks", err)
+			} else {
+				printSuccess("Removed global hooks (core.hooksPath)")
+			}
+		} else {
+			printUninstallSkip("Global hooks not installed")
+		}
+
+		if status.Local {
+			if err := hooks.UninstallLocal(); err != nil {
+				fail("remove repository hooks", err)
+			} else {
+				printSuccess("Removed hooks from this repository")
+			}
+		}
+	}
+
+	// SSH config
+	if cfg.SSHConfigInclude != "" {
+		if removed, err := ssh.RemoveSSHConfigInclude(cfg.SSHConfigInclude); err != nil {
+			fail("remove SSH config include file", err)
+		} else {
+			if removed {
+				configPath, _ := ssh.SSHConfigPath()
+				printSuccess(describeIncludeRemoval(cfg.SSHConfigInclude, configPath))
+			}
+			if !uninstallDeleteConfig {
+				if err := setSSHConfigInclude(""); err != nil {
+					fail("update config", err)
+				}
+			}
+		}
+	}
+	removed, err := ssh.RemoveSSHConfigBlock()
+	switch {
+	case errors.Is(err, ssh.ErrMalformedBlock):
+		fail("remove SSH config block", fmt.Errorf("%w; remove the gitch lines by hand", err))
+	case err != nil:
+		fail("remove SSH config block", err)
+	case removed:
+		configPath, _ := ssh.SSHConfigPath()
+		printSuccess(fmt.Sprintf("Removed gitch-managed block from %s (backup: %s.gitch.backup)", configPath, configPath))
+	case cfg.SSHConfigInclude == "":
+		printUninstallSkip("No gitch-managed SSH config block")
+	}
+
+	// Cached state
+	if cachePath, err := prompt.CachePath(); err == nil && !fileExists(cachePath) {
+		printUninstallSkip("No prompt cache")
+	} else if err := prompt.ClearCache(); err != nil {
+		fail("clear prompt cache", err)
+	} else {
+		printSuccess("Cleared prompt cache")
+	}
+	if historyPath, err := history.Path(); err == nil && !fileExists(historyPath) {
+		printUninstallSkip("No switch history")
+	} else if err := history.Clear(); err != nil {
+		fail("clear switch history", err)
+	} else {
+		printSuccess("Cleared switch history")
+	}
+
+	// Config
+	configPath, err := config.ConfigPath()
+	switch {
+	case err != nil:
+		fail("determine config path", err)
+	case !uninstallDeleteConfig:
+		printInfo("Kept config at %s (use --delete-config to delete it)", configPath)
+	default:
+		if err := os.Remove(configPath); err == nil {
+			printSuccess(fmt.Sprintf("Deleted config %s", configPath))
+		} else if os.IsNotExist(err) {
+			printUninstallSkip("No config file")
+		} else {
+			fail("delete config", err)
+		}
+
+		leftovers, err := configLeftovers(configPath)
+		if err != nil {
+			fail("list config backups", err)
+		}
+		for _, path := range leftovers {
+			if err := os.Remove(path); err != nil {
+				fail("delete "+filepath.Base(path), err)
+			} else {
+				printSuccess(fmt.Sprintf("Deleted %s", path))
+			}
+		}
+	}
+
+	printInfo("%s", ui.DimStyle.Render("SSH and GPG keys were not touched. Remove any 'gitch shell-init' or 'gitch prompt' lines from your shell rc files by hand."))
+
+	if failed > 0 {
+		return fmt.Errorf("%d step(s) failed; see the warnings above", failed)
+	}
+	return nil
+}
+
+// configLeftovers returns the backups ('gitch config backup', imports) and
+// the lock file left next to the config at configPath
+func configLeftovers(configPath string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Dir(configPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	base := filepath.Base(configPath)
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && (name == base+".lock" ||
+			strings.HasPrefix(name, base+".") && strings.HasSuffix(name, ".bak")) {
+			paths = append(paths, filepath.Join(filepath.Dir(configPath), name))
+		}
+	}
+	return paths, nil
+}
+
+// fileExists reports whether path exists
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// printUninstallSkip prints a step 'gitch uninstall' had nothing to do for
+func printUninstallSkip(msg string) {
+	printInfo("%s", ui.DimStyle.Render(msg+", skipped"))
+}
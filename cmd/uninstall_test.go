@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/history"
+	"github.com/orzazade/gitch/internal/hooks"
+	"github.com/orzazade/gitch/internal/prompt"
+	"github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/xdgtest"
+)
+
+// useTempHome points HOME, the XDG directories, the global git config and
+// GITCH_CONFIG into a temp directory and runs the test from a directory
+// outside any repository. Returns the temp home directory.
+func useTempHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(home, ".gitconfig"))
+	t.Setenv("GITCH_CONFIG", filepath.Join(home, ".config", "gitch", "config.yaml"))
+	xdgtest.Setenv(t, "XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Chdir(t.TempDir())
+	return home
+}
+
+// captureStdout runs fn and returns what it wrote to stdout
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	stdout, err := os.CreateTemp(t.TempDir(), "stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+
+	oldStdout := os.Stdout
+	os.Stdout = stdout
+	defer func() { os.Stdout = oldStdout }()
+	fn()
+
+	data, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// runUninstallWith runs 'gitch uninstall --yes' and returns its output
+func runUninstallWith(t *testing.T, deleteConfig bool) string {
+	t.Helper()
+	uninstallYes, uninstallDeleteConfig = true, deleteConfig
+	t.Cleanup(func() { uninstallYes, uninstallDeleteConfig = false, false })
+
+	return captureStdout(t, func() {
+		if err := runUninstall(uninstallCmd, nil); err != nil {
+			t.Fatalf("runUninstall() error = %v", err)
+		}
+	})
+}
+
+func TestRunUninstall_NothingInstalled(t *testing.T) {
+	useTempHome(t)
+
+	out := runUninstallWith(t, true)
+	for _, want := range []string{
+		"Global hooks not installed, skipped",
+		"No gitch-managed SSH config block, skipped",
+		"No prompt cache, skipped",
+		"No switch history, skipped",
+		"No config file, skipped",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunUninstall_RemovesEverything(t *testing.T) {
+	home := useTempHome(t)
+	configPath := os.Getenv("GITCH_CONFIG")
+
+	if err := hooks.InstallGlobal(); err != nil {
+		t.Fatalf("InstallGlobal() error = %v", err)
+	}
+	sshConfig := filepath.Join(home, ".ssh", "config")
+	if err := os.MkdirAll(filepath.Dir(sshConfig), 0700); err != nil {
+		t.Fatal(err)
+	}
+	userContent := "Host example\n  User me\n"
+	block := ssh.MarkerStart + "\nHost github.com-work\n  HostName github.com\n" + ssh.MarkerEnd + "\n"
+	if err := os.WriteFile(sshConfig, []byte(userContent+"\n"+block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := prompt.UpdateCache("work"); err != nil {
+		t.Fatal(err)
+	}
+	if err := history.Record(history.Entry{To: "work", Scope: history.ScopeGlobal}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	leftovers := []string{
+		configPath,
+		configPath + ".20260102-150405.bak",
+		configPath + ".20260102-150405-1.bak",
+		configPath + ".lock",
+	}
+	for _, path := range leftovers {
+		if err := os.WriteFile(path, []byte("identities: []\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := filepath.Join(filepath.Dir(configPath), "notes.bak")
+	if err := os.WriteFile(other, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runUninstallWith(t, true)
+	for _, want := range []string{
+		"Removed global hooks",
+		"Removed gitch-managed block",
+		"Cleared prompt cache",
+		"Cleared switch history",
+		"Deleted config " + configPath,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	status, err := hooks.IsInstalled()
+	if err != nil || status.Global {
+		t.Errorf("global hooks still installed: %+v, %v", status, err)
+	}
+	if data, err := os.ReadFile(sshConfig); err != nil || string(data) != userContent {
+		t.Errorf("SSH config = %q, %v; want only the user's content", data, err)
+	}
+	for _, path := range leftovers {
+		if fileExists(path) {
+			t.Errorf("%s was not deleted", path)
+		}
+	}
+	if !fileExists(other) {
+		t.Errorf("%s is not gitch's and should be kept", other)
+	}
+	if cachePath, _ := prompt.CachePath(); fileExists(cachePath) {
+		if data, _ := os.ReadFile(cachePath); len(data) > 0 {
+			t.Errorf("prompt cache still holds %q", data)
+		}
+	}
+	if entries, err := history.Load(); err != nil || len(entries) > 0 {
+		t.Errorf("history = %v, %v; want it cleared", entries, err)
+	}
+}
+
+func TestRunUninstall_KeepsConfig(t *testing.T) {
+	useTempHome(t)
+	configPath := os.Getenv("GITCH_CONFIG")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	backupPath := configPath + ".20260102-150405.bak"
+	for _, path := range []string{configPath, backupPath} {
+		if err := os.WriteFile(path, []byte("identities: []\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	out := runUninstallWith(t, false)
+	if !strings.Contains(out, "Kept config at "+configPath) {
+		t.Errorf("output missing the kept config:\n%s", out)
+	}
+	for _, path := range []string{configPath, backupPath} {
+		if !fileExists(path) {
+			t.Errorf("%s was deleted without --delete-config", path)
+		}
+	}
+}
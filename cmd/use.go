@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/orzazade/gitch/internal/config"
 	"github.com/orzazade/gitch/internal/git"
+	"github.com/orzazade/gitch/internal/history"
 	"github.com/orzazade/gitch/internal/prompt"
 	"github.com/orzazade/gitch/internal/rules"
 	sshpkg "github.com/orzazade/gitch/internal/ssh"
@@ -14,26 +18,76 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	useKeepAgentKeys bool
+	useDryRun        bool
+	useLocal         bool
+	useTags          []string
+)
+
 var useCmd = &cobra.Command{
-	Use:   "use [identity-name]",
+	Use:   "use [identity-name | -]",
 	Short: "Switch to a git identity",
 	Long: `Switch to a git identity by name.
 
-When called without arguments, launches an interactive selector.
-When called with an identity name, switches directly.
+When called without arguments, launches an interactive selector; --tag
+(repeatable) limits it to identities with one of the tags. When called with
+an identity name, switches directly.
 
 Updates the global git config (user.name and user.email) to use
-the specified identity.
+the specified identity. With --local, the identity (and commit signing
+config) is written to the current repository's config instead, leaving
+the global identity untouched.
+
+The new identity's SSH keys are added to ssh-agent and the previous
+identity's keys are removed, so the agent doesn't offer stale keys. Use
+--keep-agent-keys to leave previously loaded keys in the agent.
+
+Use --dry-run to print the git config and ssh-agent changes without
+applying them.
+
+Like 'cd -', 'gitch use -' switches back to the identity that was active
+before the last switch in the same scope (global, or this repository with
+--local). Running it again switches back once more.
 
 Examples:
   gitch use          # Interactive selector
+  gitch use --tag client-a  # Selector with client-a identities only
   gitch use work     # Direct switch
-  gitch use personal`,
+  gitch use personal --keep-agent-keys
+  gitch use work --dry-run
+  gitch use work --local  # This repository only
+  gitch use -        # Back to the previous identity`,
 	Args:              cobra.MaximumNArgs(1),
 	ValidArgsFunction: identityCompletionFunc,
 	RunE:              runUse,
 }
 
+// previousIdentityName returns the identity that was active before the last
+// switch in the global scope or, if global is false, in the current
+// repository. Used by 'gitch use -'.
+func previousIdentityName(global bool) (string, error) {
+	scope, repo := history.ScopeGlobal, ""
+	if !global {
+		scope, repo = history.ScopeLocal, git.RepoRoot()
+	}
+
+	last, err := history.LastInScope(scope, repo)
+	if err != nil {
+		return "", err
+	}
+	if last == nil {
+		if global {
+			return "", errors.New("no previous identity recorded; switch with 'gitch use <name>' first")
+		}
+		return "", errors.New("no previous identity recorded for this repository; switch with 'gitch use <name> --local' first")
+	}
+	if last.From == "" {
+		return "", fmt.Errorf("the identity before '%s' is unknown; use 'gitch use <name>' instead", last.To)
+	}
+	return last.From, nil
+}
+
 // identityCompletionFunc returns completions for identity names.
 // This provides tab completion for commands that take an identity name argument.
 func identityCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -57,11 +111,29 @@ func identityCompletionFunc(cmd *cobra.Command, args []string, toComplete string
 	return completions, cobra.ShellCompDirectiveNoFileComp
 }
 
+// identityFlagCompletionFunc completes identity names for flags such as
+// --use, regardless of the positional args already given.
+func identityFlagCompletionFunc(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return identityCompletionFunc(cmd, nil, toComplete)
+}
+
 func init() {
 	rootCmd.AddCommand(useCmd)
+	useCmd.Flags().BoolVar(&useKeepAgentKeys, "keep-agent-keys", false, "Don't remove the previous identity's SSH key from ssh-agent")
+	useCmd.Flags().BoolVar(&useDryRun, "dry-run", false, "Show what would change without switching")
+	useCmd.Flags().BoolVar(&useLocal, "local", false, "Apply the identity to the current repository only")
+	useCmd.Flags().StringArrayVar(&useTags, "tag", nil, "Only offer identities with this tag in the selector (repeatable)")
+	_ = useCmd.RegisterFlagCompletionFunc("tag", tagCompletionFunc)
 }
 
 func runUse(cmd *cobra.Command, args []string) error {
+	if useLocal && git.RepoRoot() == "" {
+		return errors.New("--local requires a git repository; run it from inside a repo")
+	}
+	if len(useTags) > 0 && len(args) > 0 {
+		return errors.New("--tag only applies to the interactive selector; omit the identity name")
+	}
+
 	// Load config
 	cfg, err := config.Load()
 	if err != nil {
@@ -70,6 +142,14 @@ func runUse(cmd *cobra.Command, args []string) error {
 
 	var identity *config.Identity
 
+	// Remember the active identity so its SSH key can be unloaded
+	previousName, previousEmail, _ := git.GetCurrentIdentity()
+	if useLocal {
+		// The effective identity in this repo, including any local override
+		previousName, _ = git.GetConfig("user.name", false)
+		previousEmail, _ = git.GetConfig("user.email", false)
+	}
+
 	if len(args) == 0 {
 		// Interactive mode
 		identities := cfg.ListIdentities()
@@ -78,9 +158,12 @@ func runUse(cmd *cobra.Command, args []string) error {
 			fmt.Println(ui.DimStyle.Render("Run 'gitch setup' to create one."))
 			return nil
 		}
-
-		// Get current active email for highlighting
-		_, activeEmail, _ := git.GetCurrentIdentity()
+		if len(useTags) > 0 {
+			identities = cfg.IdentitiesWithTag(useTags...)
+			if len(identities) == 0 {
+				return fmt.Errorf("no identities tagged %s", strings.Join(useTags, " or "))
+			}
+		}
 
 		// Check if a rule matches - use rule's identity as default selection
 		defaultName := cfg.Default
@@ -90,7 +173,7 @@ func runUse(cmd *cobra.Command, args []string) error {
 			defaultName = matchedRule.Identity
 		}
 
-		selected, err := selector.Run(identities, activeEmail, defaultName)
+		selected, err := selector.Run(identities, previousEmail, defaultName)
 		if err != nil {
 			return fmt.Errorf("selector error: %w", err)
 		}
@@ -104,36 +187,49 @@ func runUse(cmd *cobra.Command, args []string) error {
 	} else {
 		// Direct mode (existing logic)
 		name := args[0]
+		if name == "-" {
+			name, err = previousIdentityName(!useLocal)
+			if err != nil {
+				return err
+			}
+		}
 		identity, err = cfg.GetIdentity(name)
 		if err != nil {
 			return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", name)
 		}
 	}
 
-	// Apply identity to git config
-	if err := git.ApplyIdentity(identity.Name, identity.Email); err != nil {
+	if useDryRun {
+		printUseDryRun(cfg, identity, previousName, previousEmail)
+		return nil
+	}
+
+	global := !useLocal
+
+	// Apply identity and its signing config (cleared when it doesn't sign)
+	if err := applyIdentity(identity, global); err != nil {
 		return fmt.Errorf("failed to switch identity: %w", err)
 	}
 
-	// Configure GPG signing if identity has GPG key
-	if identity.GPGKeyID != "" {
-		if err := git.ApplySigningConfig(identity.GPGKeyID); err != nil {
-			// Print warning but don't fail the switch
-			fmt.Fprintf(os.Stderr, "Warning: failed to configure GPG signing: %v\n", err)
-		}
-	} else {
-		// Clear signing config when switching to identity without GPG
-		if err := git.ClearSigningConfig(); err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to clear GPG signing config: %v\n", err)
-		}
+	recordSwitch(findGitIdentity(cfg, previousName, previousEmail), identity, global)
+
+	// Remove the previous identity's SSH key from the agent
+	if !useKeepAgentKeys {
+		removePreviousSSHKey(cfg, previousName, previousEmail, identity)
 	}
 
-	// Add SSH key to agent if configured
-	if identity.SSHKeyPath != "" {
-		if err := addSSHKeyToAgent(identity.SSHKeyPath); err != nil {
-			// Print warning but don't fail the switch
-			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
-		}
+	// Add SSH keys to agent if configured; only an interactive switch
+	// explains why they weren't loaded
+	if !addAgentKeys(identity) && !quiet && ui.IsInteractive() {
+		fmt.Fprintln(os.Stderr, ui.DimStyle.Render(fmt.Sprintf(
+			"ssh-agent not running, so SSH keys were not loaded. Start it with eval $(ssh-agent), or run 'gitch config agent %s off'.",
+			identity.Name)))
+	}
+
+	// The prompt cache tracks the global identity
+	if useLocal {
+		printSuccess(fmt.Sprintf("Switched to '%s' (%s) in this repository", identity.Name, identity.Email))
+		return nil
 	}
 
 	// Update prompt cache (best effort - don't fail the switch)
@@ -142,24 +238,175 @@ func runUse(cmd *cobra.Command, args []string) error {
 	}
 
 	// Print success
-	msg := fmt.Sprintf("Switched to '%s' (%s)", identity.Name, identity.Email)
-	fmt.Println(ui.SuccessStyle.Render(msg))
+	printSuccess(fmt.Sprintf("Switched to '%s' (%s)", identity.Name, identity.Email))
 
 	return nil
 }
 
-// addSSHKeyToAgent adds an SSH key to the ssh-agent.
+// applyIdentity writes the identity's name, email and signing config (GPG or
+// SSH, per its signing method) to the global or repository git config.
+// Failing to configure signing is a warning, not an error, so a switch never
+// stops half way with only the name and email changed.
+func applyIdentity(identity *config.Identity, global bool) error {
+	if err := git.ApplyIdentityScope(identity.Name, identity.Email, global); err != nil {
+		return err
+	}
+
+	format := ""
+	if identity.GetSigningMethod() == config.SigningSSH {
+		format = git.SigningFormatSSH
+	}
+	if err := git.ApplySigning(identity.SigningKey(), format, global); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to configure commit signing: %v\n", err)
+	}
+	return nil
+}
+
+// addAgentKeys adds the identity's SSH keys (see Identity.AgentKeys) to
+// ssh-agent. Failures are printed as warnings but don't fail the switch.
+// Returns false, without a warning, if no ssh-agent is running.
+func addAgentKeys(identity *config.Identity) bool {
+	for _, keyPath := range identity.AgentKeys() {
+		err := addSSHKeyToAgent(keyPath, identity.AgentLifetime)
+		if errors.Is(err, sshpkg.ErrAgentNotRunning) {
+			return false
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return true
+}
+
+// addSSHKeyToAgent adds an SSH key to the ssh-agent, expiring after lifetime
+// seconds if positive.
 // Returns an error if the key file doesn't exist or if adding fails.
-func addSSHKeyToAgent(keyPath string) error {
+func addSSHKeyToAgent(keyPath string, lifetime int) error {
 	// Check if key file exists
 	if _, err := os.Stat(keyPath); os.IsNotExist(err) {
 		return fmt.Errorf("SSH key not found: %s", keyPath)
 	}
 
 	// Add to agent (will prompt for passphrase if needed)
-	if err := sshpkg.AddKeyToAgent(keyPath); err != nil {
+	if err := sshpkg.AddKeyToAgent(keyPath, lifetime); err != nil {
 		return fmt.Errorf("failed to add SSH key to agent: %w", err)
 	}
 
 	return nil
 }
+
+// findGitIdentity returns the identity matching the git user.email (preferring
+// one that also matches user.name), or nil if none does
+func findGitIdentity(cfg *config.Config, gitName, gitEmail string) *config.Identity {
+	if gitEmail == "" {
+		return nil
+	}
+
+	var found *config.Identity
+	identities := cfg.ListIdentities()
+	for i := range identities {
+		if !strings.EqualFold(identities[i].Email, gitEmail) {
+			continue
+		}
+		if found == nil || identities[i].Name == gitName {
+			found = &identities[i]
+		}
+	}
+	return found
+}
+
+// previousSSHKeys returns the ssh-agent keys (see Identity.AgentKeys) of the
+// identity matching previousEmail (preferring one that also matches
+// previousName), leaving out keys the identity being switched to also uses.
+func previousSSHKeys(cfg *config.Config, previousName, previousEmail string, next *config.Identity) []string {
+	previous := findGitIdentity(cfg, previousName, previousEmail)
+	if previous == nil {
+		return nil
+	}
+
+	var keys []string
+	for _, keyPath := range previous.AgentKeys() {
+		if !slices.Contains(next.SSHKeys(), keyPath) {
+			keys = append(keys, keyPath)
+		}
+	}
+	return keys
+}
+
+// removePreviousSSHKey removes the previous identity's SSH keys from
+// ssh-agent, except those the identity being switched to also uses.
+// Failures are printed as warnings.
+func removePreviousSSHKey(cfg *config.Config, previousName, previousEmail string, next *config.Identity) {
+	for _, keyPath := range previousSSHKeys(cfg, previousName, previousEmail, next) {
+		err := sshpkg.RemoveKeyFromAgent(keyPath)
+		if errors.Is(err, sshpkg.ErrAgentNotRunning) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to remove previous SSH key from agent: %v\n", err)
+		}
+	}
+}
+
+// printUseDryRun prints the changes switching to identity would make,
+// without applying them
+func printUseDryRun(cfg *config.Config, identity *config.Identity, previousName, previousEmail string) {
+	if previousName == identity.Name && strings.EqualFold(previousEmail, identity.Email) {
+		fmt.Printf("Already using '%s' (%s); git identity would not change.\n", identity.Name, identity.Email)
+	} else {
+		fmt.Printf("Would switch to '%s' (%s)\n", identity.Name, identity.Email)
+	}
+	fmt.Println()
+
+	scope := "global"
+	if useLocal {
+		scope = "local"
+	}
+	fmt.Printf("Git config (%s):\n", scope)
+	fmt.Printf("  user.name:  %s\n", describeChange(previousName, identity.Name))
+	fmt.Printf("  user.email: %s\n", describeChange(previousEmail, identity.Email))
+	if signingKey := identity.SigningKey(); signingKey != "" {
+		currentKey, _ := git.GetConfig("user.signingkey", !useLocal)
+		fmt.Printf("  user.signingkey: %s\n", describeChange(currentKey, signingKey))
+		if identity.GetSigningMethod() == config.SigningSSH {
+			fmt.Println("  gpg.format: ssh")
+		}
+		fmt.Println("  commit.gpgsign: true")
+	} else {
+		fmt.Println("  user.signingkey, commit.gpgsign: would be unset")
+	}
+	fmt.Println()
+
+	var removeKeys []string
+	if !useKeepAgentKeys {
+		removeKeys = previousSSHKeys(cfg, previousName, previousEmail, identity)
+	}
+
+	fmt.Println("ssh-agent:")
+	addKeys := identity.AgentKeys()
+	for _, keyPath := range addKeys {
+		line := "  would add " + keyPath
+		if identity.AgentLifetime > 0 {
+			line += fmt.Sprintf(" (expires after %ds)", identity.AgentLifetime)
+		}
+		fmt.Println(line)
+	}
+	for _, keyPath := range removeKeys {
+		fmt.Println("  would remove " + keyPath)
+	}
+	if len(addKeys) == 0 && len(removeKeys) == 0 {
+		fmt.Println("  no changes")
+	}
+}
+
+// describeChange formats a config value change as "old -> new", or the value
+// alone when it doesn't change
+func describeChange(current, next string) string {
+	if current == next {
+		return next + " (unchanged)"
+	}
+	if current == "" {
+		current = "(not set)"
+	}
+	return current + " -> " + next
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/config"
+)
+
+func TestAddAgentKeys_NoAgent(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	keyPath := filepath.Join(t.TempDir(), "id_work")
+	writeTestSSHKey(t, keyPath)
+
+	identity := &config.Identity{Name: "work", Email: "me@company.com", SSHKeyPath: keyPath}
+	if addAgentKeys(identity) {
+		t.Error("addAgentKeys() = true without an agent, want false")
+	}
+
+	// Nothing to load needs no agent
+	if !addAgentKeys(&config.Identity{Name: "personal", Email: "me@example.com"}) {
+		t.Error("addAgentKeys() = false for an identity without keys, want true")
+	}
+}
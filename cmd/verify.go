@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	sshpkg "github.com/orzazade/gitch/internal/ssh"
+	"github.com/orzazade/gitch/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyHost              string
+	verifyAcceptNewHostKeys bool
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <identity>",
+	Short: "Check that an identity's SSH key authenticates with its Git hosts",
+	Long: `Check that an identity's SSH key is accepted by its Git hosts.
+
+Runs 'ssh -T git@<host>' with only the identity's key for every host the
+identity maps to (the same hosts as 'gitch ssh-config generate'), and reports
+whether each provider greeted you as authenticated. Your ~/.ssh/config is
+ignored so the result reflects the key alone.
+
+Use --host to test a single host, by hostname or alias.
+
+Hosts must already be in ~/.ssh/known_hosts. With --accept-new-host-keys,
+the key of a host not seen before is trusted on first use and added to
+~/.ssh/known_hosts; a changed key is still rejected.
+
+Examples:
+  gitch verify work
+  gitch verify work --host github.com
+  gitch verify work --accept-new-host-keys
+  gitch verify corp --host git.corp.internal`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: identityCompletionFunc,
+	RunE:              runVerify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVar(&verifyHost, "host", "", "Only test this host (hostname or alias)")
+	verifyCmd.Flags().BoolVar(&verifyAcceptNewHostKeys, "accept-new-host-keys", false, "Add the keys of hosts not yet in ~/.ssh/known_hosts")
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	// Load config
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	identity, err := cfg.GetIdentity(args[0])
+	if err != nil {
+		return fmt.Errorf("identity '%s' not found. Use 'gitch list' to see available identities", args[0])
+	}
+
+	hosts := sshpkg.IdentityToHosts(*identity)
+	if len(hosts) == 0 {
+		return fmt.Errorf("identity '%s' has no SSH key; use 'gitch add --generate-ssh' to create one", identity.Name)
+	}
+
+	if verifyHost != "" {
+		hosts = filterHosts(hosts, verifyHost)
+		if len(hosts) == 0 {
+			return fmt.Errorf("identity '%s' has no host '%s'", identity.Name, verifyHost)
+		}
+	}
+
+	if verifyAcceptNewHostKeys {
+		printInfo("%s", ui.DimStyle.Render("Keys of hosts not in ~/.ssh/known_hosts will be added to it."))
+	}
+
+	failed, hostKeyFailed := 0, false
+	for _, host := range hosts {
+		fmt.Printf("Testing %s...\n", host.HostName)
+		result := sshpkg.VerifyHost(host, verifyAcceptNewHostKeys)
+		if result.OK {
+			fmt.Println(ui.SuccessStyle.Render(fmt.Sprintf("  ✓ %s", result.Message)))
+		} else {
+			failed++
+			hostKeyFailed = hostKeyFailed || result.IsHostKeyFailure()
+			fmt.Println(ui.ErrorStyle.Render(fmt.Sprintf("  ✗ %s", result.Message)))
+		}
+	}
+
+	if hostKeyFailed && !verifyAcceptNewHostKeys {
+		fmt.Println(ui.DimStyle.Render("A host key isn't in ~/.ssh/known_hosts. Check it against the host's published fingerprints, then run again with --accept-new-host-keys."))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("SSH authentication failed for %d of %d host(s)", failed, len(hosts))
+	}
+	return nil
+}
+
+// filterHosts returns the hosts whose hostname or alias matches name
+func filterHosts(hosts []sshpkg.HostConfig, name string) []sshpkg.HostConfig {
+	var matched []sshpkg.HostConfig
+	for _, host := range hosts {
+		if strings.EqualFold(host.HostName, name) || strings.EqualFold(host.Alias, name) {
+			matched = append(matched, host)
+		}
+	}
+	return matched
+}
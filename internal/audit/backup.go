@@ -2,10 +2,32 @@ package audit
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
 )
 
+// CheckBackupDir verifies that dir exists, is a directory, and is writable,
+// so a backup can be created there before history is rewritten.
+func CheckBackupDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("backup directory %s: %w", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("backup directory %s is not a directory", dir)
+	}
+
+	probe, err := os.CreateTemp(dir, ".gitch-write-test-*")
+	if err != nil {
+		return fmt.Errorf("backup directory %s is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return nil
+}
+
 // CreateMirrorBackup creates a full mirror backup of the current git repository.
 // The destPath should be an absolute path where the mirror will be created.
 // Uses --no-local to avoid hardlink issues that could cause data loss.
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is the absolute date format accepted by --since/--until
+const dateLayout = "2006-01-02"
+
+// ParseDate parses an audit date bound relative to now.
+// Accepts "2006-01-02", RFC3339 timestamps, "today", "yesterday", and
+// Git-style relative dates like "2 weeks ago" or "3.days.ago".
+// When endOfDay is true, date-only values resolve to the last second of that
+// day so that --until is inclusive.
+func ParseDate(value string, now time.Time, endOfDay bool) (time.Time, error) {
+	value = strings.TrimSpace(strings.ToLower(value))
+	if value == "" {
+		return time.Time{}, fmt.Errorf("date cannot be empty")
+	}
+
+	dayBound := func(t time.Time) time.Time {
+		start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		if endOfDay {
+			return start.Add(24*time.Hour - time.Second)
+		}
+		return start
+	}
+
+	switch value {
+	case "now":
+		return now, nil
+	case "today":
+		return dayBound(now), nil
+	case "yesterday":
+		return dayBound(now.AddDate(0, 0, -1)), nil
+	}
+
+	if t, err := time.ParseInLocation(dateLayout, value, now.Location()); err == nil {
+		return dayBound(t), nil
+	}
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(value)); err == nil {
+		return t, nil
+	}
+
+	// Relative: "<n> <unit> ago", with spaces or dots as separators
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == '.' })
+	if len(fields) == 3 && fields[2] == "ago" {
+		n, err := strconv.Atoi(fields[0])
+		if err == nil && n >= 0 {
+			switch strings.TrimSuffix(fields[1], "s") {
+			case "second":
+				return now.Add(-time.Duration(n) * time.Second), nil
+			case "minute":
+				return now.Add(-time.Duration(n) * time.Minute), nil
+			case "hour":
+				return now.Add(-time.Duration(n) * time.Hour), nil
+			case "day":
+				return now.AddDate(0, 0, -n), nil
+			case "week":
+				return now.AddDate(0, 0, -7*n), nil
+			case "month":
+				return now.AddDate(0, -n, 0), nil
+			case "year":
+				return now.AddDate(-n, 0, 0), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or a relative date like \"2 weeks ago\"", value)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,22 +15,35 @@ import (
 // ConfirmPhrase is the exact phrase users must type to confirm destructive operations.
 const ConfirmPhrase = "I UNDERSTAND"
 
-// GenerateMailmap creates mailmap content to remap wrong emails to the expected email.
-// Mailmap format: <correct-email> <wrong-email>
-func GenerateMailmap(mismatches []Result, expectedEmail string) string {
+// GenerateMailmap creates mailmap content to remap wrong emails to the expected identity.
+// Mailmap format: Correct Name <correct-email> <wrong-email>, so wrong author names
+// are corrected too. Falls back to <correct-email> <wrong-email> when expectedName is empty.
+func GenerateMailmap(mismatches []Result, expectedName, expectedEmail string) string {
 	// Collect unique wrong emails
 	uniqueEmails := make(map[string]bool)
 	for _, r := range mismatches {
-		if r.IsMismatched {
+		if !r.IsMismatched {
+			continue
+		}
+		if r.MismatchKind != CommitterMismatch {
 			uniqueEmails[r.Commit.AuthorEmail] = true
 		}
+		if r.MismatchKind == CommitterMismatch || r.MismatchKind == BothMismatch {
+			uniqueEmails[r.Commit.CommitterEmail] = true
+		}
 	}
 
 	// Generate mailmap lines
+	correct := fmt.Sprintf("<%s>", expectedEmail)
+	if expectedName != "" {
+		correct = expectedName + " " + correct
+	}
+
 	var lines []string
 	for wrongEmail := range uniqueEmails {
-		lines = append(lines, fmt.Sprintf("<%s> <%s>", expectedEmail, wrongEmail))
+		lines = append(lines, fmt.Sprintf("%s <%s>", correct, wrongEmail))
 	}
+	sort.Strings(lines)
 
 	return strings.Join(lines, "\n")
 }
@@ -93,27 +107,111 @@ func RemoveRemotes() error {
 	return nil
 }
 
+// mismatchedResults returns the results whose commits need rewriting
+func mismatchedResults(results []Result) []Result {
+	var toFix []Result
+	for _, r := range results {
+		if r.IsMismatched {
+			toFix = append(toFix, r)
+		}
+	}
+	return toFix
+}
+
+// FixOptions configures Fix and FixDryRun
+type FixOptions struct {
+	BackupDir string // Directory for the mirror backup (empty = os.TempDir())
+}
+
+// fixBackupPath returns the mirror backup path for the current repository
+// inside dir (os.TempDir() if empty), named after the repository and
+// timestamped with now
+func fixBackupPath(dir string, now time.Time) (string, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get repository root: %w", err)
+	}
+	repoName := filepath.Base(strings.TrimSpace(string(output)))
+
+	timestamp := now.Format("20060102-150405")
+	return filepath.Join(dir, fmt.Sprintf("%s-backup-%s", repoName, timestamp)), nil
+}
+
+// FixDryRun previews what Fix would do: the commits that would be rewritten,
+// the mailmap passed to git-filter-repo, the backup location and the remotes
+// that would be removed. It changes nothing and asks for no confirmation.
+func FixDryRun(scanResult *ScanResult, opts FixOptions) error {
+	toFix := mismatchedResults(scanResult.Results)
+	if len(toFix) == 0 {
+		return fmt.Errorf("no mismatched commits to fix")
+	}
+
+	fmt.Printf("Would rewrite %d commit(s):\n", len(toFix))
+	for _, r := range toFix {
+		hash := r.Commit.Hash
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+		pushed := "local"
+		if r.IsPushed {
+			pushed = "pushed"
+		}
+		fmt.Printf("  %s  %-9s  %-6s  %s\n", hash, r.MismatchKind, pushed, r.Commit.Subject)
+	}
+
+	fmt.Println()
+	fmt.Println("Mailmap:")
+	for _, line := range strings.Split(GenerateMailmap(toFix, scanResult.ExpectedName, scanResult.ExpectedEmail), "\n") {
+		fmt.Printf("  %s\n", line)
+	}
+
+	fmt.Println()
+	if backupPath, err := fixBackupPath(opts.BackupDir, time.Now()); err == nil {
+		fmt.Printf("Backup would be created at: %s\n", backupPath)
+	}
+	if opts.BackupDir != "" {
+		if err := CheckBackupDir(opts.BackupDir); err != nil {
+			fmt.Println(ui.WarningStyle.Render(fmt.Sprintf("Warning: %v", err)))
+		}
+	}
+	if remotes, _ := GetRemotes(); len(remotes) > 0 {
+		fmt.Printf("Remotes that would be removed: %s\n", strings.Join(remotes, ", "))
+	}
+	if !IsFilterRepoAvailable() {
+		fmt.Println(ui.WarningStyle.Render("git-filter-repo is not installed; install it before running --fix."))
+	}
+
+	fmt.Println()
+	fmt.Println(ui.DimStyle.Render("Dry run: no changes made. Re-run without --dry-run to rewrite history."))
+
+	return nil
+}
+
 // Fix rewrites git history to correct mismatched commit identities.
 // This is a destructive operation with multiple safety guardrails:
-// 1. Checks git-filter-repo availability
-// 2. Creates mirror backup before any changes
+// 1. Checks git-filter-repo availability and that the backup dir is writable
+// 2. Creates mirror backup (in opts.BackupDir, or the temp dir) before any changes
 // 3. Shows GPG signature loss warning
 // 4. Requires typed confirmation ("I UNDERSTAND")
 // 5. Removes remotes after rewrite to prevent accidental force-push
-func Fix(scanResult *ScanResult) error {
+func Fix(scanResult *ScanResult, opts FixOptions) error {
 	// Step 1: Prerequisites check
 	if !IsFilterRepoAvailable() {
 		return fmt.Errorf("git-filter-repo not found\n\nInstall with:\n  brew install git-filter-repo\n  # or: pip install git-filter-repo")
 	}
-
-	// Step 2: Collect commits that need fixing
-	var toFix []Result
-	for _, r := range scanResult.Results {
-		if r.IsMismatched {
-			toFix = append(toFix, r)
+	if opts.BackupDir != "" {
+		if err := CheckBackupDir(opts.BackupDir); err != nil {
+			return err
 		}
 	}
 
+	// Step 2: Collect commits that need fixing
+	toFix := mismatchedResults(scanResult.Results)
 	if len(toFix) == 0 {
 		return fmt.Errorf("no mismatched commits to fix")
 	}
@@ -151,17 +249,10 @@ func Fix(scanResult *ScanResult) error {
 	}
 
 	// Step 6: Create backup (AUDIT-05)
-	// Get repo name for backup path
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
+	backupPath, err := fixBackupPath(opts.BackupDir, time.Now())
 	if err != nil {
-		return fmt.Errorf("failed to get repository root: %w", err)
+		return err
 	}
-	repoRoot := strings.TrimSpace(string(output))
-	repoName := filepath.Base(repoRoot)
-
-	timestamp := time.Now().Format("20060102-150405")
-	backupPath := filepath.Join(os.TempDir(), fmt.Sprintf("%s-backup-%s", repoName, timestamp))
 
 	fmt.Printf("\nCreating backup at: %s\n", backupPath)
 	if err := CreateMirrorBackup(backupPath); err != nil {
@@ -169,7 +260,7 @@ func Fix(scanResult *ScanResult) error {
 	}
 
 	// Step 7: Generate and write mailmap
-	mailmapContent := GenerateMailmap(toFix, scanResult.ExpectedEmail)
+	mailmapContent := GenerateMailmap(toFix, scanResult.ExpectedName, scanResult.ExpectedEmail)
 	mailmapPath := filepath.Join(os.TempDir(), "gitch-mailmap")
 	if err := os.WriteFile(mailmapPath, []byte(mailmapContent), 0644); err != nil {
 		return fmt.Errorf("failed to write mailmap: %w", err)
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSONCommit is the JSON representation of an audited commit
+type JSONCommit struct {
+	Hash            string `json:"hash"`
+	AuthorName      string `json:"author_name"`
+	AuthorEmail     string `json:"author_email"`
+	CommitterEmail  string `json:"committer_email"`
+	ExpectedEmail   string `json:"expected_email"`
+	Date            string `json:"date"` // RFC3339
+	Subject         string `json:"subject"`
+	IsMismatched    bool   `json:"is_mismatched"`
+	MismatchKind    string `json:"mismatch_kind,omitempty"` // author, committer, or both
+	IsPushed        bool   `json:"is_pushed"`
+	SignatureStatus string `json:"signature_status,omitempty"` // set with --verify-signatures
+}
+
+// JSONReport is the JSON representation of a ScanResult, used by gitch audit --json
+type JSONReport struct {
+	MatchedRule       string       `json:"matched_rule"`
+	ExpectedEmail     string       `json:"expected_email"`
+	TotalScanned      int          `json:"total_scanned"`
+	MismatchCount     int          `json:"mismatch_count"`
+	LocalOnlyCount    int          `json:"local_only_count"`
+	PushedCount       int          `json:"pushed_count"`
+	NoUpstream        bool         `json:"no_upstream"`
+	BadSignatureCount int          `json:"bad_signature_count,omitempty"` // set with --verify-signatures
+	Results           []JSONCommit `json:"results"`
+}
+
+// NewJSONReport converts a ScanResult into its JSON representation
+// MatchedRule is empty when no rule matches the repository
+func NewJSONReport(result *ScanResult) JSONReport {
+	report := JSONReport{
+		ExpectedEmail:     result.ExpectedEmail,
+		TotalScanned:      result.TotalScanned,
+		MismatchCount:     result.MismatchCount,
+		LocalOnlyCount:    result.LocalOnlyCount,
+		PushedCount:       result.PushedCount,
+		NoUpstream:        result.NoUpstream,
+		BadSignatureCount: result.BadSignatureCount,
+		Results:           make([]JSONCommit, 0, len(result.Results)),
+	}
+	if result.MatchedRule != nil {
+		report.MatchedRule = result.MatchedRule.Pattern
+	}
+
+	for _, r := range result.Results {
+		report.Results = append(report.Results, JSONCommit{
+			Hash:            r.Commit.Hash,
+			AuthorName:      r.Commit.AuthorName,
+			AuthorEmail:     r.Commit.AuthorEmail,
+			CommitterEmail:  r.Commit.CommitterEmail,
+			ExpectedEmail:   r.ExpectedEmail,
+			Date:            r.Commit.Date.Format(time.RFC3339),
+			Subject:         r.Commit.Subject,
+			IsMismatched:    r.IsMismatched,
+			MismatchKind:    r.MismatchKind.String(),
+			IsPushed:        r.IsPushed,
+			SignatureStatus: string(r.SignatureStatus),
+		})
+	}
+
+	return report
+}
+
+// MarshalJSONReport serializes a ScanResult as indented JSON
+func MarshalJSONReport(result *ScanResult) ([]byte, error) {
+	return json.MarshalIndent(NewJSONReport(result), "", "  ")
+}
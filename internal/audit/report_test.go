@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orzazade/gitch/internal/rules"
+)
+
+// TestMarshalJSONReport tests the JSON serialization used by gitch audit --json
+func TestMarshalJSONReport(t *testing.T) {
+	result := &ScanResult{
+		Results: []Result{
+			{
+				Commit: Commit{
+					Hash:        "abc1234",
+					AuthorName:  "John Doe",
+					AuthorEmail: "john@personal.com",
+					Date:        time.Date(2024, 1, 15, 10, 30, 0, 0, time.FixedZone("", -5*3600)),
+					Subject:     "Add feature",
+				},
+				ExpectedEmail: "john@work.com",
+				IsMismatched:  true,
+				IsPushed:      false,
+			},
+		},
+		ExpectedEmail: "john@work.com",
+		MatchedRule:   &rules.Rule{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		TotalScanned:  10,
+		MismatchCount: 1,
+	}
+
+	data, err := MarshalJSONReport(result)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var report JSONReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("failed to unmarshal report: %v", err)
+	}
+
+	if report.MatchedRule != "~/work/**" {
+		t.Errorf("expected matched rule '~/work/**', got %q", report.MatchedRule)
+	}
+	if report.TotalScanned != 10 || report.MismatchCount != 1 {
+		t.Errorf("expected total=10 mismatches=1, got total=%d mismatches=%d", report.TotalScanned, report.MismatchCount)
+	}
+	if len(report.Results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(report.Results))
+	}
+
+	got := report.Results[0]
+	if got.Hash != "abc1234" || got.AuthorEmail != "john@personal.com" || got.ExpectedEmail != "john@work.com" {
+		t.Errorf("unexpected result fields: %+v", got)
+	}
+	if !got.IsMismatched || got.IsPushed {
+		t.Errorf("expected IsMismatched=true IsPushed=false, got %+v", got)
+	}
+	if got.Date != "2024-01-15T10:30:00-05:00" {
+		t.Errorf("expected RFC3339 date, got %q", got.Date)
+	}
+}
+
+// TestMarshalJSONReport_NoRule tests JSON serialization when no rule matches
+func TestMarshalJSONReport_NoRule(t *testing.T) {
+	data, err := MarshalJSONReport(&ScanResult{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Results should serialize as an empty array, not null
+	if !strings.Contains(string(data), `"results": []`) {
+		t.Errorf("expected empty results array, got:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"matched_rule": ""`) {
+		t.Errorf("expected empty matched_rule, got:\n%s", data)
+	}
+}
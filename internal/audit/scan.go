@@ -1,29 +1,72 @@
 package audit
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 	"time"
 
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/gpg"
 	"github.com/orzazade/gitch/internal/rules"
 )
 
-// Delimiters for parsing git log output
+// Delimiters for parsing git log output. NUL cannot appear in commit
+// metadata, so fields are separated by NUL and commits by NUL followed by
+// the ASCII record separator.
 const (
-	fieldDelim  = "|||"
-	commitDelim = "<<<COMMIT>>>"
+	fieldDelim  = "\x00"
+	commitDelim = "\x00\x1e"
+)
+
+// Placeholders that make git log emit the delimiters above. NUL can't be
+// passed in a command-line argument, so git expands them instead.
+const (
+	fieldDelimFormat  = "%x00"
+	commitDelimFormat = "%x00%x1e"
 )
 
 // Commit represents a single git commit with metadata
 type Commit struct {
-	Hash        string
-	AuthorName  string
-	AuthorEmail string
-	Date        time.Time
-	Subject     string
+	Hash           string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+	Date           time.Time
+	Subject        string
+}
+
+// MismatchKind indicates which identity field of a commit is wrong
+type MismatchKind int
+
+const (
+	// NoMismatch means both author and committer match the expected identity
+	NoMismatch MismatchKind = iota
+	// AuthorMismatch means only the author email is wrong
+	AuthorMismatch
+	// CommitterMismatch means only the committer email is wrong (e.g., after a rebase or cherry-pick)
+	CommitterMismatch
+	// BothMismatch means both author and committer emails are wrong
+	BothMismatch
+)
+
+// String returns a short label for the mismatch kind
+func (k MismatchKind) String() string {
+	switch k {
+	case AuthorMismatch:
+		return "author"
+	case CommitterMismatch:
+		return "committer"
+	case BothMismatch:
+		return "both"
+	default:
+		return ""
+	}
 }
 
 // Result represents an audited commit with mismatch status
@@ -31,94 +74,213 @@ type Result struct {
 	Commit        Commit
 	ExpectedEmail string
 	IsMismatched  bool
+	MismatchKind  MismatchKind
 	IsPushed      bool // true = pushed to remote, false = local-only
+	// SignatureStatus is only set when ScanOptions.VerifySignatures is true
+	SignatureStatus SignatureStatus
 }
 
-// GetCommits retrieves commits from git log
-// If limit > 0, limits the number of commits returned
-// Returns empty slice with nil error for empty repos
-func GetCommits(limit int) ([]Commit, error) {
-	// Build git log command with custom format
-	// Format: <<<COMMIT>>>hash|||name|||email|||date|||subject
-	formatArg := fmt.Sprintf("--format=%s%%H%s%%an%s%%ae%s%%ai%s%%s",
-		commitDelim, fieldDelim, fieldDelim, fieldDelim, fieldDelim)
+// classifyMismatch compares a commit's author and committer emails against
+// the expected email (case-insensitive). An empty committer email is ignored.
+func classifyMismatch(commit Commit, expectedEmail string) MismatchKind {
+	authorWrong := !strings.EqualFold(commit.AuthorEmail, expectedEmail)
+	committerWrong := commit.CommitterEmail != "" && !strings.EqualFold(commit.CommitterEmail, expectedEmail)
+
+	switch {
+	case authorWrong && committerWrong:
+		return BothMismatch
+	case authorWrong:
+		return AuthorMismatch
+	case committerWrong:
+		return CommitterMismatch
+	default:
+		return NoMismatch
+	}
+}
+
+// commitFormat is the git log format of a commit record, parsed by parseCommitLine
+// Format: <RS>hash<NUL>author name<NUL>author email<NUL>committer name<NUL>committer email<NUL>date<NUL>subject
+var commitFormat = fmt.Sprintf("%s%%H%s%%an%s%%ae%s%%cn%s%%ce%s%%ai%s%%s",
+	commitDelimFormat, fieldDelimFormat, fieldDelimFormat, fieldDelimFormat, fieldDelimFormat, fieldDelimFormat, fieldDelimFormat)
 
-	args := []string{"log", formatArg}
+// buildLogArgs constructs the git log arguments for GetCommits
+func buildLogArgs(limit int, since, until time.Time, author string) []string {
+	return logArgs(commitFormat, limit, since, until, author)
+}
+
+// logArgs constructs git log arguments with the given format and commit range
+// Date bounds and the author pattern are passed to git so filtering happens
+// before output
+func logArgs(format string, limit int, since, until time.Time, author string) []string {
+	args := []string{"log", "--format=" + format}
 	if limit > 0 {
 		args = append(args, fmt.Sprintf("--max-count=%d", limit))
 	}
+	if !since.IsZero() {
+		args = append(args, "--since="+since.Format(time.RFC3339))
+	}
+	if !until.IsZero() {
+		args = append(args, "--until="+until.Format(time.RFC3339))
+	}
+	if author != "" {
+		args = append(args, "--author="+author)
+	}
 
+	return args
+}
+
+// GetCommits retrieves commits from git log
+// If limit > 0, limits the number of commits returned
+// Non-zero since/until restrict commits to that date range
+// A non-empty author keeps only commits whose author matches that pattern
+// (as git log --author: a regex against "Name <email>")
+// Returns empty slice with nil error for empty repos
+func GetCommits(limit int, since, until time.Time, author string) ([]Commit, error) {
+	commits := []Commit{}
+	err := EachCommit(limit, since, until, author, func(commit Commit) error {
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return commits, nil
+}
+
+// EachCommit streams commits from git log to fn, newest first, without
+// holding the whole history in memory. Limit, date bounds and author work
+// as in GetCommits. Stops early and returns the error if fn returns one.
+// Empty repos produce no commits and a nil error.
+func EachCommit(limit int, since, until time.Time, author string, fn func(Commit) error) error {
+	return runLog(buildLogArgs(limit, since, until, author), func(r io.Reader) error {
+		return readCommits(r, fn)
+	})
+}
+
+// runLog runs git log with args and passes its output to read as it is
+// produced. Empty repos produce no output and a nil error.
+func runLog(args []string, read func(io.Reader) error) error {
 	cmd := exec.Command("git", args...)
-	output, err := cmd.Output()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
+		return fmt.Errorf("failed to run git log: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run git log: %w", err)
+	}
+
+	if err := read(stdout); err != nil {
+		// Stop git and reap it; its exit status no longer matters
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	if err := cmd.Wait(); err != nil {
 		// Check for empty repo or no commits
-		errStr := string(output)
+		errStr := stderr.String()
 		if strings.Contains(errStr, "fatal: your current branch") ||
 			strings.Contains(errStr, "does not have any commits") {
-			return []Commit{}, nil
-		}
-		// Also check exit error message
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr := string(exitErr.Stderr)
-			if strings.Contains(stderr, "fatal: your current branch") ||
-				strings.Contains(stderr, "does not have any commits") {
-				return []Commit{}, nil
-			}
+			return nil
 		}
-		return nil, fmt.Errorf("failed to run git log: %w", err)
+		return fmt.Errorf("failed to run git log: %w", err)
 	}
 
-	return parseCommits(string(output))
+	return nil
 }
 
-// parseCommits parses the git log output into Commit structs
-func parseCommits(output string) ([]Commit, error) {
-	output = strings.TrimSpace(output)
-	if output == "" {
-		return []Commit{}, nil
-	}
+// maxCommitRecordSize bounds a single commit record in git log output
+const maxCommitRecordSize = 1024 * 1024
 
-	// Split by commit delimiter
-	parts := strings.Split(output, commitDelim)
-
-	var commits []Commit
-	for _, part := range parts {
-		part = strings.TrimSpace(part)
-		if part == "" {
-			continue
-		}
-
-		commit, err := parseCommitLine(part)
+// readCommits parses git log output from r and passes each commit to fn
+// Malformed commits are skipped
+func readCommits(r io.Reader, fn func(Commit) error) error {
+	return readRecords(r, func(record string) error {
+		commit, err := parseCommitLine(record)
 		if err != nil {
 			// Skip malformed commits instead of failing entirely
+			return nil
+		}
+		return fn(commit)
+	})
+}
+
+// readRecords splits git log output from r into commit records and passes
+// each non-empty record to fn
+func readRecords(r io.Reader, fn func(record string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxCommitRecordSize)
+	scanner.Split(splitCommits)
+
+	for scanner.Scan() {
+		record := strings.TrimSpace(scanner.Text())
+		if record == "" {
 			continue
 		}
-		commits = append(commits, commit)
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read git log output: %w", err)
+	}
+	return nil
+}
+
+// splitCommits is a bufio.SplitFunc that splits git log output on commitDelim
+func splitCommits(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.Index(data, []byte(commitDelim)); i >= 0 {
+		return i + len(commitDelim), data[:i], nil
 	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	// Request more data
+	return 0, nil, nil
+}
 
+// parseCommits parses the git log output into Commit structs
+func parseCommits(output string) ([]Commit, error) {
+	commits := []Commit{}
+	err := readCommits(strings.NewReader(output), func(commit Commit) error {
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return commits, nil
 }
 
 // parseCommitLine parses a single commit line into a Commit struct
 func parseCommitLine(line string) (Commit, error) {
 	parts := strings.Split(line, fieldDelim)
-	if len(parts) < 5 {
-		return Commit{}, fmt.Errorf("malformed commit line: expected 5 fields, got %d", len(parts))
+	if len(parts) < 7 {
+		return Commit{}, fmt.Errorf("malformed commit line: expected 7 fields, got %d", len(parts))
 	}
 
 	// Parse the date
-	dateStr := strings.TrimSpace(parts[3])
+	dateStr := strings.TrimSpace(parts[5])
 	date, err := time.Parse("2006-01-02 15:04:05 -0700", dateStr)
 	if err != nil {
 		return Commit{}, fmt.Errorf("failed to parse date %q: %w", dateStr, err)
 	}
 
 	return Commit{
-		Hash:        strings.TrimSpace(parts[0]),
-		AuthorName:  strings.TrimSpace(parts[1]),
-		AuthorEmail: strings.TrimSpace(parts[2]),
-		Date:        date,
-		Subject:     strings.TrimSpace(parts[4]),
+		Hash:           strings.TrimSpace(parts[0]),
+		AuthorName:     strings.TrimSpace(parts[1]),
+		AuthorEmail:    strings.TrimSpace(parts[2]),
+		CommitterName:  strings.TrimSpace(parts[3]),
+		CommitterEmail: strings.TrimSpace(parts[4]),
+		Date:           date,
+		Subject:        strings.TrimSpace(parts[6]),
 	}, nil
 }
 
@@ -163,13 +325,19 @@ func GetLocalOnlyHashes() (map[string]bool, error) {
 
 // ScanOptions configures the Scan function behavior
 type ScanOptions struct {
-	Limit   int  // Max commits to scan (0 = default 1000)
-	ShowAll bool // Include matching commits in results
+	Limit   int       // Max commits to scan (0 = default 1000)
+	ShowAll bool      // Include matching commits in results
+	Since   time.Time // Only scan commits after this time (zero = no bound)
+	Until   time.Time // Only scan commits before this time (zero = no bound)
+	Author  string    // Only scan commits whose author matches this git log --author pattern (empty = all)
+	// VerifySignatures checks each commit's GPG signature against the expected identity's key
+	VerifySignatures bool
 }
 
 // ScanResult contains the results of an audit scan
 type ScanResult struct {
 	Results        []Result
+	ExpectedName   string
 	ExpectedEmail  string
 	MatchedRule    *rules.Rule
 	TotalScanned   int
@@ -177,10 +345,14 @@ type ScanResult struct {
 	LocalOnlyCount int
 	PushedCount    int
 	NoUpstream     bool // true if we couldn't determine pushed status
+	// BadSignatureCount counts commits whose signature is bad or made by another key
+	BadSignatureCount int
+	// SignaturesVerified is true if signatures were checked (false when gpg is unavailable)
+	SignaturesVerified bool
 }
 
 // Scan performs an identity audit on the git history
-// It compares commit author emails against the expected identity for this repo
+// It compares commit author and committer emails against the expected identity for this repo
 func Scan(opts ScanOptions) (*ScanResult, error) {
 	// Load config
 	cfg, err := config.Load()
@@ -210,7 +382,7 @@ func Scan(opts ScanOptions) (*ScanResult, error) {
 	// Get expected identity
 	expectedIdentity, err := cfg.GetIdentity(matchedRule.Identity)
 	if err != nil {
-		return nil, fmt.Errorf("rule references unknown identity %q: %w", matchedRule.Identity, err)
+		return nil, fmt.Errorf("rule references unknown identity %q (run 'gitch doctor'): %w", matchedRule.Identity, err)
 	}
 
 	// Handle limit:
@@ -224,21 +396,24 @@ func Scan(opts ScanOptions) (*ScanResult, error) {
 		limit = 0 // Pass 0 to GetCommits = unlimited (no --max-count flag)
 	}
 
-	// Get commits
-	commits, err := GetCommits(limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get commits: %w", err)
-	}
-
 	// Get local-only hashes
 	localHashes, _ := GetLocalOnlyHashes()
 	noUpstream := localHashes == nil
 
-	// Process commits
+	// Verify signatures if requested (skipped gracefully without gpg)
+	var signingKey *expectedKey
+	verifySignatures := opts.VerifySignatures && gpg.IsGPGAvailable()
+	if verifySignatures {
+		signingKey = resolveExpectedKey(expectedIdentity.GPGKeyID)
+	}
+
 	var results []Result
-	var mismatchCount, localOnlyCount, pushedCount int
+	var totalScanned, mismatchCount, localOnlyCount, pushedCount, badSignatureCount int
+
+	// sig is nil when signatures aren't being verified
+	process := func(commit Commit, sig *signatureInfo) error {
+		totalScanned++
 
-	for _, commit := range commits {
 		// Determine if pushed
 		var isPushed bool
 		if noUpstream {
@@ -256,32 +431,66 @@ func Scan(opts ScanOptions) (*ScanResult, error) {
 			localOnlyCount++
 		}
 
-		// Check for mismatch (case-insensitive email comparison)
-		isMismatched := !strings.EqualFold(commit.AuthorEmail, expectedIdentity.Email)
+		// Check author and committer for mismatch (case-insensitive email comparison)
+		kind := classifyMismatch(commit, expectedIdentity.Email)
+		isMismatched := kind != NoMismatch
 		if isMismatched {
 			mismatchCount++
 		}
 
-		// Include in results if mismatch or ShowAll
-		if isMismatched || opts.ShowAll {
+		// Check signature
+		var sigStatus SignatureStatus
+		if opts.VerifySignatures {
+			sigStatus = SignatureUnknown
+			if sig != nil {
+				sigStatus = classifySignature(*sig, signingKey)
+			}
+			if sigStatus == SignatureBad {
+				badSignatureCount++
+			}
+		}
+
+		// Include in results if mismatch, bad signature, or ShowAll
+		if isMismatched || sigStatus == SignatureBad || opts.ShowAll {
 			results = append(results, Result{
-				Commit:        commit,
-				ExpectedEmail: expectedIdentity.Email,
-				IsMismatched:  isMismatched,
-				IsPushed:      isPushed,
+				Commit:          commit,
+				ExpectedEmail:   expectedIdentity.Email,
+				IsMismatched:    isMismatched,
+				MismatchKind:    kind,
+				IsPushed:        isPushed,
+				SignatureStatus: sigStatus,
 			})
 		}
+		return nil
+	}
+
+	// Process commits as git log streams them
+	if verifySignatures {
+		err = eachSignedCommit(limit, opts.Since, opts.Until, opts.Author, func(commit Commit, sig signatureInfo) error {
+			return process(commit, &sig)
+		})
+	} else {
+		err = EachCommit(limit, opts.Since, opts.Until, opts.Author, func(commit Commit) error {
+			return process(commit, nil)
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commits: %w", err)
 	}
 
 	return &ScanResult{
 		Results:        results,
+		ExpectedName:   expectedIdentity.Name,
 		ExpectedEmail:  expectedIdentity.Email,
 		MatchedRule:    matchedRule,
-		TotalScanned:   len(commits),
+		TotalScanned:   totalScanned,
 		MismatchCount:  mismatchCount,
 		LocalOnlyCount: localOnlyCount,
 		PushedCount:    pushedCount,
 		NoUpstream:     noUpstream,
+		// Signature results (only populated with VerifySignatures)
+		BadSignatureCount:  badSignatureCount,
+		SignaturesVerified: verifySignatures,
 	}, nil
 }
 
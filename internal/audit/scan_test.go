@@ -1,15 +1,18 @@
 package audit
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
 // TestParseCommitLine_Valid tests parsing a normal commit line
 func TestParseCommitLine_Valid(t *testing.T) {
-	line := "abc1234|||John Doe|||john@example.com|||2024-01-15 10:30:00 -0500|||Add new feature"
+	line := "abc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Add new feature"
 
 	commit, err := parseCommitLine(line)
 	if err != nil {
@@ -36,10 +39,8 @@ func TestParseCommitLine_Valid(t *testing.T) {
 }
 
 // TestParseCommitLine_SpecialChars tests parsing with special characters in subject
-// Note: If subject contains our delimiter (|||), parsing will fail gracefully
 func TestParseCommitLine_SpecialChars(t *testing.T) {
-	// Subject with special characters but NOT our delimiter
-	line := "abc1234|||Jane Doe|||jane@example.com|||2024-01-15 10:30:00 -0500|||Fix: handle \"quotes\" and <brackets>"
+	line := "abc1234\x00Jane Doe\x00jane@example.com\x00Jane Doe\x00jane@example.com\x002024-01-15 10:30:00 -0500\x00Fix: handle \"quotes\" and <brackets>"
 
 	commit, err := parseCommitLine(line)
 	if err != nil {
@@ -49,20 +50,23 @@ func TestParseCommitLine_SpecialChars(t *testing.T) {
 	if commit.Subject != "Fix: handle \"quotes\" and <brackets>" {
 		t.Errorf("unexpected subject: %q", commit.Subject)
 	}
+}
 
-	// Subject containing delimiter - this will produce more than 5 parts
-	// The 5th field will be partial, but it should still work since we have >= 5 parts
-	lineWithDelim := "abc1234|||Jane|||jane@example.com|||2024-01-15 10:30:00 -0500|||feat: add ||| support"
+// TestParseCommitLine_PipesInSubject tests that a subject containing the old
+// ||| delimiter is kept intact
+func TestParseCommitLine_PipesInSubject(t *testing.T) {
+	line := "abc1234\x00Jane\x00jane@example.com\x00Jane\x00jane@example.com\x002024-01-15 10:30:00 -0500\x00feat: add ||| support"
 
-	commit2, err := parseCommitLine(lineWithDelim)
+	commit, err := parseCommitLine(line)
 	if err != nil {
-		t.Fatalf("unexpected error for line with delimiter in subject: %v", err)
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	// Subject will be truncated at the first ||| since we split by it
-	// This is acceptable - the 5th part becomes the subject (may be partial)
-	if commit2.Hash != "abc1234" {
-		t.Errorf("expected hash 'abc1234', got %q", commit2.Hash)
+	if commit.Subject != "feat: add ||| support" {
+		t.Errorf("expected full subject, got %q", commit.Subject)
+	}
+	if commit.AuthorEmail != "jane@example.com" {
+		t.Errorf("expected author email 'jane@example.com', got %q", commit.AuthorEmail)
 	}
 }
 
@@ -76,7 +80,7 @@ func TestParseCommitLine_Empty(t *testing.T) {
 
 // TestParseCommitLine_MalformedDate tests parsing with invalid date format
 func TestParseCommitLine_MalformedDate(t *testing.T) {
-	line := "abc1234|||John Doe|||john@example.com|||not-a-date|||Add feature"
+	line := "abc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x00not-a-date\x00Add feature"
 
 	_, err := parseCommitLine(line)
 	if err == nil {
@@ -94,9 +98,10 @@ func TestParseCommitLine_InsufficientFields(t *testing.T) {
 		input string
 	}{
 		{"one_field", "abc1234"},
-		{"two_fields", "abc1234|||John Doe"},
-		{"three_fields", "abc1234|||John Doe|||john@example.com"},
-		{"four_fields", "abc1234|||John Doe|||john@example.com|||2024-01-15 10:30:00 -0500"},
+		{"two_fields", "abc1234\x00John Doe"},
+		{"three_fields", "abc1234\x00John Doe\x00john@example.com"},
+		{"four_fields", "abc1234\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500"},
+		{"six_fields", "abc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500"},
 	}
 
 	for _, tc := range testCases {
@@ -114,9 +119,7 @@ func TestParseCommitLine_InsufficientFields(t *testing.T) {
 
 // TestParseCommits_Multiple tests parsing multiple commits
 func TestParseCommits_Multiple(t *testing.T) {
-	output := `<<<COMMIT>>>abc1234|||John Doe|||john@example.com|||2024-01-15 10:30:00 -0500|||First commit
-<<<COMMIT>>>def5678|||Jane Doe|||jane@example.com|||2024-01-16 11:45:00 -0500|||Second commit
-<<<COMMIT>>>ghi9012|||Bob Smith|||bob@example.com|||2024-01-17 09:00:00 -0500|||Third commit`
+	output := "\x00\x1eabc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00First commit\n\x00\x1edef5678\x00Jane Doe\x00jane@example.com\x00Jane Doe\x00jane@example.com\x002024-01-16 11:45:00 -0500\x00Second commit\n\x00\x1eghi9012\x00Bob Smith\x00bob@example.com\x00Bob Smith\x00bob@example.com\x002024-01-17 09:00:00 -0500\x00Third commit"
 
 	commits, err := parseCommits(output)
 	if err != nil {
@@ -170,7 +173,7 @@ func TestParseCommits_Empty(t *testing.T) {
 
 // TestParseCommits_SingleCommit tests parsing a single commit
 func TestParseCommits_SingleCommit(t *testing.T) {
-	output := "<<<COMMIT>>>abc1234|||John Doe|||john@example.com|||2024-01-15 10:30:00 -0500|||Only commit"
+	output := "\x00\x1eabc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Only commit"
 
 	commits, err := parseCommits(output)
 	if err != nil {
@@ -188,9 +191,7 @@ func TestParseCommits_SingleCommit(t *testing.T) {
 
 // TestParseCommits_SkipsMalformed tests that malformed commits are skipped
 func TestParseCommits_SkipsMalformed(t *testing.T) {
-	output := `<<<COMMIT>>>abc1234|||John Doe|||john@example.com|||2024-01-15 10:30:00 -0500|||Good commit
-<<<COMMIT>>>malformed_line_missing_fields
-<<<COMMIT>>>def5678|||Jane Doe|||jane@example.com|||2024-01-16 11:45:00 -0500|||Another good commit`
+	output := "\x00\x1eabc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Good commit\n\x00\x1emalformed_line_missing_fields\n\x00\x1edef5678\x00Jane Doe\x00jane@example.com\x00Jane Doe\x00jane@example.com\x002024-01-16 11:45:00 -0500\x00Another good commit"
 
 	commits, err := parseCommits(output)
 	if err != nil {
@@ -392,12 +393,7 @@ func TestScanResult_EmptyResults(t *testing.T) {
 // TestParseCommits_WithNewlines tests parsing commits with newlines in output
 func TestParseCommits_WithNewlines(t *testing.T) {
 	// Git log output often has trailing newlines
-	output := `
-<<<COMMIT>>>abc1234|||John Doe|||john@example.com|||2024-01-15 10:30:00 -0500|||First commit
-
-<<<COMMIT>>>def5678|||Jane Doe|||jane@example.com|||2024-01-16 11:45:00 -0500|||Second commit
-
-`
+	output := "\n\x00\x1eabc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00First commit\n\n\x00\x1edef5678\x00Jane Doe\x00jane@example.com\x00Jane Doe\x00jane@example.com\x002024-01-16 11:45:00 -0500\x00Second commit\n\n"
 
 	commits, err := parseCommits(output)
 	if err != nil {
@@ -411,7 +407,7 @@ func TestParseCommits_WithNewlines(t *testing.T) {
 
 // TestParseCommitLine_WhitespaceHandling tests that whitespace is trimmed
 func TestParseCommitLine_WhitespaceHandling(t *testing.T) {
-	line := "  abc1234  |||  John Doe  |||  john@example.com  |||  2024-01-15 10:30:00 -0500  |||  Subject with spaces  "
+	line := "  abc1234  \x00  John Doe  \x00  john@example.com  \x00  John Doe  \x00  john@example.com  \x00  2024-01-15 10:30:00 -0500  \x00  Subject with spaces  "
 
 	commit, err := parseCommitLine(line)
 	if err != nil {
@@ -429,3 +425,430 @@ func TestParseCommitLine_WhitespaceHandling(t *testing.T) {
 		t.Errorf("expected trimmed subject, got %q", commit.Subject)
 	}
 }
+
+// TestBuildLogArgs_NoBounds tests git log args without limit or date range
+func TestBuildLogArgs_NoBounds(t *testing.T) {
+	args := buildLogArgs(0, time.Time{}, time.Time{}, "")
+
+	if len(args) != 2 || args[0] != "log" || !strings.HasPrefix(args[1], "--format=") {
+		t.Errorf("expected [log --format=...], got %v", args)
+	}
+}
+
+// TestBuildLogArgs_WithBounds tests that limit and date range are passed to git
+func TestBuildLogArgs_WithBounds(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+
+	args := buildLogArgs(50, since, until, "")
+	joined := strings.Join(args, " ")
+
+	for _, want := range []string{
+		"--max-count=50",
+		"--since=2024-01-01T00:00:00Z",
+		"--until=2024-03-31T23:59:59Z",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %q in args, got %v", want, args)
+		}
+	}
+}
+
+// TestBuildLogArgs_Author tests that an author pattern is passed to git
+func TestBuildLogArgs_Author(t *testing.T) {
+	args := buildLogArgs(0, time.Time{}, time.Time{}, "jane@example.com")
+
+	found := false
+	for _, arg := range args {
+		if arg == "--author=jane@example.com" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected --author=jane@example.com in args, got %v", args)
+	}
+}
+
+// TestBuildLogArgs_NoAuthor tests that an empty author scans all commits
+func TestBuildLogArgs_NoAuthor(t *testing.T) {
+	args := buildLogArgs(50, time.Time{}, time.Time{}, "")
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "--author") {
+			t.Errorf("expected no --author in args, got %v", args)
+		}
+	}
+}
+
+// TestBuildLogArgs_SinceOnly tests that a zero until bound is omitted
+func TestBuildLogArgs_SinceOnly(t *testing.T) {
+	args := buildLogArgs(0, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}, "")
+	joined := strings.Join(args, " ")
+
+	if !strings.Contains(joined, "--since=") {
+		t.Errorf("expected --since in args, got %v", args)
+	}
+	if strings.Contains(joined, "--until=") || strings.Contains(joined, "--max-count") {
+		t.Errorf("expected no --until or --max-count in args, got %v", args)
+	}
+}
+
+// TestParseDate tests absolute and relative date parsing for --since/--until
+func TestParseDate(t *testing.T) {
+	now := time.Date(2024, 6, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		input    string
+		endOfDay bool
+		want     time.Time
+	}{
+		{"2024-01-15", false, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-15", true, time.Date(2024, 1, 15, 23, 59, 59, 0, time.UTC)},
+		{"2024-01-15T10:00:00Z", false, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{"today", false, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", false, time.Date(2024, 6, 14, 0, 0, 0, 0, time.UTC)},
+		{"2 weeks ago", false, time.Date(2024, 6, 1, 14, 30, 0, 0, time.UTC)},
+		{"3.days.ago", false, time.Date(2024, 6, 12, 14, 30, 0, 0, time.UTC)},
+		{"1 month ago", false, time.Date(2024, 5, 15, 14, 30, 0, 0, time.UTC)},
+		{"6 hours ago", false, time.Date(2024, 6, 15, 8, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.input, now, tt.endOfDay)
+		if err != nil {
+			t.Errorf("ParseDate(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseDate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestParseDate_Invalid tests that unparseable dates are rejected
+func TestParseDate_Invalid(t *testing.T) {
+	now := time.Now()
+	for _, input := range []string{"", "next tuesday", "2024-13-01", "two weeks ago", "5 fortnights ago"} {
+		if _, err := ParseDate(input, now, false); err == nil {
+			t.Errorf("ParseDate(%q) expected error, got nil", input)
+		}
+	}
+}
+
+// TestParseCommitLine_Committer tests that committer fields are parsed separately from author
+func TestParseCommitLine_Committer(t *testing.T) {
+	line := "abc1234\x00John Doe\x00john@work.com\x00John Doe\x00john@personal.com\x002024-01-15 10:30:00 -0500\x00Rebased commit"
+
+	commit, err := parseCommitLine(line)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if commit.AuthorEmail != "john@work.com" {
+		t.Errorf("expected author email 'john@work.com', got %q", commit.AuthorEmail)
+	}
+	if commit.CommitterName != "John Doe" {
+		t.Errorf("expected committer name 'John Doe', got %q", commit.CommitterName)
+	}
+	if commit.CommitterEmail != "john@personal.com" {
+		t.Errorf("expected committer email 'john@personal.com', got %q", commit.CommitterEmail)
+	}
+}
+
+// TestClassifyMismatch tests author/committer mismatch detection
+func TestClassifyMismatch(t *testing.T) {
+	testCases := []struct {
+		name      string
+		author    string
+		committer string
+		want      MismatchKind
+	}{
+		{"both_match", "john@work.com", "john@work.com", NoMismatch},
+		{"case_insensitive", "JOHN@work.com", "John@Work.com", NoMismatch},
+		{"author_wrong", "john@personal.com", "john@work.com", AuthorMismatch},
+		{"committer_wrong", "john@work.com", "john@personal.com", CommitterMismatch},
+		{"both_wrong", "john@personal.com", "john@personal.com", BothMismatch},
+		{"empty_committer_ignored", "john@work.com", "", NoMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			commit := Commit{AuthorEmail: tc.author, CommitterEmail: tc.committer}
+			if got := classifyMismatch(commit, "john@work.com"); got != tc.want {
+				t.Errorf("classifyMismatch() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestGenerateMailmap_Committer tests that wrong committer emails are remapped too
+func TestGenerateMailmap_Committer(t *testing.T) {
+	mismatches := []Result{
+		{
+			Commit:       Commit{AuthorEmail: "john@work.com", CommitterEmail: "john@laptop.local"},
+			IsMismatched: true,
+			MismatchKind: CommitterMismatch,
+		},
+		{
+			Commit:       Commit{AuthorEmail: "john@personal.com", CommitterEmail: "john@work.com"},
+			IsMismatched: true,
+			MismatchKind: AuthorMismatch,
+		},
+	}
+
+	mailmap := GenerateMailmap(mismatches, "", "john@work.com")
+
+	if !strings.Contains(mailmap, "<john@work.com> <john@laptop.local>") {
+		t.Errorf("expected committer email remapped, got:\n%s", mailmap)
+	}
+	if !strings.Contains(mailmap, "<john@work.com> <john@personal.com>") {
+		t.Errorf("expected author email remapped, got:\n%s", mailmap)
+	}
+	if strings.Contains(mailmap, "<john@work.com> <john@work.com>") {
+		t.Errorf("expected correct email not to be remapped, got:\n%s", mailmap)
+	}
+}
+
+// TestGenerateMailmap_Name tests that the expected name is used to remap author names
+func TestGenerateMailmap_Name(t *testing.T) {
+	mismatches := []Result{
+		{
+			Commit:       Commit{AuthorName: "johnny", AuthorEmail: "john@personal.com", CommitterEmail: "john@personal.com"},
+			IsMismatched: true,
+			MismatchKind: BothMismatch,
+		},
+	}
+
+	mailmap := GenerateMailmap(mismatches, "John Doe", "john@work.com")
+
+	if mailmap != "John Doe <john@work.com> <john@personal.com>" {
+		t.Errorf("expected name and email remap line, got:\n%s", mailmap)
+	}
+}
+
+// TestGenerateMailmap_EmailOnly tests the email-only form when no name is known
+func TestGenerateMailmap_EmailOnly(t *testing.T) {
+	mismatches := []Result{
+		{
+			Commit:       Commit{AuthorEmail: "john@personal.com", CommitterEmail: "john@work.com"},
+			IsMismatched: true,
+			MismatchKind: AuthorMismatch,
+		},
+	}
+
+	mailmap := GenerateMailmap(mismatches, "", "john@work.com")
+
+	if mailmap != "<john@work.com> <john@personal.com>" {
+		t.Errorf("expected email-only remap line, got:\n%s", mailmap)
+	}
+}
+
+// TestBuildSignatureLogArgs tests that signatures are read with the commit fields over the same range
+func TestBuildSignatureLogArgs(t *testing.T) {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	args := buildSignatureLogArgs(50, since, time.Time{}, "jane@example.com")
+	joined := strings.Join(args, " ")
+
+	for _, want := range []string{"%H", "%ae", "%ce", "%G?", "%GK", "%GF", "%GP", "--max-count=50", "--since=2024-01-01T00:00:00Z", "--author=jane@example.com"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected %q in args, got %v", want, args)
+		}
+	}
+}
+
+// TestParseSignedCommit tests parsing a commit record with signature details
+func TestParseSignedCommit(t *testing.T) {
+	record := "abc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Add feature" +
+		"\x00G\x00ABCD1234EFGH5678\x000000AAAA1111BBBB2222CCCC3333DDDD4444EEEE\x000000AAAA1111BBBB2222CCCC3333DDDD4444EEEE"
+
+	commit, sig, err := parseSignedCommit(record)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commit.Hash != "abc1234" || commit.Subject != "Add feature" {
+		t.Errorf("unexpected commit: %+v", commit)
+	}
+	if sig.Code != "G" || sig.KeyID != "ABCD1234EFGH5678" || sig.PrimaryFingerprint != "0000AAAA1111BBBB2222CCCC3333DDDD4444EEEE" {
+		t.Errorf("unexpected signature: %+v", sig)
+	}
+
+	unsigned := "def5678\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Fix bug\x00N\x00\x00\x00"
+	if _, sig, err := parseSignedCommit(unsigned); err != nil || sig.Code != "N" {
+		t.Errorf("parseSignedCommit(unsigned) = %+v, %v; want code N", sig, err)
+	}
+
+	// A record without the signature fields is malformed
+	if _, _, err := parseSignedCommit("abc1234\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Add feature"); err == nil {
+		t.Error("expected error for a record without signature fields")
+	}
+}
+
+// TestClassifySignature tests mapping git signature codes to SignatureStatus
+func TestClassifySignature(t *testing.T) {
+	expected := &expectedKey{ID: "ABCD1234EFGH5678"}
+	good := signatureInfo{Code: "G", KeyID: "ABCD1234EFGH5678"}
+	otherKey := signatureInfo{Code: "G", KeyID: "1111222233334444"}
+
+	testCases := []struct {
+		name string
+		sig  signatureInfo
+		key  *expectedKey
+		want SignatureStatus
+	}{
+		{"good_expected_key", good, expected, SignatureGood},
+		{"good_no_expected_key", otherKey, nil, SignatureGood},
+		{"good_unknown_validity", signatureInfo{Code: "U", KeyID: "ABCD1234EFGH5678"}, expected, SignatureGood},
+		{"good_wrong_key", otherKey, expected, SignatureBad},
+		{"bad", signatureInfo{Code: "B"}, expected, SignatureBad},
+		{"revoked", signatureInfo{Code: "R"}, expected, SignatureBad},
+		{"unsigned", signatureInfo{Code: "N"}, expected, SignatureNone},
+		{"missing", signatureInfo{}, expected, SignatureNone},
+		{"cannot_check", signatureInfo{Code: "E"}, expected, SignatureUnknown},
+		{"expired", signatureInfo{Code: "X"}, expected, SignatureUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := classifySignature(tc.sig, tc.key); got != tc.want {
+				t.Errorf("classifySignature() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestExpectedKey_MatchesFingerprint tests matching by primary or subkey fingerprint
+func TestExpectedKey_MatchesFingerprint(t *testing.T) {
+	key := &expectedKey{ID: "3333DDDD4444EEEE", Fingerprint: "0000AAAA1111BBBB2222CCCC3333DDDD4444EEEE"}
+
+	// Signed by a subkey: primary fingerprint matches
+	subkeySig := signatureInfo{
+		Code:               "G",
+		KeyID:              "9999888877776666",
+		Fingerprint:        "FFFF000011112222333344449999888877776666",
+		PrimaryFingerprint: "0000aaaa1111bbbb2222cccc3333dddd4444eeee",
+	}
+	if !key.matches(subkeySig) {
+		t.Error("expected subkey signature to match primary fingerprint")
+	}
+
+	other := signatureInfo{Code: "G", Fingerprint: "FFFF000011112222333344449999888877776666"}
+	if key.matches(other) {
+		t.Error("expected different fingerprint not to match")
+	}
+}
+
+// TestReadCommits_SmallReads tests that commits split across reads are reassembled
+func TestReadCommits_SmallReads(t *testing.T) {
+	output := generateLogOutput(50)
+
+	var commits []Commit
+	err := readCommits(iotest.OneByteReader(strings.NewReader(output)), func(commit Commit) error {
+		commits = append(commits, commit)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(commits) != 50 {
+		t.Fatalf("expected 50 commits, got %d", len(commits))
+	}
+	if commits[49].Subject != "Commit 49" {
+		t.Errorf("expected last subject 'Commit 49', got %q", commits[49].Subject)
+	}
+}
+
+// TestReadCommits_StopsOnError tests that a callback error stops reading
+func TestReadCommits_StopsOnError(t *testing.T) {
+	stop := errors.New("stop")
+	seen := 0
+
+	err := readCommits(strings.NewReader(generateLogOutput(10)), func(commit Commit) error {
+		seen++
+		if seen == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if seen != 3 {
+		t.Errorf("expected reading to stop after 3 commits, got %d", seen)
+	}
+}
+
+// generateLogOutput builds git log output for n commits in the GetCommits format
+func generateLogOutput(n int) string {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\x00\x1e%040x\x00John Doe\x00john@example.com\x00John Doe\x00john@example.com\x002024-01-15 10:30:00 -0500\x00Commit %d\n", i, i)
+	}
+	return b.String()
+}
+
+// BenchmarkReadCommits measures streaming parse throughput on a large history
+func BenchmarkReadCommits(b *testing.B) {
+	output := generateLogOutput(100000)
+	b.SetBytes(int64(len(output)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		count := 0
+		err := readCommits(strings.NewReader(output), func(commit Commit) error {
+			count++
+			return nil
+		})
+		if err != nil || count != 100000 {
+			b.Fatalf("readCommits() = %d commits, %v", count, err)
+		}
+	}
+}
+
+// TestFixDryRun_NoMismatches tests that a dry run without mismatches reports an error
+func TestFixDryRun_NoMismatches(t *testing.T) {
+	result := &ScanResult{
+		Results: []Result{
+			{Commit: Commit{Hash: "abc1234"}, IsMismatched: false},
+		},
+		ExpectedEmail: "john@work.com",
+	}
+
+	if err := FixDryRun(result, FixOptions{}); err == nil {
+		t.Error("expected error when there is nothing to fix")
+	}
+}
+
+// TestMismatchedResults tests that only mismatched commits are selected for fixing
+func TestMismatchedResults(t *testing.T) {
+	results := []Result{
+		{Commit: Commit{Hash: "abc1234"}, IsMismatched: true},
+		{Commit: Commit{Hash: "def5678"}, IsMismatched: false},
+		{Commit: Commit{Hash: "ghi9012"}, IsMismatched: true},
+	}
+
+	toFix := mismatchedResults(results)
+	if len(toFix) != 2 || toFix[0].Commit.Hash != "abc1234" || toFix[1].Commit.Hash != "ghi9012" {
+		t.Errorf("unexpected results to fix: %+v", toFix)
+	}
+}
+
+// TestCheckBackupDir tests validation of the backup directory
+func TestCheckBackupDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := CheckBackupDir(dir); err != nil {
+		t.Errorf("expected writable temp dir to pass, got %v", err)
+	}
+
+	if err := CheckBackupDir(dir + "/missing"); err == nil {
+		t.Error("expected error for missing directory")
+	}
+
+	file := dir + "/file"
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckBackupDir(file); err == nil {
+		t.Error("expected error for a file")
+	}
+}
@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/orzazade/gitch/internal/gpg"
+)
+
+// SignatureStatus describes whether a commit's GPG signature validates
+// against the expected identity's key
+type SignatureStatus string
+
+const (
+	// SignatureGood means the signature is valid (and made by the expected key, if one is configured)
+	SignatureGood SignatureStatus = "good"
+	// SignatureBad means the signature is invalid, revoked, or made by a different key
+	SignatureBad SignatureStatus = "bad"
+	// SignatureNone means the commit is not signed
+	SignatureNone SignatureStatus = "none"
+	// SignatureUnknown means the signature could not be checked (e.g., gpg missing or key not in keyring)
+	SignatureUnknown SignatureStatus = "unknown"
+)
+
+// signatureInfo holds the raw signature fields reported by git for a commit
+type signatureInfo struct {
+	Code               string // %G? status letter
+	KeyID              string // %GK signing key ID
+	Fingerprint        string // %GF signing key fingerprint
+	PrimaryFingerprint string // %GP primary key fingerprint (differs when signed by a subkey)
+}
+
+// buildSignatureLogArgs constructs git log arguments that report each
+// commit's signature details after its fields, for the same commit range as
+// buildLogArgs
+func buildSignatureLogArgs(limit int, since, until time.Time, author string) []string {
+	// Format: <commit fields><NUL>status<NUL>key id<NUL>fingerprint<NUL>primary fingerprint
+	format := commitFormat + fmt.Sprintf("%s%%G?%s%%GK%s%%GF%s%%GP",
+		fieldDelimFormat, fieldDelimFormat, fieldDelimFormat, fieldDelimFormat)
+	return logArgs(format, limit, since, until, author)
+}
+
+// eachSignedCommit streams commits and their signature details to fn, like
+// EachCommit. git verifies each signature using gpg while producing the output.
+func eachSignedCommit(limit int, since, until time.Time, author string, fn func(Commit, signatureInfo) error) error {
+	return runLog(buildSignatureLogArgs(limit, since, until, author), func(r io.Reader) error {
+		return readRecords(r, func(record string) error {
+			commit, sig, err := parseSignedCommit(record)
+			if err != nil {
+				// Skip malformed commits instead of failing entirely
+				return nil
+			}
+			return fn(commit, sig)
+		})
+	})
+}
+
+// parseSignedCommit parses a commit record produced by buildSignatureLogArgs
+func parseSignedCommit(record string) (Commit, signatureInfo, error) {
+	commit, err := parseCommitLine(record)
+	if err != nil {
+		return Commit{}, signatureInfo{}, err
+	}
+
+	fields := strings.Split(record, fieldDelim)
+	if len(fields) < 11 {
+		return Commit{}, signatureInfo{}, fmt.Errorf("malformed commit line: expected 11 fields, got %d", len(fields))
+	}
+
+	return commit, signatureInfo{
+		Code:               strings.TrimSpace(fields[7]),
+		KeyID:              strings.TrimSpace(fields[8]),
+		Fingerprint:        strings.TrimSpace(fields[9]),
+		PrimaryFingerprint: strings.TrimSpace(fields[10]),
+	}, nil
+}
+
+// expectedKey identifies the signing key of the expected identity.
+// Fingerprint is empty when the key isn't in the local keyring.
+type expectedKey struct {
+	ID          string
+	Fingerprint string
+}
+
+// resolveExpectedKey looks up the expected identity's GPG key.
+// Returns nil if the identity has no GPG key configured.
+func resolveExpectedKey(gpgKeyID string) *expectedKey {
+	if gpgKeyID == "" {
+		return nil
+	}
+
+	key := &expectedKey{ID: gpgKeyID}
+	if info, err := gpg.GetKeyInfo(gpgKeyID); err == nil {
+		key.ID = info.ID
+		key.Fingerprint = info.Fingerprint
+	}
+	return key
+}
+
+// matches reports whether a signature was made by this key or one of its subkeys
+func (k *expectedKey) matches(sig signatureInfo) bool {
+	if k.Fingerprint != "" {
+		return strings.EqualFold(sig.PrimaryFingerprint, k.Fingerprint) ||
+			strings.EqualFold(sig.Fingerprint, k.Fingerprint)
+	}
+
+	// Without a fingerprint, compare key IDs (a long ID is the fingerprint's suffix)
+	id := strings.ToUpper(k.ID)
+	for _, candidate := range []string{sig.KeyID, sig.Fingerprint, sig.PrimaryFingerprint} {
+		candidate = strings.ToUpper(candidate)
+		if candidate != "" && (strings.HasSuffix(candidate, id) || strings.HasSuffix(id, candidate)) {
+			return true
+		}
+	}
+	return false
+}
+
+// classifySignature converts git's signature status into a SignatureStatus,
+// cross-referencing the signing key against the expected key when one is known
+func classifySignature(sig signatureInfo, key *expectedKey) SignatureStatus {
+	switch sig.Code {
+	case "G", "U":
+		// Good signature (U = good but key validity unknown)
+		if key != nil && !key.matches(sig) {
+			return SignatureBad
+		}
+		return SignatureGood
+	case "B", "R":
+		// Bad signature, or made by a revoked key
+		return SignatureBad
+	case "N", "":
+		return SignatureNone
+	default:
+		// E = cannot be checked (missing key), X/Y = expired signature/key
+		return SignatureUnknown
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// backupTimeFormat is the timestamp in backup file names
+const backupTimeFormat = "20060102-150405"
+
+// ErrNoConfigFile is returned when backing up a config file that doesn't exist yet
+var ErrNoConfigFile = errors.New("no config file to back up")
+
+// Backup copies the config file to a timestamped file next to it, e.g.
+// config.yaml.20260102-150405.bak, and returns the backup path.
+// The backup is readable only by the owner since the config may hold HTTPS tokens.
+// Returns ErrNoConfigFile if the config file doesn't exist.
+func Backup() (string, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine config path: %w", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoConfigFile
+		}
+		return "", fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	// Never overwrite an earlier backup made in the same second
+	stamp := time.Now().Format(backupTimeFormat)
+	for i := 0; ; i++ {
+		backupPath := fmt.Sprintf("%s.%s.bak", configPath, stamp)
+		if i > 0 {
+			backupPath = fmt.Sprintf("%s.%s-%d.bak", configPath, stamp, i)
+		}
+
+		file, err := os.OpenFile(backupPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to create backup: %w", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			file.Close()
+			os.Remove(backupPath)
+			return "", fmt.Errorf("failed to write backup: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			os.Remove(backupPath)
+			return "", fmt.Errorf("failed to write backup: %w", err)
+		}
+		return backupPath, nil
+	}
+}
+
+// Restore replaces the config file with the config at path, after checking
+// that it parses, its identities are valid and its rules and default refer
+// to existing identities. The file is copied as-is and the swap is atomic.
+func Restore(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	cfg, err := parse(data)
+	if err != nil {
+		return err
+	}
+	var problems []error
+	for _, identity := range cfg.Identities {
+		if err := identity.Validate(); err != nil {
+			problems = append(problems, fmt.Errorf("identity %q: %w", identity.Name, err))
+		}
+	}
+	problems = append(problems, cfg.Validate()...)
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config in %s: %w", path, errors.Join(problems...))
+	}
+
+	configPath, err := ConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine config path: %w", err)
+	}
+	return WithLock(func() error {
+		return writeConfigFile(configPath, data)
+	})
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupRestore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(ConfigEnvVar, path)
+
+	if _, err := Backup(); !errors.Is(err, ErrNoConfigFile) {
+		t.Fatalf("Backup() without config error = %v, want ErrNoConfigFile", err)
+	}
+
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	backupPath, err := Backup()
+	if err != nil {
+		t.Fatalf("Backup() returned error: %v", err)
+	}
+	if filepath.Dir(backupPath) != filepath.Dir(path) {
+		t.Errorf("backup %q not next to config %q", backupPath, path)
+	}
+
+	// A second backup in the same second must not overwrite the first
+	second, err := Backup()
+	if err != nil {
+		t.Fatalf("second Backup() returned error: %v", err)
+	}
+	if second == backupPath {
+		t.Errorf("second Backup() reused path %q", backupPath)
+	}
+
+	// Change the config, then restore the backup
+	cfg.Identities[0].Email = "changed@example.com"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := Restore(backupPath); err != nil {
+		t.Fatalf("Restore() returned error: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if loaded.Identities[0].Email != "work@example.com" {
+		t.Errorf("email after restore = %q, want work@example.com", loaded.Identities[0].Email)
+	}
+}
+
+func TestRestore_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	t.Setenv(ConfigEnvVar, path)
+
+	original := []byte("identities:\n  - name: work\n    email: work@example.com\n")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"unparseable":     "identities: [",
+		"invalid email":   "identities:\n  - name: work\n    email: not-an-email\n",
+		"dangling rule":   "identities: []\nrules:\n  - type: directory\n    pattern: ~/work/**\n    identity: work\n",
+		"missing default": "default: work\nidentities: []\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			backupPath := filepath.Join(dir, "bad.yaml")
+			if err := os.WriteFile(backupPath, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := Restore(backupPath); err == nil {
+				t.Fatal("Restore() returned nil error, want validation error")
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != string(original) {
+				t.Errorf("config changed after failed restore: %q", data)
+			}
+		})
+	}
+}
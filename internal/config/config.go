@@ -17,10 +17,52 @@ type Config struct {
 	Default    string       `mapstructure:"default" yaml:"default"`
 	Identities []Identity   `mapstructure:"identities" yaml:"identities"`
 	Rules      []rules.Rule `mapstructure:"rules" yaml:"rules,omitempty"`
+	// RequireRule controls what the pre-commit hook does in a repository no
+	// rule matches: off (the default), warn or block.
+	RequireRule string `mapstructure:"require_rule" yaml:"require_rule,omitempty"`
+	// SSHConfigInclude is the file 'gitch ssh-config update --include-file'
+	// writes the managed block to, or "" if the block is inline in
+	// ~/.ssh/config.
+	SSHConfigInclude string `mapstructure:"ssh_config_include" yaml:"ssh_config_include,omitempty"`
 }
 
-// ConfigPath returns the XDG config file path for gitch
+// RequireRule constants define how the pre-commit hook treats repositories
+// that no rule matches
+const (
+	RequireRuleOff   = "off"   // Allow the commit silently (default)
+	RequireRuleWarn  = "warn"  // Allow the commit with a warning
+	RequireRuleBlock = "block" // Block the commit until a rule is added
+)
+
+// ValidateRequireRule validates that the require_rule setting is a valid value
+func ValidateRequireRule(mode string) error {
+	switch mode {
+	case RequireRuleOff, RequireRuleWarn, RequireRuleBlock:
+		return nil
+	case "":
+		return nil // Empty is valid, defaults to off
+	default:
+		return fmt.Errorf("invalid require_rule %q: must be one of: off, warn, block", mode)
+	}
+}
+
+// GetRequireRule returns the require_rule setting, defaulting to off if not set
+func (c *Config) GetRequireRule() string {
+	if c.RequireRule == "" {
+		return RequireRuleOff
+	}
+	return c.RequireRule
+}
+
+// ConfigEnvVar is the environment variable that overrides the config file path
+const ConfigEnvVar = "GITCH_CONFIG"
+
+// ConfigPath returns the config file path for gitch
+// Uses $GITCH_CONFIG when set, otherwise the XDG config file path
 func ConfigPath() (string, error) {
+	if path := os.Getenv(ConfigEnvVar); path != "" {
+		return filepath.Abs(path)
+	}
 	return xdg.ConfigFile("gitch/config.yaml")
 }
 
@@ -43,6 +85,11 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
+	return parse(data)
+}
+
+// parse decodes config YAML and normalizes the result
+func parse(data []byte) (*Config, error) {
 	var cfg Config
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
@@ -58,28 +105,65 @@ func Load() (*Config, error) {
 		cfg.Rules = []rules.Rule{}
 	}
 
+	// Migrate identities with an ssh_key_paths list but no ssh_key_path
+	for i := range cfg.Identities {
+		cfg.Identities[i].normalizeSSHKeys()
+	}
+
 	return &cfg, nil
 }
 
 // Save writes the config to the XDG config file
+// The write is atomic (temp file + rename), so readers never see a partially
+// written file. Callers that loaded the config to change it should hold the
+// config lock (see WithLock) from Load through Save.
 func (c *Config) Save() error {
 	configPath, err := ConfigPath()
 	if err != nil {
 		return fmt.Errorf("failed to determine config path: %w", err)
 	}
 
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return writeConfigFile(configPath, data)
+}
+
+// writeConfigFile replaces the config file with data atomically
+func writeConfigFile(configPath string, data []byte) error {
 	// Create config directory if it doesn't exist
 	configDir := filepath.Dir(configPath)
 	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(c)
+	// Write to a temp file in the same directory, then rename over the config
+	tmp, err := os.CreateTemp(configDir, ".config.yaml.*.tmp")
 	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
+		return fmt.Errorf("failed to create temp config file: %w", err)
 	}
+	tmpPath := tmp.Name()
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	// The config can hold HTTPS tokens, so only the user may read it
+	if err := os.Chmod(tmpPath, 0600); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to set config file permissions: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, configPath); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -109,8 +193,11 @@ func (c *Config) GetIdentity(name string) (*Identity, error) {
 }
 
 // AddIdentity adds a new identity to the config
-// Validates the identity, checks for duplicate names, and warns on duplicate emails
+// Normalizes the email (see NormalizeEmail), validates the identity, checks
+// for duplicate names, and warns on duplicate emails
 func (c *Config) AddIdentity(identity Identity) error {
+	identity.Email = NormalizeEmail(identity.Email)
+
 	// Validate the identity
 	if err := identity.Validate(); err != nil {
 		return err
@@ -152,12 +239,66 @@ func (c *Config) DeleteIdentity(name string) error {
 	return nil
 }
 
+// RenameIdentity renames an identity and updates all references to it
+// Validates the new name and rejects collisions with other identities (case-insensitive)
+// Rewrites rules pointing at the old name and updates the default if it matched
+// Returns the number of rules that were updated
+func (c *Config) RenameIdentity(oldName, newName string) (int, error) {
+	idx := c.findIdentityIndex(oldName)
+	if idx == -1 {
+		return 0, fmt.Errorf("identity %q not found", oldName)
+	}
+
+	if err := ValidateName(newName); err != nil {
+		return 0, err
+	}
+
+	// Allow case-only renames of the same identity
+	if existing := c.findIdentityIndex(newName); existing != -1 && existing != idx {
+		return 0, fmt.Errorf("identity with name %q already exists", newName)
+	}
+
+	storedName := c.Identities[idx].Name
+	c.Identities[idx].Name = newName
+
+	// Update rules that referenced the old name
+	updated := 0
+	for i := range c.Rules {
+		if strings.EqualFold(c.Rules[i].Identity, storedName) {
+			c.Rules[i].Identity = newName
+			updated++
+		}
+	}
+
+	// Update default if it pointed at the renamed identity
+	if strings.EqualFold(c.Default, storedName) {
+		c.Default = newName
+	}
+
+	return updated, nil
+}
+
 // ListIdentities returns all identities
 // Returns an empty slice if there are no identities
 func (c *Config) ListIdentities() []Identity {
 	return c.Identities
 }
 
+// IdentitiesWithTag returns the identities that have any of the tags
+// (case-insensitive), in config order
+func (c *Config) IdentitiesWithTag(tags ...string) []Identity {
+	var identities []Identity
+	for _, identity := range c.Identities {
+		for _, tag := range tags {
+			if identity.HasTag(tag) {
+				identities = append(identities, identity)
+				break
+			}
+		}
+	}
+	return identities
+}
+
 // SetDefault sets the default identity
 // Returns an error if the identity doesn't exist
 func (c *Config) SetDefault(name string) error {
@@ -208,14 +349,93 @@ func (c *Config) RemoveRule(pattern string) error {
 	return fmt.Errorf("rule with pattern %q not found", pattern)
 }
 
+// SetRulePriority sets the priority of the rule with the given pattern (exact match)
+// Returns an error if the rule is not found
+func (c *Config) SetRulePriority(pattern string, priority int) error {
+	for i := range c.Rules {
+		if c.Rules[i].Pattern == pattern {
+			c.Rules[i].Priority = priority
+			return nil
+		}
+	}
+	return fmt.Errorf("rule with pattern %q not found", pattern)
+}
+
 // ListRules returns all rules
 func (c *Config) ListRules() []rules.Rule {
 	return c.Rules
 }
 
+// Validate checks that every rule and the default reference an existing
+// identity, and that require_rule is valid. It returns all problems found;
+// an empty slice means the config is consistent.
+func (c *Config) Validate() []error {
+	var problems []error
+
+	if err := ValidateRequireRule(c.RequireRule); err != nil {
+		problems = append(problems, err)
+	}
+
+	if c.Default != "" && c.findIdentityIndex(c.Default) == -1 {
+		problems = append(problems, fmt.Errorf("default identity %q does not exist", c.Default))
+	}
+
+	for _, rule := range c.Rules {
+		if c.findIdentityIndex(rule.Identity) == -1 {
+			problems = append(problems, fmt.Errorf("%s rule %q references unknown identity %q", rule.Type, rule.Pattern, rule.Identity))
+		}
+	}
+
+	return problems
+}
+
+// DuplicateEmails returns the emails used by more than one identity, keyed by
+// lowercased email, with the names of the identities sharing each one in
+// config order. Switching between such identities is ambiguous.
+func (c *Config) DuplicateEmails() map[string][]string {
+	byEmail := make(map[string][]string)
+	for _, id := range c.Identities {
+		email := strings.ToLower(id.Email)
+		byEmail[email] = append(byEmail[email], id.Name)
+	}
+
+	duplicates := make(map[string][]string)
+	for email, names := range byEmail {
+		if len(names) > 1 {
+			duplicates[email] = names
+		}
+	}
+	return duplicates
+}
+
+// RulesForIdentity returns all rules that reference the given identity (case-insensitive)
+func (c *Config) RulesForIdentity(name string) []rules.Rule {
+	var matched []rules.Rule
+	for _, rule := range c.Rules {
+		if strings.EqualFold(rule.Identity, name) {
+			matched = append(matched, rule)
+		}
+	}
+	return matched
+}
+
+// RemoveRulesForIdentity removes all rules that reference the given identity (case-insensitive)
+// Returns the number of rules removed
+func (c *Config) RemoveRulesForIdentity(name string) int {
+	kept := make([]rules.Rule, 0, len(c.Rules))
+	for _, rule := range c.Rules {
+		if !strings.EqualFold(rule.Identity, name) {
+			kept = append(kept, rule)
+		}
+	}
+	removed := len(c.Rules) - len(kept)
+	c.Rules = kept
+	return removed
+}
+
 // FindOverlappingRules returns rules that might conflict with the new rule
 // For directory rules: checks if patterns share a common prefix or one is a subset of another
-// For remote rules: checks if patterns share the same host and overlapping org/repo paths
+// For remote rules: checks if some remote could match both patterns
 func (c *Config) FindOverlappingRules(newRule rules.Rule) []rules.Rule {
 	var overlapping []rules.Rule
 
@@ -248,33 +468,89 @@ func (c *Config) FindOverlappingRules(newRule rules.Rule) []rules.Rule {
 
 // isDirectoryOverlap checks if two directory patterns might overlap
 func isDirectoryOverlap(pattern1, pattern2 string) bool {
-	// Normalize patterns by removing trailing wildcards for prefix comparison
-	p1 := strings.TrimSuffix(strings.TrimSuffix(pattern1, "/**"), "/*")
-	p2 := strings.TrimSuffix(strings.TrimSuffix(pattern2, "/**"), "/*")
+	// Compare the literal directories before the first wildcard, so a
+	// mid-pattern ** (~/work/**/src) is treated like a trailing one
+	p1 := directoryLiteralPrefix(pattern1)
+	p2 := directoryLiteralPrefix(pattern2)
 
 	// Check if one is a prefix of the other
 	return strings.HasPrefix(p1, p2) || strings.HasPrefix(p2, p1)
 }
 
-// isRemoteOverlap checks if two remote patterns might overlap
+// directoryLiteralPrefix returns the directories of a pattern before its
+// first wildcard segment, or the whole pattern if it has no wildcard
+func directoryLiteralPrefix(pattern string) string {
+	first := strings.IndexAny(pattern, "*?[")
+	if first == -1 {
+		return pattern
+	}
+	return pattern[:strings.LastIndex(pattern[:first], "/")+1]
+}
+
+// isRemoteOverlap checks if some remote would match both patterns.
+// Patterns are compared segment by segment (host/org/repo) using the same
+// semantics as rules.MatchRemote: a wildcard pattern must match every
+// segment of the remote, while an exact pattern also matches any remote
+// below it (github.com/company matches github.com/company/repo).
 func isRemoteOverlap(pattern1, pattern2 string) bool {
-	// Split patterns into host and path
-	parts1 := strings.SplitN(pattern1, "/", 2)
-	parts2 := strings.SplitN(pattern2, "/", 2)
+	segs1, exact1 := remotePatternSegments(pattern1)
+	segs2, exact2 := remotePatternSegments(pattern2)
 
-	// If different hosts, no overlap
-	if parts1[0] != parts2[0] {
+	// Wildcard patterns only match remotes with exactly as many segments;
+	// exact patterns match remotes with at least as many
+	switch {
+	case !exact1 && !exact2 && len(segs1) != len(segs2):
+		return false
+	case exact1 && !exact2 && len(segs2) < len(segs1):
+		return false
+	case exact2 && !exact1 && len(segs1) < len(segs2):
 		return false
 	}
 
-	// Same host - check path overlap
-	if len(parts1) < 2 || len(parts2) < 2 {
-		return true // One pattern is just the host, overlaps with all on that host
+	for i := 0; i < len(segs1) && i < len(segs2); i++ {
+		if !segmentsOverlap(segs1[i], segs2[i]) {
+			return false
+		}
 	}
+	return true
+}
 
-	path1 := strings.TrimSuffix(parts1[1], "/*")
-	path2 := strings.TrimSuffix(parts2[1], "/*")
+// remotePatternSegments splits a remote pattern into lowercase segments and
+// reports whether it is exact (contains no wildcard)
+func remotePatternSegments(pattern string) ([]string, bool) {
+	pattern = strings.ToLower(strings.Trim(pattern, "/"))
+	return strings.Split(pattern, "/"), !strings.Contains(pattern, "*")
+}
+
+// segmentsOverlap reports whether some path segment could match both glob
+// segments. Two globs are compared by their literal prefix and suffix,
+// which is conservative: it may report overlap for globs that can't both match.
+func segmentsOverlap(seg1, seg2 string) bool {
+	hasGlob1 := strings.ContainsAny(seg1, "*?[")
+	hasGlob2 := strings.ContainsAny(seg2, "*?[")
+
+	switch {
+	case !hasGlob1 && !hasGlob2:
+		return seg1 == seg2
+	case !hasGlob2:
+		matched, _ := filepath.Match(seg1, seg2)
+		return matched
+	case !hasGlob1:
+		matched, _ := filepath.Match(seg2, seg1)
+		return matched
+	}
+
+	prefix1, suffix1 := globLiterals(seg1)
+	prefix2, suffix2 := globLiterals(seg2)
+	prefixesAgree := strings.HasPrefix(prefix1, prefix2) || strings.HasPrefix(prefix2, prefix1)
+	suffixesAgree := strings.HasSuffix(suffix1, suffix2) || strings.HasSuffix(suffix2, suffix1)
+	return prefixesAgree && suffixesAgree
+}
 
-	// Check if one path is a prefix of the other
-	return strings.HasPrefix(path1, path2) || strings.HasPrefix(path2, path1)
+// globLiterals returns the literal text before the first and after the last
+// glob metacharacter
+func globLiterals(glob string) (string, string) {
+	first := strings.IndexAny(glob, "*?[")
+	last := strings.LastIndexAny(glob, "*?]")
+	return glob[:first], glob[last+1:]
 }
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/orzazade/gitch/internal/rules"
 )
 
 // testConfig creates a new config with the given identities
@@ -33,6 +35,17 @@ func TestAddIdentity_Success(t *testing.T) {
 	}
 }
 
+func TestAddIdentity_NormalizesEmail(t *testing.T) {
+	cfg := testConfig()
+
+	if err := cfg.AddIdentity(Identity{Name: "work", Email: "Jane.Doe@Company.COM"}); err != nil {
+		t.Fatalf("AddIdentity() returned error: %v", err)
+	}
+	if got := cfg.Identities[0].Email; got != "Jane.Doe@company.com" {
+		t.Errorf("stored email = %q, want %q", got, "Jane.Doe@company.com")
+	}
+}
+
 func TestAddIdentity_DuplicateName(t *testing.T) {
 	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
 
@@ -173,6 +186,189 @@ func TestDeleteIdentity_ClearsDefaultCaseInsensitive(t *testing.T) {
 	}
 }
 
+func TestRulesForIdentity(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	cfg.Rules = []rules.Rule{
+		{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		{Type: rules.RemoteRule, Pattern: "github.com/company/*", Identity: "Work"},
+		{Type: rules.DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+	}
+
+	matched := cfg.RulesForIdentity("WORK")
+	if len(matched) != 2 {
+		t.Fatalf("Expected 2 rules for 'work', got %d", len(matched))
+	}
+
+	if len(cfg.RulesForIdentity("oss")) != 0 {
+		t.Error("Expected no rules for 'oss'")
+	}
+}
+
+func TestRemoveRulesForIdentity(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	cfg.Rules = []rules.Rule{
+		{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		{Type: rules.DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+		{Type: rules.RemoteRule, Pattern: "github.com/company/*", Identity: "Work"},
+	}
+
+	removed := cfg.RemoveRulesForIdentity("work")
+	if removed != 2 {
+		t.Errorf("Expected 2 rules removed, got %d", removed)
+	}
+	if len(cfg.Rules) != 1 || cfg.Rules[0].Identity != "personal" {
+		t.Errorf("Expected only the 'personal' rule to remain, got %+v", cfg.Rules)
+	}
+}
+
+func TestSetRulePriority(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	cfg.Rules = []rules.Rule{
+		{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+	}
+
+	if err := cfg.SetRulePriority("~/work/**", 10); err != nil {
+		t.Fatalf("SetRulePriority failed: %v", err)
+	}
+	if cfg.Rules[0].Priority != 10 {
+		t.Errorf("Expected priority 10, got %d", cfg.Rules[0].Priority)
+	}
+
+	if err := cfg.SetRulePriority("~/missing/**", 1); err == nil {
+		t.Error("Expected error for unknown pattern")
+	}
+}
+
+func TestValidate_Consistent(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	cfg.Default = "Work"
+	cfg.Rules = []rules.Rule{
+		{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "WORK"},
+	}
+
+	if problems := cfg.Validate(); len(problems) != 0 {
+		t.Errorf("Validate() returned problems for a consistent config: %v", problems)
+	}
+
+	// An empty config has nothing to check
+	if problems := testConfig().Validate(); len(problems) != 0 {
+		t.Errorf("Validate() returned problems for an empty config: %v", problems)
+	}
+}
+
+func TestValidate_DanglingReferences(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	cfg.Default = "deleted"
+	cfg.Rules = []rules.Rule{
+		{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		{Type: rules.DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+		{Type: rules.RemoteRule, Pattern: "github.com/oss/*", Identity: "oss"},
+	}
+
+	problems := cfg.Validate()
+	if len(problems) != 3 {
+		t.Fatalf("Expected 3 problems, got %d: %v", len(problems), problems)
+	}
+	if !strings.Contains(problems[0].Error(), "default identity \"deleted\"") {
+		t.Errorf("Expected default problem first, got: %v", problems[0])
+	}
+	if !strings.Contains(problems[1].Error(), "~/personal/**") || !strings.Contains(problems[1].Error(), "personal") {
+		t.Errorf("Expected problem for personal rule, got: %v", problems[1])
+	}
+}
+
+func TestValidate_RequireRule(t *testing.T) {
+	for _, mode := range []string{"", RequireRuleOff, RequireRuleWarn, RequireRuleBlock} {
+		cfg := testConfig()
+		cfg.RequireRule = mode
+		if problems := cfg.Validate(); len(problems) != 0 {
+			t.Errorf("Validate() with require_rule %q returned problems: %v", mode, problems)
+		}
+	}
+
+	cfg := testConfig()
+	cfg.RequireRule = "always"
+	problems := cfg.Validate()
+	if len(problems) != 1 || !strings.Contains(problems[0].Error(), "require_rule") {
+		t.Errorf("Validate() with invalid require_rule = %v, want one require_rule problem", problems)
+	}
+
+	if got := testConfig().GetRequireRule(); got != RequireRuleOff {
+		t.Errorf("GetRequireRule() = %q, want %q", got, RequireRuleOff)
+	}
+}
+
+func TestRenameIdentity_Success(t *testing.T) {
+	cfg := testConfig(
+		Identity{Name: "work", Email: "work@example.com"},
+		Identity{Name: "personal", Email: "personal@example.com"},
+	)
+	cfg.Default = "work"
+	cfg.Rules = []rules.Rule{
+		{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		{Type: rules.RemoteRule, Pattern: "github.com/company/*", Identity: "Work"},
+		{Type: rules.DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+	}
+
+	updated, err := cfg.RenameIdentity("WORK", "company")
+	if err != nil {
+		t.Fatalf("RenameIdentity() returned error: %v", err)
+	}
+
+	if updated != 2 {
+		t.Errorf("Expected 2 rules updated, got %d", updated)
+	}
+	if cfg.Identities[0].Name != "company" {
+		t.Errorf("Expected identity to be renamed to 'company', got %q", cfg.Identities[0].Name)
+	}
+	if cfg.Default != "company" {
+		t.Errorf("Expected default to be 'company', got %q", cfg.Default)
+	}
+	if cfg.Rules[0].Identity != "company" || cfg.Rules[1].Identity != "company" {
+		t.Errorf("Expected work rules to point at 'company', got %+v", cfg.Rules)
+	}
+	if cfg.Rules[2].Identity != "personal" {
+		t.Errorf("Expected personal rule to be unchanged, got %q", cfg.Rules[2].Identity)
+	}
+}
+
+func TestRenameIdentity_Collision(t *testing.T) {
+	cfg := testConfig(
+		Identity{Name: "work", Email: "work@example.com"},
+		Identity{Name: "personal", Email: "personal@example.com"},
+	)
+
+	_, err := cfg.RenameIdentity("work", "Personal")
+	if err == nil {
+		t.Fatal("RenameIdentity() should return error for name collision")
+	}
+	if !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Error should mention 'already exists', got: %v", err)
+	}
+}
+
+func TestRenameIdentity_CaseOnly(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+
+	if _, err := cfg.RenameIdentity("work", "Work"); err != nil {
+		t.Fatalf("RenameIdentity() should allow case-only rename: %v", err)
+	}
+	if cfg.Identities[0].Name != "Work" {
+		t.Errorf("Expected name 'Work', got %q", cfg.Identities[0].Name)
+	}
+}
+
+func TestRenameIdentity_InvalidName(t *testing.T) {
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+
+	if _, err := cfg.RenameIdentity("work", "-bad"); err == nil {
+		t.Fatal("RenameIdentity() should return error for invalid name")
+	}
+	if _, err := cfg.RenameIdentity("missing", "other"); err == nil {
+		t.Fatal("RenameIdentity() should return error for nonexistent identity")
+	}
+}
+
 func TestListIdentities_Empty(t *testing.T) {
 	cfg := testConfig()
 
@@ -195,6 +391,22 @@ func TestListIdentities_Multiple(t *testing.T) {
 	}
 }
 
+func TestIdentitiesWithTag(t *testing.T) {
+	cfg := testConfig(
+		Identity{Name: "work", Email: "work@example.com", Tags: []string{"client-a"}},
+		Identity{Name: "personal", Email: "personal@example.com"},
+		Identity{Name: "oss", Email: "oss@example.com", Tags: []string{"oss", "Client-A"}},
+	)
+
+	identities := cfg.IdentitiesWithTag("CLIENT-A")
+	if len(identities) != 2 || identities[0].Name != "work" || identities[1].Name != "oss" {
+		t.Errorf("IdentitiesWithTag(CLIENT-A) = %v, want [work oss]", identities)
+	}
+	if identities := cfg.IdentitiesWithTag("none", "oss"); len(identities) != 1 || identities[0].Name != "oss" {
+		t.Errorf("IdentitiesWithTag(none, oss) = %v, want [oss]", identities)
+	}
+}
+
 func TestSetDefault_Valid(t *testing.T) {
 	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
 
@@ -364,3 +576,199 @@ func TestLoad_NonexistentFile(t *testing.T) {
 		t.Errorf("Expected 0 identities for nonexistent file, got %d", len(cfg.Identities))
 	}
 }
+
+func TestConfigPath_EnvOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profiles", "work.yaml")
+	t.Setenv(ConfigEnvVar, path)
+
+	got, err := ConfigPath()
+	if err != nil {
+		t.Fatalf("ConfigPath() returned error: %v", err)
+	}
+	if got != path {
+		t.Errorf("ConfigPath() = %q, want %q", got, path)
+	}
+}
+
+func TestSaveLoad_EnvOverride(t *testing.T) {
+	// Directory does not exist yet; Save must create it
+	path := filepath.Join(t.TempDir(), "profiles", "work.yaml")
+	t.Setenv(ConfigEnvVar, path)
+
+	cfg := testConfig(Identity{Name: "work", Email: "work@example.com"})
+	cfg.Default = "work"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected config at GITCH_CONFIG path: %v", err)
+	}
+
+	loaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if loaded.Default != "work" || len(loaded.Identities) != 1 {
+		t.Errorf("Load() = %+v, want the saved config", loaded)
+	}
+}
+
+func TestLoad_EnvOverrideMissingFile(t *testing.T) {
+	t.Setenv(ConfigEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error for missing file: %v", err)
+	}
+	if len(cfg.Identities) != 0 {
+		t.Errorf("Expected 0 identities, got %d", len(cfg.Identities))
+	}
+}
+
+func TestDuplicateEmails(t *testing.T) {
+	cfg := &Config{
+		Identities: []Identity{
+			{Name: "work", Email: "me@company.com"},
+			{Name: "personal", Email: "me@home.com"},
+			{Name: "work-gpg", Email: "Me@Company.com"},
+		},
+	}
+
+	duplicates := cfg.DuplicateEmails()
+	if len(duplicates) != 1 {
+		t.Fatalf("expected 1 duplicate email, got %d: %v", len(duplicates), duplicates)
+	}
+
+	names := duplicates["me@company.com"]
+	if len(names) != 2 || names[0] != "work" || names[1] != "work-gpg" {
+		t.Errorf("expected [work work-gpg], got %v", names)
+	}
+}
+
+func TestDuplicateEmailsNone(t *testing.T) {
+	cfg := &Config{
+		Identities: []Identity{
+			{Name: "work", Email: "me@company.com"},
+			{Name: "personal", Email: "me@home.com"},
+		},
+	}
+
+	if duplicates := cfg.DuplicateEmails(); len(duplicates) != 0 {
+		t.Errorf("expected no duplicates, got %v", duplicates)
+	}
+}
+
+func TestIsRemoteOverlap(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern1 string
+		pattern2 string
+		want     bool
+	}{
+		{"org wildcard vs repo", "github.com/company/*", "github.com/company/repo", true},
+		{"org exact vs org wildcard", "github.com/company", "github.com/company/*", true},
+		{"org exact vs repo", "github.com/company", "github.com/company/repo", true},
+		{"host vs org wildcard", "github.com", "github.com/company/*", true},
+		{"case insensitive", "GitHub.com/Company/*", "github.com/company/repo", true},
+		{"repo wildcard vs org wildcard", "github.com/*/repo", "github.com/company/*", true},
+		{"partial glob vs repo", "github.com/comp*/*", "github.com/company/app", true},
+		{"different orgs", "github.com/company/*", "github.com/other/*", false},
+		{"different hosts", "github.com/company/*", "gitlab.com/company/*", false},
+		{"different repos", "github.com/company/api", "github.com/company/web", false},
+		{"repo name prefix", "github.com/company/api", "github.com/company/api-v2", false},
+		{"org name prefix", "github.com/comp/*", "github.com/company/*", false},
+		{"disjoint partial globs", "github.com/a*/*", "github.com/b*/*", false},
+		{"host wildcard vs repo", "github.com/*", "github.com/company/repo", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRemoteOverlap(tt.pattern1, tt.pattern2); got != tt.want {
+				t.Errorf("isRemoteOverlap(%q, %q) = %v, want %v", tt.pattern1, tt.pattern2, got, tt.want)
+			}
+			if got := isRemoteOverlap(tt.pattern2, tt.pattern1); got != tt.want {
+				t.Errorf("isRemoteOverlap(%q, %q) = %v, want %v", tt.pattern2, tt.pattern1, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindOverlappingRules_Remote(t *testing.T) {
+	cfg := &Config{
+		Rules: []rules.Rule{
+			{Type: rules.RemoteRule, Pattern: "github.com/company/*", Identity: "work"},
+			{Type: rules.RemoteRule, Pattern: "github.com/personal/*", Identity: "personal"},
+			{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		},
+	}
+
+	overlapping := cfg.FindOverlappingRules(rules.Rule{Type: rules.RemoteRule, Pattern: "github.com/company/secret", Identity: "personal"})
+	if len(overlapping) != 1 || overlapping[0].Pattern != "github.com/company/*" {
+		t.Errorf("FindOverlappingRules() = %v, want only github.com/company/*", overlapping)
+	}
+}
+
+func TestIsDirectoryOverlap(t *testing.T) {
+	tests := []struct {
+		pattern1 string
+		pattern2 string
+		want     bool
+	}{
+		{"~/work/**", "~/work/api/**", true},
+		{"~/work/**", "~/work", true},
+		{"~/work/*", "~/work/**", true},
+		{"~/projects/**/frontend", "~/projects/shop/**", true},
+		{"~/projects/**/frontend", "~/work/**", false},
+		{"~/work/**", "~/work2/**", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern1+" "+tt.pattern2, func(t *testing.T) {
+			if got := isDirectoryOverlap(tt.pattern1, tt.pattern2); got != tt.want {
+				t.Errorf("isDirectoryOverlap(%q, %q) = %v, want %v", tt.pattern1, tt.pattern2, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad_MigratesSSHKeyPaths(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	t.Setenv(ConfigEnvVar, path)
+
+	data := `identities:
+  - name: work
+    email: work@example.com
+    ssh_key_paths:
+      - /keys/auth
+      - /keys/signing
+  - name: personal
+    email: me@example.com
+    ssh_key_path: /keys/personal
+`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+
+	work, _ := cfg.GetIdentity("work")
+	if work.SSHKeyPath != "/keys/auth" {
+		t.Errorf("SSHKeyPath = %q, want the first of ssh_key_paths", work.SSHKeyPath)
+	}
+	if got := work.SSHKeys(); len(got) != 2 || got[1] != "/keys/signing" {
+		t.Errorf("SSHKeys() = %v, want [/keys/auth /keys/signing]", got)
+	}
+
+	// Single-key identities are left as they were
+	personal, _ := cfg.GetIdentity("personal")
+	if personal.SSHKeyPaths != nil {
+		t.Errorf("SSHKeyPaths = %v, want nil for a single-key identity", personal.SSHKeyPaths)
+	}
+	if got := personal.SSHKeys(); len(got) != 1 || got[0] != "/keys/personal" {
+		t.Errorf("SSHKeys() = %v, want [/keys/personal]", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"os"
 	"regexp"
 	"strings"
 )
@@ -18,13 +19,95 @@ const (
 	HookModeBlock = "block" // Block commits until identity matches
 )
 
+// SigningMethod constants define how commits made as an identity are signed
+const (
+	SigningGPG  = "gpg"  // Sign with the identity's GPG key
+	SigningSSH  = "ssh"  // Sign with the identity's SSH key (gpg.format=ssh)
+	SigningNone = "none" // Don't sign commits
+)
+
 // Identity represents a git identity with name and email
 type Identity struct {
-	Name       string `mapstructure:"name" yaml:"name"`
-	Email      string `mapstructure:"email" yaml:"email"`
-	SSHKeyPath string `mapstructure:"ssh_key_path" yaml:"ssh_key_path,omitempty"`
-	GPGKeyID   string `mapstructure:"gpg_key_id" yaml:"gpg_key_id,omitempty"`
-	HookMode   string `mapstructure:"hook_mode" yaml:"hook_mode,omitempty"`
+	Name       string `mapstructure:"name" yaml:"name" json:"name"`
+	Email      string `mapstructure:"email" yaml:"email" json:"email"`
+	SSHKeyPath string `mapstructure:"ssh_key_path" yaml:"ssh_key_path,omitempty" json:"ssh_key_path,omitempty"`
+	GPGKeyID   string `mapstructure:"gpg_key_id" yaml:"gpg_key_id,omitempty" json:"gpg_key_id,omitempty"`
+	HookMode   string `mapstructure:"hook_mode" yaml:"hook_mode,omitempty" json:"hook_mode,omitempty"`
+	// Description is a free-form note telling similar identities apart,
+	// shown in 'gitch list' and the selector.
+	Description string `mapstructure:"description" yaml:"description,omitempty" json:"description,omitempty"`
+	// Tags group identities, e.g. by client, for 'gitch list --tag',
+	// 'gitch use --tag' and 'gitch export --tag'.
+	Tags []string `mapstructure:"tags" yaml:"tags,omitempty" json:"tags,omitempty"`
+	// Hosts lists custom SSH hostnames (e.g., self-hosted GitLab/Gitea) for this identity.
+	// When empty, the default github/gitlab/bitbucket/azure hosts are used.
+	Hosts []string `mapstructure:"hosts" yaml:"hosts,omitempty" json:"hosts,omitempty"`
+	// SSHKeyPaths lists every SSH private key of the identity, SSHKeyPath first
+	// (e.g. separate auth and signing keys). Empty for single-key identities,
+	// which keep using only SSHKeyPath so older gitch versions can read them.
+	SSHKeyPaths []string `mapstructure:"ssh_key_paths" yaml:"ssh_key_paths,omitempty" json:"ssh_key_paths,omitempty"`
+	// AgentLifetime is how long, in seconds, ssh-agent keeps this identity's key
+	// loaded (ssh-add -t). Zero means no expiry.
+	AgentLifetime int `mapstructure:"agent_lifetime" yaml:"agent_lifetime,omitempty" json:"agent_lifetime,omitempty"`
+	// SSHPort is the SSH port written to generated Host blocks. Zero means the default (22).
+	SSHPort int `mapstructure:"ssh_port" yaml:"ssh_port,omitempty" json:"ssh_port,omitempty"`
+	// ProxyJump is the bastion host(s) written to generated Host blocks, e.g. "user@bastion:2222".
+	ProxyJump string `mapstructure:"proxy_jump" yaml:"proxy_jump,omitempty" json:"proxy_jump,omitempty"`
+	// HTTPSUser is the username 'gitch git-credential' returns for HTTPS remotes.
+	HTTPSUser string `mapstructure:"https_user" yaml:"https_user,omitempty" json:"https_user,omitempty"`
+	// HTTPSToken is the access token 'gitch git-credential' returns as the password.
+	// It is never included in JSON output or exports.
+	HTTPSToken string `mapstructure:"https_token" yaml:"https_token,omitempty" json:"-"`
+	// SigningMethod selects how commits are signed: gpg, ssh or none. Empty
+	// means gpg when a GPG key is configured, none otherwise.
+	SigningMethod string `mapstructure:"signing_method" yaml:"signing_method,omitempty" json:"signing_method,omitempty"`
+	// NoAgent stops gitch from adding this identity's SSH keys to ssh-agent,
+	// or removing them, when switching identities.
+	NoAgent bool `mapstructure:"no_agent" yaml:"no_agent,omitempty" json:"no_agent,omitempty"`
+}
+
+// NoAgentEnvVar is the environment variable that, when set to a non-empty
+// value, stops gitch from using ssh-agent for every identity
+const NoAgentEnvVar = "GITCH_NO_AGENT"
+
+// AgentKeys returns the SSH keys gitch loads into ssh-agent for the identity:
+// SSHKeys, or nil if NoAgent or $GITCH_NO_AGENT is set.
+func (i *Identity) AgentKeys() []string {
+	if i.NoAgent || os.Getenv(NoAgentEnvVar) != "" {
+		return nil
+	}
+	return i.SSHKeys()
+}
+
+// SSHKeys returns all of the identity's SSH key paths, primary key first,
+// without duplicates. Returns nil if the identity has no SSH key.
+func (i *Identity) SSHKeys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, key := range append([]string{i.SSHKeyPath}, i.SSHKeyPaths...) {
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// normalizeSSHKeys keeps SSHKeyPath and SSHKeyPaths consistent: SSHKeyPath is
+// the first entry of SSHKeyPaths whenever the identity has several keys.
+func (i *Identity) normalizeSSHKeys() {
+	keys := i.SSHKeys()
+	if len(keys) == 0 {
+		i.SSHKeyPaths = nil
+		return
+	}
+	i.SSHKeyPath = keys[0]
+	if len(keys) == 1 {
+		i.SSHKeyPaths = nil
+		return
+	}
+	i.SSHKeyPaths = keys
 }
 
 // ValidateHookMode validates that the hook mode is a valid value
@@ -39,6 +122,14 @@ func ValidateHookMode(mode string) error {
 	}
 }
 
+// ValidateAgentLifetime validates an ssh-agent key lifetime in seconds
+func ValidateAgentLifetime(seconds int) error {
+	if seconds < 0 {
+		return fmt.Errorf("invalid agent lifetime %d: must be 0 (no expiry) or a positive number of seconds", seconds)
+	}
+	return nil
+}
+
 // GetHookMode returns the hook mode for the identity, defaulting to warn if not set
 func (i *Identity) GetHookMode() string {
 	if i.HookMode == "" {
@@ -47,6 +138,44 @@ func (i *Identity) GetHookMode() string {
 	return i.HookMode
 }
 
+// ValidateSigningMethod validates that the signing method is a valid value
+func ValidateSigningMethod(method string) error {
+	switch method {
+	case SigningGPG, SigningSSH, SigningNone, "":
+		return nil
+	default:
+		return fmt.Errorf("invalid signing method %q: must be one of: gpg, ssh, none", method)
+	}
+}
+
+// GetSigningMethod returns the signing method for the identity, defaulting to
+// gpg when a GPG key is configured and none otherwise
+func (i *Identity) GetSigningMethod() string {
+	if i.SigningMethod != "" {
+		return i.SigningMethod
+	}
+	if i.GPGKeyID != "" {
+		return SigningGPG
+	}
+	return SigningNone
+}
+
+// SigningKey returns the value git's user.signingkey should hold for the
+// identity: the GPG key ID, or the public half of the SSH key for SSH
+// signing. Returns "" if commits aren't signed.
+func (i *Identity) SigningKey() string {
+	switch i.GetSigningMethod() {
+	case SigningGPG:
+		return i.GPGKeyID
+	case SigningSSH:
+		if i.SSHKeyPath == "" {
+			return ""
+		}
+		return i.SSHKeyPath + ".pub"
+	}
+	return ""
+}
+
 // nameRegex validates identity names: alphanumeric + hyphens, no leading/trailing hyphens
 var nameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]*[a-zA-Z0-9])?$`)
 
@@ -86,21 +215,150 @@ func ValidateName(name string) error {
 	return nil
 }
 
-// ValidateEmail validates an email address using RFC 5322 parsing
+// tagRegex validates tags: alphanumeric plus '.', '_' and '-', starting
+// alphanumeric
+var tagRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// ValidateTag validates an identity tag
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return errors.New("tag cannot be empty")
+	}
+	if len(tag) > MaxNameLength {
+		return fmt.Errorf("tag cannot exceed %d characters", MaxNameLength)
+	}
+	if !tagRegex.MatchString(tag) {
+		return fmt.Errorf("invalid tag %q: must start with a letter or digit and contain only letters, digits, '.', '_' and '-'", tag)
+	}
+	return nil
+}
+
+// HasTag reports whether the identity has the tag (case-insensitive)
+func (i *Identity) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag adds a tag to the identity unless it already has it. Returns
+// whether the tag was added.
+func (i *Identity) AddTag(tag string) bool {
+	if i.HasTag(tag) {
+		return false
+	}
+	i.Tags = append(i.Tags, tag)
+	return true
+}
+
+// RemoveTag removes a tag from the identity (case-insensitive). Returns
+// whether the identity had the tag.
+func (i *Identity) RemoveTag(tag string) bool {
+	for idx, t := range i.Tags {
+		if strings.EqualFold(t, tag) {
+			i.Tags = append(i.Tags[:idx:idx], i.Tags[idx+1:]...)
+			if len(i.Tags) == 0 {
+				i.Tags = nil
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEmail validates an email address using RFC 5322 parsing.
+// Only a bare address is accepted (no display name or angle brackets).
+// Single-label domains such as user@localhost are allowed for intranets.
 func ValidateEmail(email string) error {
 	if email == "" {
 		return errors.New("email cannot be empty")
 	}
 
-	_, err := mail.ParseAddress(email)
+	addr, err := mail.ParseAddress(email)
 	if err != nil {
 		return fmt.Errorf("invalid email format: %w", err)
 	}
+	if addr.Name != "" || addr.Address != email {
+		return fmt.Errorf("invalid email format: use a bare address like user@example.com, not %q", email)
+	}
 
 	return nil
 }
 
-// Validate validates both name and email of the identity
+// NormalizeEmail trims surrounding whitespace and lowercases the domain of an
+// email address. The local part is kept as entered since it may be case
+// sensitive. Emails are stored normalized so imports and exports round-trip.
+func NormalizeEmail(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at == -1 {
+		return email
+	}
+	return email[:at+1] + strings.ToLower(email[at+1:])
+}
+
+// ValidateHost validates an SSH hostname for use in a Host block
+func ValidateHost(host string) error {
+	if host == "" {
+		return errors.New("host cannot be empty")
+	}
+
+	if strings.ContainsAny(host, " \t\n*?!/@:") {
+		return fmt.Errorf("invalid host %q: must be a plain hostname like git.company.com", host)
+	}
+
+	return nil
+}
+
+// ValidateSSHPort validates an SSH port; zero means the default port
+func ValidateSSHPort(port int) error {
+	if port < 0 || port > 65535 {
+		return fmt.Errorf("invalid SSH port %d: must be between 1 and 65535", port)
+	}
+	return nil
+}
+
+// ValidateProxyJump validates an SSH ProxyJump value such as
+// "bastion", "user@bastion:2222" or a comma-separated chain of jump hosts
+func ValidateProxyJump(proxyJump string) error {
+	if proxyJump == "" {
+		return nil
+	}
+
+	if strings.ContainsAny(proxyJump, " \t\n") {
+		return fmt.Errorf("invalid ProxyJump %q: must not contain whitespace", proxyJump)
+	}
+
+	for _, hop := range strings.Split(proxyJump, ",") {
+		if hop == "" {
+			return fmt.Errorf("invalid ProxyJump %q: empty jump host", proxyJump)
+		}
+	}
+
+	return nil
+}
+
+// ValidateHTTPSCredential validates an HTTPS username or token, which git's
+// credential protocol sends as a single line
+func ValidateHTTPSCredential(field, value string) error {
+	if strings.ContainsAny(value, "\n\r\x00") {
+		return fmt.Errorf("invalid %s: must not contain newlines", field)
+	}
+	return nil
+}
+
+// ValidateDescription validates an identity description, which is shown on
+// a single line
+func ValidateDescription(description string) error {
+	if strings.ContainsAny(description, "\n\r\x00") {
+		return errors.New("invalid description: must be a single line")
+	}
+	return nil
+}
+
+// Validate validates the name, email, hook mode and custom hosts of the identity
 func (i *Identity) Validate() error {
 	if err := ValidateName(i.Name); err != nil {
 		return err
@@ -110,5 +368,55 @@ func (i *Identity) Validate() error {
 		return err
 	}
 
+	if err := ValidateDescription(i.Description); err != nil {
+		return err
+	}
+
+	for _, tag := range i.Tags {
+		if err := ValidateTag(tag); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateHookMode(i.HookMode); err != nil {
+		return err
+	}
+
+	if err := ValidateAgentLifetime(i.AgentLifetime); err != nil {
+		return err
+	}
+
+	for _, host := range i.Hosts {
+		if err := ValidateHost(host); err != nil {
+			return err
+		}
+	}
+
+	if err := ValidateSSHPort(i.SSHPort); err != nil {
+		return err
+	}
+
+	if err := ValidateProxyJump(i.ProxyJump); err != nil {
+		return err
+	}
+
+	if err := ValidateHTTPSCredential("HTTPS user", i.HTTPSUser); err != nil {
+		return err
+	}
+
+	if err := ValidateHTTPSCredential("HTTPS token", i.HTTPSToken); err != nil {
+		return err
+	}
+
+	if err := ValidateSigningMethod(i.SigningMethod); err != nil {
+		return err
+	}
+	if i.SigningMethod == SigningGPG && i.GPGKeyID == "" {
+		return errors.New("signing method gpg requires a GPG key")
+	}
+	if i.SigningMethod == SigningSSH && i.SSHKeyPath == "" {
+		return errors.New("signing method ssh requires an SSH key")
+	}
+
 	return nil
 }
@@ -71,6 +71,8 @@ func TestValidateEmail_Valid(t *testing.T) {
 		{"user@subdomain.example.com"},
 		{"first.last@example.co.uk"},
 		{"a@b.com"},
+		{"user@localhost"},
+		{"dev@buildhost"},
 	}
 
 	for _, tt := range tests {
@@ -93,6 +95,9 @@ func TestValidateEmail_Invalid(t *testing.T) {
 		{"user@", "invalid email"},
 		{"user@.com", "invalid email"},
 		{"user name@example.com", "invalid email"},
+		{"Jane Doe <jane@example.com>", "bare address"},
+		{"<jane@example.com>", "bare address"},
+		{"user@example.", "invalid email"},
 	}
 
 	for _, tt := range tests {
@@ -109,6 +114,27 @@ func TestValidateEmail_Invalid(t *testing.T) {
 	}
 }
 
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"user@example.com", "user@example.com"},
+		{"user@Example.COM", "user@example.com"},
+		{"John.Doe@Sub.Example.Com", "John.Doe@sub.example.com"},
+		{"  user@EXAMPLE.com\n", "user@example.com"},
+		{"invalid", "invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := NormalizeEmail(tt.email); got != tt.want {
+				t.Errorf("NormalizeEmail(%q) = %q, want %q", tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIdentity_Validate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -145,6 +171,113 @@ func TestIdentity_Validate(t *testing.T) {
 			wantErr:   true,
 			errSubstr: "empty",
 		},
+		{
+			name:     "valid hook mode",
+			identity: Identity{Name: "work", Email: "user@example.com", HookMode: HookModeBlock},
+			wantErr:  false,
+		},
+		{
+			name:      "invalid hook mode",
+			identity:  Identity{Name: "work", Email: "user@example.com", HookMode: "strict"},
+			wantErr:   true,
+			errSubstr: "invalid hook mode",
+		},
+		{
+			name:     "valid description",
+			identity: Identity{Name: "work", Email: "user@example.com", Description: "ACME contractor account"},
+			wantErr:  false,
+		},
+		{
+			name:      "multi-line description",
+			identity:  Identity{Name: "work", Email: "user@example.com", Description: "ACME\ncontractor"},
+			wantErr:   true,
+			errSubstr: "invalid description",
+		},
+		{
+			name:     "valid agent lifetime",
+			identity: Identity{Name: "work", Email: "user@example.com", AgentLifetime: 3600},
+			wantErr:  false,
+		},
+		{
+			name:      "negative agent lifetime",
+			identity:  Identity{Name: "work", Email: "user@example.com", AgentLifetime: -1},
+			wantErr:   true,
+			errSubstr: "invalid agent lifetime",
+		},
+		{
+			name:     "valid custom hosts",
+			identity: Identity{Name: "work", Email: "user@example.com", Hosts: []string{"git.company.internal", "gitea.example.com"}},
+			wantErr:  false,
+		},
+		{
+			name:      "invalid custom host",
+			identity:  Identity{Name: "work", Email: "user@example.com", Hosts: []string{"git@company.com"}},
+			wantErr:   true,
+			errSubstr: "invalid host",
+		},
+		{
+			name:      "empty custom host",
+			identity:  Identity{Name: "work", Email: "user@example.com", Hosts: []string{""}},
+			wantErr:   true,
+			errSubstr: "host cannot be empty",
+		},
+		{
+			name:     "valid SSH port and ProxyJump",
+			identity: Identity{Name: "work", Email: "user@example.com", SSHPort: 2222, ProxyJump: "jump@bastion:22,inner"},
+			wantErr:  false,
+		},
+		{
+			name:      "SSH port out of range",
+			identity:  Identity{Name: "work", Email: "user@example.com", SSHPort: 70000},
+			wantErr:   true,
+			errSubstr: "invalid SSH port",
+		},
+		{
+			name:      "ProxyJump with whitespace",
+			identity:  Identity{Name: "work", Email: "user@example.com", ProxyJump: "bastion other"},
+			wantErr:   true,
+			errSubstr: "invalid ProxyJump",
+		},
+		{
+			name:      "ProxyJump with empty hop",
+			identity:  Identity{Name: "work", Email: "user@example.com", ProxyJump: "bastion,"},
+			wantErr:   true,
+			errSubstr: "empty jump host",
+		},
+		{
+			name:     "valid HTTPS credentials",
+			identity: Identity{Name: "work", Email: "user@example.com", HTTPSUser: "octocat", HTTPSToken: "ghp_token"},
+			wantErr:  false,
+		},
+		{
+			name:      "HTTPS token with newline",
+			identity:  Identity{Name: "work", Email: "user@example.com", HTTPSToken: "ghp_token\npassword=x"},
+			wantErr:   true,
+			errSubstr: "invalid HTTPS token",
+		},
+		{
+			name:     "valid SSH signing",
+			identity: Identity{Name: "work", Email: "user@example.com", SSHKeyPath: "/home/u/.ssh/id_work", SigningMethod: SigningSSH},
+			wantErr:  false,
+		},
+		{
+			name:      "invalid signing method",
+			identity:  Identity{Name: "work", Email: "user@example.com", SigningMethod: "x509"},
+			wantErr:   true,
+			errSubstr: "invalid signing method",
+		},
+		{
+			name:      "SSH signing without SSH key",
+			identity:  Identity{Name: "work", Email: "user@example.com", SigningMethod: SigningSSH},
+			wantErr:   true,
+			errSubstr: "requires an SSH key",
+		},
+		{
+			name:      "GPG signing without GPG key",
+			identity:  Identity{Name: "work", Email: "user@example.com", SigningMethod: SigningGPG},
+			wantErr:   true,
+			errSubstr: "requires a GPG key",
+		},
 	}
 
 	for _, tt := range tests {
@@ -166,3 +299,110 @@ func TestIdentity_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentity_SigningKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		identity   Identity
+		wantMethod string
+		wantKey    string
+	}{
+		{
+			name:       "no keys",
+			identity:   Identity{},
+			wantMethod: SigningNone,
+			wantKey:    "",
+		},
+		{
+			name:       "GPG key defaults to gpg",
+			identity:   Identity{GPGKeyID: "ABCD1234", SSHKeyPath: "/k/id_work"},
+			wantMethod: SigningGPG,
+			wantKey:    "ABCD1234",
+		},
+		{
+			name:       "SSH signing uses the public key",
+			identity:   Identity{GPGKeyID: "ABCD1234", SSHKeyPath: "/k/id_work", SigningMethod: SigningSSH},
+			wantMethod: SigningSSH,
+			wantKey:    "/k/id_work.pub",
+		},
+		{
+			name:       "signing disabled",
+			identity:   Identity{GPGKeyID: "ABCD1234", SigningMethod: SigningNone},
+			wantMethod: SigningNone,
+			wantKey:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.identity.GetSigningMethod(); got != tt.wantMethod {
+				t.Errorf("GetSigningMethod() = %q, want %q", got, tt.wantMethod)
+			}
+			if got := tt.identity.SigningKey(); got != tt.wantKey {
+				t.Errorf("SigningKey() = %q, want %q", got, tt.wantKey)
+			}
+		})
+	}
+}
+
+func TestIdentity_AgentKeys(t *testing.T) {
+	identity := Identity{Name: "work", Email: "work@example.com", SSHKeyPath: "/keys/id_work"}
+
+	t.Setenv(NoAgentEnvVar, "")
+	if got := identity.AgentKeys(); len(got) != 1 || got[0] != "/keys/id_work" {
+		t.Errorf("AgentKeys() = %v, want [/keys/id_work]", got)
+	}
+
+	identity.NoAgent = true
+	if got := identity.AgentKeys(); got != nil {
+		t.Errorf("AgentKeys() with NoAgent = %v, want nil", got)
+	}
+
+	identity.NoAgent = false
+	t.Setenv(NoAgentEnvVar, "1")
+	if got := identity.AgentKeys(); got != nil {
+		t.Errorf("AgentKeys() with %s set = %v, want nil", NoAgentEnvVar, got)
+	}
+}
+
+func TestIdentity_Tags(t *testing.T) {
+	identity := Identity{Name: "acme", Email: "me@acme.com"}
+
+	if !identity.AddTag("client-a") || !identity.AddTag("contract") {
+		t.Fatal("AddTag() = false for new tags")
+	}
+	if identity.AddTag("Client-A") {
+		t.Error("AddTag() added a case-insensitive duplicate")
+	}
+	if !identity.HasTag("CLIENT-A") {
+		t.Error("HasTag() should match case-insensitively")
+	}
+
+	if !identity.RemoveTag("Contract") {
+		t.Error("RemoveTag() = false for an existing tag")
+	}
+	if identity.RemoveTag("contract") {
+		t.Error("RemoveTag() = true for a missing tag")
+	}
+	if len(identity.Tags) != 1 || identity.Tags[0] != "client-a" {
+		t.Errorf("Tags = %v, want [client-a]", identity.Tags)
+	}
+
+	identity.RemoveTag("client-a")
+	if identity.Tags != nil {
+		t.Errorf("Tags = %v after removing the last tag, want nil", identity.Tags)
+	}
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{"client-a", "v1.2", "team_x", "A"} {
+		if err := ValidateTag(tag); err != nil {
+			t.Errorf("ValidateTag(%q) error = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "-a", "+a", "has space", "a,b"} {
+		if err := ValidateTag(tag); err == nil {
+			t.Errorf("ValidateTag(%q) = nil, want error", tag)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// lockTimeout is how long to wait for another gitch process to release the lock
+	lockTimeout = 5 * time.Second
+	// lockRetryInterval is the delay between attempts to acquire the lock
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// ErrLocked is returned when the config lock cannot be acquired in time
+var ErrLocked = errors.New("config is locked by another gitch process")
+
+// errLockHeld is returned by tryLockFile when another open file holds the lock
+var errLockHeld = errors.New("lock is held")
+
+// LockPath returns the path of the advisory lock file for the config
+func LockPath() (string, error) {
+	configPath, err := ConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return configPath + ".lock", nil
+}
+
+// WithLock runs fn while holding an exclusive lock on the config's lock
+// file. Use it around load-modify-save cycles so concurrent gitch
+// invocations (e.g. a hook and a manual command) don't overwrite each
+// other's changes.
+//
+// The lock is an operating system file lock, so it is released when the
+// process exits, even if it crashes. It is not reentrant: fn must not call
+// WithLock again, or it waits on itself until lockTimeout.
+func WithLock(fn func() error) error {
+	lockPath, err := LockPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine lock path: %w", err)
+	}
+
+	f, err := acquireLock(lockPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = unlockFile(f)
+		_ = f.Close()
+	}()
+
+	return fn()
+}
+
+// acquireLock opens the lock file and locks it, retrying until lockTimeout.
+// The file is left in place when the lock is released; only the lock on it
+// matters.
+func acquireLock(lockPath string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := tryLockFile(f)
+		if err == nil {
+			// Record the owner for debugging
+			_ = f.Truncate(0)
+			_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+			return f, nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			_ = f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", lockPath, err)
+		}
+
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			return nil, fmt.Errorf("%w (%s)", ErrLocked, lockPath)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// useTempConfig points GITCH_CONFIG at a file in a temp directory
+func useTempConfig(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "gitch", "config.yaml")
+	t.Setenv(ConfigEnvVar, path)
+
+	return path
+}
+
+func TestWithLock_IgnoresLeftoverLockFile(t *testing.T) {
+	useTempConfig(t)
+
+	// A lock file left behind by a crashed process holds no lock
+	lockPath, err := LockPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, []byte("12345"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	if err := WithLock(func() error { return nil }); err != nil {
+		t.Fatalf("WithLock() error = %v", err)
+	}
+	if time.Since(start) > lockTimeout/2 {
+		t.Error("WithLock() waited on a lock file nobody holds")
+	}
+}
+
+func TestWithLock_WaitsForHolder(t *testing.T) {
+	useTempConfig(t)
+
+	held := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- WithLock(func() error {
+			close(held)
+			<-release
+			cfg := &Config{Identities: []Identity{{Name: "work", Email: "work@example.com"}}}
+			return cfg.Save()
+		})
+	}()
+	<-held
+
+	// The second caller only gets the lock once the first has saved
+	go func() {
+		time.Sleep(10 * lockRetryInterval)
+		close(release)
+	}()
+	err := WithLock(func() error {
+		cfg, err := Load()
+		if err != nil {
+			return err
+		}
+		if len(cfg.Identities) != 1 {
+			t.Errorf("identities inside the lock = %+v, want the first holder's save", cfg.Identities)
+		}
+		cfg.Identities = append(cfg.Identities, Identity{Name: "personal", Email: "me@example.com"})
+		return cfg.Save()
+	})
+	if err != nil {
+		t.Fatalf("WithLock() error = %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("first WithLock() error = %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() returned error: %v", err)
+	}
+	if len(cfg.Identities) != 2 {
+		t.Errorf("identities after both saves = %+v, want both", cfg.Identities)
+	}
+}
+
+func TestSave_AtomicLeavesNoTempFiles(t *testing.T) {
+	useTempConfig(t)
+
+	cfg := &Config{Identities: []Identity{{Name: "work", Email: "work@example.com"}}}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	configPath, err := ConfigPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(configPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if e.Name() != filepath.Base(configPath) {
+			t.Errorf("unexpected file left in config dir: %s", e.Name())
+		}
+	}
+
+	info, err := os.Stat(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config permissions = %o, want 600", info.Mode().Perm())
+	}
+}
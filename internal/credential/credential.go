@@ -0,0 +1,91 @@
+// Package credential implements the parts of git's credential helper
+// protocol gitch needs to answer HTTPS credential requests.
+package credential
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Request is a credential description read from git, e.g.
+//
+//	protocol=https
+//	host=github.com
+//	path=company/project.git
+//
+// Path is only sent when credential.useHttpPath is enabled.
+type Request struct {
+	Protocol string
+	Host     string
+	Path     string
+	Username string
+}
+
+// ReadRequest parses key=value lines from r until a blank line or EOF.
+// Unknown keys are ignored, as git may send attributes newer than gitch.
+func ReadRequest(r io.Reader) (*Request, error) {
+	req := &Request{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			break
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential line %q: expected key=value", line)
+		}
+
+		switch key {
+		case "protocol":
+			req.Protocol = value
+		case "host":
+			req.Host = value
+		case "path":
+			req.Path = value
+		case "username":
+			req.Username = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read credential request: %w", err)
+	}
+	return req, nil
+}
+
+// RemoteURL reconstructs the remote URL the request is for, suitable for
+// matching remote rules. Returns an empty string when host is missing.
+func (r *Request) RemoteURL() string {
+	if r.Host == "" {
+		return ""
+	}
+	protocol := r.Protocol
+	if protocol == "" {
+		protocol = "https"
+	}
+	url := protocol + "://" + r.Host
+	if r.Path != "" {
+		url += "/" + strings.TrimPrefix(r.Path, "/")
+	}
+	return url
+}
+
+// WriteResponse writes the username and password attributes git reads from
+// a helper's get action. Empty values are omitted so git can fill them in
+// from other helpers or a prompt.
+func WriteResponse(w io.Writer, username, password string) error {
+	if username != "" {
+		if _, err := fmt.Fprintf(w, "username=%s\n", username); err != nil {
+			return err
+		}
+	}
+	if password != "" {
+		if _, err := fmt.Fprintf(w, "password=%s\n", password); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package credential
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadRequest(t *testing.T) {
+	input := "protocol=https\nhost=github.com\npath=company/project.git\nwwwauth[]=Basic realm=\"GitHub\"\n\nignored=after-blank\n"
+
+	req, err := ReadRequest(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadRequest() error = %v", err)
+	}
+	if req.Protocol != "https" || req.Host != "github.com" || req.Path != "company/project.git" {
+		t.Errorf("ReadRequest() = %+v", req)
+	}
+}
+
+func TestReadRequest_InvalidLine(t *testing.T) {
+	if _, err := ReadRequest(strings.NewReader("protocol=https\ngarbage\n")); err == nil {
+		t.Error("ReadRequest() should reject a line without '='")
+	}
+}
+
+func TestRequest_RemoteURL(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{"with path", Request{Protocol: "https", Host: "github.com", Path: "company/project.git"}, "https://github.com/company/project.git"},
+		{"host only", Request{Protocol: "https", Host: "gitlab.com"}, "https://gitlab.com"},
+		{"default protocol", Request{Host: "github.com", Path: "org/repo"}, "https://github.com/org/repo"},
+		{"no host", Request{Protocol: "https"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.req.RemoteURL(); got != tt.want {
+				t.Errorf("RemoteURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteResponse(&buf, "octocat", "ghp_token"); err != nil {
+		t.Fatalf("WriteResponse() error = %v", err)
+	}
+	if got, want := buf.String(), "username=octocat\npassword=ghp_token\n"; got != want {
+		t.Errorf("WriteResponse() = %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	if err := WriteResponse(&buf, "octocat", ""); err != nil {
+		t.Fatalf("WriteResponse() error = %v", err)
+	}
+	if got, want := buf.String(), "username=octocat\n"; got != want {
+		t.Errorf("WriteResponse() without token = %q, want %q", got, want)
+	}
+}
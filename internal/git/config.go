@@ -108,28 +108,108 @@ func GetCurrentIdentity() (name string, email string, err error) {
 	return name, email, nil
 }
 
+// RepoRoot returns the top-level directory of the current git repository,
+// or "" if the working directory is not inside one
+func RepoRoot() string {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // ApplyIdentity sets git user.name and user.email globally.
 // Returns the first error encountered, if any.
 func ApplyIdentity(name, email string) error {
-	if err := SetConfig("user.name", name, true); err != nil {
+	return ApplyIdentityScope(name, email, true)
+}
+
+// ApplyIdentityScope sets git user.name and user.email.
+// If global is true, writes to --global scope; otherwise writes to the local repo.
+// Returns the first error encountered, if any.
+func ApplyIdentityScope(name, email string, global bool) error {
+	if err := SetConfig("user.name", name, global); err != nil {
 		return fmt.Errorf("failed to apply identity: %w", err)
 	}
 
-	if err := SetConfig("user.email", email, true); err != nil {
+	if err := SetConfig("user.email", email, global); err != nil {
 		return fmt.Errorf("failed to apply identity: %w", err)
 	}
 
 	return nil
 }
 
+// ApplySigning configures commit signing: with a signingKey,
+// user.signingkey is set and commit.gpgsign enabled; without one, both are
+// removed. signingFormat is SigningFormatSSH to sign with an SSH key
+// (signingKey is then the public key path), or empty for GPG. In a
+// repository (global false), signing is also disabled locally if it would
+// otherwise be inherited from the global config with another identity's key.
+func ApplySigning(signingKey, signingFormat string, global bool) error {
+	if err := applySigningFormat(signingKey, signingFormat, global); err != nil {
+		return err
+	}
+
+	if signingKey != "" {
+		return ApplySigningConfigScope(signingKey, global)
+	}
+
+	if err := ClearSigningConfigScope(global); err != nil {
+		return err
+	}
+	if global {
+		return nil
+	}
+	if sign, _ := GetConfig("commit.gpgsign", false); sign == "true" {
+		if err := SetConfig("commit.gpgsign", "false", false); err != nil {
+			return fmt.Errorf("failed to disable commit signing: %w", err)
+		}
+	}
+	return nil
+}
+
+// SigningFormatSSH is the gpg.format value for signing commits with SSH keys
+const SigningFormatSSH = "ssh"
+
+// applySigningFormat sets gpg.format to ssh for SSH signing and removes it
+// otherwise, so git uses its openpgp default. In a repository, GPG signing
+// also overrides an ssh format inherited from the global config.
+func applySigningFormat(signingKey, signingFormat string, global bool) error {
+	if signingKey != "" && signingFormat == SigningFormatSSH {
+		if err := SetConfig("gpg.format", SigningFormatSSH, global); err != nil {
+			return fmt.Errorf("failed to set signing format: %w", err)
+		}
+		return nil
+	}
+
+	if err := UnsetConfig("gpg.format", global); err != nil {
+		return fmt.Errorf("failed to unset signing format: %w", err)
+	}
+	if global || signingKey == "" {
+		return nil
+	}
+	if format, _ := GetConfig("gpg.format", false); format == SigningFormatSSH {
+		if err := SetConfig("gpg.format", "openpgp", false); err != nil {
+			return fmt.Errorf("failed to set signing format: %w", err)
+		}
+	}
+	return nil
+}
+
 // ApplySigningConfig configures git to use the specified GPG key for signing commits.
 // Sets user.signingkey and commit.gpgsign globally.
 func ApplySigningConfig(keyID string) error {
-	if err := SetConfig("user.signingkey", keyID, true); err != nil {
+	return ApplySigningConfigScope(keyID, true)
+}
+
+// ApplySigningConfigScope sets user.signingkey and commit.gpgsign in the
+// global (if global is true) or local repo scope.
+func ApplySigningConfigScope(keyID string, global bool) error {
+	if err := SetConfig("user.signingkey", keyID, global); err != nil {
 		return fmt.Errorf("failed to set signing key: %w", err)
 	}
 
-	if err := SetConfig("commit.gpgsign", "true", true); err != nil {
+	if err := SetConfig("commit.gpgsign", "true", global); err != nil {
 		return fmt.Errorf("failed to enable commit signing: %w", err)
 	}
 
@@ -139,11 +219,17 @@ func ApplySigningConfig(keyID string) error {
 // ClearSigningConfig removes GPG signing configuration from git global config.
 // This is idempotent - returns nil even if the keys were not set.
 func ClearSigningConfig() error {
-	if err := UnsetConfig("user.signingkey", true); err != nil {
+	return ClearSigningConfigScope(true)
+}
+
+// ClearSigningConfigScope removes user.signingkey and commit.gpgsign from the
+// global (if global is true) or local repo scope. Idempotent.
+func ClearSigningConfigScope(global bool) error {
+	if err := UnsetConfig("user.signingkey", global); err != nil {
 		return fmt.Errorf("failed to unset signing key: %w", err)
 	}
 
-	if err := UnsetConfig("commit.gpgsign", true); err != nil {
+	if err := UnsetConfig("commit.gpgsign", global); err != nil {
 		return fmt.Errorf("failed to unset commit signing: %w", err)
 	}
 
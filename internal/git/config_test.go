@@ -285,3 +285,162 @@ func TestGetConfig_LocalScope(t *testing.T) {
 		t.Errorf("expected empty global value, got '%s'", globalValue)
 	}
 }
+
+func TestApplyIdentityScope_Local(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.cleanup(t)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(env.dir)
+
+	if err := ApplyIdentity("Global User", "global@example.com"); err != nil {
+		t.Fatalf("ApplyIdentity failed: %v", err)
+	}
+	if err := ApplyIdentityScope("Local User", "local@example.com", false); err != nil {
+		t.Fatalf("ApplyIdentityScope local failed: %v", err)
+	}
+
+	// Local scope wins inside the repo
+	email, err := GetConfig("user.email", false)
+	if err != nil {
+		t.Fatalf("GetConfig local failed: %v", err)
+	}
+	if email != "local@example.com" {
+		t.Errorf("expected local email 'local@example.com', got '%s'", email)
+	}
+
+	// Global config is untouched
+	name, email, err := GetCurrentIdentity()
+	if err != nil {
+		t.Fatalf("GetCurrentIdentity failed: %v", err)
+	}
+	if name != "Global User" || email != "global@example.com" {
+		t.Errorf("expected global identity unchanged, got '%s' <%s>", name, email)
+	}
+}
+
+func TestSigningConfigScope_Local(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.cleanup(t)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(env.dir)
+
+	if err := ApplySigningConfigScope("ABCD1234EFGH5678", false); err != nil {
+		t.Fatalf("ApplySigningConfigScope local failed: %v", err)
+	}
+
+	key, _ := GetConfig("user.signingkey", false)
+	if key != "ABCD1234EFGH5678" {
+		t.Errorf("expected local signing key, got '%s'", key)
+	}
+	globalKey, _ := GetConfig("user.signingkey", true)
+	if globalKey != "" {
+		t.Errorf("expected no global signing key, got '%s'", globalKey)
+	}
+
+	if err := ClearSigningConfigScope(false); err != nil {
+		t.Fatalf("ClearSigningConfigScope local failed: %v", err)
+	}
+	if key, _ := GetConfig("user.signingkey", false); key != "" {
+		t.Errorf("expected local signing key to be cleared, got '%s'", key)
+	}
+}
+
+func TestApplySigning_Global(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.cleanup(t)
+
+	if err := ApplySigning("ABCD1234EFGH5678", "", true); err != nil {
+		t.Fatalf("ApplySigning failed: %v", err)
+	}
+
+	for key, want := range map[string]string{
+		"user.signingkey": "ABCD1234EFGH5678",
+		"commit.gpgsign":  "true",
+	} {
+		if got, _ := GetConfig(key, true); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	// Switching to an identity without a key clears signing
+	if err := ApplySigning("", "", true); err != nil {
+		t.Fatalf("ApplySigning failed: %v", err)
+	}
+	if key, _ := GetConfig("user.signingkey", true); key != "" {
+		t.Errorf("expected signing key to be cleared, got %q", key)
+	}
+	if sign, _ := GetConfig("commit.gpgsign", true); sign != "" {
+		t.Errorf("expected commit.gpgsign to be cleared, got %q", sign)
+	}
+}
+
+func TestApplySigning_LocalDisablesInheritedSigning(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.cleanup(t)
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(env.dir)
+
+	if err := ApplySigningConfigScope("ABCD1234EFGH5678", true); err != nil {
+		t.Fatalf("ApplySigningConfigScope global failed: %v", err)
+	}
+
+	if err := ApplySigning("", "", false); err != nil {
+		t.Fatalf("ApplySigning local failed: %v", err)
+	}
+
+	// The global key would otherwise sign this repo's commits
+	if sign, _ := GetConfig("commit.gpgsign", false); sign != "false" {
+		t.Errorf("expected commit.gpgsign disabled in the repo, got %q", sign)
+	}
+	if key, _ := GetConfig("user.signingkey", true); key != "ABCD1234EFGH5678" {
+		t.Errorf("global signing key should be untouched, got %q", key)
+	}
+}
+
+func TestApplySigning_SSHSigning(t *testing.T) {
+	env := setupTestEnv(t)
+	defer env.cleanup(t)
+
+	if err := ApplySigning("/keys/id_work.pub", SigningFormatSSH, true); err != nil {
+		t.Fatalf("ApplySigning failed: %v", err)
+	}
+
+	for key, want := range map[string]string{
+		"gpg.format":      "ssh",
+		"user.signingkey": "/keys/id_work.pub",
+		"commit.gpgsign":  "true",
+	} {
+		if got, _ := GetConfig(key, true); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+
+	// Switching to a GPG identity drops the ssh format
+	if err := ApplySigning("ABCD1234EFGH5678", "", true); err != nil {
+		t.Fatalf("ApplySigning failed: %v", err)
+	}
+	if format, _ := GetConfig("gpg.format", true); format != "" {
+		t.Errorf("expected gpg.format to be cleared, got %q", format)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(env.dir)
+
+	// A GPG identity in a repo overrides an inherited ssh format
+	if err := SetConfig("gpg.format", SigningFormatSSH, true); err != nil {
+		t.Fatalf("SetConfig failed: %v", err)
+	}
+	if err := ApplySigning("ABCD1234EFGH5678", "", false); err != nil {
+		t.Fatalf("ApplySigning local failed: %v", err)
+	}
+	if format, _ := GetConfig("gpg.format", false); format != "openpgp" {
+		t.Errorf("expected gpg.format openpgp in the repo, got %q", format)
+	}
+}
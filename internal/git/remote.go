@@ -31,24 +31,86 @@ func IsAzureDevOpsRemote(remoteURL string) bool {
 		strings.Contains(host, "visualstudio.com")
 }
 
-// GetCurrentRemoteType detects if the current git repository's origin remote
-// is an Azure DevOps repository.
-// Returns (true, nil) if Azure DevOps is detected.
-// Returns (false, nil) if not Azure DevOps or no origin remote exists.
-// Returns (false, error) only if the git command fails for other reasons.
-func GetCurrentRemoteType() (isAzureDevOps bool, err error) {
+// RemoteProvider identifies the Git hosting service behind a remote
+type RemoteProvider int
+
+const (
+	ProviderUnknown     RemoteProvider = iota // self-hosted or unrecognized host
+	ProviderGitHub                            // github.com
+	ProviderGitLab                            // gitlab.com
+	ProviderBitbucket                         // bitbucket.org
+	ProviderAzureDevOps                       // dev.azure.com, *.visualstudio.com
+)
+
+// String returns the provider's display name
+func (p RemoteProvider) String() string {
+	switch p {
+	case ProviderGitHub:
+		return "GitHub"
+	case ProviderGitLab:
+		return "GitLab"
+	case ProviderBitbucket:
+		return "Bitbucket"
+	case ProviderAzureDevOps:
+		return "Azure DevOps"
+	default:
+		return "Unknown"
+	}
+}
+
+// DetectProvider returns the hosting provider for a remote URL.
+// Supports HTTPS, SSH, and SCP-style URL formats. Self-hosted instances
+// (e.g. GitLab on a company domain) are ProviderUnknown.
+func DetectProvider(remoteURL string) RemoteProvider {
+	if IsAzureDevOpsRemote(remoteURL) {
+		return ProviderAzureDevOps
+	}
+	if remoteURL == "" {
+		return ProviderUnknown
+	}
+
+	u, err := giturls.Parse(remoteURL)
+	if err != nil {
+		return ProviderUnknown
+	}
+
+	// ssh.github.com serves SSH over port 443
+	switch strings.ToLower(u.Hostname()) {
+	case "github.com", "ssh.github.com":
+		return ProviderGitHub
+	case "gitlab.com":
+		return ProviderGitLab
+	case "bitbucket.org":
+		return ProviderBitbucket
+	default:
+		return ProviderUnknown
+	}
+}
+
+// GetCurrentRemoteType detects the hosting provider of the current git
+// repository's origin remote.
+// Returns (ProviderUnknown, nil) if the host is not recognized or no origin
+// remote exists.
+func GetCurrentRemoteType() (RemoteProvider, error) {
 	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
 	output, err := cmd.Output()
 	if err != nil {
-		// If the command fails (e.g., no origin remote), return false without error
+		// If the command fails (e.g., no origin remote), return unknown without error
 		// This handles: not in a git repo, no origin remote, etc.
-		return false, nil
+		return ProviderUnknown, nil
 	}
 
 	remoteURL := strings.TrimSpace(string(output))
 	if remoteURL == "" {
-		return false, nil
+		return ProviderUnknown, nil
 	}
 
-	return IsAzureDevOpsRemote(remoteURL), nil
+	return DetectProvider(remoteURL), nil
+}
+
+// IsAzureDevOps reports whether the current repository's origin remote is
+// an Azure DevOps repository, which needs RSA SSH keys
+func IsAzureDevOps() bool {
+	provider, _ := GetCurrentRemoteType()
+	return provider == ProviderAzureDevOps
 }
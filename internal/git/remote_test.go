@@ -146,3 +146,31 @@ func TestIsAzureDevOpsRemote(t *testing.T) {
 		})
 	}
 }
+
+func TestDetectProvider(t *testing.T) {
+	tests := []struct {
+		url  string
+		want RemoteProvider
+	}{
+		{"git@github.com:company/project.git", ProviderGitHub},
+		{"https://github.com/company/project.git", ProviderGitHub},
+		{"ssh://git@ssh.github.com:443/company/project.git", ProviderGitHub},
+		{"git@gitlab.com:group/subgroup/project.git", ProviderGitLab},
+		{"https://GitLab.com/group/project", ProviderGitLab},
+		{"git@bitbucket.org:team/repo.git", ProviderBitbucket},
+		{"https://user@bitbucket.org/team/repo.git", ProviderBitbucket},
+		{"git@ssh.dev.azure.com:v3/org/project/repo", ProviderAzureDevOps},
+		{"https://org.visualstudio.com/project/_git/repo", ProviderAzureDevOps},
+		{"git@gitlab.company.internal:team/repo.git", ProviderUnknown},
+		{"https://notgithub.com/org/repo", ProviderUnknown},
+		{"", ProviderUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := DetectProvider(tt.url); got != tt.want {
+				t.Errorf("DetectProvider(%q) = %v, want %v", tt.url, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+// Package gpgtest provides helpers for tests that run gpg.
+package gpgtest
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// UseTempKeyring points gpg at an empty keyring for the duration of the
+// test, skipping the test if gpg is not installed
+func UseTempKeyring(t testing.TB) {
+	t.Helper()
+
+	if err := exec.Command("gpg", "--version").Run(); err != nil {
+		t.Skip("gpg not installed, skipping test")
+	}
+
+	// Keep the path short; gpg-agent sockets have a length limit
+	home, err := os.MkdirTemp("", "gpg")
+	if err != nil {
+		t.Fatalf("failed to create GNUPGHOME: %v", err)
+	}
+	t.Cleanup(func() {
+		// Stop the agent started for this keyring only
+		kill := exec.Command("gpgconf", "--kill", "gpg-agent")
+		kill.Env = append(os.Environ(), "GNUPGHOME="+home)
+		_ = kill.Run()
+		os.RemoveAll(home)
+	})
+	if err := os.Chmod(home, 0700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GNUPGHOME", home)
+}
@@ -4,9 +4,13 @@ import (
 	"bytes"
 	"crypto"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
@@ -17,8 +21,19 @@ import (
 // GenerateKey generates a new Ed25519 GPG key and imports it into the system gpg keyring.
 // The key is created with the given name and email, using go-crypto for pure Go generation.
 // If passphrase is provided, the key will be encrypted.
+// The key never expires; use GenerateKeyWithExpiry to set a lifetime.
 // Returns KeyInfo for the newly created key.
 func GenerateKey(name, email string, passphrase []byte) (*KeyInfo, error) {
+	return GenerateKeyWithExpiry(name, email, passphrase, 0)
+}
+
+// GenerateKeyWithExpiry generates a new Ed25519 GPG key like GenerateKey,
+// expiring after lifetime. A zero lifetime means the key never expires.
+func GenerateKeyWithExpiry(name, email string, passphrase []byte, lifetime time.Duration) (*KeyInfo, error) {
+	if lifetime < 0 || lifetime.Seconds() > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid key lifetime: %s", lifetime)
+	}
+
 	// Create entity config for Ed25519
 	config := &packet.Config{
 		Algorithm:              packet.PubKeyAlgoEdDSA,
@@ -26,6 +41,8 @@ func GenerateKey(name, email string, passphrase []byte) (*KeyInfo, error) {
 		DefaultCipher:          packet.CipherAES256,
 		DefaultCompressionAlgo: packet.CompressionZLIB,
 		Time:                   func() time.Time { return time.Now() },
+		// Applied to the primary key's self-signature and subkey bindings
+		KeyLifetimeSecs: uint32(lifetime.Seconds()),
 	}
 
 	// Create comment for the key
@@ -69,7 +86,7 @@ func GenerateKey(name, email string, passphrase []byte) (*KeyInfo, error) {
 
 	// Import the key into system gpg
 	armoredKey := privateKeyBuf.Bytes()
-	err = importKeyToGPG(armoredKey)
+	err = ImportKey(armoredKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to import key to gpg: %w", err)
 	}
@@ -89,17 +106,113 @@ func GenerateKey(name, email string, passphrase []byte) (*KeyInfo, error) {
 	return &keys[len(keys)-1], nil
 }
 
-// importKeyToGPG imports an armored private key into the system gpg keyring.
-func importKeyToGPG(armoredKey []byte) error {
-	cmd := exec.Command("gpg", "--import", "--batch")
-	cmd.Stdin = bytes.NewReader(armoredKey)
+// ParseExpiry parses a key expiry such as "2y", "6m", "4w" or "30d" (the
+// units gpg uses: years, 30-day months, weeks, days). "never", "0" and the
+// empty string mean no expiry and return zero.
+func ParseExpiry(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "never" || s == "0" {
+		return 0, nil
+	}
+
+	unit := s[len(s)-1]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid expiry %q: use a positive number with d, w, m or y (e.g. 2y), or never", s)
+	}
+
+	var days int64
+	switch unit {
+	case 'd':
+		days = 1
+	case 'w':
+		days = 7
+	case 'm':
+		days = 30
+	case 'y':
+		days = 365
+	default:
+		return 0, fmt.Errorf("invalid expiry %q: use a positive number with d, w, m or y (e.g. 2y), or never", s)
+	}
+
+	// OpenPGP stores the lifetime as 32-bit seconds
+	unitSeconds := days * 24 * 60 * 60
+	if int64(n) > math.MaxUint32/unitSeconds {
+		return 0, fmt.Errorf("invalid expiry %q: too far in the future", s)
+	}
+	return time.Duration(int64(n)*unitSeconds) * time.Second, nil
+}
+
+// ImportKey imports an armored public or private key into the system gpg keyring.
+func ImportKey(armoredKey []byte) error {
+	_, err := importKeys(bytes.NewReader(armoredKey))
+	return err
+}
 
-	output, err := cmd.CombinedOutput()
+// ImportKeyFile imports the armored key(s) in the file at path into the
+// system gpg keyring and returns the secret keys it contained.
+// Returns an error if the file has no secret key.
+func ImportKeyFile(path string) ([]KeyInfo, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("gpg import failed: %s - %w", string(output), err)
+		return nil, fmt.Errorf("failed to open key file: %w", err)
 	}
+	defer file.Close()
 
-	return nil
+	fingerprints, err := importKeys(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []KeyInfo
+	for _, fingerprint := range fingerprints {
+		// GetKeyInfo only lists secret keys, so public-only keys are skipped
+		info, err := GetKeyInfo(fingerprint)
+		if err != nil {
+			continue
+		}
+		keys = append(keys, *info)
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s contains no GPG secret key; export one with 'gpg --armor --export-secret-keys <key-id>'", path)
+	}
+
+	return keys, nil
+}
+
+// importKeys pipes r to gpg --import and returns the fingerprints of the
+// imported keys, including keys that were already in the keyring.
+func importKeys(r io.Reader) ([]string, error) {
+	cmd := exec.Command("gpg", "--import", "--batch", "--status-fd", "1")
+	cmd.Stdin = r
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("gpg import failed: %s - %w", stderr.String(), err)
+	}
+
+	return parseImportStatus(string(output)), nil
+}
+
+// parseImportStatus extracts the key fingerprints from the IMPORT_OK lines
+// of gpg --status-fd output, without duplicates. gpg reports a secret key
+// import twice, once for the public part.
+func parseImportStatus(output string) []string {
+	var fingerprints []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[0] != "[GNUPG:]" || fields[1] != "IMPORT_OK" {
+			continue
+		}
+		if fingerprint := fields[3]; !seen[fingerprint] {
+			seen[fingerprint] = true
+			fingerprints = append(fingerprints, fingerprint)
+		}
+	}
+	return fingerprints
 }
 
 // DefaultKeyPath returns the default path for a GPG key file for a gitch identity.
@@ -153,7 +266,13 @@ func ExportPrivateKey(keyID string) (string, error) {
 
 // WriteKeyBackup writes the exported public and private keys to files.
 // This creates a backup of the key outside the gpg keyring.
+// The public key goes to {basePath}.pub.asc and the private key to {basePath}.asc (0600).
 func WriteKeyBackup(keyID, basePath string) error {
+	// Ensure parent directory exists with secure permissions
+	if err := os.MkdirAll(filepath.Dir(basePath), 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
 	// Export and write public key
 	pubKey, err := ExportPublicKey(keyID)
 	if err != nil {
@@ -177,6 +296,10 @@ func WriteKeyBackup(keyID, basePath string) error {
 		os.Remove(pubPath) // Clean up
 		return fmt.Errorf("failed to write private key backup: %w", err)
 	}
+	// WriteFile keeps the mode of an existing file; tighten it
+	if err := os.Chmod(privPath, 0600); err != nil {
+		return fmt.Errorf("failed to restrict private key backup permissions: %w", err)
+	}
 
 	return nil
 }
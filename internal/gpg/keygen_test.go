@@ -0,0 +1,183 @@
+package gpg
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orzazade/gitch/internal/gpg/gpgtest"
+)
+
+func TestParseExpiry(t *testing.T) {
+	day := 24 * time.Hour
+
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "", expected: 0},
+		{input: "never", expected: 0},
+		{input: "Never", expected: 0},
+		{input: "0", expected: 0},
+		{input: "30d", expected: 30 * day},
+		{input: "4w", expected: 28 * day},
+		{input: "6m", expected: 180 * day},
+		{input: "2y", expected: 730 * day},
+		{input: "2", wantErr: true},
+		{input: "y", wantErr: true},
+		{input: "-1y", wantErr: true},
+		{input: "0d", wantErr: true},
+		{input: "2h", wantErr: true},
+		{input: "1000y", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseExpiry(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseExpiry(%q) expected error, got %v", tt.input, result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseExpiry(%q) error = %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseExpiry(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateKeyWithExpiry(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+
+	before := time.Now()
+	info, err := GenerateKeyWithExpiry("Test", "expiry@example.com", nil, 365*24*time.Hour)
+	if err != nil {
+		t.Fatalf("GenerateKeyWithExpiry() error = %v", err)
+	}
+
+	info, err = GetKeyInfo(info.ID)
+	if err != nil {
+		t.Fatalf("GetKeyInfo() error = %v", err)
+	}
+	if info.Expires == nil {
+		t.Fatal("expected Expires to be set")
+	}
+
+	want := before.Add(365 * 24 * time.Hour)
+	if diff := info.Expires.Sub(want); diff < -time.Minute || diff > time.Minute {
+		t.Errorf("Expires = %v, want about %v", info.Expires, want)
+	}
+}
+
+func TestGenerateKey_NoExpiry(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+
+	info, err := GenerateKey("Test", "noexpiry@example.com", nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	info, err = GetKeyInfo(info.ID)
+	if err != nil {
+		t.Fatalf("GetKeyInfo() error = %v", err)
+	}
+	if info.Expires != nil {
+		t.Errorf("expected no expiry, got %v", info.Expires)
+	}
+}
+
+func TestWriteKeyBackup(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+
+	info, err := GenerateKey("Test", "backup@example.com", nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	basePath := filepath.Join(t.TempDir(), "backups", "gitch-test")
+	if err := WriteKeyBackup(info.ID, basePath); err != nil {
+		t.Fatalf("WriteKeyBackup() error = %v", err)
+	}
+
+	pub, err := os.ReadFile(basePath + ".pub.asc")
+	if err != nil {
+		t.Fatalf("public key backup not found: %v", err)
+	}
+	if !strings.Contains(string(pub), "BEGIN PGP PUBLIC KEY BLOCK") {
+		t.Error("expected armored public key")
+	}
+
+	privInfo, err := os.Stat(basePath + ".asc")
+	if err != nil {
+		t.Fatalf("private key backup not found: %v", err)
+	}
+	if privInfo.Mode().Perm() != 0600 {
+		t.Errorf("private key backup permissions = %o, want 0600", privInfo.Mode().Perm())
+	}
+}
+
+func TestParseImportStatus(t *testing.T) {
+	output := `[GNUPG:] KEY_CONSIDERED 0123456789ABCDEF0123456789ABCDEF01234567 0
+[GNUPG:] IMPORTED 0123456789ABCDEF Test <test@example.com>
+[GNUPG:] IMPORT_OK 1 0123456789ABCDEF0123456789ABCDEF01234567
+[GNUPG:] IMPORT_OK 17 0123456789ABCDEF0123456789ABCDEF01234567
+[GNUPG:] IMPORT_OK 0 FEDCBA9876543210FEDCBA9876543210FEDCBA98
+[GNUPG:] IMPORT_RES 2 0 1 0 1 0 0 0 0 1 1 0 0 0 0
+`
+	got := parseImportStatus(output)
+	want := []string{
+		"0123456789ABCDEF0123456789ABCDEF01234567",
+		"FEDCBA9876543210FEDCBA9876543210FEDCBA98",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("parseImportStatus() = %v, want %v", got, want)
+	}
+
+	if got := parseImportStatus(""); len(got) != 0 {
+		t.Errorf("parseImportStatus(\"\") = %v, want none", got)
+	}
+}
+
+func TestImportKeyFile(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+
+	info, err := GenerateKey("Test", "import@example.com", nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	basePath := filepath.Join(t.TempDir(), "gitch-test")
+	if err := WriteKeyBackup(info.ID, basePath); err != nil {
+		t.Fatalf("WriteKeyBackup() error = %v", err)
+	}
+
+	// Import into a fresh keyring
+	gpgtest.UseTempKeyring(t)
+
+	if _, err := ImportKeyFile(basePath + ".pub.asc"); err == nil {
+		t.Error("expected error importing a file without a secret key")
+	}
+
+	keys, err := ImportKeyFile(basePath + ".asc")
+	if err != nil {
+		t.Fatalf("ImportKeyFile() error = %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("ImportKeyFile() returned %d keys, want 1", len(keys))
+	}
+	if keys[0].ID != info.ID || keys[0].Email != "import@example.com" {
+		t.Errorf("ImportKeyFile() = %s <%s>, want %s <import@example.com>", keys[0].ID, keys[0].Email, info.ID)
+	}
+
+	// Importing again reports the key that is already in the keyring
+	keys, err = ImportKeyFile(basePath + ".asc")
+	if err != nil || len(keys) != 1 {
+		t.Errorf("second ImportKeyFile() = %d keys, %v; want 1 key", len(keys), err)
+	}
+}
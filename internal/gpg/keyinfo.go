@@ -33,6 +33,17 @@ type KeyInfo struct {
 	Name string
 }
 
+// IsExpired reports whether the key has expired at the given time.
+func (k *KeyInfo) IsExpired(now time.Time) bool {
+	return k.Expires != nil && !k.Expires.After(now)
+}
+
+// ExpiresWithin reports whether the key expires within window of the given
+// time. Already expired keys are included.
+func (k *KeyInfo) ExpiresWithin(now time.Time, window time.Duration) bool {
+	return k.Expires != nil && k.Expires.Before(now.Add(window))
+}
+
 // GetKeyInfo retrieves information about a GPG key by its key ID.
 // The keyID can be a short ID, long ID, fingerprint, or email address.
 // Returns an error if the key is not found in the gpg keyring.
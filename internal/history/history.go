@@ -0,0 +1,161 @@
+// Package history records identity switches so they can be listed and undone.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/adrg/xdg"
+)
+
+// MaxEntries is how many switches the history log keeps; older ones are dropped
+const MaxEntries = 100
+
+// Scope values for Entry.Scope
+const (
+	ScopeGlobal = "global"
+	ScopeLocal  = "local"
+)
+
+// Entry records a single identity switch
+type Entry struct {
+	Time  time.Time `json:"time"`
+	Repo  string    `json:"repo,omitempty"` // repository root the switch was made in, if any
+	From  string    `json:"from,omitempty"` // previous identity, empty if unknown
+	To    string    `json:"to"`
+	Scope string    `json:"scope"` // ScopeGlobal or ScopeLocal
+}
+
+// Path returns the XDG cache file path of the history log
+func Path() (string, error) {
+	return xdg.CacheFile("gitch/history.jsonl")
+}
+
+// Load reads the history log, oldest entry first.
+// Returns an empty slice (no error) if the log doesn't exist.
+// Lines that fail to parse are skipped.
+func Load() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Entry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Record appends entry to the history log, keeping only the newest
+// MaxEntries. The log is rewritten atomically (temp file + rename).
+func Record(entry Entry) error {
+	entries, err := Load()
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > MaxEntries {
+		entries = entries[len(entries)-MaxEntries:]
+	}
+	return save(entries)
+}
+
+// Clear deletes the history log.
+// Silently succeeds if the log doesn't exist.
+func Clear() error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove history: %w", err)
+	}
+	return nil
+}
+
+// Last returns the most recent switch, or nil if the history is empty
+func Last() (*Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	return &entries[len(entries)-1], nil
+}
+
+// LastInScope returns the most recent switch in scope, or nil if there is
+// none. For ScopeLocal, only switches made in the repository at repo count.
+func LastInScope(scope, repo string) (*Entry, error) {
+	entries, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Scope != scope || (scope == ScopeLocal && entry.Repo != repo) {
+			continue
+		}
+		return &entry, nil
+	}
+	return nil, nil
+}
+
+// save writes entries to the history log, one JSON object per line
+func save(entries []Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	// Create directory if needed
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to encode history entry: %w", err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	// Write to temp file first for atomic operation
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+
+	// Atomic rename
+	if err := os.Rename(tmpPath, path); err != nil {
+		// Clean up temp file on failure
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
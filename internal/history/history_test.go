@@ -0,0 +1,177 @@
+package history
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/orzazade/gitch/internal/xdgtest"
+)
+
+// useTempCache points XDG_CACHE_HOME at a temp dir for the duration of the test
+func useTempCache(t *testing.T) {
+	t.Helper()
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", t.TempDir())
+}
+
+func TestLoad_Missing(t *testing.T) {
+	useTempCache(t)
+
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no entries, got %d", len(entries))
+	}
+
+	last, err := Last()
+	if err != nil || last != nil {
+		t.Errorf("Last() = %v, %v; want nil, nil", last, err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	useTempCache(t)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := Entry{Time: now, From: "personal", To: "work", Scope: ScopeGlobal}
+	second := Entry{Time: now.Add(time.Minute), Repo: "/src/oss", From: "work", To: "personal", Scope: ScopeLocal}
+
+	for _, entry := range []Entry{first, second} {
+		if err := Record(entry); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[0] != first || entries[1] != second {
+		t.Errorf("entries = %+v, want %+v then %+v", entries, first, second)
+	}
+
+	last, err := Last()
+	if err != nil {
+		t.Fatalf("Last failed: %v", err)
+	}
+	if *last != second {
+		t.Errorf("Last() = %+v, want %+v", *last, second)
+	}
+
+	// No temp file is left behind
+	path, _ := Path()
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Error("expected temp file to be renamed away")
+	}
+}
+
+func TestLastInScope(t *testing.T) {
+	useTempCache(t)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Time: now, Repo: "/src/app", From: "personal", To: "work", Scope: ScopeGlobal},
+		{Time: now.Add(time.Minute), Repo: "/src/oss", From: "work", To: "personal", Scope: ScopeLocal},
+		{Time: now.Add(2 * time.Minute), Repo: "/src/app", From: "work", To: "client", Scope: ScopeLocal},
+	}
+	for _, entry := range entries {
+		if err := Record(entry); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		scope string
+		repo  string
+		want  *Entry
+	}{
+		// Global switches count wherever they were made
+		{ScopeGlobal, "", &entries[0]},
+		{ScopeLocal, "/src/oss", &entries[1]},
+		{ScopeLocal, "/src/app", &entries[2]},
+		{ScopeLocal, "/src/other", nil},
+	}
+	for _, tt := range tests {
+		got, err := LastInScope(tt.scope, tt.repo)
+		if err != nil {
+			t.Fatalf("LastInScope(%s, %s) failed: %v", tt.scope, tt.repo, err)
+		}
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("LastInScope(%s, %s) = %+v, want %+v", tt.scope, tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestRecord_Cap(t *testing.T) {
+	useTempCache(t)
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < MaxEntries+5; i++ {
+		entry := Entry{Time: start.Add(time.Duration(i) * time.Minute), To: "work", Scope: ScopeGlobal}
+		if err := Record(entry); err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != MaxEntries {
+		t.Fatalf("expected %d entries, got %d", MaxEntries, len(entries))
+	}
+	// The oldest entries are dropped
+	if want := start.Add(5 * time.Minute); !entries[0].Time.Equal(want) {
+		t.Errorf("oldest entry time = %v, want %v", entries[0].Time, want)
+	}
+}
+
+func TestLoad_SkipsCorruptLines(t *testing.T) {
+	useTempCache(t)
+
+	path, _ := Path()
+	content := `{"time":"2024-05-01T12:00:00Z","to":"work","scope":"global"}
+not json
+{"time":"2024-05-01T12:01:00Z","from":"work","to":"personal","scope":"global"}
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	if entries[1].To != "personal" {
+		t.Errorf("expected last entry to switch to personal, got %q", entries[1].To)
+	}
+}
+
+func TestClear(t *testing.T) {
+	useTempCache(t)
+
+	if err := Record(Entry{Time: time.Now().UTC(), To: "work", Scope: ScopeGlobal}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	path, _ := Path()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected history log to be removed, stat error = %v", err)
+	}
+
+	// Clearing again is fine
+	if err := Clear(); err != nil {
+		t.Errorf("second Clear failed: %v", err)
+	}
+}
@@ -2,14 +2,41 @@
 package hooks
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/adrg/xdg"
 	"github.com/orzazade/gitch/internal/git"
 )
 
+// LocalBackupName is the file name a pre-existing repository hook is moved to
+// by InstallLocal. The gitch hook runs it before validating identity.
+const LocalBackupName = "pre-commit.gitch-backup"
+
+// LocalCommitMsgBackupName is the file name a pre-existing repository
+// commit-msg hook is moved to by InstallSignCheckLocal.
+const LocalCommitMsgBackupName = "commit-msg.gitch-backup"
+
+// ErrNotGitRepo is returned by local hook operations outside a git repository
+var ErrNotGitRepo = errors.New("not in a git repository")
+
+// InstallStatus reports where gitch hooks are installed
+type InstallStatus struct {
+	Global          bool // core.hooksPath points to the gitch hooks directory
+	Local           bool // the current repository's pre-commit hook is gitch's
+	GlobalSignCheck bool // the global commit-msg signing check is installed
+	LocalSignCheck  bool // the current repository's commit-msg hook is gitch's
+	// GlobalOutdated and LocalOutdated report installed gitch hooks that
+	// differ from the scripts this version writes, e.g. written before the
+	// pre-commit script handled require_rule
+	GlobalOutdated bool
+	LocalOutdated  bool
+}
+
 // HooksDir returns the gitch hooks directory path
 func HooksDir() (string, error) {
 	return xdg.ConfigFile("gitch/hooks")
@@ -60,8 +87,36 @@ func UninstallGlobal() error {
 	return nil
 }
 
-// IsInstalled checks if gitch hooks are globally installed
-func IsInstalled() (bool, error) {
+// IsInstalled reports whether gitch hooks are installed globally and in the
+// current repository. Local is false outside a git repository.
+func IsInstalled() (*InstallStatus, error) {
+	global, err := isInstalledGlobal()
+	if err != nil {
+		return nil, err
+	}
+
+	status := &InstallStatus{Global: global}
+
+	if global {
+		if hooksDir, err := HooksDir(); err == nil {
+			status.GlobalSignCheck = isGitchHook(filepath.Join(hooksDir, "commit-msg"), commitMsgHookMarker)
+			status.GlobalOutdated = !isCurrentHook(filepath.Join(hooksDir, "pre-commit"), PreCommitScript) ||
+				(status.GlobalSignCheck && !isCurrentHook(filepath.Join(hooksDir, "commit-msg"), CommitMsgScript))
+		}
+	}
+
+	if hooksDir, err := LocalHooksDir(); err == nil {
+		status.Local = isGitchHook(filepath.Join(hooksDir, "pre-commit"), hookMarker)
+		status.LocalSignCheck = isGitchHook(filepath.Join(hooksDir, "commit-msg"), commitMsgHookMarker)
+		status.LocalOutdated = (status.Local && !isCurrentHook(filepath.Join(hooksDir, "pre-commit"), LocalPreCommitScript())) ||
+			(status.LocalSignCheck && !isCurrentHook(filepath.Join(hooksDir, "commit-msg"), LocalCommitMsgScript()))
+	}
+
+	return status, nil
+}
+
+// isInstalledGlobal checks if core.hooksPath points to the gitch hooks directory
+func isInstalledGlobal() (bool, error) {
 	// Get current core.hooksPath value
 	currentPath, err := git.GetConfig("core.hooksPath", true)
 	if err != nil {
@@ -81,3 +136,145 @@ func IsInstalled() (bool, error) {
 	// Compare paths (normalize for comparison)
 	return filepath.Clean(currentPath) == filepath.Clean(hooksDir), nil
 }
+
+// LocalHooksDir returns the hooks directory of the current repository.
+// Uses the common git dir so worktrees share hooks, and ignores core.hooksPath.
+// Returns ErrNotGitRepo outside a git repository.
+func LocalHooksDir() (string, error) {
+	// --git-common-dir fails outside a repository; it may be relative to the cwd
+	cmd := exec.Command("git", "rev-parse", "--git-common-dir")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", ErrNotGitRepo
+	}
+
+	gitDir, err := filepath.Abs(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve git directory: %w", err)
+	}
+
+	return filepath.Join(gitDir, "hooks"), nil
+}
+
+// InstallSignCheckGlobal writes the commit-msg signing check into the gitch
+// hooks directory. It takes effect once InstallGlobal has set core.hooksPath.
+func InstallSignCheckGlobal() error {
+	hooksDir, err := HooksDir()
+	if err != nil {
+		return fmt.Errorf("failed to determine hooks directory: %w", err)
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	commitMsgPath := filepath.Join(hooksDir, "commit-msg")
+	if err := os.WriteFile(commitMsgPath, []byte(CommitMsgScript), 0755); err != nil {
+		return fmt.Errorf("failed to write commit-msg hook: %w", err)
+	}
+
+	return nil
+}
+
+// InstallLocal installs the pre-commit hook in the current repository.
+// An existing non-gitch pre-commit hook is moved to LocalBackupName and
+// chained so it still runs before gitch's validation.
+func InstallLocal() error {
+	return installLocalHook("pre-commit", LocalBackupName, LocalPreCommitScript(), hookMarker)
+}
+
+// InstallSignCheckLocal installs the commit-msg signing check in the current
+// repository, preserving an existing hook the same way as InstallLocal.
+func InstallSignCheckLocal() error {
+	return installLocalHook("commit-msg", LocalCommitMsgBackupName, LocalCommitMsgScript(), commitMsgHookMarker)
+}
+
+// UninstallLocal removes gitch's hooks from the current repository and
+// restores any previous hooks that were backed up.
+func UninstallLocal() error {
+	hooksDir, err := LocalHooksDir()
+	if err != nil {
+		return err
+	}
+
+	if !isGitchHook(filepath.Join(hooksDir, "pre-commit"), hookMarker) {
+		return errors.New("gitch pre-commit hook is not installed in this repository")
+	}
+
+	if err := uninstallLocalHook(hooksDir, "pre-commit", LocalBackupName); err != nil {
+		return err
+	}
+
+	if isGitchHook(filepath.Join(hooksDir, "commit-msg"), commitMsgHookMarker) {
+		if err := uninstallLocalHook(hooksDir, "commit-msg", LocalCommitMsgBackupName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// installLocalHook writes script as the named hook in the current repository.
+// An existing hook without marker is moved to backupName first.
+func installLocalHook(name, backupName, script, marker string) error {
+	hooksDir, err := LocalHooksDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	hookPath := filepath.Join(hooksDir, name)
+	backupPath := filepath.Join(hooksDir, backupName)
+
+	// Preserve an existing hook that isn't ours
+	if _, err := os.Stat(hookPath); err == nil && !isGitchHook(hookPath, marker) {
+		if _, err := os.Stat(backupPath); err == nil {
+			return fmt.Errorf("cannot back up existing %s hook: %s already exists", name, backupPath)
+		}
+		if err := os.Rename(hookPath, backupPath); err != nil {
+			return fmt.Errorf("failed to back up existing %s hook: %w", name, err)
+		}
+	}
+
+	if err := os.WriteFile(hookPath, []byte(script), 0755); err != nil {
+		return fmt.Errorf("failed to write %s hook: %w", name, err)
+	}
+
+	return nil
+}
+
+// uninstallLocalHook removes the named hook and restores its backup, if any
+func uninstallLocalHook(hooksDir, name, backupName string) error {
+	hookPath := filepath.Join(hooksDir, name)
+	if err := os.Remove(hookPath); err != nil {
+		return fmt.Errorf("failed to remove %s hook: %w", name, err)
+	}
+
+	// Restore the previous hook
+	backupPath := filepath.Join(hooksDir, backupName)
+	if _, err := os.Stat(backupPath); err == nil {
+		if err := os.Rename(backupPath, hookPath); err != nil {
+			return fmt.Errorf("failed to restore previous %s hook: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// isGitchHook checks if the file at path is a hook written by gitch
+func isGitchHook(path, marker string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), marker)
+}
+
+// isCurrentHook reports whether the file at path is exactly script
+func isCurrentHook(path, script string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && string(data) == script
+}
@@ -0,0 +1,245 @@
+package hooks
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/xdgtest"
+)
+
+// setupTestRepo creates a temp git repo, isolates global git config,
+// and changes into the repo for the duration of the test.
+func setupTestRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	globalConfig := filepath.Join(dir, ".gitconfig")
+	if err := os.WriteFile(globalConfig, []byte{}, 0644); err != nil {
+		t.Fatalf("failed to create temp gitconfig: %v", err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", globalConfig)
+	t.Setenv("HOME", dir)
+
+	repo := filepath.Join(dir, "repo")
+	cmd := exec.Command("git", "init", repo)
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("failed to init git repo: %v", err)
+	}
+
+	t.Chdir(repo)
+	return repo
+}
+
+func TestLocalPreCommitScript(t *testing.T) {
+	script := LocalPreCommitScript()
+
+	if !strings.HasPrefix(script, "#!/bin/bash\n") {
+		t.Error("expected script to start with shebang")
+	}
+	if !strings.Contains(script, hookMarker) {
+		t.Error("expected script to contain gitch marker")
+	}
+	if !strings.Contains(script, LocalBackupName) {
+		t.Error("expected script to chain to backed-up hook")
+	}
+	if !strings.Contains(script, "gitch hook validate") {
+		t.Error("expected script to run validation")
+	}
+}
+
+func TestInstallLocal_Fresh(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	if err := InstallLocal(); err != nil {
+		t.Fatalf("InstallLocal failed: %v", err)
+	}
+
+	preCommit := filepath.Join(repo, ".git", "hooks", "pre-commit")
+	info, err := os.Stat(preCommit)
+	if err != nil {
+		t.Fatalf("expected pre-commit hook to exist: %v", err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Error("expected pre-commit hook to be executable")
+	}
+
+	status, err := IsInstalled()
+	if err != nil {
+		t.Fatalf("IsInstalled failed: %v", err)
+	}
+	if !status.Local {
+		t.Error("expected Local to be true after InstallLocal")
+	}
+	if status.Global {
+		t.Error("expected Global to be false")
+	}
+}
+
+func TestInstallLocal_PreservesExistingHook(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	hooksDir := filepath.Join(repo, ".git", "hooks")
+	preCommit := filepath.Join(hooksDir, "pre-commit")
+	existing := "#!/bin/sh\necho existing\n"
+	if err := os.WriteFile(preCommit, []byte(existing), 0755); err != nil {
+		t.Fatalf("failed to write existing hook: %v", err)
+	}
+
+	if err := InstallLocal(); err != nil {
+		t.Fatalf("InstallLocal failed: %v", err)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(hooksDir, LocalBackupName))
+	if err != nil {
+		t.Fatalf("expected existing hook to be backed up: %v", err)
+	}
+	if string(backup) != existing {
+		t.Errorf("backup content = %q, want %q", backup, existing)
+	}
+
+	// Installing again is a no-op for the backup
+	if err := InstallLocal(); err != nil {
+		t.Fatalf("second InstallLocal failed: %v", err)
+	}
+
+	if err := UninstallLocal(); err != nil {
+		t.Fatalf("UninstallLocal failed: %v", err)
+	}
+
+	restored, err := os.ReadFile(preCommit)
+	if err != nil {
+		t.Fatalf("expected pre-commit hook to be restored: %v", err)
+	}
+	if string(restored) != existing {
+		t.Errorf("restored content = %q, want %q", restored, existing)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, LocalBackupName)); !os.IsNotExist(err) {
+		t.Error("expected backup to be removed after restore")
+	}
+}
+
+func TestInstallSignCheckLocal(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	hooksDir := filepath.Join(repo, ".git", "hooks")
+	commitMsg := filepath.Join(hooksDir, "commit-msg")
+	existing := "#!/bin/sh\necho existing\n"
+	if err := os.WriteFile(commitMsg, []byte(existing), 0755); err != nil {
+		t.Fatalf("failed to write existing hook: %v", err)
+	}
+
+	if err := InstallLocal(); err != nil {
+		t.Fatalf("InstallLocal failed: %v", err)
+	}
+	if err := InstallSignCheckLocal(); err != nil {
+		t.Fatalf("InstallSignCheckLocal failed: %v", err)
+	}
+
+	status, err := IsInstalled()
+	if err != nil {
+		t.Fatalf("IsInstalled failed: %v", err)
+	}
+	if !status.LocalSignCheck {
+		t.Error("expected LocalSignCheck to be true")
+	}
+
+	script, err := os.ReadFile(commitMsg)
+	if err != nil {
+		t.Fatalf("failed to read commit-msg hook: %v", err)
+	}
+	if !strings.Contains(string(script), LocalCommitMsgBackupName) {
+		t.Error("expected commit-msg hook to chain to backed-up hook")
+	}
+
+	// Uninstall removes both hooks and restores the previous commit-msg hook
+	if err := UninstallLocal(); err != nil {
+		t.Fatalf("UninstallLocal failed: %v", err)
+	}
+
+	restored, err := os.ReadFile(commitMsg)
+	if err != nil {
+		t.Fatalf("expected commit-msg hook to be restored: %v", err)
+	}
+	if string(restored) != existing {
+		t.Errorf("restored content = %q, want %q", restored, existing)
+	}
+}
+
+func TestIsInstalled_Outdated(t *testing.T) {
+	repo := setupTestRepo(t)
+	xdgtest.Setenv(t, "XDG_CONFIG_HOME", filepath.Join(t.TempDir(), "config"))
+
+	if err := InstallGlobal(); err != nil {
+		t.Fatalf("InstallGlobal failed: %v", err)
+	}
+	if err := InstallLocal(); err != nil {
+		t.Fatalf("InstallLocal failed: %v", err)
+	}
+	status, err := IsInstalled()
+	if err != nil {
+		t.Fatalf("IsInstalled failed: %v", err)
+	}
+	if !status.Global || !status.Local || status.GlobalOutdated || status.LocalOutdated {
+		t.Fatalf("IsInstalled() = %+v, want both installed and current", status)
+	}
+
+	// A script from an older version, before the exit code 2 handling
+	old := "#!/bin/bash\n" + hookMarker + "\ngitch hook validate || exit 1\n"
+	hooksDir, _ := HooksDir()
+	for _, path := range []string{filepath.Join(hooksDir, "pre-commit"), filepath.Join(repo, ".git", "hooks", "pre-commit")} {
+		if err := os.WriteFile(path, []byte(old), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status, err = IsInstalled()
+	if err != nil {
+		t.Fatalf("IsInstalled failed: %v", err)
+	}
+	if !status.Global || !status.Local || !status.GlobalOutdated || !status.LocalOutdated {
+		t.Errorf("IsInstalled() = %+v, want both installed and outdated", status)
+	}
+
+	// Reinstalling brings them up to date
+	if err := InstallGlobal(); err != nil {
+		t.Fatalf("InstallGlobal failed: %v", err)
+	}
+	if err := InstallLocal(); err != nil {
+		t.Fatalf("InstallLocal failed: %v", err)
+	}
+	status, err = IsInstalled()
+	if err != nil {
+		t.Fatalf("IsInstalled failed: %v", err)
+	}
+	if status.GlobalOutdated || status.LocalOutdated {
+		t.Errorf("IsInstalled() = %+v after reinstalling, want current hooks", status)
+	}
+}
+
+func TestUninstallLocal_NotInstalled(t *testing.T) {
+	setupTestRepo(t)
+
+	if err := UninstallLocal(); err == nil {
+		t.Error("expected error when hook is not installed")
+	}
+}
+
+func TestInstallLocal_NotGitRepo(t *testing.T) {
+	t.Setenv("GIT_CEILING_DIRECTORIES", os.TempDir())
+	t.Chdir(t.TempDir())
+
+	if err := InstallLocal(); !errors.Is(err, ErrNotGitRepo) {
+		t.Errorf("InstallLocal error = %v, want ErrNotGitRepo", err)
+	}
+
+	status, err := IsInstalled()
+	if err != nil {
+		t.Fatalf("IsInstalled failed: %v", err)
+	}
+	if status.Local {
+		t.Error("expected Local to be false outside a git repository")
+	}
+}
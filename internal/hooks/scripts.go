@@ -1,5 +1,46 @@
 package hooks
 
+import "strings"
+
+// BypassEnvVar is the environment variable that skips the hooks when set to 1
+const BypassEnvVar = "GITCH_BYPASS"
+
+// Markers identify hook scripts written by gitch
+const (
+	hookMarker          = "# gitch pre-commit hook"
+	commitMsgHookMarker = "# gitch commit-msg hook"
+)
+
+// localChainSnippet runs a pre-existing repository hook, backed up under
+// backupName, before gitch's checks
+func localChainSnippet(backupName string) string {
+	return `
+# Run the previous hook first (backed up by 'gitch hook install --local')
+previous_hook="$(dirname "$0")/` + backupName + `"
+if [ -x "$previous_hook" ]; then
+    "$previous_hook" "$@" || exit $?
+fi
+`
+}
+
+// withLocalChain inserts the chain snippet after the shebang and marker comment lines
+func withLocalChain(script, backupName string) string {
+	lines := strings.SplitN(script, "\n", 3)
+	return lines[0] + "\n" + lines[1] + "\n" + localChainSnippet(backupName) + lines[2]
+}
+
+// LocalPreCommitScript returns the pre-commit script for per-repository installs.
+// It chains to the backed-up previous hook, if any, before validating identity.
+func LocalPreCommitScript() string {
+	return withLocalChain(PreCommitScript, LocalBackupName)
+}
+
+// LocalCommitMsgScript returns the commit-msg script for per-repository installs.
+// It chains to the backed-up previous hook, if any, before checking signing config.
+func LocalCommitMsgScript() string {
+	return withLocalChain(CommitMsgScript, LocalCommitMsgBackupName)
+}
+
 // PreCommitScript is the bash script installed as pre-commit hook
 const PreCommitScript = `#!/bin/bash
 # gitch pre-commit hook - validates identity before commit
@@ -14,9 +55,19 @@ result=$(gitch hook validate 2>&1)
 exit_code=$?
 
 if [ $exit_code -eq 0 ]; then
+    # Show the warning printed when no rule matches (require_rule: warn)
+    if [ -n "$result" ]; then
+        echo "$result"
+    fi
     exit 0
 fi
 
+# No rule matches (require_rule: block) - nothing to switch to
+if [ $exit_code -eq 2 ]; then
+    echo "$result"
+    exit 1
+fi
+
 # Identity mismatch detected
 # Check if we have an interactive terminal
 if [ -t 0 ]; then
@@ -44,3 +95,27 @@ else
     esac
 fi
 `
+
+// CommitMsgScript is the bash script installed as commit-msg hook by --sign-check.
+// It blocks commits when the expected identity has a GPG key but git is not
+// configured to sign with it.
+const CommitMsgScript = `#!/bin/bash
+# gitch commit-msg hook - verifies GPG signing config before commit
+
+# Check for bypass
+if [ "$GITCH_BYPASS" = "1" ]; then
+    exit 0
+fi
+
+# Run gitch signing check
+result=$(gitch hook validate-signing 2>&1)
+exit_code=$?
+
+if [ $exit_code -ne 0 ]; then
+    echo "$result"
+    echo "Use GITCH_BYPASS=1 to skip this check."
+    exit 1
+fi
+
+exit 0
+`
@@ -0,0 +1,153 @@
+package hooks
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/git"
+)
+
+// SigningResult contains the result of commit signing config validation
+type SigningResult struct {
+	OK               bool
+	ExpectedIdentity *config.Identity
+	GPGSign          bool   // effective commit.gpgsign value
+	SigningKey       string // effective user.signingkey value
+	Format           string // effective gpg.format value
+}
+
+// ValidateSigning checks that git will sign commits with the expected
+// identity's GPG or SSH signing key. Passes when no rule matches or the
+// expected identity doesn't sign commits.
+func ValidateSigning() (*SigningResult, error) {
+	validation, err := Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	identity := validation.ExpectedIdentity
+	if identity == nil || identity.SigningKey() == "" {
+		return &SigningResult{OK: true}, nil
+	}
+
+	// Read effective values (local overrides global)
+	gpgSign, err := git.GetConfig("commit.gpgsign", false)
+	if err != nil {
+		return nil, err
+	}
+	signingKey, err := git.GetConfig("user.signingkey", false)
+	if err != nil {
+		return nil, err
+	}
+	format, err := git.GetConfig("gpg.format", false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SigningResult{
+		ExpectedIdentity: identity,
+		GPGSign:          parseGitBool(gpgSign),
+		SigningKey:       signingKey,
+		Format:           format,
+	}
+	result.OK = result.GPGSign && result.keyMatches() && result.formatMatches()
+
+	return result, nil
+}
+
+// FormatProblem formats the signing validation failure for display
+func (r *SigningResult) FormatProblem() string {
+	if r.OK || r.ExpectedIdentity == nil {
+		return ""
+	}
+
+	var problems []string
+	if !r.GPGSign {
+		problems = append(problems, "  commit.gpgsign is not enabled")
+	}
+	if !r.keyMatches() {
+		current := r.SigningKey
+		if current == "" {
+			current = "(not set)"
+		}
+		problems = append(problems, fmt.Sprintf("  user.signingkey is %s, expected %s", current, r.ExpectedIdentity.SigningKey()))
+	}
+	if !r.formatMatches() {
+		current := r.Format
+		if current == "" {
+			current = "(not set)"
+		}
+		problems = append(problems, fmt.Sprintf("  gpg.format is %s, expected %s", current, r.expectedFormat()))
+	}
+
+	return fmt.Sprintf("Commit signing required for '%s'!\n%s\nRun 'gitch use %s' to apply its signing config.",
+		r.ExpectedIdentity.Name, strings.Join(problems, "\n"), r.ExpectedIdentity.Name)
+}
+
+// expectedFormat returns the gpg.format value the expected identity signs with
+func (r *SigningResult) expectedFormat() string {
+	if r.ExpectedIdentity.GetSigningMethod() == config.SigningSSH {
+		return git.SigningFormatSSH
+	}
+	return "openpgp"
+}
+
+// keyMatches reports whether user.signingkey is the expected identity's key.
+// SSH public key paths must match exactly.
+func (r *SigningResult) keyMatches() bool {
+	expected := r.ExpectedIdentity.SigningKey()
+	if r.ExpectedIdentity.GetSigningMethod() == config.SigningSSH {
+		return r.SigningKey == expected
+	}
+	return signingKeyMatches(r.SigningKey, expected)
+}
+
+// formatMatches reports whether gpg.format selects the expected identity's
+// signing method. An unset format means openpgp.
+func (r *SigningResult) formatMatches() bool {
+	format := strings.ToLower(strings.TrimSpace(r.Format))
+	if format == "" {
+		format = "openpgp"
+	}
+	return format == r.expectedFormat()
+}
+
+// parseGitBool interprets a git config boolean value
+func parseGitBool(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// signingKeyMatches reports whether the configured signing key refers to the
+// expected key. Long key IDs match the tail of a full fingerprint.
+func signingKeyMatches(configured, expected string) bool {
+	c := normalizeKeyID(configured)
+	e := normalizeKeyID(expected)
+	if c == "" || e == "" {
+		return false
+	}
+	if c == e {
+		return true
+	}
+
+	// Compare the shorter ID against the tail of the longer one
+	if len(c) < len(e) {
+		c, e = e, c
+	}
+	return len(e) >= 8 && strings.HasSuffix(c, e)
+}
+
+// normalizeKeyID strips formatting from a GPG key ID for comparison
+func normalizeKeyID(id string) string {
+	id = strings.TrimSpace(id)
+	id = strings.TrimSuffix(id, "!")
+	if strings.HasPrefix(id, "0x") || strings.HasPrefix(id, "0X") {
+		id = id[2:]
+	}
+	id = strings.ReplaceAll(id, " ", "")
+	return strings.ToUpper(id)
+}
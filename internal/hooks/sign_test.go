@@ -0,0 +1,88 @@
+package hooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/config"
+)
+
+func TestSigningKeyMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured string
+		expected   string
+		want       bool
+	}{
+		{"exact", "ABCDEF0123456789", "ABCDEF0123456789", true},
+		{"case insensitive", "abcdef0123456789", "ABCDEF0123456789", true},
+		{"0x prefix", "0xABCDEF0123456789", "ABCDEF0123456789", true},
+		{"force suffix", "ABCDEF0123456789!", "ABCDEF0123456789", true},
+		{"fingerprint vs long id", "0123456789ABCDEF0123ABCDEF0123456789ABCD", "ABCDEF0123456789ABCD", true},
+		{"long id vs fingerprint", "89ABCDEF01234567", "0123456789ABCDEF0123456789ABCDEF01234567", true},
+		{"different key", "1111111111111111", "ABCDEF0123456789", false},
+		{"too short to compare", "6789", "ABCDEF0123456789", false},
+		{"not set", "", "ABCDEF0123456789", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signingKeyMatches(tt.configured, tt.expected); got != tt.want {
+				t.Errorf("signingKeyMatches(%q, %q) = %v, want %v", tt.configured, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGitBool(t *testing.T) {
+	for _, v := range []string{"true", "TRUE", "yes", "on", "1"} {
+		if !parseGitBool(v) {
+			t.Errorf("parseGitBool(%q) = false, want true", v)
+		}
+	}
+	for _, v := range []string{"", "false", "no", "off", "0"} {
+		if parseGitBool(v) {
+			t.Errorf("parseGitBool(%q) = true, want false", v)
+		}
+	}
+}
+
+func TestSigningResult_FormatProblem(t *testing.T) {
+	result := &SigningResult{
+		ExpectedIdentity: &config.Identity{Name: "work", GPGKeyID: "ABCDEF0123456789"},
+		GPGSign:          false,
+	}
+
+	msg := result.FormatProblem()
+	if !strings.Contains(msg, "commit.gpgsign is not enabled") {
+		t.Errorf("expected gpgsign problem in %q", msg)
+	}
+	if !strings.Contains(msg, "user.signingkey is (not set)") {
+		t.Errorf("expected signingkey problem in %q", msg)
+	}
+
+	ok := &SigningResult{OK: true}
+	if ok.FormatProblem() != "" {
+		t.Error("expected empty message when signing config is valid")
+	}
+}
+
+func TestSigningResult_SSHSigning(t *testing.T) {
+	identity := &config.Identity{Name: "work", SSHKeyPath: "/keys/id_work", SigningMethod: config.SigningSSH}
+
+	result := &SigningResult{
+		ExpectedIdentity: identity,
+		GPGSign:          true,
+		SigningKey:       "/keys/id_work.pub",
+		Format:           "ssh",
+	}
+	if !result.keyMatches() || !result.formatMatches() {
+		t.Error("expected SSH signing config to match")
+	}
+
+	result.Format = ""
+	msg := result.FormatProblem()
+	if !strings.Contains(msg, "gpg.format is (not set), expected ssh") {
+		t.Errorf("expected gpg.format problem in %q", msg)
+	}
+}
@@ -3,6 +3,7 @@ package hooks
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/orzazade/gitch/internal/config"
@@ -19,10 +20,61 @@ type ValidationResult struct {
 	ExpectedEmail    string
 	MatchedRule      *rules.Rule
 	ExpectedIdentity *config.Identity
+	// NoRule is set when no rule matches and require_rule is warn or block.
+	// Match is false only for block.
+	NoRule bool
 }
 
-// Validate checks if current git identity matches expected for this context
+// Repository opt-outs: the hooks skip a repository with a BypassFile at its
+// root or with BypassConfigKey set to true in its git config
+const (
+	BypassFile      = ".gitch-ignore"
+	BypassConfigKey = "gitch.bypass"
+)
+
+// RepoBypass returns what makes the hooks skip the current repository: the
+// path of its BypassFile or BypassConfigKey. Returns "" if neither is set.
+//
+// Precedence: GITCH_BYPASS=1 (checked by the hook scripts) skips everything,
+// then the repository opt-out, then normal validation.
+func RepoBypass() string {
+	root := git.RepoRoot()
+	if root == "" {
+		return ""
+	}
+
+	marker := filepath.Join(root, BypassFile)
+	if _, err := os.Stat(marker); err == nil {
+		return marker
+	}
+
+	if value, err := git.GetConfig(BypassConfigKey, false); err == nil && parseGitBool(value) {
+		return BypassConfigKey
+	}
+
+	return ""
+}
+
+// Validate checks if current git identity matches expected for this context.
+// Repositories that opt out (see RepoBypass) always match.
 func Validate() (*ValidationResult, error) {
+	if RepoBypass() != "" {
+		return &ValidationResult{Match: true}, nil
+	}
+	return validate(true)
+}
+
+// Check evaluates the rules for the current context like Validate, but
+// ignores the repository bypass and require_rule. A nil MatchedRule means no
+// rule matches. Used by 'gitch check'.
+func Check() (*ValidationResult, error) {
+	return validate(false)
+}
+
+// validate compares the current git identity with the one the best matching
+// rule expects. With requireRule, a missing rule fails or warns as set by
+// the require_rule config.
+func validate(requireRule bool) (*ValidationResult, error) {
 	// 1. Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -40,15 +92,25 @@ func Validate() (*ValidationResult, error) {
 
 	matchedRule := rules.FindBestMatch(cfg.Rules, cwd, remoteURL)
 
-	// 4. If no rule matches, validation passes (no expectation)
+	// 4. If no rule matches, validation passes (no expectation) unless
+	// require_rule asks for a warning or a block
 	if matchedRule == nil {
+		if !requireRule {
+			return &ValidationResult{Match: true}, nil
+		}
+		switch cfg.GetRequireRule() {
+		case config.RequireRuleWarn:
+			return &ValidationResult{Match: true, NoRule: true}, nil
+		case config.RequireRuleBlock:
+			return &ValidationResult{Match: false, NoRule: true}, nil
+		}
 		return &ValidationResult{Match: true}, nil
 	}
 
 	// 5. Get expected identity from rule
 	expectedIdentity, err := cfg.GetIdentity(matchedRule.Identity)
 	if err != nil {
-		return nil, fmt.Errorf("rule references unknown identity %q: %w", matchedRule.Identity, err)
+		return nil, fmt.Errorf("rule references unknown identity %q (run 'gitch doctor'): %w", matchedRule.Identity, err)
 	}
 
 	// 6. Get current git identity
@@ -73,6 +135,14 @@ func Validate() (*ValidationResult, error) {
 
 // FormatMismatch formats the validation result for display
 func (r *ValidationResult) FormatMismatch() string {
+	if r.NoRule {
+		mode := config.RequireRuleBlock
+		if r.Match {
+			mode = config.RequireRuleWarn
+		}
+		return fmt.Sprintf("No gitch rule matches this repository (require_rule: %s).\n"+
+			"  Add one with 'gitch rule add', or turn the check off with 'gitch config require-rule off'.", mode)
+	}
 	if r.MatchedRule == nil {
 		return "Identity mismatch (no rule context)"
 	}
@@ -0,0 +1,197 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orzazade/gitch/internal/rules"
+)
+
+func TestRepoBypass(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	if got := RepoBypass(); got != "" {
+		t.Fatalf("RepoBypass() = %q without a marker, want empty", got)
+	}
+
+	// The marker file is found from subdirectories too
+	marker := filepath.Join(repo, BypassFile)
+	if err := os.WriteFile(marker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(repo, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(sub)
+	if got := RepoBypass(); !sameFile(t, got, marker) {
+		t.Errorf("RepoBypass() = %q, want %q", got, marker)
+	}
+
+	if err := os.Remove(marker); err != nil {
+		t.Fatal(err)
+	}
+	if err := exec.Command("git", "config", BypassConfigKey, "true").Run(); err != nil {
+		t.Fatalf("failed to set %s: %v", BypassConfigKey, err)
+	}
+	if got := RepoBypass(); got != BypassConfigKey {
+		t.Errorf("RepoBypass() = %q, want %q", got, BypassConfigKey)
+	}
+
+	if err := exec.Command("git", "config", BypassConfigKey, "false").Run(); err != nil {
+		t.Fatalf("failed to set %s: %v", BypassConfigKey, err)
+	}
+	if got := RepoBypass(); got != "" {
+		t.Errorf("RepoBypass() = %q with %s=false, want empty", got, BypassConfigKey)
+	}
+}
+
+func TestRepoBypass_NotGitRepo(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile(filepath.Join(dir, BypassFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := RepoBypass(); got != "" {
+		t.Errorf("RepoBypass() = %q outside a git repository, want empty", got)
+	}
+}
+
+func TestValidate_RepoBypass(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	// require_rule: block fails validation in a repository no rule matches
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("require_rule: block\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITCH_CONFIG", configPath)
+
+	result, err := Validate()
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if result.Match || !result.NoRule {
+		t.Fatalf("Validate() = %+v, want a no-rule failure", result)
+	}
+
+	if err := os.WriteFile(filepath.Join(repo, BypassFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err = Validate()
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !result.Match {
+		t.Errorf("Validate() = %+v in a bypassed repository, want a match", result)
+	}
+}
+
+func TestCheck_IgnoresBypassAndRequireRule(t *testing.T) {
+	repo := setupTestRepo(t)
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := "identities:\n  - name: work\n    email: work@example.com\n" +
+		"rules:\n  - type: directory\n    pattern: " + repo + "\n    identity: work\n" +
+		"require_rule: block\n"
+	if err := os.WriteFile(configPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITCH_CONFIG", configPath)
+	if err := os.WriteFile(filepath.Join(repo, BypassFile), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rule matches but git has no identity configured
+	result, err := Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if result.Match || result.MatchedRule == nil || result.ExpectedName != "work" {
+		t.Errorf("Check() = %+v, want a mismatch against 'work'", result)
+	}
+
+	// Outside the rule, Check reports no rule instead of require_rule's block
+	t.Chdir(t.TempDir())
+	result, err = Check()
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if !result.Match || result.MatchedRule != nil || result.NoRule {
+		t.Errorf("Check() = %+v outside the rule, want no rule and no require_rule failure", result)
+	}
+}
+
+func TestValidate_NoRule(t *testing.T) {
+	setupTestRepo(t)
+
+	tests := []struct {
+		requireRule string
+		wantMatch   bool
+		wantNoRule  bool
+		wantMessage string
+	}{
+		{"", true, false, ""},
+		{"off", true, false, ""},
+		{"warn", true, true, "require_rule: warn"},
+		{"block", false, true, "require_rule: block"},
+	}
+
+	for _, tt := range tests {
+		t.Run("require_rule="+tt.requireRule, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte("require_rule: "+tt.requireRule+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			t.Setenv("GITCH_CONFIG", configPath)
+
+			result, err := Validate()
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if result.Match != tt.wantMatch || result.NoRule != tt.wantNoRule {
+				t.Fatalf("Validate() = %+v, want Match %v and NoRule %v", result, tt.wantMatch, tt.wantNoRule)
+			}
+			if tt.wantNoRule {
+				message := result.FormatMismatch()
+				if !strings.Contains(message, tt.wantMessage) || !strings.Contains(message, "gitch rule add") {
+					t.Errorf("FormatMismatch() = %q, want the mode and how to add a rule", message)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatMismatch(t *testing.T) {
+	result := &ValidationResult{
+		CurrentName:   "Me",
+		CurrentEmail:  "me@example.com",
+		ExpectedName:  "work",
+		ExpectedEmail: "me@company.com",
+		MatchedRule:   &rules.Rule{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+	}
+	want := "Identity mismatch!\n" +
+		"  Expected: work (me@company.com)\n" +
+		"  Current:  Me (me@example.com)\n" +
+		"  Rule:     ~/work/** -> work"
+	if got := result.FormatMismatch(); got != want {
+		t.Errorf("FormatMismatch() = %q, want %q", got, want)
+	}
+
+	if got := (&ValidationResult{}).FormatMismatch(); got != "Identity mismatch (no rule context)" {
+		t.Errorf("FormatMismatch() without a rule = %q", got)
+	}
+}
+
+// sameFile reports whether two paths name the same file, allowing for
+// symlinked temp directories (e.g. /var and /private/var on macOS)
+func sameFile(t *testing.T, a, b string) bool {
+	t.Helper()
+	infoA, errA := os.Stat(a)
+	infoB, errB := os.Stat(b)
+	return errA == nil && errB == nil && os.SameFile(infoA, infoB)
+}
@@ -6,9 +6,18 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	"strings"
 
 	"filippo.io/age"
 	"filippo.io/age/armor"
+	"github.com/orzazade/gitch/internal/ssh"
+)
+
+// Encryption methods recorded in EncryptionInfo.Method
+const (
+	EncryptionScrypt = "age-scrypt" // age passphrase (default)
+	EncryptionX25519 = "age-x25519" // age recipient public key
 )
 
 var (
@@ -16,6 +25,8 @@ var (
 	ErrEmptyPassphrase = errors.New("passphrase cannot be empty")
 	// ErrDecryptionFailed is returned when decryption fails due to wrong passphrase or corrupted data.
 	ErrDecryptionFailed = errors.New("decryption failed: wrong passphrase or corrupted data")
+	// ErrNoAgeIdentities is returned when decrypting an age-x25519 export without identities.
+	ErrNoAgeIdentities = errors.New("no age identities to decrypt with")
 )
 
 // EncryptWithPassphrase encrypts plaintext using age with a passphrase.
@@ -30,6 +41,78 @@ func EncryptWithPassphrase(plaintext, passphrase []byte) ([]byte, error) {
 		return nil, fmt.Errorf("failed to create recipient: %w", err)
 	}
 
+	return encrypt(plaintext, recipient)
+}
+
+// DecryptWithPassphrase decrypts age-encrypted ciphertext using a passphrase.
+// The ciphertext should be ASCII-armored (from EncryptWithPassphrase).
+func DecryptWithPassphrase(ciphertext, passphrase []byte) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, ErrEmptyPassphrase
+	}
+
+	identity, err := age.NewScryptIdentity(string(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create identity: %w", err)
+	}
+
+	// IMPORTANT: Set max work factor to prevent DoS from malicious high values
+	identity.SetMaxWorkFactor(22)
+
+	return decrypt(ciphertext, identity)
+}
+
+// EncryptToRecipient encrypts plaintext to an age X25519 recipient (an
+// "age1..." public key from age-keygen), so no passphrase is needed.
+// Returns ASCII-armored ciphertext suitable for embedding in YAML.
+func EncryptToRecipient(plaintext []byte, recipient string) ([]byte, error) {
+	r, err := age.ParseX25519Recipient(strings.TrimSpace(recipient))
+	if err != nil {
+		return nil, fmt.Errorf("invalid age recipient: %w", err)
+	}
+	return encrypt(plaintext, r)
+}
+
+// ValidateRecipient checks that recipient is an age X25519 public key
+func ValidateRecipient(recipient string) error {
+	if _, err := age.ParseX25519Recipient(strings.TrimSpace(recipient)); err != nil {
+		return fmt.Errorf("invalid age recipient: %w", err)
+	}
+	return nil
+}
+
+// DecryptWithIdentities decrypts age-encrypted ciphertext with the identities
+// from an age identity file (see LoadIdentityFile).
+func DecryptWithIdentities(ciphertext []byte, identities []age.Identity) ([]byte, error) {
+	if len(identities) == 0 {
+		return nil, ErrNoAgeIdentities
+	}
+	return decrypt(ciphertext, identities...)
+}
+
+// LoadIdentityFile reads the age identities (private keys) in the file at
+// path, as written by age-keygen. The path supports ~ expansion.
+func LoadIdentityFile(path string) ([]age.Identity, error) {
+	expandedPath, err := ssh.ExpandPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	file, err := os.Open(expandedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open age identity file: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse age identity file: %w", err)
+	}
+	return identities, nil
+}
+
+// encrypt encrypts plaintext to the recipient and armors the result
+func encrypt(plaintext []byte, recipient age.Recipient) ([]byte, error) {
 	var buf bytes.Buffer
 	armorWriter := armor.NewWriter(&buf)
 
@@ -54,24 +137,11 @@ func EncryptWithPassphrase(plaintext, passphrase []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// DecryptWithPassphrase decrypts age-encrypted ciphertext using a passphrase.
-// The ciphertext should be ASCII-armored (from EncryptWithPassphrase).
-func DecryptWithPassphrase(ciphertext, passphrase []byte) ([]byte, error) {
-	if len(passphrase) == 0 {
-		return nil, ErrEmptyPassphrase
-	}
-
-	identity, err := age.NewScryptIdentity(string(passphrase))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create identity: %w", err)
-	}
-
-	// IMPORTANT: Set max work factor to prevent DoS from malicious high values
-	identity.SetMaxWorkFactor(22)
-
+// decrypt decrypts armored ciphertext with any of the identities
+func decrypt(ciphertext []byte, identities ...age.Identity) ([]byte, error) {
 	armorReader := armor.NewReader(bytes.NewReader(ciphertext))
 
-	r, err := age.Decrypt(armorReader, identity)
+	r, err := age.Decrypt(armorReader, identities...)
 	if err != nil {
 		return nil, ErrDecryptionFailed
 	}
@@ -83,3 +153,48 @@ func DecryptWithPassphrase(ciphertext, passphrase []byte) ([]byte, error) {
 
 	return plaintext, nil
 }
+
+// KeyCipher encrypts and decrypts the key material embedded in encrypted
+// exports, either with a passphrase or with an age key pair.
+type KeyCipher interface {
+	// Method returns the EncryptionInfo.Method recorded in the export
+	Method() string
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// PassphraseCipher encrypts with an age scrypt passphrase.
+type PassphraseCipher []byte
+
+// Method returns EncryptionScrypt
+func (p PassphraseCipher) Method() string { return EncryptionScrypt }
+
+// Encrypt encrypts plaintext with the passphrase
+func (p PassphraseCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return EncryptWithPassphrase(plaintext, p)
+}
+
+// Decrypt decrypts ciphertext with the passphrase
+func (p PassphraseCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return DecryptWithPassphrase(ciphertext, p)
+}
+
+// RecipientCipher encrypts to an age X25519 recipient on export and decrypts
+// with the matching age identities on import.
+type RecipientCipher struct {
+	Recipient  string         // "age1..." public key, used to encrypt
+	Identities []age.Identity // private keys, used to decrypt
+}
+
+// Method returns EncryptionX25519
+func (c RecipientCipher) Method() string { return EncryptionX25519 }
+
+// Encrypt encrypts plaintext to the recipient
+func (c RecipientCipher) Encrypt(plaintext []byte) ([]byte, error) {
+	return EncryptToRecipient(plaintext, c.Recipient)
+}
+
+// Decrypt decrypts ciphertext with the identities
+func (c RecipientCipher) Decrypt(ciphertext []byte) ([]byte, error) {
+	return DecryptWithIdentities(ciphertext, c.Identities)
+}
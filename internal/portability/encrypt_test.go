@@ -3,7 +3,11 @@ package portability
 import (
 	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"filippo.io/age"
 )
 
 func TestEncryptDecrypt_Roundtrip(t *testing.T) {
@@ -111,3 +115,73 @@ func TestDecrypt_InvalidCiphertext(t *testing.T) {
 		t.Error("expected error for invalid ciphertext")
 	}
 }
+
+func TestEncryptToRecipient_Roundtrip(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	plaintext := []byte("secret key material")
+
+	encrypted, err := EncryptToRecipient(plaintext, identity.Recipient().String())
+	if err != nil {
+		t.Fatalf("encryption failed: %v", err)
+	}
+	if !bytes.Contains(encrypted, []byte("-----BEGIN AGE ENCRYPTED FILE-----")) {
+		t.Error("encrypted output is not ASCII armored")
+	}
+
+	decrypted, err := DecryptWithIdentities(encrypted, []age.Identity{identity})
+	if err != nil {
+		t.Fatalf("decryption failed: %v", err)
+	}
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Errorf("roundtrip failed: got %q, want %q", decrypted, plaintext)
+	}
+
+	other, _ := age.GenerateX25519Identity()
+	if _, err := DecryptWithIdentities(encrypted, []age.Identity{other}); !errors.Is(err, ErrDecryptionFailed) {
+		t.Errorf("expected ErrDecryptionFailed with the wrong identity, got %v", err)
+	}
+	if _, err := DecryptWithIdentities(encrypted, nil); !errors.Is(err, ErrNoAgeIdentities) {
+		t.Errorf("expected ErrNoAgeIdentities, got %v", err)
+	}
+}
+
+func TestEncryptToRecipient_InvalidRecipient(t *testing.T) {
+	if _, err := EncryptToRecipient([]byte("data"), "not-a-recipient"); err == nil {
+		t.Error("expected error for invalid recipient")
+	}
+}
+
+func TestLoadIdentityFile(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("failed to generate identity: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "key.txt")
+	content := "# created: 2024-01-01T00:00:00Z\n# public key: " + identity.Recipient().String() + "\n" + identity.String() + "\n"
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write identity file: %v", err)
+	}
+
+	identities, err := LoadIdentityFile(path)
+	if err != nil {
+		t.Fatalf("LoadIdentityFile failed: %v", err)
+	}
+	if len(identities) != 1 {
+		t.Fatalf("expected 1 identity, got %d", len(identities))
+	}
+
+	cipher := RecipientCipher{Recipient: identity.Recipient().String(), Identities: identities}
+	encrypted, err := cipher.Encrypt([]byte("data"))
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if decrypted, err := cipher.Decrypt(encrypted); err != nil || string(decrypted) != "data" {
+		t.Errorf("Decrypt = %q, %v; want \"data\"", decrypted, err)
+	}
+	if cipher.Method() != EncryptionX25519 {
+		t.Errorf("Method() = %q, want %q", cipher.Method(), EncryptionX25519)
+	}
+}
@@ -1,13 +1,18 @@
 package portability
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/rules"
 	"github.com/orzazade/gitch/internal/ssh"
 	"gopkg.in/yaml.v3"
 )
@@ -17,20 +22,69 @@ var ErrNoIdentities = errors.New("no identities to export")
 
 // BuildExportConfig builds an ExportConfig from the given config.
 // Returns the export structure with all identities and rules.
+// HTTPS tokens are left out; they are secrets tied to this machine.
 func BuildExportConfig(cfg *config.Config) *ExportConfig {
+	var identities []config.Identity
+	if cfg.Identities != nil {
+		identities = make([]config.Identity, len(cfg.Identities))
+		for i, id := range cfg.Identities {
+			id.HTTPSToken = ""
+			identities[i] = id
+		}
+	}
+
 	return &ExportConfig{
 		Version:    CurrentExportVersion,
 		ExportedAt: time.Now().UTC(),
 		Default:    cfg.Default,
-		Identities: cfg.Identities,
+		Identities: identities,
 		Rules:      cfg.Rules,
 	}
 }
 
-// ExportToFile exports the configuration to a YAML file at the specified path.
+// BuildExportConfigFiltered builds an ExportConfig containing only the named
+// identities (case-insensitive) and the rules that reference them.
+// The default is kept only if it is one of the named identities.
+// Returns an error if any name does not match an identity.
+func BuildExportConfigFiltered(cfg *config.Config, names []string) (*ExportConfig, error) {
+	included := make(map[string]bool)
+	var identities []config.Identity
+	for _, name := range names {
+		identity, err := cfg.GetIdentity(name)
+		if err != nil {
+			return nil, err
+		}
+		key := strings.ToLower(identity.Name)
+		if included[key] {
+			continue
+		}
+		included[key] = true
+		identities = append(identities, *identity)
+	}
+
+	var filteredRules []rules.Rule
+	for _, rule := range cfg.Rules {
+		if included[strings.ToLower(rule.Identity)] {
+			filteredRules = append(filteredRules, rule)
+		}
+	}
+
+	export := BuildExportConfig(&config.Config{
+		Identities: identities,
+		Rules:      filteredRules,
+	})
+	if included[strings.ToLower(cfg.Default)] {
+		export.Default = cfg.Default
+	}
+
+	return export, nil
+}
+
+// ExportToFile exports the configuration to a YAML or JSON file at the specified path.
+// An empty format is inferred from the file extension (.json for JSON, YAML otherwise).
 // The path supports ~ expansion for home directory.
 // Returns ErrNoIdentities if there are no identities to export.
-func ExportToFile(cfg *config.Config, path string) error {
+func ExportToFile(cfg *config.Config, path string, format Format) error {
 	if len(cfg.Identities) == 0 {
 		return ErrNoIdentities
 	}
@@ -41,45 +95,87 @@ func ExportToFile(cfg *config.Config, path string) error {
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	// Create parent directory if it doesn't exist
-	dir := filepath.Dir(expandedPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	// Build export config
 	export := BuildExportConfig(cfg)
 
-	// Create the file
-	file, err := os.Create(expandedPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	// Write header comment
 	header := fmt.Sprintf("# gitch configuration export\n# Exported: %s\n# Version: %d\n\n",
 		export.ExportedAt.Format(time.RFC3339),
 		export.Version,
 	)
-	if _, err := file.WriteString(header); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+	return writeExport(expandedPath, export, header, format)
+}
+
+// BuildExportBytes marshals the configuration in the given format (YAML if
+// empty), without the header comment written to export files. Used to write
+// an export to stdout.
+// Returns ErrNoIdentities if there are no identities to export.
+func BuildExportBytes(cfg *config.Config, format Format) ([]byte, error) {
+	if len(cfg.Identities) == 0 {
+		return nil, ErrNoIdentities
+	}
+	return marshalExport(BuildExportConfig(cfg), format)
+}
+
+// marshalExport encodes the export as indented JSON, or YAML for any other
+// format
+func marshalExport(export *ExportConfig, format Format) ([]byte, error) {
+	var buf bytes.Buffer
+
+	if format == FormatJSON {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(export); err != nil {
+			return nil, fmt.Errorf("failed to write JSON: %w", err)
+		}
+		return buf.Bytes(), nil
 	}
 
 	// Write YAML with pretty formatting
-	encoder := yaml.NewEncoder(file)
+	encoder := yaml.NewEncoder(&buf)
 	encoder.SetIndent(2)
 	if err := encoder.Encode(export); err != nil {
-		return fmt.Errorf("failed to write YAML: %w", err)
+		return nil, fmt.Errorf("failed to write YAML: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeExport writes the export to path in the given format, creating the
+// parent directory if needed. The header comment is only written for YAML
+// since JSON has no comments. An empty format is inferred from the file extension.
+func writeExport(path string, export *ExportConfig, header string, format Format) error {
+	if format == "" {
+		format = FormatFromPath(path)
+	}
+
+	data, err := marshalExport(export, format)
+	if err != nil {
+		return err
+	}
+	if format != FormatJSON {
+		data = append([]byte(header), data...)
+	}
+
+	// Create parent directory if it doesn't exist
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	return encoder.Close()
+	if err := os.WriteFile(path, data, 0666); err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	return nil
 }
 
 // ExportToFileEncrypted exports configuration with encrypted SSH private keys.
-// Reads SSH private key files, encrypts them with the passphrase, and embeds in YAML.
+// Reads SSH private key files, encrypts them with the cipher (a passphrase or
+// an age recipient), and embeds them in YAML or JSON.
+// gpgKeys controls whether GPG public and (encrypted) secret keys are embedded too.
+// An empty format is inferred from the file extension.
 // Returns ErrNoIdentities if there are no identities to export.
-func ExportToFileEncrypted(cfg *config.Config, path string, passphrase []byte) error {
+func ExportToFileEncrypted(cfg *config.Config, path string, cipher KeyCipher, format Format, gpgKeys GPGExport) error {
 	if len(cfg.Identities) == 0 {
 		return ErrNoIdentities
 	}
@@ -90,18 +186,12 @@ func ExportToFileEncrypted(cfg *config.Config, path string, passphrase []byte) e
 		return fmt.Errorf("invalid path: %w", err)
 	}
 
-	// Create parent directory if it doesn't exist
-	dir := filepath.Dir(expandedPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
-	}
-
 	// Build encrypted export config
 	export := &ExportConfig{
 		Version:    CurrentExportVersion,
 		ExportedAt: time.Now().UTC(),
 		Encryption: &EncryptionInfo{
-			Method:  "age-scrypt",
+			Method:  cipher.Method(),
 			Armored: true,
 		},
 		Default:             cfg.Default,
@@ -113,58 +203,89 @@ func ExportToFileEncrypted(cfg *config.Config, path string, passphrase []byte) e
 	for _, id := range cfg.Identities {
 		encId := ToEncryptedIdentity(id)
 
-		// If identity has SSH key, read and encrypt it
-		if id.SSHKeyPath != "" {
-			keyPath, err := ssh.ExpandPath(id.SSHKeyPath)
-			if err != nil {
-				return fmt.Errorf("invalid SSH key path for %q: %w", id.Name, err)
-			}
+		if err := embedGPGKeys(&encId, cipher, gpgKeys); err != nil {
+			return err
+		}
 
-			keyData, err := os.ReadFile(keyPath)
+		// Read and encrypt each of the identity's SSH keys; missing key files
+		// are skipped, keeping just the path
+		for _, path := range id.SSHKeys() {
+			encrypted, err := encryptSSHKey(path, cipher)
 			if err != nil {
-				if os.IsNotExist(err) {
-					// Key file doesn't exist, skip encryption but keep path
-					export.EncryptedIdentities = append(export.EncryptedIdentities, encId)
-					continue
-				}
-				return fmt.Errorf("failed to read SSH key for %q: %w", id.Name, err)
+				return fmt.Errorf("failed to encrypt SSH key %s for %q: %w", path, id.Name, err)
 			}
-
-			// Encrypt the key
-			encrypted, err := EncryptWithPassphrase(keyData, passphrase)
-			if err != nil {
-				return fmt.Errorf("failed to encrypt SSH key for %q: %w", id.Name, err)
+			if encrypted == "" {
+				continue
 			}
-
-			encId.SSHKeyEncrypted = string(encrypted)
+			if path == id.SSHKeyPath {
+				encId.SSHKeyEncrypted = encrypted
+				continue
+			}
+			if encId.SSHExtraKeysEncrypted == nil {
+				encId.SSHExtraKeysEncrypted = make(map[string]string)
+			}
+			encId.SSHExtraKeysEncrypted[path] = encrypted
 		}
 
 		export.EncryptedIdentities = append(export.EncryptedIdentities, encId)
 	}
 
-	// Create the file
-	file, err := os.Create(expandedPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file: %w", err)
-	}
-	defer file.Close()
-
-	// Write header comment
 	header := fmt.Sprintf("# gitch encrypted configuration export\n# Exported: %s\n# Version: %d\n# Encryption: %s\n\n",
 		export.ExportedAt.Format(time.RFC3339),
 		export.Version,
 		export.Encryption.Method,
 	)
-	if _, err := file.WriteString(header); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+	return writeExport(expandedPath, export, header, format)
+}
+
+// encryptSSHKey reads the private key at path and encrypts it with cipher.
+// Returns "" if the key file doesn't exist.
+func encryptSSHKey(path string, cipher KeyCipher) (string, error) {
+	keyPath, err := ssh.ExpandPath(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path: %w", err)
 	}
 
-	// Write YAML with pretty formatting
-	encoder := yaml.NewEncoder(file)
-	encoder.SetIndent(2)
-	if err := encoder.Encode(export); err != nil {
-		return fmt.Errorf("failed to write YAML: %w", err)
+	keyData, err := os.ReadFile(keyPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	encrypted, err := cipher.Encrypt(keyData)
+	if err != nil {
+		return "", err
+	}
+	return string(encrypted), nil
+}
+
+// embedGPGKeys adds the identity's GPG key material to encId as selected by gpgKeys.
+func embedGPGKeys(encId *EncryptedIdentity, cipher KeyCipher, gpgKeys GPGExport) error {
+	if gpgKeys == GPGExportNone || encId.GPGKeyID == "" {
+		return nil
+	}
+
+	publicKey, err := gpg.ExportPublicKey(encId.GPGKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to export GPG public key for %q: %w", encId.Name, err)
+	}
+	encId.GPGPublicKey = publicKey
+
+	if gpgKeys < GPGExportSecret {
+		return nil
+	}
+
+	secretKey, err := gpg.ExportPrivateKey(encId.GPGKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to export GPG secret key for %q: %w", encId.Name, err)
+	}
+	encrypted, err := cipher.Encrypt([]byte(secretKey))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt GPG secret key for %q: %w", encId.Name, err)
 	}
+	encId.GPGSecretKeyEncrypted = string(encrypted)
 
-	return encoder.Close()
+	return nil
 }
@@ -2,6 +2,9 @@
 package portability
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/orzazade/gitch/internal/config"
@@ -12,54 +15,159 @@ import (
 // Increment this when making breaking changes to the export format.
 const CurrentExportVersion = 2
 
+// Format is the file format used for export and import.
+type Format string
+
+const (
+	// FormatYAML is the default export format.
+	FormatYAML Format = "yaml"
+	// FormatJSON exports as JSON (no header comments).
+	FormatJSON Format = "json"
+)
+
+// ParseFormat parses a --format flag value.
+// An empty value returns an empty Format, meaning "infer from the file extension".
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("invalid format %q: must be yaml or json", s)
+	}
+}
+
+// FormatFromPath infers the format from a file extension.
+// Returns FormatJSON for .json files and FormatYAML otherwise.
+func FormatFromPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatYAML
+}
+
 // EncryptionInfo describes the encryption method used for SSH keys.
 type EncryptionInfo struct {
-	Method  string `yaml:"method"`  // "age-scrypt"
-	Armored bool   `yaml:"armored"` // true if ASCII armored
+	Method  string `yaml:"method" json:"method"`   // "age-scrypt"
+	Armored bool   `yaml:"armored" json:"armored"` // true if ASCII armored
 }
 
 // EncryptedIdentity extends Identity with optional encrypted SSH key content.
-// When exporting with --encrypt, SSHKeyEncrypted contains the age-encrypted private key.
+// When exporting with --encrypt, SSHKeyEncrypted contains the age-encrypted
+// private key, and SSHExtraKeysEncrypted the identity's other keys.
 type EncryptedIdentity struct {
-	Name            string `yaml:"name"`
-	Email           string `yaml:"email"`
-	SSHKeyPath      string `yaml:"ssh_key_path,omitempty"`
-	SSHKeyEncrypted string `yaml:"ssh_key_encrypted,omitempty"`
-	GPGKeyID        string `yaml:"gpg_key_id,omitempty"`
-	HookMode        string `yaml:"hook_mode,omitempty"`
+	Name            string   `yaml:"name" json:"name"`
+	Email           string   `yaml:"email" json:"email"`
+	SSHKeyPath      string   `yaml:"ssh_key_path,omitempty" json:"ssh_key_path,omitempty"`
+	SSHKeyEncrypted string   `yaml:"ssh_key_encrypted,omitempty" json:"ssh_key_encrypted,omitempty"`
+	SSHKeyPaths     []string `yaml:"ssh_key_paths,omitempty" json:"ssh_key_paths,omitempty"`
+	GPGKeyID        string   `yaml:"gpg_key_id,omitempty" json:"gpg_key_id,omitempty"`
+	HookMode        string   `yaml:"hook_mode,omitempty" json:"hook_mode,omitempty"`
+	Description     string   `yaml:"description,omitempty" json:"description,omitempty"`
+	Tags            []string `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Hosts           []string `yaml:"hosts,omitempty" json:"hosts,omitempty"`
+	AgentLifetime   int      `yaml:"agent_lifetime,omitempty" json:"agent_lifetime,omitempty"`
+	SSHPort         int      `yaml:"ssh_port,omitempty" json:"ssh_port,omitempty"`
+	ProxyJump       string   `yaml:"proxy_jump,omitempty" json:"proxy_jump,omitempty"`
+	HTTPSUser       string   `yaml:"https_user,omitempty" json:"https_user,omitempty"`
+	SigningMethod   string   `yaml:"signing_method,omitempty" json:"signing_method,omitempty"`
+	NoAgent         bool     `yaml:"no_agent,omitempty" json:"no_agent,omitempty"`
+	// SSHExtraKeysEncrypted maps each key in SSHKeyPaths other than
+	// SSHKeyPath to its encrypted private key
+	SSHExtraKeysEncrypted map[string]string `yaml:"ssh_extra_keys_encrypted,omitempty" json:"ssh_extra_keys_encrypted,omitempty"`
+	// GPGPublicKey is the armored GPG public key, when exported with GPG keys
+	GPGPublicKey string `yaml:"gpg_public_key,omitempty" json:"gpg_public_key,omitempty"`
+	// GPGSecretKeyEncrypted is the age-encrypted armored GPG secret key
+	GPGSecretKeyEncrypted string `yaml:"gpg_secret_key_encrypted,omitempty" json:"gpg_secret_key_encrypted,omitempty"`
 }
 
+// GPGExport selects which GPG key material an encrypted export embeds.
+type GPGExport int
+
+const (
+	// GPGExportNone references GPG keys by ID only (the default).
+	GPGExportNone GPGExport = iota
+	// GPGExportPublic embeds each identity's armored GPG public key.
+	GPGExportPublic
+	// GPGExportSecret also embeds the GPG secret key, encrypted with the export passphrase.
+	GPGExportSecret
+)
+
 // ExportConfig is the root structure for exported configuration.
 // It contains all identities and rules that can be backed up and restored.
 type ExportConfig struct {
-	Version    int               `yaml:"version"`
-	ExportedAt time.Time         `yaml:"exported_at"`
-	Encryption *EncryptionInfo   `yaml:"encryption,omitempty"`
-	Default    string            `yaml:"default,omitempty"`
-	Identities []config.Identity `yaml:"identities,omitempty"`
+	Version    int               `yaml:"version" json:"version"`
+	ExportedAt time.Time         `yaml:"exported_at" json:"exported_at"`
+	Encryption *EncryptionInfo   `yaml:"encryption,omitempty" json:"encryption,omitempty"`
+	Default    string            `yaml:"default,omitempty" json:"default,omitempty"`
+	Identities []config.Identity `yaml:"identities,omitempty" json:"identities,omitempty"`
 	// EncryptedIdentities is used when exporting with --encrypt flag
-	EncryptedIdentities []EncryptedIdentity `yaml:"encrypted_identities,omitempty"`
-	Rules               []rules.Rule        `yaml:"rules,omitempty"`
+	EncryptedIdentities []EncryptedIdentity `yaml:"encrypted_identities,omitempty" json:"encrypted_identities,omitempty"`
+	Rules               []rules.Rule        `yaml:"rules,omitempty" json:"rules,omitempty"`
+}
+
+// encryptedSSHKey is an encrypted SSH private key and the path it belongs at
+type encryptedSSHKey struct {
+	Path      string
+	Encrypted string
+}
+
+// encryptedSSHKeys returns the identity's encrypted SSH keys, the primary
+// key first
+func (e EncryptedIdentity) encryptedSSHKeys() []encryptedSSHKey {
+	var keys []encryptedSSHKey
+	if e.SSHKeyEncrypted != "" {
+		keys = append(keys, encryptedSSHKey{Path: e.SSHKeyPath, Encrypted: e.SSHKeyEncrypted})
+	}
+	for _, path := range e.SSHKeyPaths {
+		if encrypted := e.SSHExtraKeysEncrypted[path]; encrypted != "" && path != e.SSHKeyPath {
+			keys = append(keys, encryptedSSHKey{Path: path, Encrypted: encrypted})
+		}
+	}
+	return keys
 }
 
 // ToEncryptedIdentity converts a config.Identity to EncryptedIdentity.
 func ToEncryptedIdentity(id config.Identity) EncryptedIdentity {
 	return EncryptedIdentity{
-		Name:       id.Name,
-		Email:      id.Email,
-		SSHKeyPath: id.SSHKeyPath,
-		GPGKeyID:   id.GPGKeyID,
-		HookMode:   id.HookMode,
+		Name:          id.Name,
+		Email:         id.Email,
+		SSHKeyPath:    id.SSHKeyPath,
+		GPGKeyID:      id.GPGKeyID,
+		HookMode:      id.HookMode,
+		Hosts:         id.Hosts,
+		Description:   id.Description,
+		Tags:          id.Tags,
+		AgentLifetime: id.AgentLifetime,
+		SSHPort:       id.SSHPort,
+		ProxyJump:     id.ProxyJump,
+		HTTPSUser:     id.HTTPSUser,
+		SSHKeyPaths:   id.SSHKeyPaths,
+		SigningMethod: id.SigningMethod,
+		NoAgent:       id.NoAgent,
 	}
 }
 
 // ToIdentity converts an EncryptedIdentity back to config.Identity.
 func (e EncryptedIdentity) ToIdentity() config.Identity {
 	return config.Identity{
-		Name:       e.Name,
-		Email:      e.Email,
-		SSHKeyPath: e.SSHKeyPath,
-		GPGKeyID:   e.GPGKeyID,
-		HookMode:   e.HookMode,
+		Name:          e.Name,
+		Email:         e.Email,
+		SSHKeyPath:    e.SSHKeyPath,
+		GPGKeyID:      e.GPGKeyID,
+		HookMode:      e.HookMode,
+		Hosts:         e.Hosts,
+		Description:   e.Description,
+		Tags:          e.Tags,
+		AgentLifetime: e.AgentLifetime,
+		SSHPort:       e.SSHPort,
+		ProxyJump:     e.ProxyJump,
+		HTTPSUser:     e.HTTPSUser,
+		SSHKeyPaths:   e.SSHKeyPaths,
+		SigningMethod: e.SigningMethod,
+		NoAgent:       e.NoAgent,
 	}
 }
@@ -1,13 +1,19 @@
 package portability
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/gpg"
 	"github.com/orzazade/gitch/internal/rules"
 	"github.com/orzazade/gitch/internal/ssh"
 	"gopkg.in/yaml.v3"
@@ -38,12 +44,90 @@ type ImportResult struct {
 	UpdatedIdentities []string
 	UpdatedRules      []string
 	Skipped           []string
+	// Changes lists the fields that changed for each updated identity, keyed by name
+	Changes map[string][]FieldChange
+}
+
+// FieldChange describes one identity field that an import overwrote.
+type FieldChange struct {
+	Field string // config key, e.g. "email" or "ssh_key_path"
+	Old   string
+	New   string
+}
+
+// MergeStrategy resolves import conflicts without prompting.
+type MergeStrategy string
+
+const (
+	// MergeSkip keeps existing identities, rules and key files.
+	MergeSkip MergeStrategy = "skip"
+	// MergeOverwrite replaces them with the imported ones.
+	MergeOverwrite MergeStrategy = "overwrite"
+	// MergeNewer prefers whichever side was modified more recently.
+	MergeNewer MergeStrategy = "newer"
+)
+
+// ParseMergeStrategy parses a --merge-strategy flag value.
+// An empty value returns an empty MergeStrategy, meaning "prompt per conflict".
+func ParseMergeStrategy(s string) (MergeStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return "", nil
+	case "skip":
+		return MergeSkip, nil
+	case "overwrite":
+		return MergeOverwrite, nil
+	case "newer":
+		return MergeNewer, nil
+	default:
+		return "", fmt.Errorf("invalid merge strategy %q: must be skip, overwrite or newer", s)
+	}
+}
+
+// TimestampHints tell MergeNewer when each side was last modified.
+type TimestampHints struct {
+	Existing time.Time // modification time of the current config file
+	Incoming time.Time // ExportConfig.ExportedAt of the import file
+}
+
+// IncomingNewer reports whether the import file is more recent than the
+// existing config. An unknown incoming time counts as older, and an unknown
+// existing time (no config file yet) as newer.
+func (h TimestampHints) IncomingNewer() bool {
+	if h.Incoming.IsZero() {
+		return false
+	}
+	return h.Existing.IsZero() || h.Incoming.After(h.Existing)
+}
+
+// Overwrites reports whether the strategy replaces existing items.
+func (s MergeStrategy) Overwrites(hints TimestampHints) bool {
+	switch s {
+	case MergeOverwrite:
+		return true
+	case MergeNewer:
+		return hints.IncomingNewer()
+	default:
+		return false
+	}
+}
+
+// ResolveConflicts builds the overwrite map for MergeConfig from a strategy,
+// resolving every conflict the same way.
+func ResolveConflicts(conflicts []Conflict, strategy MergeStrategy, hints TimestampHints) map[string]bool {
+	overwrite := make(map[string]bool, len(conflicts))
+	decision := strategy.Overwrites(hints)
+	for _, c := range conflicts {
+		overwrite[c.Key] = decision
+	}
+	return overwrite
 }
 
 // ErrVersionTooNew is returned when the export file version is newer than supported.
 var ErrVersionTooNew = errors.New("export file version is newer than supported")
 
-// ImportFromFile reads and parses a YAML export file.
+// ImportFromFile reads and parses a YAML or JSON export file.
+// JSON is detected from a .json extension or a leading '{'.
 // The path supports ~ expansion for home directory.
 func ImportFromFile(path string) (*ExportConfig, error) {
 	// Expand path (handle ~)
@@ -61,7 +145,11 @@ func ImportFromFile(path string) (*ExportConfig, error) {
 	}
 
 	var export ExportConfig
-	if err := yaml.Unmarshal(data, &export); err != nil {
+	if isJSON(expandedPath, data) {
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal(data, &export); err != nil {
 		return nil, fmt.Errorf("invalid YAML: %w", err)
 	}
 
@@ -71,6 +159,11 @@ func ImportFromFile(path string) (*ExportConfig, error) {
 			ErrVersionTooNew, export.Version, CurrentExportVersion)
 	}
 
+	// Upgrade older formats to the current one
+	if err := migrate(&export); err != nil {
+		return nil, err
+	}
+
 	// Ensure slices are not nil
 	if export.Identities == nil {
 		export.Identities = []config.Identity{}
@@ -90,6 +183,14 @@ func ImportFromFile(path string) (*ExportConfig, error) {
 	return &export, nil
 }
 
+// isJSON reports whether an export file should be parsed as JSON.
+func isJSON(path string, data []byte) bool {
+	if FormatFromPath(path) == FormatJSON {
+		return true
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
 // DetectConflicts finds conflicts between existing config and imported export.
 // Uses case-insensitive comparison for identity names.
 // For rules, matches by exact pattern.
@@ -136,27 +237,113 @@ func DetectConflicts(cfg *config.Config, export *ExportConfig) []Conflict {
 	return conflicts
 }
 
+// DanglingRules returns imported rules whose identity exists in neither the
+// existing config nor the import file. MergeConfig skips these rules.
+func DanglingRules(cfg *config.Config, export *ExportConfig) []rules.Rule {
+	var dangling []rules.Rule
+	for _, incoming := range export.Rules {
+		if !identityAvailable(cfg, export, incoming.Identity) {
+			dangling = append(dangling, incoming)
+		}
+	}
+	return dangling
+}
+
+// identityAvailable reports whether name will exist after merging export into cfg.
+func identityAvailable(cfg *config.Config, export *ExportConfig, name string) bool {
+	if _, err := cfg.GetIdentity(name); err == nil {
+		return true
+	}
+	for _, id := range export.Identities {
+		if strings.EqualFold(id.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
 // identitiesEqual checks if two identities are functionally equal.
-// Compares email, ssh_key_path, and gpg_key_id (case-insensitive for email).
+// Compares every field exported, see diffIdentities (case-insensitive for
+// email).
 func identitiesEqual(a, b *config.Identity) bool {
-	if !strings.EqualFold(a.Email, b.Email) {
-		return false
+	return len(diffIdentities(a, b)) == 0
+}
+
+// diffIdentities returns the fields that differ between the existing and the
+// incoming identity, in the order they appear in the config file.
+func diffIdentities(existing, incoming *config.Identity) []FieldChange {
+	var changes []FieldChange
+	if !strings.EqualFold(existing.Email, incoming.Email) {
+		changes = append(changes, FieldChange{Field: "email", Old: existing.Email, New: incoming.Email})
 	}
-	if a.SSHKeyPath != b.SSHKeyPath {
-		return false
+	if existing.SSHKeyPath != incoming.SSHKeyPath {
+		changes = append(changes, FieldChange{Field: "ssh_key_path", Old: existing.SSHKeyPath, New: incoming.SSHKeyPath})
 	}
-	if a.GPGKeyID != b.GPGKeyID {
-		return false
+	if !slices.Equal(existing.SSHKeyPaths, incoming.SSHKeyPaths) {
+		changes = append(changes, FieldChange{Field: "ssh_key_paths", Old: strings.Join(existing.SSHKeyPaths, ","), New: strings.Join(incoming.SSHKeyPaths, ",")})
 	}
-	if a.HookMode != b.HookMode {
-		return false
+	if existing.GPGKeyID != incoming.GPGKeyID {
+		changes = append(changes, FieldChange{Field: "gpg_key_id", Old: existing.GPGKeyID, New: incoming.GPGKeyID})
 	}
-	return true
+	if existing.HookMode != incoming.HookMode {
+		changes = append(changes, FieldChange{Field: "hook_mode", Old: existing.HookMode, New: incoming.HookMode})
+	}
+	if existing.Description != incoming.Description {
+		changes = append(changes, FieldChange{Field: "description", Old: existing.Description, New: incoming.Description})
+	}
+	if !tagsEqual(existing.Tags, incoming.Tags) {
+		changes = append(changes, FieldChange{Field: "tags", Old: strings.Join(existing.Tags, ","), New: strings.Join(incoming.Tags, ",")})
+	}
+	if !slices.Equal(existing.Hosts, incoming.Hosts) {
+		changes = append(changes, FieldChange{Field: "hosts", Old: strings.Join(existing.Hosts, ","), New: strings.Join(incoming.Hosts, ",")})
+	}
+	if existing.AgentLifetime != incoming.AgentLifetime {
+		changes = append(changes, FieldChange{Field: "agent_lifetime", Old: formatOptionalInt(existing.AgentLifetime), New: formatOptionalInt(incoming.AgentLifetime)})
+	}
+	if existing.SSHPort != incoming.SSHPort {
+		changes = append(changes, FieldChange{Field: "ssh_port", Old: formatOptionalInt(existing.SSHPort), New: formatOptionalInt(incoming.SSHPort)})
+	}
+	if existing.ProxyJump != incoming.ProxyJump {
+		changes = append(changes, FieldChange{Field: "proxy_jump", Old: existing.ProxyJump, New: incoming.ProxyJump})
+	}
+	if existing.HTTPSUser != incoming.HTTPSUser {
+		changes = append(changes, FieldChange{Field: "https_user", Old: existing.HTTPSUser, New: incoming.HTTPSUser})
+	}
+	if existing.SigningMethod != incoming.SigningMethod {
+		changes = append(changes, FieldChange{Field: "signing_method", Old: existing.SigningMethod, New: incoming.SigningMethod})
+	}
+	if existing.NoAgent != incoming.NoAgent {
+		changes = append(changes, FieldChange{Field: "no_agent", Old: strconv.FormatBool(existing.NoAgent), New: strconv.FormatBool(incoming.NoAgent)})
+	}
+	return changes
+}
+
+// tagsEqual reports whether a and b hold the same tags. Tags are matched
+// case-insensitively and their order doesn't matter.
+func tagsEqual(a, b []string) bool {
+	normalize := func(tags []string) []string {
+		folded := make([]string, len(tags))
+		for i, tag := range tags {
+			folded[i] = strings.ToLower(tag)
+		}
+		slices.Sort(folded)
+		return slices.Compact(folded)
+	}
+	return slices.Equal(normalize(a), normalize(b))
+}
+
+// formatOptionalInt formats an optional numeric field, empty when unset
+func formatOptionalInt(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
 }
 
 // rulesEqual checks if two rules are functionally equal.
 func rulesEqual(a, b *rules.Rule) bool {
-	return a.Type == b.Type && a.Pattern == b.Pattern && a.Identity == b.Identity
+	return a.Type == b.Type && a.Pattern == b.Pattern && a.Identity == b.Identity && a.Priority == b.Priority &&
+		a.IgnoreCase == b.IgnoreCase
 }
 
 // MergeConfig merges imported configuration into existing config.
@@ -169,6 +356,7 @@ func MergeConfig(cfg *config.Config, export *ExportConfig, overwrite map[string]
 		UpdatedIdentities: []string{},
 		UpdatedRules:      []string{},
 		Skipped:           []string{},
+		Changes:           make(map[string][]FieldChange),
 	}
 
 	// Ensure overwrite map is not nil
@@ -197,10 +385,12 @@ func MergeConfig(cfg *config.Config, export *ExportConfig, overwrite map[string]
 		// Check if we should overwrite
 		if shouldOverwrite, ok := overwrite[incoming.Name]; ok && shouldOverwrite {
 			// Update the identity
-			if err := updateIdentity(cfg, incoming); err != nil {
+			changes, err := updateIdentity(cfg, incoming)
+			if err != nil {
 				return nil, fmt.Errorf("failed to update identity %q: %w", incoming.Name, err)
 			}
 			result.UpdatedIdentities = append(result.UpdatedIdentities, incoming.Name)
+			result.Changes[incoming.Name] = changes
 		} else {
 			// Skip this identity
 			result.Skipped = append(result.Skipped, fmt.Sprintf("identity:%s", incoming.Name))
@@ -209,6 +399,12 @@ func MergeConfig(cfg *config.Config, export *ExportConfig, overwrite map[string]
 
 	// Process rules
 	for _, incoming := range export.Rules {
+		// Identities are merged first, so a missing identity here would dangle
+		if _, err := cfg.GetIdentity(incoming.Identity); err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("rule:%s", incoming.Pattern))
+			continue
+		}
+
 		existingIdx := -1
 		for i, existing := range cfg.Rules {
 			if existing.Pattern == incoming.Pattern {
@@ -247,16 +443,23 @@ func MergeConfig(cfg *config.Config, export *ExportConfig, overwrite map[string]
 }
 
 // updateIdentity updates an existing identity with new values.
-func updateIdentity(cfg *config.Config, updated config.Identity) error {
+// Returns the fields that changed.
+func updateIdentity(cfg *config.Config, updated config.Identity) ([]FieldChange, error) {
 	for i, id := range cfg.Identities {
 		if strings.EqualFold(id.Name, updated.Name) {
 			// Preserve the original name case
 			updated.Name = id.Name
+			updated.Email = config.NormalizeEmail(updated.Email)
+			// Exports never carry HTTPS tokens, so keep the local one
+			if updated.HTTPSToken == "" {
+				updated.HTTPSToken = id.HTTPSToken
+			}
+			changes := diffIdentities(&id, &updated)
 			cfg.Identities[i] = updated
-			return nil
+			return changes, nil
 		}
 	}
-	return fmt.Errorf("identity %q not found", updated.Name)
+	return nil, fmt.Errorf("identity %q not found", updated.Name)
 }
 
 // KeyExtractionResult tracks extracted SSH keys.
@@ -266,10 +469,11 @@ type KeyExtractionResult struct {
 	Errors        []string // Errors during extraction
 }
 
-// ExtractEncryptedKeys decrypts and writes SSH keys from an encrypted export.
+// ExtractEncryptedKeys decrypts and writes SSH keys from an encrypted export,
+// using a cipher matching the export's encryption method.
 // The overwriteKeys map specifies which existing key files to overwrite.
 // Keys are written with 0600 permissions.
-func ExtractEncryptedKeys(export *ExportConfig, passphrase []byte, overwriteKeys map[string]bool) (*KeyExtractionResult, error) {
+func ExtractEncryptedKeys(export *ExportConfig, cipher KeyCipher, overwriteKeys map[string]bool) (*KeyExtractionResult, error) {
 	if export.Encryption == nil {
 		return &KeyExtractionResult{}, nil // Not an encrypted export
 	}
@@ -281,57 +485,87 @@ func ExtractEncryptedKeys(export *ExportConfig, passphrase []byte, overwriteKeys
 	}
 
 	for _, encId := range export.EncryptedIdentities {
-		// Skip if no encrypted key
-		if encId.SSHKeyEncrypted == "" {
-			continue
+		for _, key := range encId.encryptedSSHKeys() {
+			extractKey(result, encId.Name, key, cipher, overwriteKeys)
 		}
+	}
 
-		// Skip if no path specified
-		if encId.SSHKeyPath == "" {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: no SSH key path specified", encId.Name))
-			continue
-		}
+	return result, nil
+}
 
-		// Expand path
-		keyPath, err := ssh.ExpandPath(encId.SSHKeyPath)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: invalid path: %v", encId.Name, err))
-			continue
-		}
+// extractKey decrypts one SSH key and writes it to its path with 0600
+// permissions, recording the outcome in result
+func extractKey(result *KeyExtractionResult, name string, key encryptedSSHKey, cipher KeyCipher, overwriteKeys map[string]bool) {
+	// Skip if no path specified
+	if key.Path == "" {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: no SSH key path specified", name))
+		return
+	}
 
-		// Check if file exists
-		if _, err := os.Stat(keyPath); err == nil {
-			// File exists, check if we should overwrite
-			if shouldOverwrite, ok := overwriteKeys[keyPath]; !ok || !shouldOverwrite {
-				result.SkippedKeys = append(result.SkippedKeys, keyPath)
-				continue
-			}
-		}
+	// Expand path
+	keyPath, err := ssh.ExpandPath(key.Path)
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: invalid path: %v", name, err))
+		return
+	}
 
-		// Decrypt the key
-		decrypted, err := DecryptWithPassphrase([]byte(encId.SSHKeyEncrypted), passphrase)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: decryption failed: %v", encId.Name, err))
-			continue
+	// Check if file exists
+	if _, err := os.Stat(keyPath); err == nil {
+		// File exists, check if we should overwrite
+		if shouldOverwrite, ok := overwriteKeys[keyPath]; !ok || !shouldOverwrite {
+			result.SkippedKeys = append(result.SkippedKeys, keyPath)
+			return
 		}
+	}
 
-		// Create parent directory if needed
-		dir := filepath.Dir(keyPath)
-		if err := os.MkdirAll(dir, 0700); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to create directory: %v", encId.Name, err))
-			continue
-		}
+	// Decrypt the key
+	decrypted, err := cipher.Decrypt([]byte(key.Encrypted))
+	if err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: decryption failed: %v", name, err))
+		return
+	}
 
-		// Write key with 0600 permissions (owner read/write only)
-		if err := os.WriteFile(keyPath, decrypted, 0600); err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to write key: %v", encId.Name, err))
-			continue
-		}
+	// Create parent directory if needed
+	dir := filepath.Dir(keyPath)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to create directory: %v", name, err))
+		return
+	}
 
-		result.ExtractedKeys = append(result.ExtractedKeys, keyPath)
+	// Write key with 0600 permissions (owner read/write only)
+	if err := os.WriteFile(keyPath, decrypted, 0600); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("%s: failed to write key: %v", name, err))
+		return
 	}
 
-	return result, nil
+	result.ExtractedKeys = append(result.ExtractedKeys, keyPath)
+}
+
+// VerifyPassphrase reports whether the passphrase decrypts the export's
+// encrypted keys, by decrypting the first one. Returns true if the export has
+// no encrypted keys.
+func VerifyPassphrase(export *ExportConfig, passphrase []byte) bool {
+	return VerifyCipher(export, PassphraseCipher(passphrase))
+}
+
+// VerifyCipher reports whether the cipher decrypts the export's encrypted
+// keys, by decrypting the first one. Returns true if the export has no
+// encrypted keys.
+func VerifyCipher(export *ExportConfig, cipher KeyCipher) bool {
+	for _, encId := range export.EncryptedIdentities {
+		candidates := []string{encId.GPGSecretKeyEncrypted}
+		for _, key := range encId.encryptedSSHKeys() {
+			candidates = append(candidates, key.Encrypted)
+		}
+		for _, encrypted := range candidates {
+			if encrypted == "" {
+				continue
+			}
+			_, err := cipher.Decrypt([]byte(encrypted))
+			return err == nil
+		}
+	}
+	return true
 }
 
 // HasEncryptedKeys returns true if the export contains encrypted SSH keys.
@@ -340,7 +574,7 @@ func HasEncryptedKeys(export *ExportConfig) bool {
 		return false
 	}
 	for _, encId := range export.EncryptedIdentities {
-		if encId.SSHKeyEncrypted != "" {
+		if len(encId.encryptedSSHKeys()) > 0 {
 			return true
 		}
 	}
@@ -351,11 +585,81 @@ func HasEncryptedKeys(export *ExportConfig) bool {
 func GetEncryptedKeyPaths(export *ExportConfig) []string {
 	paths := []string{}
 	for _, encId := range export.EncryptedIdentities {
-		if encId.SSHKeyEncrypted != "" && encId.SSHKeyPath != "" {
-			if expanded, err := ssh.ExpandPath(encId.SSHKeyPath); err == nil {
+		for _, key := range encId.encryptedSSHKeys() {
+			if key.Path == "" {
+				continue
+			}
+			if expanded, err := ssh.ExpandPath(key.Path); err == nil {
 				paths = append(paths, expanded)
 			}
 		}
 	}
 	return paths
 }
+
+// GPGImportResult tracks GPG keys imported into the gpg keyring.
+type GPGImportResult struct {
+	Imported []string // Identity names whose keys were imported
+	Errors   []string // Errors during import
+}
+
+// HasGPGKeys returns true if the export contains GPG public or secret keys.
+func HasGPGKeys(export *ExportConfig) bool {
+	for _, encId := range export.EncryptedIdentities {
+		if encId.GPGPublicKey != "" || encId.GPGSecretKeyEncrypted != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// HasGPGSecretKeys returns true if the export contains encrypted GPG secret keys.
+func HasGPGSecretKeys(export *ExportConfig) bool {
+	for _, encId := range export.EncryptedIdentities {
+		if encId.GPGSecretKeyEncrypted != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// ImportGPGKeys imports embedded GPG keys into the gpg keyring.
+// Secret keys are decrypted with the cipher and imported in place of the
+// public key (which they include). The cipher may be nil when the export has
+// only public keys.
+func ImportGPGKeys(export *ExportConfig, cipher KeyCipher) (*GPGImportResult, error) {
+	result := &GPGImportResult{
+		Imported: []string{},
+		Errors:   []string{},
+	}
+
+	for _, encId := range export.EncryptedIdentities {
+		key := []byte(encId.GPGPublicKey)
+
+		if encId.GPGSecretKeyEncrypted != "" {
+			if cipher == nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: GPG secret key decryption failed: %v", encId.Name, ErrEmptyPassphrase))
+				continue
+			}
+			decrypted, err := cipher.Decrypt([]byte(encId.GPGSecretKeyEncrypted))
+			if err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: GPG secret key decryption failed: %v", encId.Name, err))
+				continue
+			}
+			key = decrypted
+		}
+
+		if len(key) == 0 {
+			continue
+		}
+
+		if err := gpg.ImportKey(key); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", encId.Name, err))
+			continue
+		}
+
+		result.Imported = append(result.Imported, encId.Name)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package portability
+
+import "fmt"
+
+// migrator upgrades an export in place from one format version to the next.
+type migrator func(export *ExportConfig) error
+
+// migrators maps a format version to the step that upgrades it to version+1.
+// Add an entry here whenever CurrentExportVersion is incremented.
+var migrators = map[int]migrator{
+	1: migrateV1,
+}
+
+// migrate upgrades an older export to the current format by running each
+// migrator in order. Files without a version are treated as version 1.
+// export.Version is left as the version the file was written with.
+func migrate(export *ExportConfig) error {
+	for v := max(export.Version, 1); v < CurrentExportVersion; v++ {
+		m, ok := migrators[v]
+		if !ok {
+			return fmt.Errorf("no migration from export version %d", v)
+		}
+		if err := m(export); err != nil {
+			return fmt.Errorf("failed to migrate export from version %d: %w", v, err)
+		}
+	}
+	return nil
+}
+
+// migrateV1 upgrades a version 1 export to version 2.
+// Version 2 only added encrypted_identities, so nothing needs to change.
+func migrateV1(export *ExportConfig) error {
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/gpg/gpgtest"
 	"github.com/orzazade/gitch/internal/rules"
 )
 
@@ -47,6 +50,59 @@ func TestBuildExportConfig(t *testing.T) {
 	}
 }
 
+func TestBuildExportConfigFiltered(t *testing.T) {
+	cfg := &config.Config{
+		Default: "personal",
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", SSHKeyPath: "~/.ssh/work"},
+			{Name: "personal", Email: "personal@example.com"},
+			{Name: "oss", Email: "oss@example.com"},
+		},
+		Rules: []rules.Rule{
+			{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+			{Type: rules.RemoteRule, Pattern: "github.com/company/*", Identity: "Work"},
+			{Type: rules.DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+		},
+	}
+
+	export, err := BuildExportConfigFiltered(cfg, []string{"WORK", "work"})
+	if err != nil {
+		t.Fatalf("BuildExportConfigFiltered failed: %v", err)
+	}
+
+	if len(export.Identities) != 1 || export.Identities[0].Name != "work" {
+		t.Errorf("expected only 'work' identity, got %+v", export.Identities)
+	}
+	if len(export.Rules) != 2 {
+		t.Errorf("expected 2 rules referencing 'work', got %d", len(export.Rules))
+	}
+	if export.Default != "" {
+		t.Errorf("expected default to be dropped when not exported, got %q", export.Default)
+	}
+
+	// Default is kept when its identity is included
+	export, err = BuildExportConfigFiltered(cfg, []string{"personal"})
+	if err != nil {
+		t.Fatalf("BuildExportConfigFiltered failed: %v", err)
+	}
+	if export.Default != "personal" {
+		t.Errorf("expected default 'personal', got %q", export.Default)
+	}
+}
+
+func TestBuildExportConfigFiltered_UnknownIdentity(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com"},
+		},
+	}
+
+	_, err := BuildExportConfigFiltered(cfg, []string{"work", "missing"})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected error for unknown identity, got %v", err)
+	}
+}
+
 func TestExportToFile(t *testing.T) {
 	cfg := &config.Config{
 		Default: "work",
@@ -64,7 +120,7 @@ func TestExportToFile(t *testing.T) {
 	exportPath := filepath.Join(tmpDir, "gitch-export.yaml")
 
 	// Export
-	err := ExportToFile(cfg, exportPath)
+	err := ExportToFile(cfg, exportPath, "")
 	if err != nil {
 		t.Fatalf("ExportToFile failed: %v", err)
 	}
@@ -111,7 +167,7 @@ func TestExportToFile_EmptyConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	exportPath := filepath.Join(tmpDir, "gitch-export.yaml")
 
-	err := ExportToFile(cfg, exportPath)
+	err := ExportToFile(cfg, exportPath, "")
 	if err != ErrNoIdentities {
 		t.Errorf("expected ErrNoIdentities, got %v", err)
 	}
@@ -601,7 +657,7 @@ func TestExportImportRoundTrip(t *testing.T) {
 	// Export
 	tmpDir := t.TempDir()
 	exportPath := filepath.Join(tmpDir, "export.yaml")
-	if err := ExportToFile(original, exportPath); err != nil {
+	if err := ExportToFile(original, exportPath, ""); err != nil {
 		t.Fatalf("ExportToFile failed: %v", err)
 	}
 
@@ -641,6 +697,136 @@ func TestExportImportRoundTrip(t *testing.T) {
 	}
 }
 
+func TestExportToFile_JSON(t *testing.T) {
+	cfg := &config.Config{
+		Default: "work",
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", GPGKeyID: "ABCD1234"},
+		},
+	}
+
+	tmpDir := t.TempDir()
+	exportPath := filepath.Join(tmpDir, "gitch-export.json")
+
+	// Format inferred from .json extension
+	if err := ExportToFile(cfg, exportPath, ""); err != nil {
+		t.Fatalf("ExportToFile failed: %v", err)
+	}
+
+	data, err := os.ReadFile(exportPath)
+	if err != nil {
+		t.Fatalf("failed to read export file: %v", err)
+	}
+	content := string(data)
+
+	// JSON has no header comments
+	if !strings.HasPrefix(content, "{") {
+		t.Errorf("expected JSON object at start of file, got:\n%s", content)
+	}
+	if !strings.Contains(content, fmt.Sprintf(`"version": %d`, CurrentExportVersion)) {
+		t.Errorf("expected version in JSON, got:\n%s", content)
+	}
+	if !strings.Contains(content, `"gpg_key_id": "ABCD1234"`) {
+		t.Errorf("expected snake_case keys in JSON, got:\n%s", content)
+	}
+}
+
+func TestExportImportRoundTrip_JSON(t *testing.T) {
+	original := &config.Config{
+		Default: "work",
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", SSHKeyPath: "~/.ssh/work", GPGKeyID: "ABC123", Hosts: []string{"git.company.internal"}},
+			{Name: "personal", Email: "personal@example.com", HookMode: "block", Description: "side projects", Tags: []string{"oss"}},
+		},
+		Rules: []rules.Rule{
+			{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+			{Type: rules.RemoteRule, Pattern: "github.com/company/*", Identity: "work"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		file   string
+		format Format
+	}{
+		{"inferred from extension", "export.json", ""},
+		{"explicit format override", "export.conf", FormatJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exportPath := filepath.Join(t.TempDir(), tt.file)
+			if err := ExportToFile(original, exportPath, tt.format); err != nil {
+				t.Fatalf("ExportToFile failed: %v", err)
+			}
+
+			imported, err := ImportFromFile(exportPath)
+			if err != nil {
+				t.Fatalf("ImportFromFile failed: %v", err)
+			}
+
+			if imported.Default != original.Default {
+				t.Errorf("default mismatch: expected %q, got %q", original.Default, imported.Default)
+			}
+			if len(imported.Identities) != len(original.Identities) {
+				t.Fatalf("identity count mismatch: expected %d, got %d", len(original.Identities), len(imported.Identities))
+			}
+			if len(imported.Rules) != len(original.Rules) {
+				t.Fatalf("rule count mismatch: expected %d, got %d", len(original.Rules), len(imported.Rules))
+			}
+
+			for i, orig := range original.Identities {
+				imp := imported.Identities[i]
+				if !identitiesEqual(&orig, &imp) || orig.Name != imp.Name {
+					t.Errorf("identity %d mismatch: original=%+v, imported=%+v", i, orig, imp)
+				}
+			}
+			for i, orig := range original.Rules {
+				imp := imported.Rules[i]
+				if !rulesEqual(&orig, &imp) {
+					t.Errorf("rule %d mismatch: original=%+v, imported=%+v", i, orig, imp)
+				}
+			}
+		})
+	}
+}
+
+func TestImportFromFile_InvalidJSON(t *testing.T) {
+	importPath := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(importPath, []byte(`{"version": 2, "identities": [`), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	_, err := ImportFromFile(importPath)
+	if err == nil || !strings.Contains(err.Error(), "invalid JSON") {
+		t.Errorf("expected invalid JSON error, got %v", err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		{"", "", false},
+		{"yaml", FormatYAML, false},
+		{"YML", FormatYAML, false},
+		{"json", FormatJSON, false},
+		{"toml", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormat(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
 // ============================================================================
 // Helper function tests
 // ============================================================================
@@ -675,6 +861,18 @@ func TestIdentitiesEqual(t *testing.T) {
 			b:        &config.Identity{Name: "work", Email: "work@example.com", GPGKeyID: "XYZ"},
 			expected: false,
 		},
+		{
+			name:     "different description",
+			a:        &config.Identity{Name: "work", Email: "work@example.com", Description: "ACME"},
+			b:        &config.Identity{Name: "work", Email: "work@example.com", Description: "ACME contractor"},
+			expected: false,
+		},
+		{
+			name:     "different tags",
+			a:        &config.Identity{Name: "work", Email: "work@example.com", Tags: []string{"client-a"}},
+			b:        &config.Identity{Name: "work", Email: "work@example.com", Tags: []string{"client-a", "contract"}},
+			expected: false,
+		},
 		{
 			name:     "email case insensitive",
 			a:        &config.Identity{Name: "work", Email: "Work@Example.com"},
@@ -745,3 +943,585 @@ func TestExportedAtTimestamp(t *testing.T) {
 		t.Errorf("ExportedAt %v not within expected range [%v, %v]", export.ExportedAt, before, after)
 	}
 }
+
+// ============================================================================
+// GPG Key Export Tests
+// ============================================================================
+
+func TestExportImportGPGKeys(t *testing.T) {
+	if !gpg.IsGPGAvailable() {
+		t.Skip("gpg not installed, skipping test")
+	}
+
+	gpgtest.UseTempKeyring(t)
+	key, err := gpg.GenerateKey("Work", "work@example.com", nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", GPGKeyID: key.ID},
+			{Name: "personal", Email: "personal@example.com"},
+		},
+	}
+	passphrase := []byte("export-passphrase")
+	path := filepath.Join(t.TempDir(), "backup.yaml")
+
+	if err := ExportToFileEncrypted(cfg, path, PassphraseCipher(passphrase), FormatYAML, GPGExportSecret); err != nil {
+		t.Fatalf("ExportToFileEncrypted() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "PGP PRIVATE KEY") {
+		t.Error("GPG secret key must not be stored unencrypted")
+	}
+
+	export, err := ImportFromFile(path)
+	if err != nil {
+		t.Fatalf("ImportFromFile() error = %v", err)
+	}
+	if !HasGPGKeys(export) || !HasGPGSecretKeys(export) {
+		t.Fatal("expected export to contain GPG public and secret keys")
+	}
+	if !strings.Contains(export.EncryptedIdentities[0].GPGPublicKey, "BEGIN PGP PUBLIC KEY BLOCK") {
+		t.Error("expected armored GPG public key")
+	}
+
+	// Import into a fresh keyring, as on a new machine
+	gpgtest.UseTempKeyring(t)
+
+	result, err := ImportGPGKeys(export, PassphraseCipher("wrong"))
+	if err != nil {
+		t.Fatalf("ImportGPGKeys() error = %v", err)
+	}
+	if len(result.Errors) != 1 || len(result.Imported) != 0 {
+		t.Errorf("expected decryption error with wrong passphrase, got %+v", result)
+	}
+
+	result, err = ImportGPGKeys(export, PassphraseCipher(passphrase))
+	if err != nil {
+		t.Fatalf("ImportGPGKeys() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("ImportGPGKeys() errors = %v", result.Errors)
+	}
+	if len(result.Imported) != 1 || result.Imported[0] != "work" {
+		t.Errorf("Imported = %v, want [work]", result.Imported)
+	}
+
+	imported, err := gpg.GetKeyInfo(key.ID)
+	if err != nil {
+		t.Fatalf("expected secret key in new keyring: %v", err)
+	}
+	if imported.ID != key.ID {
+		t.Errorf("imported key ID = %s, want %s", imported.ID, key.ID)
+	}
+}
+
+func TestExportToFileEncrypted_NoGPGKeys(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", GPGKeyID: "ABCD1234EFGH5678"},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "backup.yaml")
+
+	// GPG keys are referenced by ID only unless requested
+	if err := ExportToFileEncrypted(cfg, path, PassphraseCipher("passphrase"), FormatYAML, GPGExportNone); err != nil {
+		t.Fatalf("ExportToFileEncrypted() error = %v", err)
+	}
+
+	export, err := ImportFromFile(path)
+	if err != nil {
+		t.Fatalf("ImportFromFile() error = %v", err)
+	}
+	if HasGPGKeys(export) {
+		t.Error("expected no embedded GPG keys")
+	}
+	if export.Identities[0].GPGKeyID != "ABCD1234EFGH5678" {
+		t.Errorf("GPGKeyID = %q, want ABCD1234EFGH5678", export.Identities[0].GPGKeyID)
+	}
+}
+
+func TestExportImportEncrypted_AllSSHKeys(t *testing.T) {
+	dir := t.TempDir()
+	primary := filepath.Join(dir, "id_work")
+	extra := filepath.Join(dir, "id_work_signing")
+	for _, path := range []string{primary, extra} {
+		if err := os.WriteFile(path, []byte("private key "+filepath.Base(path)), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", SSHKeyPath: primary, SSHKeyPaths: []string{primary, extra}},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "backup.yaml")
+	cipher := PassphraseCipher("passphrase")
+	if err := ExportToFileEncrypted(cfg, path, cipher, FormatYAML, GPGExportNone); err != nil {
+		t.Fatalf("ExportToFileEncrypted() error = %v", err)
+	}
+
+	export, err := ImportFromFile(path)
+	if err != nil {
+		t.Fatalf("ImportFromFile() error = %v", err)
+	}
+	if got := GetEncryptedKeyPaths(export); !reflect.DeepEqual(got, []string{primary, extra}) {
+		t.Errorf("GetEncryptedKeyPaths() = %v, want both keys", got)
+	}
+
+	// Both keys come back on a new machine
+	for _, p := range []string{primary, extra} {
+		if err := os.Remove(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	result, err := ExtractEncryptedKeys(export, cipher, nil)
+	if err != nil {
+		t.Fatalf("ExtractEncryptedKeys() error = %v", err)
+	}
+	if len(result.Errors) > 0 || len(result.ExtractedKeys) != 2 {
+		t.Fatalf("ExtractEncryptedKeys() = %+v, want both keys extracted", result)
+	}
+	for _, p := range []string{primary, extra} {
+		data, err := os.ReadFile(p)
+		if err != nil || string(data) != "private key "+filepath.Base(p) {
+			t.Errorf("key %s = %q, %v; want the original key", p, data, err)
+		}
+	}
+}
+
+func TestParseMergeStrategy(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    MergeStrategy
+		wantErr bool
+	}{
+		{"", "", false},
+		{"skip", MergeSkip, false},
+		{"Overwrite", MergeOverwrite, false},
+		{"newer", MergeNewer, false},
+		{"merge", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMergeStrategy(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseMergeStrategy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMergeStrategy(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	conflicts := []Conflict{
+		{Type: IdentityConflict, Key: "work"},
+		{Type: RuleConflict, Key: "~/work/**"},
+	}
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		strategy MergeStrategy
+		hints    TimestampHints
+		want     bool
+	}{
+		{"skip", MergeSkip, TimestampHints{}, false},
+		{"overwrite", MergeOverwrite, TimestampHints{}, true},
+		{"newer import", MergeNewer, TimestampHints{Existing: older, Incoming: newer}, true},
+		{"older import", MergeNewer, TimestampHints{Existing: newer, Incoming: older}, false},
+		{"unknown import time", MergeNewer, TimestampHints{Existing: older}, false},
+		{"no existing config", MergeNewer, TimestampHints{Incoming: older}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overwrite := ResolveConflicts(conflicts, tt.strategy, tt.hints)
+			for _, c := range conflicts {
+				if got, ok := overwrite[c.Key]; !ok || got != tt.want {
+					t.Errorf("overwrite[%q] = %v (present %v), want %v", c.Key, got, ok, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeConfig_StrategySkip(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{{Name: "work", Email: "old@example.com"}},
+	}
+	export := &ExportConfig{
+		Version:    CurrentExportVersion,
+		Identities: []config.Identity{{Name: "work", Email: "new@example.com"}},
+	}
+
+	overwrite := ResolveConflicts(DetectConflicts(cfg, export), MergeSkip, TimestampHints{})
+	result, err := MergeConfig(cfg, export, overwrite)
+	if err != nil {
+		t.Fatalf("MergeConfig() error = %v", err)
+	}
+	if len(result.Skipped) != 1 || cfg.Identities[0].Email != "old@example.com" {
+		t.Errorf("expected existing identity kept, got %+v (skipped %v)", cfg.Identities[0], result.Skipped)
+	}
+}
+
+
+func TestMergeConfig_OverwriteIdentityChanges(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "old@example.com", SSHKeyPath: "~/.ssh/id_old", GPGKeyID: "ABC123"},
+		},
+	}
+
+	export := &ExportConfig{
+		Identities: []config.Identity{
+			{Name: "work", Email: "new@example.com", SSHKeyPath: "~/.ssh/id_new", GPGKeyID: "ABC123", AgentLifetime: 3600},
+		},
+	}
+
+	result, err := MergeConfig(cfg, export, map[string]bool{"work": true})
+	if err != nil {
+		t.Fatalf("MergeConfig failed: %v", err)
+	}
+
+	want := []FieldChange{
+		{Field: "email", Old: "old@example.com", New: "new@example.com"},
+		{Field: "ssh_key_path", Old: "~/.ssh/id_old", New: "~/.ssh/id_new"},
+		{Field: "agent_lifetime", Old: "", New: "3600"},
+	}
+	if got := result.Changes["work"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes[work] = %+v, want %+v", got, want)
+	}
+}
+
+func TestDiffIdentities_EmailCaseInsensitive(t *testing.T) {
+	a := &config.Identity{Name: "work", Email: "Work@Example.com"}
+	b := &config.Identity{Name: "work", Email: "work@example.com"}
+
+	if changes := diffIdentities(a, b); len(changes) != 0 {
+		t.Errorf("expected no changes for email differing only in case, got %+v", changes)
+	}
+}
+
+func TestDiffIdentities_Tags(t *testing.T) {
+	tests := []struct {
+		name        string
+		stored      []string
+		imported    []string
+		wantChanged bool
+	}{
+		{"same", []string{"oss", "client-a"}, []string{"oss", "client-a"}, false},
+		{"reordered", []string{"oss", "client-a"}, []string{"client-a", "oss"}, false},
+		{"case differs", []string{"Client-A"}, []string{"client-a"}, false},
+		{"both empty", nil, []string{}, false},
+		{"tag added", []string{"oss"}, []string{"oss", "client-a"}, true},
+		{"tag replaced", []string{"oss"}, []string{"client-a"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &config.Identity{Name: "work", Email: "work@example.com", Tags: tt.stored}
+			b := &config.Identity{Name: "work", Email: "work@example.com", Tags: tt.imported}
+
+			changes := diffIdentities(a, b)
+			if changed := len(changes) > 0; changed != tt.wantChanged {
+				t.Errorf("diffIdentities() = %+v, want changed %v", changes, tt.wantChanged)
+			}
+		})
+	}
+}
+
+func TestDanglingRules(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "Work", Email: "work@example.com"},
+		},
+	}
+
+	export := &ExportConfig{
+		Identities: []config.Identity{
+			{Name: "personal", Email: "me@example.com"},
+		},
+		Rules: []rules.Rule{
+			{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+			{Type: rules.DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+			{Type: rules.DirectoryRule, Pattern: "~/oss/**", Identity: "missing"},
+		},
+	}
+
+	dangling := DanglingRules(cfg, export)
+	if len(dangling) != 1 || dangling[0].Pattern != "~/oss/**" {
+		t.Fatalf("expected only ~/oss/** to dangle, got %+v", dangling)
+	}
+}
+
+func TestMergeConfig_SkipsDanglingRule(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com"},
+		},
+	}
+
+	export := &ExportConfig{
+		Rules: []rules.Rule{
+			{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+			{Type: rules.DirectoryRule, Pattern: "~/oss/**", Identity: "missing"},
+		},
+	}
+
+	result, err := MergeConfig(cfg, export, nil)
+	if err != nil {
+		t.Fatalf("MergeConfig failed: %v", err)
+	}
+
+	if len(result.AddedRules) != 1 || result.AddedRules[0] != "~/work/**" {
+		t.Errorf("expected only ~/work/** added, got %v", result.AddedRules)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != "rule:~/oss/**" {
+		t.Errorf("expected rule:~/oss/** skipped, got %v", result.Skipped)
+	}
+	for _, r := range cfg.Rules {
+		if r.Identity == "missing" {
+			t.Errorf("dangling rule %q was added to config", r.Pattern)
+		}
+	}
+}
+
+func TestImportFromFile_MigratesV1Unchanged(t *testing.T) {
+	tmpDir := t.TempDir()
+	importPath := filepath.Join(tmpDir, "v1.yaml")
+
+	content := `version: 1
+exported_at: 2024-01-15T10:30:00Z
+default: work
+identities:
+  - name: work
+    email: work@example.com
+    ssh_key_path: ~/.ssh/work
+rules:
+  - type: directory
+    pattern: ~/work/**
+    identity: work
+`
+	if err := os.WriteFile(importPath, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	export, err := ImportFromFile(importPath)
+	if err != nil {
+		t.Fatalf("ImportFromFile failed: %v", err)
+	}
+
+	want := &ExportConfig{
+		Version:    1,
+		ExportedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		Default:    "work",
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", SSHKeyPath: "~/.ssh/work"},
+		},
+		Rules: []rules.Rule{
+			{Type: rules.DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		},
+	}
+	if !reflect.DeepEqual(export, want) {
+		t.Errorf("ImportFromFile() = %+v, want %+v", export, want)
+	}
+}
+
+func TestMigrate_RunsStepsInOrder(t *testing.T) {
+	saved := migrators
+	t.Cleanup(func() { migrators = saved })
+
+	var ran []int
+	migrators = map[int]migrator{}
+	for v := 1; v < CurrentExportVersion; v++ {
+		v := v
+		migrators[v] = func(*ExportConfig) error {
+			ran = append(ran, v)
+			return nil
+		}
+	}
+
+	if err := migrate(&ExportConfig{}); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if len(ran) != CurrentExportVersion-1 {
+		t.Errorf("expected %d migration steps, ran %v", CurrentExportVersion-1, ran)
+	}
+	for i, v := range ran {
+		if v != i+1 {
+			t.Errorf("step %d ran migrator for version %d", i, v)
+		}
+	}
+
+	// Current-version exports need no migration
+	ran = nil
+	if err := migrate(&ExportConfig{Version: CurrentExportVersion}); err != nil {
+		t.Fatalf("migrate failed: %v", err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("expected no steps for current version, ran %v", ran)
+	}
+}
+
+func TestMigrate_MissingStep(t *testing.T) {
+	saved := migrators
+	t.Cleanup(func() { migrators = saved })
+	migrators = map[int]migrator{}
+
+	if err := migrate(&ExportConfig{Version: 1}); err == nil {
+		t.Error("expected error when a migration step is missing")
+	}
+}
+
+func TestBuildExportConfig_OmitsHTTPSToken(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", HTTPSUser: "octocat", HTTPSToken: "ghp_secret"},
+		},
+	}
+
+	export := BuildExportConfig(cfg)
+
+	if export.Identities[0].HTTPSToken != "" {
+		t.Errorf("export should not contain the HTTPS token, got %q", export.Identities[0].HTTPSToken)
+	}
+	if export.Identities[0].HTTPSUser != "octocat" {
+		t.Errorf("expected HTTPS user 'octocat', got %q", export.Identities[0].HTTPSUser)
+	}
+	if cfg.Identities[0].HTTPSToken != "ghp_secret" {
+		t.Error("BuildExportConfig should not modify the source config")
+	}
+}
+
+func TestMergeConfig_OverwriteKeepsHTTPSToken(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", HTTPSToken: "ghp_secret"},
+		},
+	}
+	export := &ExportConfig{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", HTTPSUser: "octocat"},
+		},
+	}
+
+	if _, err := MergeConfig(cfg, export, map[string]bool{"work": true}); err != nil {
+		t.Fatalf("MergeConfig failed: %v", err)
+	}
+
+	identity, _ := cfg.GetIdentity("work")
+	if identity.HTTPSToken != "ghp_secret" {
+		t.Errorf("expected local HTTPS token to be kept, got %q", identity.HTTPSToken)
+	}
+	if identity.HTTPSUser != "octocat" {
+		t.Errorf("expected HTTPS user 'octocat', got %q", identity.HTTPSUser)
+	}
+}
+
+func TestBuildExportBytes(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com", HTTPSToken: "ghp_secret"},
+		},
+	}
+
+	data, err := BuildExportBytes(cfg, "")
+	if err != nil {
+		t.Fatalf("BuildExportBytes failed: %v", err)
+	}
+	content := string(data)
+
+	// YAML by default, without the file header
+	if strings.HasPrefix(content, "#") {
+		t.Errorf("expected no header comment, got:\n%s", content)
+	}
+	if !strings.Contains(content, "email: work@example.com") {
+		t.Errorf("expected YAML identity, got:\n%s", content)
+	}
+	if strings.Contains(content, "ghp_secret") {
+		t.Errorf("expected HTTPS token to be left out, got:\n%s", content)
+	}
+
+	data, err = BuildExportBytes(cfg, FormatJSON)
+	if err != nil {
+		t.Fatalf("BuildExportBytes JSON failed: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "{") {
+		t.Errorf("expected JSON object, got:\n%s", data)
+	}
+
+	if _, err := BuildExportBytes(&config.Config{}, ""); err != ErrNoIdentities {
+		t.Errorf("expected ErrNoIdentities, got %v", err)
+	}
+}
+
+func TestVerifyPassphrase(t *testing.T) {
+	passphrase := []byte("correct-passphrase")
+	encrypted, err := EncryptWithPassphrase([]byte("key material"), passphrase)
+	if err != nil {
+		t.Fatalf("EncryptWithPassphrase failed: %v", err)
+	}
+
+	export := &ExportConfig{
+		Encryption: &EncryptionInfo{Method: EncryptionScrypt, Armored: true},
+		EncryptedIdentities: []EncryptedIdentity{
+			{Name: "personal", Email: "personal@example.com"},
+			{Name: "work", Email: "work@example.com", SSHKeyPath: "~/.ssh/work", SSHKeyEncrypted: string(encrypted)},
+		},
+	}
+
+	if !VerifyPassphrase(export, passphrase) {
+		t.Error("expected the correct passphrase to verify")
+	}
+	if VerifyPassphrase(export, []byte("wrong-passphrase")) {
+		t.Error("expected a wrong passphrase to fail verification")
+	}
+	if !VerifyPassphrase(&ExportConfig{}, []byte("anything")) {
+		t.Error("expected an export without encrypted keys to verify")
+	}
+}
+
+func TestMergeConfig_NormalizesEmail(t *testing.T) {
+	cfg := &config.Config{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@example.com"},
+		},
+	}
+	export := &ExportConfig{
+		Identities: []config.Identity{
+			{Name: "work", Email: "work@Example.COM", HookMode: "block"},
+			{Name: "oss", Email: "Me@GitHub.com"},
+		},
+	}
+
+	result, err := MergeConfig(cfg, export, map[string]bool{"work": true})
+	if err != nil {
+		t.Fatalf("MergeConfig failed: %v", err)
+	}
+
+	work, _ := cfg.GetIdentity("work")
+	if work.Email != "work@example.com" {
+		t.Errorf("expected updated email 'work@example.com', got %q", work.Email)
+	}
+	for _, change := range result.Changes["work"] {
+		if change.Field == "email" {
+			t.Errorf("expected no email change when only the domain case differs, got %+v", change)
+		}
+	}
+
+	oss, _ := cfg.GetIdentity("oss")
+	if oss.Email != "Me@github.com" {
+		t.Errorf("expected added email 'Me@github.com', got %q", oss.Email)
+	}
+}
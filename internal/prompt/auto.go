@@ -0,0 +1,80 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AutoSwitchInit returns shell code that runs 'gitch auto' whenever the
+// working directory changes. bin is the gitch executable to invoke, so the
+// hook also works when gitch is not on PATH.
+// The output should be evaled (bash/zsh) or sourced (fish).
+func AutoSwitchInit(shell, bin string) (string, error) {
+	switch shell {
+	case "bash":
+		return bashAutoSwitch(shellQuote(bin)), nil
+	case "zsh":
+		return zshAutoSwitch(shellQuote(bin)), nil
+	case "fish":
+		return fishAutoSwitch(fishQuote(bin)), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use bash, zsh or fish)", shell)
+	}
+}
+
+func bashAutoSwitch(bin string) string {
+	return fmt.Sprintf(`# gitch auto-switching for bash
+# Add to ~/.bashrc: eval "$(gitch shell-init bash)"
+
+# Run 'gitch auto' when the directory changes
+_gitch_auto() {
+  if [[ "$PWD" != "$_GITCH_AUTO_PWD" ]]; then
+    _GITCH_AUTO_PWD="$PWD"
+    %s auto
+  fi
+}
+
+if [[ ";${PROMPT_COMMAND};" != *";_gitch_auto;"* ]]; then
+  PROMPT_COMMAND="_gitch_auto${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`, bin)
+}
+
+func zshAutoSwitch(bin string) string {
+	return fmt.Sprintf(`# gitch auto-switching for zsh
+# Add to ~/.zshrc: eval "$(gitch shell-init zsh)"
+
+# Run 'gitch auto' when the directory changes
+_gitch_auto() {
+  %s auto
+}
+
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _gitch_auto
+_gitch_auto
+`, bin)
+}
+
+func fishAutoSwitch(bin string) string {
+	return fmt.Sprintf(`# gitch auto-switching for fish
+# Add to ~/.config/fish/config.fish: gitch shell-init fish | source
+
+# Run 'gitch auto' when the directory changes
+function _gitch_auto --on-variable PWD
+  %s auto
+end
+
+_gitch_auto
+`, bin)
+}
+
+// shellQuote single-quotes s for bash and zsh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote single-quotes s for fish, where \ and ' are escaped inside quotes
+func fishQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return "'" + strings.ReplaceAll(s, "'", `\'`) + "'"
+}
@@ -0,0 +1,51 @@
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAutoSwitchInit(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"PROMPT_COMMAND", "'/opt/gitch' auto"}},
+		{"zsh", []string{"add-zsh-hook chpwd _gitch_auto", "'/opt/gitch' auto"}},
+		{"fish", []string{"--on-variable PWD", "'/opt/gitch' auto"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := AutoSwitchInit(tt.shell, "/opt/gitch")
+			if err != nil {
+				t.Fatalf("AutoSwitchInit(%q) error: %v", tt.shell, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("AutoSwitchInit(%q) missing %q:\n%s", tt.shell, want, script)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoSwitchInitUnsupportedShell(t *testing.T) {
+	if _, err := AutoSwitchInit("tcsh", "gitch"); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+func TestAutoSwitchInitQuotesPath(t *testing.T) {
+	bin := "/home/o'neil/bin/gitch"
+
+	script, _ := AutoSwitchInit("bash", bin)
+	if !strings.Contains(script, `'/home/o'\''neil/bin/gitch' auto`) {
+		t.Errorf("bash script did not quote path:\n%s", script)
+	}
+
+	script, _ = AutoSwitchInit("fish", bin)
+	if !strings.Contains(script, `'/home/o\'neil/bin/gitch' auto`) {
+		t.Errorf("fish script did not quote path:\n%s", script)
+	}
+}
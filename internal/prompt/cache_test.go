@@ -4,6 +4,8 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/orzazade/gitch/internal/xdgtest"
 )
 
 // TestUpdateCache verifies that UpdateCache writes to file and can be read back
@@ -12,9 +14,7 @@ func TestUpdateCache(t *testing.T) {
 	tmpDir := t.TempDir()
 
 	// Override XDG cache path for testing
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Test writing identity name
 	identityName := "work"
@@ -42,9 +42,7 @@ func TestUpdateCache(t *testing.T) {
 func TestClearCache(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Create cache file first
 	if err := UpdateCache("test-identity"); err != nil {
@@ -67,9 +65,7 @@ func TestClearCache(t *testing.T) {
 func TestReadCacheMissing(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Don't create any file - just read
 	content, err := ReadCache()
@@ -85,9 +81,7 @@ func TestReadCacheMissing(t *testing.T) {
 func TestClearCacheMissing(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Clear without creating - should not error
 	if err := ClearCache(); err != nil {
@@ -99,9 +93,7 @@ func TestClearCacheMissing(t *testing.T) {
 func TestAtomicWrite(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Write to cache
 	if err := UpdateCache("atomic-test"); err != nil {
@@ -129,9 +121,7 @@ func TestAtomicWrite(t *testing.T) {
 func TestUpdateCacheEmpty(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Create with content first
 	if err := UpdateCache("some-identity"); err != nil {
@@ -157,9 +147,7 @@ func TestUpdateCacheEmpty(t *testing.T) {
 func TestReadCacheTrimsWhitespace(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	origXDG := os.Getenv("XDG_CACHE_HOME")
-	os.Setenv("XDG_CACHE_HOME", tmpDir)
-	defer os.Setenv("XDG_CACHE_HOME", origXDG)
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
 
 	// Write with whitespace directly to file (simulating external modification)
 	cachePath, _ := CachePath()
@@ -180,3 +168,28 @@ func TestReadCacheTrimsWhitespace(t *testing.T) {
 		t.Errorf("Expected 'work', got %q", content)
 	}
 }
+
+// TestUpdateCacheOnSwitch verifies the cache follows successive identity
+// switches (add, then use/hook switch), as the prompt reads it on every render
+func TestUpdateCacheOnSwitch(t *testing.T) {
+	tmpDir := t.TempDir()
+	xdgtest.Setenv(t, "XDG_CACHE_HOME", tmpDir)
+
+	cachePath, _ := CachePath()
+	if filepath.Dir(filepath.Dir(cachePath)) != tmpDir {
+		t.Fatalf("cache path %q is not under temp XDG_CACHE_HOME %q", cachePath, tmpDir)
+	}
+
+	for _, name := range []string{"work", "personal", "work"} {
+		if err := UpdateCache(name); err != nil {
+			t.Fatalf("UpdateCache(%q) failed: %v", name, err)
+		}
+		content, err := ReadCache()
+		if err != nil {
+			t.Fatalf("ReadCache failed: %v", err)
+		}
+		if content != name {
+			t.Errorf("after switching to %q, cache = %q", name, content)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultFormat is the format used by 'gitch prompt' when none is given
+const DefaultFormat = "%s"
+
+// ValidateFormat checks that a prompt format contains the %s placeholder
+func ValidateFormat(format string) error {
+	if !strings.Contains(format, "%s") {
+		return fmt.Errorf("invalid format %q: must contain %%s for the identity name", format)
+	}
+	return nil
+}
+
+// FormatIdentity substitutes the identity name for each %s in format.
+// Returns an empty string when no identity is set, so prompts stay clean.
+// Other % sequences are left as-is rather than interpreted as fmt verbs.
+func FormatIdentity(format, identityName string) string {
+	if identityName == "" {
+		return ""
+	}
+	return strings.ReplaceAll(format, "%s", identityName)
+}
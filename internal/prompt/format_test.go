@@ -0,0 +1,35 @@
+package prompt
+
+import "testing"
+
+func TestFormatIdentity(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   string
+		identity string
+		expected string
+	}{
+		{name: "default format", format: DefaultFormat, identity: "work", expected: "work"},
+		{name: "wrapped", format: "(%s) ", identity: "work", expected: "(work) "},
+		{name: "no identity", format: "(%s) ", identity: "", expected: ""},
+		{name: "other verbs untouched", format: "%d[%s]%%", identity: "work", expected: "%d[work]%%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatIdentity(tt.format, tt.identity)
+			if result != tt.expected {
+				t.Errorf("FormatIdentity(%q, %q) = %q, want %q", tt.format, tt.identity, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	if err := ValidateFormat("(%s)"); err != nil {
+		t.Errorf("ValidateFormat(\"(%%s)\") error = %v", err)
+	}
+	if err := ValidateFormat("(identity)"); err == nil {
+		t.Error("ValidateFormat should reject a format without a placeholder")
+	}
+}
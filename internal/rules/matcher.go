@@ -2,14 +2,26 @@ package rules
 
 import (
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"github.com/bmatcuk/doublestar/v4"
 )
 
+// caseInsensitivePaths reports whether directory patterns ignore case by
+// default. The default file systems on macOS and Windows are case-insensitive,
+// so the same directory can be reported as ~/Work or ~/work.
+var caseInsensitivePaths = runtime.GOOS == "darwin" || runtime.GOOS == "windows"
+
 // MatchDirectory checks if the current working directory matches the given pattern
 // Pattern should be a glob pattern, optionally starting with ~ for home directory
+// Matching ignores case on macOS and Windows
 func MatchDirectory(pattern, cwd string) (bool, error) {
+	return matchDirectory(pattern, cwd, caseInsensitivePaths)
+}
+
+// matchDirectory matches cwd against pattern, optionally ignoring case
+func matchDirectory(pattern, cwd string, ignoreCase bool) (bool, error) {
 	// Expand tilde in both pattern and cwd
 	expandedPattern := expandTilde(pattern)
 	expandedCwd := expandTilde(cwd)
@@ -18,6 +30,11 @@ func MatchDirectory(pattern, cwd string) (bool, error) {
 	expandedPattern = filepath.Clean(expandedPattern)
 	expandedCwd = filepath.Clean(expandedCwd)
 
+	if ignoreCase {
+		expandedPattern = strings.ToLower(expandedPattern)
+		expandedCwd = strings.ToLower(expandedCwd)
+	}
+
 	// Use doublestar.PathMatch for OS-native path separators
 	match, err := doublestar.PathMatch(expandedPattern, expandedCwd)
 	if err != nil {
@@ -29,24 +46,43 @@ func MatchDirectory(pattern, cwd string) (bool, error) {
 
 // MatchRemote checks if a parsed remote matches the given pattern
 // Pattern format: "host/org/*" or "host/org/repo"
+// Azure DevOps remotes are matched as host/org/project/repo, so a pattern
+// can select a whole project ("dev.azure.com/org/project/*"). Patterns that
+// stop at the project ("dev.azure.com/org/project" or "dev.azure.com/org/*"),
+// as written when the project was taken for the repository, keep matching.
 func MatchRemote(pattern string, remote *ParsedRemote) bool {
 	if remote == nil {
 		return false
 	}
 
-	// Build the remote path for matching: host/org/repo
-	remotePath := remote.Host
-	if remote.Org != "" {
-		remotePath += "/" + remote.Org
-	}
-	if remote.Repo != "" {
-		remotePath += "/" + remote.Repo
+	// Normalize for comparison (lowercase)
+	pattern = strings.ToLower(pattern)
+
+	if remote.Project != "" {
+		projectPath := joinRemotePath(remote.Host, remote.Org, remote.Project)
+		if matchRemotePath(pattern, joinRemotePath(projectPath, remote.Repo)) {
+			return true
+		}
+		return matchRemotePath(pattern, projectPath)
 	}
+	return matchRemotePath(pattern, joinRemotePath(remote.Host, remote.Org, remote.Repo))
+}
 
-	// Normalize both for comparison (lowercase)
-	pattern = strings.ToLower(pattern)
-	remotePath = strings.ToLower(remotePath)
+// joinRemotePath joins the non-empty segments of a remote with "/",
+// lowercased for matching
+func joinRemotePath(segments ...string) string {
+	var kept []string
+	for _, segment := range segments {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	return strings.ToLower(strings.Join(kept, "/"))
+}
 
+// matchRemotePath matches a lowercase remote path such as github.com/org/repo
+// against a lowercase pattern
+func matchRemotePath(pattern, remotePath string) bool {
 	// Check if pattern contains wildcard
 	if strings.Contains(pattern, "*") {
 		// Use simple glob matching
@@ -68,3 +104,25 @@ func MatchRemote(pattern string, remote *ParsedRemote) bool {
 
 	return pattern == remotePath
 }
+
+// MatchBranch checks if a branch name matches the given pattern
+// Pattern is a glob where * matches within one path segment and ** across
+// segments, e.g. "release/*" or "feature/**". Matching is case-sensitive.
+func MatchBranch(pattern, branch string) (bool, error) {
+	if branch == "" {
+		return false, nil
+	}
+
+	return doublestar.Match(pattern, branch)
+}
+
+// MatchEmail checks if an email address matches the given pattern
+// Pattern is a glob such as "*@company.com". Matching is case-insensitive.
+func MatchEmail(pattern, email string) bool {
+	if email == "" {
+		return false
+	}
+
+	matched, _ := filepath.Match(strings.ToLower(pattern), strings.ToLower(email))
+	return matched
+}
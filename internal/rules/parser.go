@@ -9,21 +9,23 @@ import (
 
 // ParsedRemote represents a parsed git remote URL
 type ParsedRemote struct {
-	Host string // e.g., "github.com"
-	Org  string // e.g., "company"
-	Repo string // e.g., "project"
+	Host    string // e.g., "github.com"
+	Org     string // e.g., "company"
+	Repo    string // e.g., "project"
+	Project string // Azure DevOps project between org and repo; empty elsewhere
 }
 
 // ParseRemote parses a git remote URL and extracts host, org, and repo
-// Supports SSH (git@host:path), HTTPS, and SCP-style URLs
+// Supports SSH (git@host:path), ssh:// with an explicit port, HTTPS, and
+// SCP-style URLs. Azure DevOps URLs also fill in Project.
 func ParseRemote(rawURL string) (*ParsedRemote, error) {
 	u, err := giturls.Parse(rawURL)
 	if err != nil {
 		return nil, err
 	}
 
-	// Normalize host to lowercase
-	host := strings.ToLower(u.Host)
+	// Normalize host to lowercase, dropping any port (ssh://git@host:2222/...)
+	host := strings.ToLower(u.Hostname())
 
 	// Get path and clean it
 	path := strings.TrimPrefix(u.Path, "/")
@@ -36,6 +38,11 @@ func ParseRemote(rawURL string) (*ParsedRemote, error) {
 		Host: host,
 	}
 
+	if isAzureDevOpsHost(host) {
+		parseAzurePath(result, parts)
+		return result, nil
+	}
+
 	if len(parts) >= 1 && parts[0] != "" {
 		result.Org = parts[0]
 	}
@@ -46,9 +53,68 @@ func ParseRemote(rawURL string) (*ParsedRemote, error) {
 	return result, nil
 }
 
+// isAzureDevOpsHost reports whether host serves Azure DevOps repositories
+func isAzureDevOpsHost(host string) bool {
+	return host == "dev.azure.com" || host == "ssh.dev.azure.com" ||
+		strings.HasSuffix(host, ".visualstudio.com")
+}
+
+// parseAzurePath fills org, project and repo from an Azure DevOps path:
+//
+//	v3/org/project/repo          (SSH)
+//	org/project/_git/repo        (HTTPS, dev.azure.com)
+//	project/_git/repo            (HTTPS, org.visualstudio.com)
+//
+// The legacy DefaultCollection segment is ignored.
+func parseAzurePath(result *ParsedRemote, parts []string) {
+	if len(parts) > 0 && parts[0] == "v3" {
+		parts = parts[1:]
+	} else {
+		// Drop the _git segment and legacy DefaultCollection
+		var kept []string
+		for _, part := range parts {
+			if part != "_git" && !strings.EqualFold(part, "DefaultCollection") && part != "" {
+				kept = append(kept, part)
+			}
+		}
+		parts = kept
+		// Legacy URLs carry the org in the subdomain
+		if org, ok := strings.CutSuffix(result.Host, ".visualstudio.com"); ok && org != "vs-ssh" {
+			parts = append([]string{org}, parts...)
+		}
+	}
+
+	if len(parts) >= 1 {
+		result.Org = parts[0]
+	}
+	if len(parts) >= 3 {
+		result.Project = parts[1]
+		result.Repo = parts[2]
+	} else if len(parts) == 2 {
+		result.Repo = parts[1]
+	}
+}
+
+// OrgPattern returns a remote rule pattern matching every repository in the
+// remote's organization, e.g. "github.com/company/*".
+// Returns an empty string if the remote has no organization.
+func (p *ParsedRemote) OrgPattern() string {
+	if p.Host == "" || p.Org == "" {
+		return ""
+	}
+	return p.Host + "/" + p.Org + "/*"
+}
+
 // GetGitRemoteURL retrieves the origin remote URL from the current git repository
 func GetGitRemoteURL() (string, error) {
+	return GetGitRemoteURLIn("")
+}
+
+// GetGitRemoteURLIn retrieves the origin remote URL from the git repository
+// containing dir. An empty dir means the current directory.
+func GetGitRemoteURLIn(dir string) (string, error) {
 	cmd := exec.Command("git", "config", "--get", "remote.origin.url")
+	cmd.Dir = dir
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -56,3 +122,27 @@ func GetGitRemoteURL() (string, error) {
 
 	return strings.TrimSpace(string(output)), nil
 }
+
+// GetCurrentBranch returns the checked-out branch of the current git repository
+func GetCurrentBranch() (string, error) {
+	return GetCurrentBranchIn("")
+}
+
+// GetCurrentBranchIn returns the checked-out branch of the git repository
+// containing dir. An empty dir means the current directory.
+// Returns an empty string when HEAD is detached.
+func GetCurrentBranchIn(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	branch := strings.TrimSpace(string(output))
+	if branch == "HEAD" {
+		return "", nil
+	}
+
+	return branch, nil
+}
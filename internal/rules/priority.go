@@ -2,19 +2,34 @@ package rules
 
 import (
 	"path/filepath"
+	"sort"
 	"strings"
+
+	"github.com/orzazade/gitch/internal/git"
 )
 
 // Specificity calculates the specificity score for a rule
 // Higher scores indicate more specific rules
-// Directory rules: count path segments (*10), penalize wildcards (*-2)
+// Directory rules: count path segments (*10), penalize wildcards (*-2),
+// and each ** segment after the first (-7)
 // Remote rules: count parts (*10), exact repo match bonus (+50)
+// Branch rules: base bonus (+100), count parts (*10), penalize wildcards (*-2)
+// Email rules: exact address 10, penalize wildcards (*-2), minimum 1
+//
+// Branch rules always outrank directory and remote rules, since a branch
+// is the narrowest context within a repository. Email rules score below any
+// absolute directory or remote pattern, so they act as a fallback when no
+// directory or remote rule matches.
 func (r Rule) Specificity() int {
 	switch r.Type {
 	case DirectoryRule:
 		return directorySpecificity(r.Pattern)
 	case RemoteRule:
 		return remoteSpecificity(r.Pattern)
+	case BranchRule:
+		return branchSpecificity(r.Pattern)
+	case EmailRule:
+		return emailSpecificity(r.Pattern)
 	default:
 		return 0
 	}
@@ -26,17 +41,27 @@ func directorySpecificity(pattern string) int {
 	expanded := expandTilde(pattern)
 	expanded = filepath.Clean(expanded)
 
-	// Count path segments
-	segments := strings.Split(expanded, string(filepath.Separator))
-	score := len(segments) * 10
+	score := 0
+	globstars := 0
+	for _, segment := range strings.Split(expanded, string(filepath.Separator)) {
+		// A ** segment matches any number of directories. The first one
+		// counts as a (heavily penalized) segment; each further one only
+		// widens the match, so it lowers the score instead
+		if segment == "**" {
+			globstars++
+			if globstars > 1 {
+				score -= 7
+				continue
+			}
+		}
 
-	// Penalize wildcards
-	wildcardCount := strings.Count(pattern, "*")
-	score -= wildcardCount * 2
+		// Count the segment, penalizing wildcards
+		score += 10
+		score -= strings.Count(segment, "*") * 2
 
-	// Double star is more general, penalize more
-	doubleStarCount := strings.Count(pattern, "**")
-	score -= doubleStarCount * 3
+		// Double star is more general, penalize more
+		score -= strings.Count(segment, "**") * 3
+	}
 
 	return score
 }
@@ -61,47 +86,152 @@ func remoteSpecificity(pattern string) int {
 	return score
 }
 
+// branchSpecificity calculates specificity for branch patterns
+func branchSpecificity(pattern string) int {
+	// Base bonus keeps branch rules above directory and remote rules
+	score := 100
+
+	parts := strings.Split(pattern, "/")
+	score += len(parts) * 10
+
+	// Penalize wildcards, double star more
+	score -= strings.Count(pattern, "*") * 2
+	score -= strings.Count(pattern, "**") * 3
+
+	return score
+}
+
+// emailSpecificity calculates specificity for email patterns
+// Scores stay below absolute directory ("/**" = 15) and remote ("host/*" = 18) scores.
+func emailSpecificity(pattern string) int {
+	score := 10 - strings.Count(pattern, "*")*2
+	if score < 1 {
+		score = 1
+	}
+	return score
+}
+
 // Matches checks if a rule matches the given context
+// For branch rules, the branch is read from the repository at cwd
+// For email rules, the email is the current global git user.email
 func (r Rule) Matches(cwd, remoteURL string) bool {
+	return r.matches(newMatchContext(cwd, remoteURL))
+}
+
+// matchContext holds the values rules are matched against. The branch and
+// email require running git, so they are resolved on first use only.
+type matchContext struct {
+	cwd       string
+	remoteURL string
+	branch    func() string
+	email     func() string
+}
+
+// newMatchContext creates a match context for the given directory and remote
+func newMatchContext(cwd, remoteURL string) *matchContext {
+	return &matchContext{
+		cwd:       cwd,
+		remoteURL: remoteURL,
+		branch: lazy(func() string {
+			branch, _ := GetCurrentBranchIn(cwd)
+			return branch
+		}),
+		email: lazy(func() string {
+			_, email, _ := git.GetCurrentIdentity()
+			return email
+		}),
+	}
+}
+
+// lazy wraps fn so it runs at most once
+func lazy(fn func() string) func() string {
+	var value string
+	resolved := false
+	return func() string {
+		if !resolved {
+			value = fn()
+			resolved = true
+		}
+		return value
+	}
+}
+
+// matches checks if a rule matches the context
+func (r Rule) matches(ctx *matchContext) bool {
 	switch r.Type {
 	case DirectoryRule:
-		matched, err := MatchDirectory(r.Pattern, cwd)
+		matched, err := matchDirectory(r.Pattern, ctx.cwd, r.IgnoreCase || caseInsensitivePaths)
 		if err != nil {
 			return false
 		}
 		return matched
 	case RemoteRule:
-		if remoteURL == "" {
+		if ctx.remoteURL == "" {
 			return false
 		}
-		parsed, err := ParseRemote(remoteURL)
+		parsed, err := ParseRemote(ctx.remoteURL)
 		if err != nil {
 			return false
 		}
 		return MatchRemote(r.Pattern, parsed)
+	case BranchRule:
+		matched, err := MatchBranch(r.Pattern, ctx.branch())
+		if err != nil {
+			return false
+		}
+		return matched
+	case EmailRule:
+		return MatchEmail(r.Pattern, ctx.email())
 	default:
 		return false
 	}
 }
 
-// FindBestMatch finds the rule with the highest specificity that matches the context
+// Outranks reports whether r wins over other when both match.
+// Higher priority wins; specificity breaks ties between equal priorities.
+func (r Rule) Outranks(other Rule) bool {
+	if r.Priority != other.Priority {
+		return r.Priority > other.Priority
+	}
+	return r.Specificity() > other.Specificity()
+}
+
+// FindBestMatch finds the matching rule with the highest priority, then the
+// highest specificity. Among equal rules the first in config order wins.
 // Returns nil if no rules match
 func FindBestMatch(rules []Rule, cwd, remoteURL string) *Rule {
 	var bestMatch *Rule
-	bestScore := -1
+	ctx := newMatchContext(cwd, remoteURL)
 
 	for i := range rules {
 		rule := &rules[i]
-		if !rule.Matches(cwd, remoteURL) {
+		if !rule.matches(ctx) {
 			continue
 		}
 
-		score := rule.Specificity()
-		if score > bestScore {
-			bestScore = score
+		if bestMatch == nil || rule.Outranks(*bestMatch) {
 			bestMatch = rule
 		}
 	}
 
 	return bestMatch
 }
+
+// FindMatches returns every rule that matches the context, ordered by
+// priority and then specificity, highest first. Equal rules keep their config
+// order, so the first element is the rule FindBestMatch would choose.
+func FindMatches(rules []Rule, cwd, remoteURL string) []Rule {
+	var matches []Rule
+	ctx := newMatchContext(cwd, remoteURL)
+	for _, rule := range rules {
+		if rule.matches(ctx) {
+			matches = append(matches, rule)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Outranks(matches[j])
+	})
+
+	return matches
+}
@@ -10,7 +10,7 @@ import (
 	"github.com/bmatcuk/doublestar/v4"
 )
 
-// RuleType indicates whether a rule matches by directory or remote
+// RuleType indicates whether a rule matches by directory, remote, branch, or email
 type RuleType string
 
 const (
@@ -18,13 +18,21 @@ const (
 	DirectoryRule RuleType = "directory"
 	// RemoteRule matches based on git remote URL
 	RemoteRule RuleType = "remote"
+	// BranchRule matches based on the current git branch
+	BranchRule RuleType = "branch"
+	// EmailRule matches based on the current git user.email
+	EmailRule RuleType = "email"
 )
 
 // Rule represents an auto-switch rule that maps a pattern to an identity
 type Rule struct {
-	Type     RuleType `yaml:"type"`
-	Pattern  string   `yaml:"pattern"`
-	Identity string   `yaml:"identity"`
+	Type     RuleType `yaml:"type" json:"type"`
+	Pattern  string   `yaml:"pattern" json:"pattern"`
+	Identity string   `yaml:"identity" json:"identity"`
+	Priority int      `yaml:"priority,omitempty" json:"priority,omitempty"`
+	// IgnoreCase makes a directory rule match regardless of case. Directory
+	// rules always ignore case on macOS and Windows.
+	IgnoreCase bool `yaml:"ignore_case,omitempty" json:"ignore_case,omitempty"`
 }
 
 // IsDirectory returns true if this is a directory-based rule
@@ -37,9 +45,21 @@ func (r Rule) IsRemote() bool {
 	return r.Type == RemoteRule
 }
 
+// IsBranch returns true if this is a branch-based rule
+func (r Rule) IsBranch() bool {
+	return r.Type == BranchRule
+}
+
+// IsEmail returns true if this is an email-based rule
+func (r Rule) IsEmail() bool {
+	return r.Type == EmailRule
+}
+
 // ValidatePattern validates the rule pattern
 // For directory rules, it expands tilde and validates with doublestar
 // For remote rules, it validates the pattern format
+// For branch rules, it validates the glob syntax
+// For email rules, it requires an @ and valid glob syntax
 func (r Rule) ValidatePattern() error {
 	if r.Pattern == "" {
 		return errors.New("pattern cannot be empty")
@@ -50,6 +70,10 @@ func (r Rule) ValidatePattern() error {
 		return validateDirectoryPattern(r.Pattern)
 	case RemoteRule:
 		return validateRemotePattern(r.Pattern)
+	case BranchRule:
+		return validateBranchPattern(r.Pattern)
+	case EmailRule:
+		return validateEmailPattern(r.Pattern)
 	default:
 		return fmt.Errorf("unknown rule type: %s", r.Type)
 	}
@@ -86,6 +110,51 @@ func validateRemotePattern(pattern string) error {
 	return nil
 }
 
+// validateBranchPattern validates a branch glob pattern
+func validateBranchPattern(pattern string) error {
+	if strings.ContainsAny(pattern, " \t\n") {
+		return fmt.Errorf("branch pattern cannot contain whitespace: %s", pattern)
+	}
+
+	if !doublestar.ValidatePattern(pattern) {
+		return fmt.Errorf("invalid glob pattern: %s", pattern)
+	}
+
+	return nil
+}
+
+// validateEmailPattern validates an email glob pattern such as *@company.com
+func validateEmailPattern(pattern string) error {
+	if !strings.Contains(pattern, "@") {
+		return fmt.Errorf("email pattern must contain @, e.g. *@company.com, got: %s", pattern)
+	}
+
+	if strings.ContainsAny(pattern, " \t\n") {
+		return fmt.Errorf("email pattern cannot contain whitespace: %s", pattern)
+	}
+
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid glob pattern: %s", pattern)
+	}
+
+	return nil
+}
+
+// DirectoryPattern returns a directory rule pattern matching dir and
+// everything below it, e.g. "~/work/project/**" for a path under home.
+func DirectoryPattern(dir string) string {
+	dir = filepath.Clean(dir)
+	if home, err := os.UserHomeDir(); err == nil {
+		if dir == home {
+			return "~/**"
+		}
+		if rel, err := filepath.Rel(home, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return "~/" + filepath.ToSlash(rel) + "/**"
+		}
+	}
+	return strings.TrimSuffix(filepath.ToSlash(dir), "/") + "/**"
+}
+
 // expandTilde expands ~ to the user's home directory
 func expandTilde(path string) string {
 	if !strings.HasPrefix(path, "~") {
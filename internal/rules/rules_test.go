@@ -2,7 +2,9 @@ package rules
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -121,6 +123,66 @@ func TestMatchDirectory(t *testing.T) {
 			cwd:     filepath.Join(home, "work/project"),
 			want:    true,
 		},
+		{
+			name:    "trailing globstar matches the directory itself",
+			pattern: "~/work/**",
+			cwd:     filepath.Join(home, "work"),
+			want:    true,
+		},
+		{
+			name:    "mid-pattern globstar matches one level",
+			pattern: "~/projects/**/frontend",
+			cwd:     filepath.Join(home, "projects/shop/frontend"),
+			want:    true,
+		},
+		{
+			name:    "mid-pattern globstar matches many levels",
+			pattern: "~/projects/**/frontend",
+			cwd:     filepath.Join(home, "projects/acme/shop/web/frontend"),
+			want:    true,
+		},
+		{
+			name:    "mid-pattern globstar matches zero levels",
+			pattern: "~/projects/**/frontend",
+			cwd:     filepath.Join(home, "projects/frontend"),
+			want:    true,
+		},
+		{
+			name:    "mid-pattern globstar needs the suffix",
+			pattern: "~/projects/**/frontend",
+			cwd:     filepath.Join(home, "projects/shop/backend"),
+			want:    false,
+		},
+		{
+			name:    "mid-pattern globstar does not match below the suffix",
+			pattern: "~/projects/**/frontend",
+			cwd:     filepath.Join(home, "projects/shop/frontend/src"),
+			want:    false,
+		},
+		{
+			name:    "mid and trailing globstar match below the suffix",
+			pattern: "~/projects/**/frontend/**",
+			cwd:     filepath.Join(home, "projects/shop/frontend/src/components"),
+			want:    true,
+		},
+		{
+			name:    "multiple mid-pattern globstars",
+			pattern: "/srv/**/team-*/**/app",
+			cwd:     "/srv/git/team-web/clients/acme/app",
+			want:    true,
+		},
+		{
+			name:    "multiple mid-pattern globstars need every literal",
+			pattern: "/srv/**/team-*/**/app",
+			cwd:     "/srv/git/clients/acme/app",
+			want:    false,
+		},
+		{
+			name:    "globstar does not match a partial segment",
+			pattern: "~/projects/**/frontend",
+			cwd:     filepath.Join(home, "projects/shop/my-frontend"),
+			want:    false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -137,6 +199,89 @@ func TestMatchDirectory(t *testing.T) {
 	}
 }
 
+// setCaseInsensitivePaths simulates a platform with case-insensitive paths
+// (macOS, Windows) or case-sensitive ones (Linux) for the duration of a test
+func setCaseInsensitivePaths(t *testing.T, insensitive bool) {
+	t.Helper()
+	original := caseInsensitivePaths
+	caseInsensitivePaths = insensitive
+	t.Cleanup(func() { caseInsensitivePaths = original })
+}
+
+func TestMatchDirectory_Case(t *testing.T) {
+	tests := []struct {
+		name        string
+		insensitive bool
+		rule        Rule
+		cwd         string
+		want        bool
+	}{
+		{
+			name:        "case-sensitive platform requires exact case",
+			insensitive: false,
+			rule:        Rule{Type: DirectoryRule, Pattern: "/Users/me/Work/**"},
+			cwd:         "/Users/me/work/project",
+			want:        false,
+		},
+		{
+			name:        "case-sensitive platform matches same case",
+			insensitive: false,
+			rule:        Rule{Type: DirectoryRule, Pattern: "/Users/me/Work/**"},
+			cwd:         "/Users/me/Work/project",
+			want:        true,
+		},
+		{
+			name:        "case-insensitive platform ignores pattern case",
+			insensitive: true,
+			rule:        Rule{Type: DirectoryRule, Pattern: "/Users/me/Work/**"},
+			cwd:         "/Users/me/work/project",
+			want:        true,
+		},
+		{
+			name:        "case-insensitive platform ignores path case",
+			insensitive: true,
+			rule:        Rule{Type: DirectoryRule, Pattern: "C:/Users/me/work/**"},
+			cwd:         "c:/USERS/Me/Work/Project",
+			want:        true,
+		},
+		{
+			name:        "case-insensitive platform still needs the same path",
+			insensitive: true,
+			rule:        Rule{Type: DirectoryRule, Pattern: "/Users/me/Work/**"},
+			cwd:         "/Users/me/Personal/project",
+			want:        false,
+		},
+		{
+			name:        "ignore_case rule on case-sensitive platform",
+			insensitive: false,
+			rule:        Rule{Type: DirectoryRule, Pattern: "/mnt/share/Work/**", IgnoreCase: true},
+			cwd:         "/mnt/share/WORK/project",
+			want:        true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setCaseInsensitivePaths(t, tt.insensitive)
+
+			if got := tt.rule.Matches(tt.cwd, ""); got != tt.want {
+				t.Errorf("Rule{%q, IgnoreCase: %v}.Matches(%q) = %v, want %v",
+					tt.rule.Pattern, tt.rule.IgnoreCase, tt.cwd, got, tt.want)
+			}
+
+			if !tt.rule.IgnoreCase {
+				got, err := MatchDirectory(tt.rule.Pattern, tt.cwd)
+				if err != nil {
+					t.Fatalf("MatchDirectory() returned error: %v", err)
+				}
+				if got != tt.want {
+					t.Errorf("MatchDirectory(%q, %q) = %v, want %v", tt.rule.Pattern, tt.cwd, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
 func TestParseRemote(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -145,6 +290,8 @@ func TestParseRemote(t *testing.T) {
 		wantOrg  string
 		wantRepo string
 		wantErr  bool
+
+		wantProject string
 	}{
 		{
 			name:     "SSH URL",
@@ -175,11 +322,50 @@ func TestParseRemote(t *testing.T) {
 			wantRepo: "project",
 		},
 		{
-			name:     "Azure DevOps SSH",
-			rawURL:   "git@ssh.dev.azure.com:v3/org/project/repo",
-			wantHost: "ssh.dev.azure.com",
-			wantOrg:  "v3",
-			wantRepo: "org",
+			name:        "Azure DevOps SSH",
+			rawURL:      "git@ssh.dev.azure.com:v3/org/project/repo",
+			wantHost:    "ssh.dev.azure.com",
+			wantOrg:     "org",
+			wantRepo:    "repo",
+			wantProject: "project",
+		},
+		{
+			name:        "Azure DevOps HTTPS",
+			rawURL:      "https://user@dev.azure.com/org/project/_git/repo",
+			wantHost:    "dev.azure.com",
+			wantOrg:     "org",
+			wantRepo:    "repo",
+			wantProject: "project",
+		},
+		{
+			name:        "Azure DevOps legacy HTTPS",
+			rawURL:      "https://org.visualstudio.com/DefaultCollection/project/_git/repo",
+			wantHost:    "org.visualstudio.com",
+			wantOrg:     "org",
+			wantRepo:    "repo",
+			wantProject: "project",
+		},
+		{
+			name:        "Azure DevOps legacy SSH",
+			rawURL:      "org@vs-ssh.visualstudio.com:v3/org/project/repo",
+			wantHost:    "vs-ssh.visualstudio.com",
+			wantOrg:     "org",
+			wantRepo:    "repo",
+			wantProject: "project",
+		},
+		{
+			name:     "SSH URL with port",
+			rawURL:   "ssh://git@git.company.com:2222/team/repo.git",
+			wantHost: "git.company.com",
+			wantOrg:  "team",
+			wantRepo: "repo",
+		},
+		{
+			name:     "SSH URL without port",
+			rawURL:   "ssh://git@github.com/company/repo.git",
+			wantHost: "github.com",
+			wantOrg:  "company",
+			wantRepo: "repo",
 		},
 		{
 			name:     "Host uppercase normalized",
@@ -209,6 +395,9 @@ func TestParseRemote(t *testing.T) {
 			if got.Repo != tt.wantRepo {
 				t.Errorf("ParseRemote() Repo = %q, want %q", got.Repo, tt.wantRepo)
 			}
+			if got.Project != tt.wantProject {
+				t.Errorf("ParseRemote() Project = %q, want %q", got.Project, tt.wantProject)
+			}
 		})
 	}
 }
@@ -286,6 +475,35 @@ func TestMatchRemote(t *testing.T) {
 	}
 }
 
+func TestMatchRemote_AzureDevOps(t *testing.T) {
+	remote, err := ParseRemote("https://dev.azure.com/contoso/webapp/_git/frontend")
+	if err != nil {
+		t.Fatalf("ParseRemote() error = %v", err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		// Patterns naming the project, as written before it was parsed
+		{"dev.azure.com/contoso/webapp", true},
+		{"dev.azure.com/contoso/*", true},
+		{"dev.azure.com/contoso", true},
+		// Patterns over the full path
+		{"dev.azure.com/contoso/webapp/frontend", true},
+		{"dev.azure.com/contoso/webapp/*", true},
+		{"dev.azure.com/contoso/webapp/backend", false},
+		{"dev.azure.com/contoso/other", false},
+		{"dev.azure.com/fabrikam/*", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchRemote(tt.pattern, remote); got != tt.want {
+			t.Errorf("MatchRemote(%q, %+v) = %v, want %v", tt.pattern, remote, got, tt.want)
+		}
+	}
+}
+
 func TestSpecificity(t *testing.T) {
 	tests := []struct {
 		name string
@@ -340,6 +558,53 @@ func TestSpecificity(t *testing.T) {
 		t.Error("Exact remote should have higher specificity than wildcard remote")
 	}
 
+	exactBranch := Rule{Type: BranchRule, Pattern: "release/1.0"}
+	wildcardBranch := Rule{Type: BranchRule, Pattern: "release/*"}
+	doubleStarBranch := Rule{Type: BranchRule, Pattern: "release/**"}
+
+	if exactBranch.Specificity() <= wildcardBranch.Specificity() {
+		t.Error("Exact branch should have higher specificity than wildcard branch")
+	}
+	if wildcardBranch.Specificity() <= doubleStarBranch.Specificity() {
+		t.Error("Single wildcard branch should have higher specificity than double star")
+	}
+	exactEmail := Rule{Type: EmailRule, Pattern: "me@company.com"}
+	domainEmail := Rule{Type: EmailRule, Pattern: "*@company.com"}
+	rootDir := Rule{Type: DirectoryRule, Pattern: "/**"}
+	hostRemote := Rule{Type: RemoteRule, Pattern: "github.com/*"}
+
+	if exactEmail.Specificity() <= domainEmail.Specificity() {
+		t.Error("Exact email should have higher specificity than domain wildcard")
+	}
+	if exactEmail.Specificity() >= rootDir.Specificity() ||
+		exactEmail.Specificity() >= hostRemote.Specificity() {
+		t.Error("Email rules should rank below directory and remote rules")
+	}
+
+	if doubleStarBranch.Specificity() <= exactRemote.Specificity() ||
+		doubleStarBranch.Specificity() <= deepDir.Specificity() {
+		t.Error("Branch rules should outrank directory and remote rules")
+	}
+
+	// Each globstar widens the match, so extra globstars lower the score
+	midGlobstar := Rule{Type: DirectoryRule, Pattern: "~/projects/**/frontend"}
+	midAndTrailing := Rule{Type: DirectoryRule, Pattern: "~/projects/**/frontend/**"}
+	trailingOnly := Rule{Type: DirectoryRule, Pattern: "~/projects/**"}
+	leadingGlobstar := Rule{Type: DirectoryRule, Pattern: "~/**/frontend/**"}
+
+	if midGlobstar.Specificity() <= trailingOnly.Specificity() {
+		t.Error("A literal suffix after ** should be more specific than a trailing **")
+	}
+	if midAndTrailing.Specificity() >= midGlobstar.Specificity() {
+		t.Error("A second globstar should lower specificity")
+	}
+	if midAndTrailing.Specificity() <= trailingOnly.Specificity() {
+		t.Error("~/projects/**/frontend/** should be more specific than ~/projects/**")
+	}
+	if leadingGlobstar.Specificity() >= trailingOnly.Specificity() {
+		t.Error("~/**/frontend/** should be less specific than ~/projects/**")
+	}
+
 	// Log actual values for debugging
 	for _, tt := range tests {
 		t.Logf("%s: specificity = %d", tt.name, tt.rule.Specificity())
@@ -432,6 +697,47 @@ func TestFindBestMatch(t *testing.T) {
 	}
 }
 
+func TestFindMatches(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []Rule{
+		{Type: DirectoryRule, Pattern: "~/work/**", Identity: "work"},
+		{Type: DirectoryRule, Pattern: "~/work/company/**", Identity: "company"},
+		{Type: DirectoryRule, Pattern: "~/personal/**", Identity: "personal"},
+		{Type: RemoteRule, Pattern: "github.com/company/*", Identity: "company-remote"},
+	}
+
+	cwd := filepath.Join(home, "work/company/project")
+	remoteURL := "git@github.com:company/project.git"
+
+	matches := FindMatches(rules, cwd, remoteURL)
+	if len(matches) != 3 {
+		t.Fatalf("FindMatches() returned %d rules, want 3", len(matches))
+	}
+
+	// Ordered by descending specificity
+	for i := 1; i < len(matches); i++ {
+		if matches[i].Specificity() > matches[i-1].Specificity() {
+			t.Errorf("matches not sorted: %q (%d) after %q (%d)",
+				matches[i].Pattern, matches[i].Specificity(),
+				matches[i-1].Pattern, matches[i-1].Specificity())
+		}
+	}
+
+	// First match agrees with FindBestMatch
+	best := FindBestMatch(rules, cwd, remoteURL)
+	if best == nil || matches[0] != *best {
+		t.Errorf("FindMatches()[0] = %v, want %v", matches[0], best)
+	}
+
+	if got := FindMatches(rules, "/opt/random", ""); len(got) != 0 {
+		t.Errorf("FindMatches() = %v, want none", got)
+	}
+}
+
 func TestValidatePattern(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -458,6 +764,36 @@ func TestValidatePattern(t *testing.T) {
 			rule:    Rule{Type: RemoteRule, Pattern: "github.com"},
 			wantErr: true,
 		},
+		{
+			name:    "valid branch pattern",
+			rule:    Rule{Type: BranchRule, Pattern: "release/*"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid branch pattern - whitespace",
+			rule:    Rule{Type: BranchRule, Pattern: "release candidate"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid branch pattern - bad glob",
+			rule:    Rule{Type: BranchRule, Pattern: "release/[abc"},
+			wantErr: true,
+		},
+		{
+			name:    "valid email pattern",
+			rule:    Rule{Type: EmailRule, Pattern: "*@company.com"},
+			wantErr: false,
+		},
+		{
+			name:    "invalid email pattern - no @",
+			rule:    Rule{Type: EmailRule, Pattern: "company.com"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid email pattern - bad glob",
+			rule:    Rule{Type: EmailRule, Pattern: "[a@company.com"},
+			wantErr: true,
+		},
 		{
 			name:    "invalid rule type",
 			rule:    Rule{Type: "invalid", Pattern: "test"},
@@ -492,4 +828,262 @@ func TestRuleHelpers(t *testing.T) {
 	if !remoteRule.IsRemote() {
 		t.Error("RemoteRule.IsRemote() should return true")
 	}
+
+	emailRule := Rule{Type: EmailRule}
+	if !emailRule.IsEmail() {
+		t.Error("EmailRule.IsEmail() should return true")
+	}
+
+	branchRule := Rule{Type: BranchRule}
+	if !branchRule.IsBranch() {
+		t.Error("BranchRule.IsBranch() should return true")
+	}
+	if remoteRule.IsBranch() {
+		t.Error("RemoteRule.IsBranch() should return false")
+	}
+}
+
+func TestMatchBranch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		branch  string
+		want    bool
+	}{
+		{"main", "main", true},
+		{"main", "master", false},
+		{"release/*", "release/1.0", true},
+		{"release/*", "release/1.0/hotfix", false},
+		{"release/**", "release/1.0/hotfix", true},
+		{"feature/*", "Feature/login", false},
+		{"*", "develop", true},
+		{"release/*", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.branch, func(t *testing.T) {
+			got, err := MatchBranch(tt.pattern, tt.branch)
+			if err != nil {
+				t.Fatalf("MatchBranch() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("MatchBranch(%q, %q) = %v, want %v", tt.pattern, tt.branch, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchEmail(t *testing.T) {
+	tests := []struct {
+		pattern string
+		email   string
+		want    bool
+	}{
+		{"*@company.com", "me@company.com", true},
+		{"*@company.com", "Me@Company.COM", true},
+		{"*@company.com", "me@company.org", false},
+		{"*@*.company.com", "me@eng.company.com", true},
+		{"*@*.company.com", "me@company.com", false},
+		{"me@company.com", "me@company.com", true},
+		{"*@company.com", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"_"+tt.email, func(t *testing.T) {
+			if got := MatchEmail(tt.pattern, tt.email); got != tt.want {
+				t.Errorf("MatchEmail(%q, %q) = %v, want %v", tt.pattern, tt.email, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindBestMatch_Email(t *testing.T) {
+	dir := t.TempDir()
+	globalConfig := filepath.Join(dir, ".gitconfig")
+	if err := os.WriteFile(globalConfig, []byte("[user]\n\temail = me@company.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", globalConfig)
+
+	rules := []Rule{
+		{Type: EmailRule, Pattern: "*@company.com", Identity: "work"},
+		{Type: EmailRule, Pattern: "*@personal.dev", Identity: "personal"},
+	}
+
+	result := FindBestMatch(rules, dir, "")
+	if result == nil || result.Identity != "work" {
+		t.Fatalf("FindBestMatch() = %v, want work", result)
+	}
+
+	// A matching directory rule takes precedence
+	rules = append(rules, Rule{Type: DirectoryRule, Pattern: filepath.Join(dir, "**"), Identity: "personal"})
+	result = FindBestMatch(rules, dir, "")
+	if result == nil || result.Identity != "personal" {
+		t.Errorf("FindBestMatch() = %v, want personal", result)
+	}
+}
+
+func TestFindBestMatch_Branch(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(dir, ".gitconfig"))
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "release/2.0"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+
+	branch, err := GetCurrentBranchIn(dir)
+	if err != nil {
+		t.Fatalf("GetCurrentBranchIn() error = %v", err)
+	}
+	if branch != "release/2.0" {
+		t.Fatalf("GetCurrentBranchIn() = %q, want %q", branch, "release/2.0")
+	}
+
+	rules := []Rule{
+		{Type: DirectoryRule, Pattern: filepath.Join(dir, "**"), Identity: "personal"},
+		{Type: BranchRule, Pattern: "release/*", Identity: "work"},
+		{Type: BranchRule, Pattern: "feature/*", Identity: "other"},
+	}
+
+	result := FindBestMatch(rules, dir, "")
+	if result == nil {
+		t.Fatal("FindBestMatch() = nil, want a match")
+	}
+	if result.Identity != "work" {
+		t.Errorf("FindBestMatch().Identity = %q, want %q", result.Identity, "work")
+	}
+
+	// Outside a repository, branch rules never match
+	if result := FindBestMatch(rules[1:], t.TempDir(), ""); result != nil {
+		t.Errorf("FindBestMatch() = %v, want nil outside a repository", result)
+	}
+}
+
+func TestFindBestMatch_Priority(t *testing.T) {
+	// Equal specificity: both patterns have the same shape
+	equal := []Rule{
+		{Type: DirectoryRule, Pattern: "/srv/*/app/**", Identity: "first"},
+		{Type: DirectoryRule, Pattern: "/srv/team/*/**", Identity: "second"},
+	}
+	if equal[0].Specificity() != equal[1].Specificity() {
+		t.Fatalf("test rules must tie on specificity: %d vs %d", equal[0].Specificity(), equal[1].Specificity())
+	}
+
+	cwd := "/srv/team/app/src"
+
+	// Without priorities the first rule in config order wins
+	if got := FindBestMatch(equal, cwd, ""); got == nil || got.Identity != "first" {
+		t.Fatalf("FindBestMatch() = %v, want first", got)
+	}
+
+	// Higher priority wins the tie
+	equal[1].Priority = 5
+	if got := FindBestMatch(equal, cwd, ""); got == nil || got.Identity != "second" {
+		t.Fatalf("FindBestMatch() = %v, want second", got)
+	}
+
+	matches := FindMatches(equal, cwd, "")
+	if len(matches) != 2 || matches[0].Identity != "second" {
+		t.Errorf("FindMatches() = %v, want second first", matches)
+	}
+}
+
+func TestFindBestMatch_PriorityOverridesSpecificity(t *testing.T) {
+	rules := []Rule{
+		{Type: DirectoryRule, Pattern: "/srv/**", Identity: "general", Priority: 1},
+		{Type: DirectoryRule, Pattern: "/srv/team/app/**", Identity: "specific"},
+	}
+
+	got := FindBestMatch(rules, "/srv/team/app", "")
+	if got == nil || got.Identity != "general" {
+		t.Errorf("FindBestMatch() = %v, want general", got)
+	}
+}
+
+func TestDirectoryPattern(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{filepath.Join(home, "work", "project"), "~/work/project/**"},
+		{home, "~/**"},
+		{"/opt/src", "/opt/src/**"},
+		{"/opt/src/", "/opt/src/**"},
+	}
+
+	for _, tt := range tests {
+		if got := DirectoryPattern(tt.dir); got != tt.want {
+			t.Errorf("DirectoryPattern(%q) = %q, want %q", tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestParsedRemote_OrgPattern(t *testing.T) {
+	remote, err := ParseRemote("git@github.com:company/repo.git")
+	if err != nil {
+		t.Fatalf("ParseRemote failed: %v", err)
+	}
+	if got := remote.OrgPattern(); got != "github.com/company/*" {
+		t.Errorf("OrgPattern() = %q, want %q", got, "github.com/company/*")
+	}
+
+	if got := (&ParsedRemote{Host: "github.com"}).OrgPattern(); got != "" {
+		t.Errorf("OrgPattern() without org = %q, want empty", got)
+	}
+}
+
+func TestFindRepoRemotes(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(root, ".gitconfig"))
+
+	initRepo := func(dir, remote string) {
+		t.Helper()
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		cmds := [][]string{{"init", "-q"}}
+		if remote != "" {
+			cmds = append(cmds, []string{"remote", "add", "origin", remote})
+		}
+		for _, args := range cmds {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = dir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v failed: %v\n%s", args, err, out)
+			}
+		}
+	}
+
+	initRepo(filepath.Join(root, "work", "api"), "git@github.com:company/api.git")
+	initRepo(filepath.Join(root, "work", "web"), "https://github.com/company/web.git")
+	initRepo(filepath.Join(root, "oss", "tool"), "git@gitlab.com:me/tool.git")
+	initRepo(filepath.Join(root, "scratch"), "")
+	// Nested and hidden repositories are not searched
+	initRepo(filepath.Join(root, "work", "api", "vendor", "lib"), "git@github.com:other/lib.git")
+	initRepo(filepath.Join(root, ".cache", "repo"), "git@github.com:hidden/repo.git")
+
+	repos, err := FindRepoRemotes(root)
+	if err != nil {
+		t.Fatalf("FindRepoRemotes() error = %v", err)
+	}
+	if len(repos) != 3 {
+		t.Fatalf("FindRepoRemotes() found %d repos, want 3: %+v", len(repos), repos)
+	}
+
+	groups := GroupByOrg(repos)
+	want := []RemoteGroup{
+		{Pattern: "github.com/company/*", Dirs: []string{filepath.Join(root, "work", "api"), filepath.Join(root, "work", "web")}},
+		{Pattern: "gitlab.com/me/*", Dirs: []string{filepath.Join(root, "oss", "tool")}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupByOrg() = %+v, want %+v", groups, want)
+	}
 }
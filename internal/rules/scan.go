@@ -0,0 +1,83 @@
+package rules
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RepoRemote is a git repository found by FindRepoRemotes
+type RepoRemote struct {
+	Dir    string
+	URL    string
+	Remote *ParsedRemote
+}
+
+// RemoteGroup is a set of repositories sharing a host/org remote pattern
+type RemoteGroup struct {
+	Pattern string   // e.g., "github.com/company/*"
+	Dirs    []string // repositories matching the pattern
+}
+
+// FindRepoRemotes walks root for git repositories and returns those with a
+// parseable origin remote, sorted by directory. Hidden directories and
+// repositories nested inside other repositories are not searched.
+func FindRepoRemotes(root string) ([]RepoRemote, error) {
+	var repos []RepoRemote
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Unreadable directories are skipped, not fatal
+			if d != nil && d.IsDir() && path != root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		// .git is a directory in clones and a file in worktrees/submodules
+		if _, err := os.Lstat(filepath.Join(path, ".git")); err != nil {
+			return nil
+		}
+
+		if url, err := GetGitRemoteURLIn(path); err == nil && url != "" {
+			if remote, err := ParseRemote(url); err == nil {
+				repos = append(repos, RepoRemote{Dir: path, URL: url, Remote: remote})
+			}
+		}
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return repos, nil
+}
+
+// GroupByOrg groups repositories by their org remote pattern, sorted by
+// pattern. Remotes without an org (e.g. bare hosts) are left out.
+func GroupByOrg(repos []RepoRemote) []RemoteGroup {
+	byPattern := make(map[string][]string)
+	for _, repo := range repos {
+		pattern := repo.Remote.OrgPattern()
+		if pattern == "" {
+			continue
+		}
+		byPattern[pattern] = append(byPattern[pattern], repo.Dir)
+	}
+
+	groups := make([]RemoteGroup, 0, len(byPattern))
+	for pattern, dirs := range byPattern {
+		groups = append(groups, RemoteGroup{Pattern: pattern, Dirs: dirs})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Pattern < groups[j].Pattern
+	})
+	return groups
+}
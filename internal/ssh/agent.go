@@ -8,14 +8,19 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
 )
 
-// IsAgentRunning checks if ssh-agent is running and accessible.
+// ErrAgentNotRunning is returned when SSH_AUTH_SOCK is unset or no agent
+// answers on it
+var ErrAgentNotRunning = errors.New("ssh-agent not running. Start it with: eval $(ssh-agent)")
+
+// IsAgentAvailable checks if ssh-agent is running and accessible.
 // Returns true if SSH_AUTH_SOCK is set and the socket is reachable.
-func IsAgentRunning() bool {
+func IsAgentAvailable() bool {
 	socket := os.Getenv("SSH_AUTH_SOCK")
 	if socket == "" {
 		return false
@@ -29,24 +34,148 @@ func IsAgentRunning() bool {
 	return true
 }
 
+// AgentKey describes a key loaded in ssh-agent
+type AgentKey struct {
+	Type        string // key algorithm, e.g. "ssh-ed25519"
+	Fingerprint string // SHA256 fingerprint, e.g. "SHA256:..."
+	Comment     string
+}
+
+// ListAgentKeys returns the keys currently loaded in the running ssh-agent.
+func ListAgentKeys() ([]AgentKey, error) {
+	if !IsAgentAvailable() {
+		return nil, ErrAgentNotRunning
+	}
+
+	conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+	defer conn.Close()
+
+	keys, err := agent.NewClient(conn).List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list agent keys: %w", err)
+	}
+
+	result := make([]AgentKey, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, AgentKey{
+			Type:        key.Type(),
+			Fingerprint: ssh.FingerprintSHA256(key),
+			Comment:     key.Comment,
+		})
+	}
+
+	return result, nil
+}
+
+// KeyFingerprint returns the SHA256 fingerprint of the key at privateKeyPath.
+// Reads the ".pub" file next to it, falling back to the private key itself
+// when it is unencrypted.
+func KeyFingerprint(privateKeyPath string) (string, error) {
+	pubKey, err := loadPublicKey(privateKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	return ssh.FingerprintSHA256(pubKey), nil
+}
+
+// RemoveKeyFromAgent removes the key at privateKeyPath from the running
+// ssh-agent. Succeeds without changes if the key is not loaded.
+func RemoveKeyFromAgent(privateKeyPath string) error {
+	if !IsAgentAvailable() {
+		return ErrAgentNotRunning
+	}
+
+	pubKey, err := loadPublicKey(privateKeyPath)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.Dial("unix", os.Getenv("SSH_AUTH_SOCK"))
+	if err != nil {
+		return fmt.Errorf("failed to connect to ssh-agent: %w", err)
+	}
+	defer conn.Close()
+
+	agentClient := agent.NewClient(conn)
+
+	// Agents report an error for keys they don't hold, so check first
+	keys, err := agentClient.List()
+	if err != nil {
+		return fmt.Errorf("failed to list agent keys: %w", err)
+	}
+
+	fingerprint := ssh.FingerprintSHA256(pubKey)
+	for _, key := range keys {
+		if ssh.FingerprintSHA256(key) == fingerprint {
+			if err := agentClient.Remove(pubKey); err != nil {
+				return fmt.Errorf("failed to remove key from agent: %w", err)
+			}
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// loadPublicKey reads the public key for a private key path from its ".pub"
+// file, or from the private key itself when it is unencrypted.
+func loadPublicKey(privateKeyPath string) (ssh.PublicKey, error) {
+	path, err := ExpandPath(privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if pubData, err := os.ReadFile(path + ".pub"); err == nil {
+		pubKey, _, _, _, err := ssh.ParseAuthorizedKey(pubData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		return pubKey, nil
+	}
+
+	keyData, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return signer.PublicKey(), nil
+}
+
 // AddKeyToAgent adds an SSH key to the running ssh-agent.
 // On macOS, uses /usr/bin/ssh-add with --apple-use-keychain for Keychain integration.
 // On other platforms, uses standard ssh-add.
 // This method uses exec to shell out, allowing passphrase prompts to work interactively.
-func AddKeyToAgent(keyPath string) error {
-	if !IsAgentRunning() {
-		return errors.New("ssh-agent not running. Start it with: eval $(ssh-agent)")
+// A positive lifetime (seconds) is passed as ssh-add -t, so the agent itself
+// drops the key when it expires; zero means no expiry.
+func AddKeyToAgent(keyPath string, lifetime int) error {
+	if !IsAgentAvailable() {
+		return ErrAgentNotRunning
+	}
+
+	var args []string
+	if lifetime > 0 {
+		args = append(args, "-t", strconv.Itoa(lifetime))
 	}
+	args = append(args, keyPath)
 
 	var cmd *exec.Cmd
 	if runtime.GOOS == "darwin" {
 		// macOS: Use system ssh-add with Keychain integration
 		// CRITICAL: Use full path /usr/bin/ssh-add to avoid Homebrew's ssh-add
 		// which may not support --apple-use-keychain
-		cmd = exec.Command("/usr/bin/ssh-add", "--apple-use-keychain", keyPath)
+		cmd = exec.Command("/usr/bin/ssh-add", append([]string{"--apple-use-keychain"}, args...)...)
 	} else {
 		// Linux and other platforms: Use standard ssh-add
-		cmd = exec.Command("ssh-add", keyPath)
+		cmd = exec.Command("ssh-add", args...)
 	}
 
 	// Connect stdin/stdout/stderr for interactive passphrase prompt
@@ -59,10 +188,10 @@ func AddKeyToAgent(keyPath string) error {
 
 // AddKeyToAgentWithPassphrase adds an SSH key to the agent programmatically.
 // If passphrase is nil or empty and the key requires one, falls back to AddKeyToAgent
-// to allow interactive passphrase prompting.
-func AddKeyToAgentWithPassphrase(keyPath string, passphrase []byte) error {
-	if !IsAgentRunning() {
-		return errors.New("ssh-agent not running. Start it with: eval $(ssh-agent)")
+// to allow interactive passphrase prompting. Lifetime is as for AddKeyToAgent.
+func AddKeyToAgentWithPassphrase(keyPath string, passphrase []byte, lifetime int) error {
+	if !IsAgentAvailable() {
+		return ErrAgentNotRunning
 	}
 
 	socket := os.Getenv("SSH_AUTH_SOCK")
@@ -89,7 +218,7 @@ func AddKeyToAgentWithPassphrase(keyPath string, passphrase []byte) error {
 		// If the key needs a passphrase, fall back to shell method for interactive prompt
 		var passErr *ssh.PassphraseMissingError
 		if errors.As(err, &passErr) {
-			return AddKeyToAgent(keyPath)
+			return AddKeyToAgent(keyPath, lifetime)
 		}
 		return fmt.Errorf("failed to parse private key: %w", err)
 	}
@@ -98,7 +227,8 @@ func AddKeyToAgentWithPassphrase(keyPath string, passphrase []byte) error {
 	agentClient := agent.NewClient(conn)
 	comment := filepath.Base(keyPath)
 	return agentClient.Add(agent.AddedKey{
-		PrivateKey: privKey,
-		Comment:    comment,
+		PrivateKey:   privKey,
+		Comment:      comment,
+		LifetimeSecs: uint32(lifetime),
 	})
 }
@@ -1,32 +1,39 @@
 package ssh
 
 import (
+	"errors"
+	"net"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
 )
 
-func TestIsAgentRunning(t *testing.T) {
+func TestIsAgentAvailable(t *testing.T) {
 	// This test depends on the environment - it will pass if ssh-agent is running
 	// and fail if it's not. We test both code paths.
 	socket := os.Getenv("SSH_AUTH_SOCK")
 
 	t.Run("matches environment", func(t *testing.T) {
-		result := IsAgentRunning()
+		result := IsAgentAvailable()
 
 		if socket == "" {
 			// No SSH_AUTH_SOCK set - should return false
 			if result {
-				t.Error("IsAgentRunning() returned true when SSH_AUTH_SOCK is not set")
+				t.Error("IsAgentAvailable() returned true when SSH_AUTH_SOCK is not set")
 			}
 		} else {
 			// SSH_AUTH_SOCK is set - result depends on whether the socket is reachable
 			// Just verify we don't panic
-			t.Logf("IsAgentRunning() = %v (SSH_AUTH_SOCK=%s)", result, socket)
+			t.Logf("IsAgentAvailable() = %v (SSH_AUTH_SOCK=%s)", result, socket)
 		}
 	})
 }
 
-func TestIsAgentRunning_NoSocket(t *testing.T) {
+func TestIsAgentAvailable_NoSocket(t *testing.T) {
 	// Save and clear the socket
 	original := os.Getenv("SSH_AUTH_SOCK")
 	os.Unsetenv("SSH_AUTH_SOCK")
@@ -36,12 +43,12 @@ func TestIsAgentRunning_NoSocket(t *testing.T) {
 		}
 	}()
 
-	if IsAgentRunning() {
-		t.Error("IsAgentRunning() returned true when SSH_AUTH_SOCK is unset")
+	if IsAgentAvailable() {
+		t.Error("IsAgentAvailable() returned true when SSH_AUTH_SOCK is unset")
 	}
 }
 
-func TestIsAgentRunning_InvalidSocket(t *testing.T) {
+func TestIsAgentAvailable_InvalidSocket(t *testing.T) {
 	// Save and set an invalid socket
 	original := os.Getenv("SSH_AUTH_SOCK")
 	os.Setenv("SSH_AUTH_SOCK", "/nonexistent/path/to/socket")
@@ -53,8 +60,8 @@ func TestIsAgentRunning_InvalidSocket(t *testing.T) {
 		}
 	}()
 
-	if IsAgentRunning() {
-		t.Error("IsAgentRunning() returned true for invalid socket path")
+	if IsAgentAvailable() {
+		t.Error("IsAgentAvailable() returned true for invalid socket path")
 	}
 }
 
@@ -68,7 +75,7 @@ func TestAddKeyToAgent_NoAgent(t *testing.T) {
 		}
 	}()
 
-	err := AddKeyToAgent("/some/key/path")
+	err := AddKeyToAgent("/some/key/path", 0)
 	if err == nil {
 		t.Fatal("AddKeyToAgent() should return error when agent not running")
 	}
@@ -77,6 +84,9 @@ func TestAddKeyToAgent_NoAgent(t *testing.T) {
 	if err.Error() != expected {
 		t.Errorf("AddKeyToAgent() error = %q, want %q", err.Error(), expected)
 	}
+	if !errors.Is(err, ErrAgentNotRunning) {
+		t.Errorf("AddKeyToAgent() error = %v, want ErrAgentNotRunning", err)
+	}
 }
 
 func TestAddKeyToAgentWithPassphrase_NoAgent(t *testing.T) {
@@ -89,7 +99,7 @@ func TestAddKeyToAgentWithPassphrase_NoAgent(t *testing.T) {
 		}
 	}()
 
-	err := AddKeyToAgentWithPassphrase("/some/key/path", nil)
+	err := AddKeyToAgentWithPassphrase("/some/key/path", nil, 0)
 	if err == nil {
 		t.Fatal("AddKeyToAgentWithPassphrase() should return error when agent not running")
 	}
@@ -102,11 +112,11 @@ func TestAddKeyToAgentWithPassphrase_NoAgent(t *testing.T) {
 
 func TestAddKeyToAgentWithPassphrase_KeyNotFound(t *testing.T) {
 	// Skip if no agent running
-	if !IsAgentRunning() {
+	if !IsAgentAvailable() {
 		t.Skip("ssh-agent not running, skipping test")
 	}
 
-	err := AddKeyToAgentWithPassphrase("/nonexistent/key/path", nil)
+	err := AddKeyToAgentWithPassphrase("/nonexistent/key/path", nil, 0)
 	if err == nil {
 		t.Fatal("AddKeyToAgentWithPassphrase() should return error for nonexistent key")
 	}
@@ -119,7 +129,7 @@ func TestAddKeyToAgentWithPassphrase_KeyNotFound(t *testing.T) {
 
 func TestAddKeyToAgentWithPassphrase_ValidKey(t *testing.T) {
 	// Skip if no agent running
-	if !IsAgentRunning() {
+	if !IsAgentAvailable() {
 		t.Skip("ssh-agent not running, skipping test")
 	}
 
@@ -137,8 +147,183 @@ func TestAddKeyToAgentWithPassphrase_ValidKey(t *testing.T) {
 	}
 
 	// Add to agent
-	err = AddKeyToAgentWithPassphrase(keyPath, nil)
+	err = AddKeyToAgentWithPassphrase(keyPath, nil, 0)
 	if err != nil {
 		t.Errorf("AddKeyToAgentWithPassphrase() error = %v", err)
 	}
 }
+
+// startTestAgent serves an in-memory keyring on a temp unix socket
+// and points SSH_AUTH_SOCK at it for the duration of the test.
+func startTestAgent(t *testing.T) agent.Agent {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	keyring := agent.NewKeyring()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = agent.ServeAgent(keyring, conn)
+			}()
+		}
+	}()
+
+	t.Setenv("SSH_AUTH_SOCK", socket)
+	return keyring
+}
+
+func TestListAgentKeys(t *testing.T) {
+	keyring := startTestAgent(t)
+
+	keys, err := ListAgentKeys()
+	if err != nil {
+		t.Fatalf("ListAgentKeys() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("ListAgentKeys() returned %d keys, want 0", len(keys))
+	}
+
+	// Add a key and compare fingerprints with the key file
+	tmpDir := t.TempDir()
+	keyPath := filepath.Join(tmpDir, "test_key")
+	priv, pub, err := GenerateKeyPair("test@example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	if err := WriteKeyFiles(keyPath, priv, pub); err != nil {
+		t.Fatalf("Failed to write key files: %v", err)
+	}
+
+	rawKey, err := ssh.ParseRawPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("Failed to parse private key: %v", err)
+	}
+	if err := keyring.Add(agent.AddedKey{PrivateKey: rawKey, Comment: "test_key"}); err != nil {
+		t.Fatalf("Failed to add key to agent: %v", err)
+	}
+
+	keys, err = ListAgentKeys()
+	if err != nil {
+		t.Fatalf("ListAgentKeys() error = %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("ListAgentKeys() returned %d keys, want 1", len(keys))
+	}
+	if keys[0].Type != "ssh-ed25519" || keys[0].Comment != "test_key" {
+		t.Errorf("ListAgentKeys()[0] = %+v", keys[0])
+	}
+
+	fingerprint, err := KeyFingerprint(keyPath)
+	if err != nil {
+		t.Fatalf("KeyFingerprint() error = %v", err)
+	}
+	if keys[0].Fingerprint != fingerprint {
+		t.Errorf("agent fingerprint %q != key file fingerprint %q", keys[0].Fingerprint, fingerprint)
+	}
+
+	// Without the .pub file, the private key is used
+	if err := os.Remove(keyPath + ".pub"); err != nil {
+		t.Fatal(err)
+	}
+	fromPrivate, err := KeyFingerprint(keyPath)
+	if err != nil {
+		t.Fatalf("KeyFingerprint() without .pub error = %v", err)
+	}
+	if fromPrivate != fingerprint {
+		t.Errorf("KeyFingerprint() from private key = %q, want %q", fromPrivate, fingerprint)
+	}
+}
+
+func TestAddKeyToAgentWithPassphrase_Lifetime(t *testing.T) {
+	startTestAgent(t)
+
+	keyPath := filepath.Join(t.TempDir(), "test_key")
+	priv, pub, err := GenerateKeyPair("test@example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	if err := WriteKeyFiles(keyPath, priv, pub); err != nil {
+		t.Fatalf("Failed to write key files: %v", err)
+	}
+
+	if err := AddKeyToAgentWithPassphrase(keyPath, nil, 1); err != nil {
+		t.Fatalf("AddKeyToAgentWithPassphrase() error = %v", err)
+	}
+
+	keys, err := ListAgentKeys()
+	if err != nil {
+		t.Fatalf("ListAgentKeys() error = %v", err)
+	}
+	if len(keys) != 1 {
+		t.Fatalf("ListAgentKeys() returned %d keys, want 1", len(keys))
+	}
+
+	// The agent drops the key on its own once the lifetime passes
+	time.Sleep(1500 * time.Millisecond)
+
+	keys, err = ListAgentKeys()
+	if err != nil {
+		t.Fatalf("ListAgentKeys() error = %v", err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected key to expire, agent still has %d key(s)", len(keys))
+	}
+}
+
+func TestListAgentKeys_NoAgent(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	if _, err := ListAgentKeys(); err == nil {
+		t.Error("ListAgentKeys() should return error when agent not running")
+	}
+}
+
+func TestRemoveKeyFromAgent(t *testing.T) {
+	keyring := startTestAgent(t)
+
+	tmpDir := t.TempDir()
+	keyPath := filepath.Join(tmpDir, "test_key")
+	priv, pub, err := GenerateKeyPair("test@example.com", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate key pair: %v", err)
+	}
+	if err := WriteKeyFiles(keyPath, priv, pub); err != nil {
+		t.Fatalf("Failed to write key files: %v", err)
+	}
+
+	// Removing a key that isn't loaded is not an error
+	if err := RemoveKeyFromAgent(keyPath); err != nil {
+		t.Fatalf("RemoveKeyFromAgent() for unloaded key error = %v", err)
+	}
+
+	rawKey, err := ssh.ParseRawPrivateKey(priv)
+	if err != nil {
+		t.Fatalf("Failed to parse private key: %v", err)
+	}
+	if err := keyring.Add(agent.AddedKey{PrivateKey: rawKey}); err != nil {
+		t.Fatalf("Failed to add key to agent: %v", err)
+	}
+
+	if err := RemoveKeyFromAgent(keyPath); err != nil {
+		t.Fatalf("RemoveKeyFromAgent() error = %v", err)
+	}
+
+	keys, err := keyring.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("expected key to be removed, agent still has %d key(s)", len(keys))
+	}
+}
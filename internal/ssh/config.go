@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,12 +16,25 @@ const (
 	MarkerEnd   = "# gitch:end"
 )
 
+// ErrMalformedBlock is returned when the SSH config has a gitch start marker
+// but no end marker after it, so the managed block can't be removed safely.
+var ErrMalformedBlock = errors.New("SSH config has a gitch start marker without a matching end marker")
+
 // HostConfig represents an SSH Host block configuration
 type HostConfig struct {
 	Alias        string
 	HostName     string
 	User         string
 	IdentityFile string
+	// ExtraIdentityFiles are further keys ssh offers after IdentityFile
+	ExtraIdentityFiles []string
+	// Port and ProxyJump are emitted only when set
+	Port      int
+	ProxyJump string
+	// HostkeyAlgorithms and PubkeyAcceptedAlgorithms are emitted only when set
+	// (e.g., "+ssh-rsa" for Azure DevOps, which still requires RSA-SHA1)
+	HostkeyAlgorithms        string
+	PubkeyAcceptedAlgorithms string
 }
 
 // String generates an SSH config Host block from the HostConfig
@@ -28,9 +42,24 @@ func (h HostConfig) String() string {
 	var sb strings.Builder
 	sb.WriteString(fmt.Sprintf("Host %s\n", h.Alias))
 	sb.WriteString(fmt.Sprintf("    HostName %s\n", h.HostName))
+	if h.Port != 0 {
+		sb.WriteString(fmt.Sprintf("    Port %d\n", h.Port))
+	}
 	sb.WriteString(fmt.Sprintf("    User %s\n", h.User))
 	sb.WriteString(fmt.Sprintf("    IdentityFile %s\n", h.IdentityFile))
+	for _, file := range h.ExtraIdentityFiles {
+		sb.WriteString(fmt.Sprintf("    IdentityFile %s\n", file))
+	}
 	sb.WriteString("    IdentitiesOnly yes\n")
+	if h.ProxyJump != "" {
+		sb.WriteString(fmt.Sprintf("    ProxyJump %s\n", h.ProxyJump))
+	}
+	if h.HostkeyAlgorithms != "" {
+		sb.WriteString(fmt.Sprintf("    HostkeyAlgorithms %s\n", h.HostkeyAlgorithms))
+	}
+	if h.PubkeyAcceptedAlgorithms != "" {
+		sb.WriteString(fmt.Sprintf("    PubkeyAcceptedAlgorithms %s\n", h.PubkeyAcceptedAlgorithms))
+	}
 	return sb.String()
 }
 
@@ -58,20 +87,44 @@ func GenerateConfigBlock(hosts []HostConfig) string {
 
 // IdentityToHosts converts a config.Identity to SSH HostConfigs
 // Returns nil if the identity has no SSH key configured
-// Generates hosts for both github.com and gitlab.com
+// Generates one host per entry in identity.Hosts, or falls back to
+// github.com, gitlab.com, bitbucket.org and ssh.dev.azure.com when none are set
+// The identity's SSH port, ProxyJump and extra SSH keys apply to every generated host
 func IdentityToHosts(identity config.Identity) []HostConfig {
-	if identity.SSHKeyPath == "" {
+	keys := identity.SSHKeys()
+	if len(keys) == 0 {
 		return nil
 	}
 
-	// Expand the SSH key path
-	expandedPath, err := ExpandPath(identity.SSHKeyPath)
-	if err != nil {
-		// If expansion fails, use the original path
-		expandedPath = identity.SSHKeyPath
+	// Expand the SSH key paths; if expansion fails, use the original path
+	for i, key := range keys {
+		if expanded, err := ExpandPath(key); err == nil {
+			keys[i] = expanded
+		}
+	}
+	expandedPath := keys[0]
+	var extraPaths []string
+	if len(keys) > 1 {
+		extraPaths = keys[1:]
+	}
+
+	if len(identity.Hosts) > 0 {
+		hosts := make([]HostConfig, 0, len(identity.Hosts))
+		for _, host := range identity.Hosts {
+			hosts = append(hosts, HostConfig{
+				Alias:              fmt.Sprintf("%s-%s", hostAliasPrefix(host), identity.Name),
+				HostName:           host,
+				User:               "git",
+				IdentityFile:       expandedPath,
+				Port:               identity.SSHPort,
+				ProxyJump:          identity.ProxyJump,
+				ExtraIdentityFiles: extraPaths,
+			})
+		}
+		return hosts
 	}
 
-	return []HostConfig{
+	hosts := []HostConfig{
 		{
 			Alias:        fmt.Sprintf("github-%s", identity.Name),
 			HostName:     "github.com",
@@ -84,7 +137,38 @@ func IdentityToHosts(identity config.Identity) []HostConfig {
 			User:         "git",
 			IdentityFile: expandedPath,
 		},
+		{
+			Alias:        fmt.Sprintf("bitbucket-%s", identity.Name),
+			HostName:     "bitbucket.org",
+			User:         "git",
+			IdentityFile: expandedPath,
+		},
+		{
+			Alias:        fmt.Sprintf("azure-%s", identity.Name),
+			HostName:     "ssh.dev.azure.com",
+			User:         "git",
+			IdentityFile: expandedPath,
+			// Azure DevOps only supports RSA-SHA1 signatures
+			HostkeyAlgorithms:        "+ssh-rsa",
+			PubkeyAcceptedAlgorithms: "+ssh-rsa",
+		},
 	}
+	for i := range hosts {
+		hosts[i].Port = identity.SSHPort
+		hosts[i].ProxyJump = identity.ProxyJump
+		hosts[i].ExtraIdentityFiles = extraPaths
+	}
+	return hosts
+}
+
+// hostAliasPrefix returns the host with its top-level domain stripped,
+// used as the alias prefix (e.g., "git.company.internal" -> "git.company")
+func hostAliasPrefix(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if idx := strings.LastIndex(host, "."); idx > 0 {
+		return host[:idx]
+	}
+	return host
 }
 
 // removeManagedBlock removes the gitch-managed block from SSH config content
@@ -115,12 +199,68 @@ func removeManagedBlock(content string) string {
 	return content[:startIdx] + content[endOfBlock:]
 }
 
+// extractManagedBlock returns the gitch-managed block in content, from the
+// start marker through the end marker line, or "" if there is none
+func extractManagedBlock(content string) string {
+	startIdx := strings.Index(content, MarkerStart)
+	if startIdx == -1 {
+		return ""
+	}
+
+	endIdx := strings.Index(content[startIdx:], MarkerEnd)
+	if endIdx == -1 {
+		return ""
+	}
+
+	block := content[startIdx : startIdx+endIdx+len(MarkerEnd)]
+	return block + "\n"
+}
+
+// ReadManagedBlock returns the gitch-managed block currently in the user's
+// SSH config, or "" if there is none.
+func ReadManagedBlock() (string, error) {
+	configPath, err := SSHConfigPath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read SSH config: %w", err)
+	}
+
+	return extractManagedBlock(string(data)), nil
+}
+
+// HasManagedBlock reports whether the user's SSH config contains a
+// gitch-managed block, i.e. 'gitch ssh-config update' has been run before
+func HasManagedBlock() (bool, error) {
+	configPath, err := SSHConfigPath()
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read SSH config: %w", err)
+	}
+
+	return strings.Contains(string(data), MarkerStart), nil
+}
+
 // UpdateSSHConfig updates the user's SSH config with the new gitch block
 // Creates backup before modification and writes atomically
-func UpdateSSHConfig(newBlock string) error {
+// Returns false without touching any file if the managed block is unchanged
+func UpdateSSHConfig(newBlock string) (bool, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return false, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
 	sshDir := filepath.Join(home, ".ssh")
@@ -128,7 +268,7 @@ func UpdateSSHConfig(newBlock string) error {
 
 	// Ensure .ssh directory exists with proper permissions
 	if err := os.MkdirAll(sshDir, 0700); err != nil {
-		return fmt.Errorf("failed to create .ssh directory: %w", err)
+		return false, fmt.Errorf("failed to create .ssh directory: %w", err)
 	}
 
 	// Read existing content
@@ -136,18 +276,20 @@ func UpdateSSHConfig(newBlock string) error {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		if !os.IsNotExist(err) {
-			return fmt.Errorf("failed to read SSH config: %w", err)
+			return false, fmt.Errorf("failed to read SSH config: %w", err)
 		}
 		// File doesn't exist - that's ok
 	} else {
 		existingContent = string(data)
 
+		// Nothing to do if the managed block is already current
+		if newBlock != "" && extractManagedBlock(existingContent) == newBlock {
+			return false, nil
+		}
+
 		// Create backup if file has content
-		if len(existingContent) > 0 {
-			backupPath := configPath + ".gitch.backup"
-			if err := os.WriteFile(backupPath, data, 0600); err != nil {
-				return fmt.Errorf("failed to create backup: %w", err)
-			}
+		if err := backupSSHConfig(configPath, data); err != nil {
+			return false, err
 		}
 	}
 
@@ -165,9 +307,71 @@ func UpdateSSHConfig(newBlock string) error {
 		finalContent = cleanedContent + "\n\n" + newBlock
 	}
 
+	if err := writeSSHConfig(configPath, finalContent); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveSSHConfigBlock strips the gitch-managed block from the user's SSH
+// config, keeping a backup. Returns whether a block was found and removed.
+// Returns ErrMalformedBlock, leaving the file untouched, if the end marker
+// is missing.
+func RemoveSSHConfigBlock() (bool, error) {
+	configPath, err := SSHConfigPath()
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read SSH config: %w", err)
+	}
+
+	content := string(data)
+	startIdx := strings.Index(content, MarkerStart)
+	if startIdx == -1 {
+		return false, nil
+	}
+	if !strings.Contains(content[startIdx:], MarkerEnd) {
+		return false, ErrMalformedBlock
+	}
+
+	if err := backupSSHConfig(configPath, data); err != nil {
+		return false, err
+	}
+
+	cleanedContent := strings.TrimRight(removeManagedBlock(content), "\n\t ")
+	if cleanedContent != "" {
+		cleanedContent += "\n"
+	}
+
+	if err := writeSSHConfig(configPath, cleanedContent); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// backupSSHConfig saves data to <configPath>.gitch.backup if it is non-empty
+func backupSSHConfig(configPath string, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	backupPath := configPath + ".gitch.backup"
+	if err := os.WriteFile(backupPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to create backup: %w", err)
+	}
+	return nil
+}
+
+// writeSSHConfig atomically replaces the SSH config with content
+func writeSSHConfig(configPath, content string) error {
 	// Write to temp file first (atomic write)
 	tempPath := configPath + ".tmp"
-	if err := os.WriteFile(tempPath, []byte(finalContent), 0600); err != nil {
+	if err := os.WriteFile(tempPath, []byte(content), 0600); err != nil {
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
 
@@ -1,6 +1,9 @@
 package ssh
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -226,8 +229,8 @@ func TestIdentityToHosts_WithSSHKey(t *testing.T) {
 
 	result := IdentityToHosts(identity)
 
-	if len(result) != 2 {
-		t.Fatalf("Expected 2 hosts, got %d", len(result))
+	if len(result) != 4 {
+		t.Fatalf("Expected 4 hosts, got %d", len(result))
 	}
 
 	// Check GitHub host
@@ -253,6 +256,53 @@ func TestIdentityToHosts_WithSSHKey(t *testing.T) {
 	if gitlabHost.HostName != "gitlab.com" {
 		t.Errorf("Expected hostname 'gitlab.com', got %s", gitlabHost.HostName)
 	}
+
+	// Check Bitbucket host
+	bitbucketHost := result[2]
+	if bitbucketHost.Alias != "bitbucket-work" {
+		t.Errorf("Expected bitbucket alias 'bitbucket-work', got %s", bitbucketHost.Alias)
+	}
+	if bitbucketHost.HostName != "bitbucket.org" {
+		t.Errorf("Expected hostname 'bitbucket.org', got %s", bitbucketHost.HostName)
+	}
+
+	// Check Azure DevOps host (needs RSA-SHA1 compatibility)
+	azureHost := result[3]
+	if azureHost.Alias != "azure-work" {
+		t.Errorf("Expected azure alias 'azure-work', got %s", azureHost.Alias)
+	}
+	if azureHost.HostName != "ssh.dev.azure.com" {
+		t.Errorf("Expected hostname 'ssh.dev.azure.com', got %s", azureHost.HostName)
+	}
+	if azureHost.HostkeyAlgorithms != "+ssh-rsa" || azureHost.PubkeyAcceptedAlgorithms != "+ssh-rsa" {
+		t.Errorf("Expected Azure DevOps host to enable ssh-rsa, got %+v", azureHost)
+	}
+}
+
+func TestIdentityToHosts_GenerateConfigBlock(t *testing.T) {
+	identity := config.Identity{
+		Name:       "work",
+		Email:      "work@example.com",
+		SSHKeyPath: "/home/user/.ssh/work_key",
+	}
+
+	result := GenerateConfigBlock(IdentityToHosts(identity))
+
+	for _, alias := range []string{"github-work", "gitlab-work", "bitbucket-work", "azure-work"} {
+		if !strings.Contains(result, "Host "+alias+"\n") {
+			t.Errorf("Expected 'Host %s' in output, got:\n%s", alias, result)
+		}
+	}
+	if !strings.Contains(result, "    HostkeyAlgorithms +ssh-rsa\n") {
+		t.Errorf("Expected HostkeyAlgorithms line for Azure DevOps, got:\n%s", result)
+	}
+	if !strings.Contains(result, "    PubkeyAcceptedAlgorithms +ssh-rsa\n") {
+		t.Errorf("Expected PubkeyAcceptedAlgorithms line for Azure DevOps, got:\n%s", result)
+	}
+	// Only the Azure DevOps block should carry the RSA options
+	if strings.Count(result, "HostkeyAlgorithms") != 1 {
+		t.Errorf("Expected exactly one HostkeyAlgorithms line, got:\n%s", result)
+	}
 }
 
 func TestIdentityToHosts_TildeExpansion(t *testing.T) {
@@ -264,8 +314,8 @@ func TestIdentityToHosts_TildeExpansion(t *testing.T) {
 
 	result := IdentityToHosts(identity)
 
-	if len(result) != 2 {
-		t.Fatalf("Expected 2 hosts, got %d", len(result))
+	if len(result) != 4 {
+		t.Fatalf("Expected 4 hosts, got %d", len(result))
 	}
 
 	// IdentityFile should be expanded (not start with ~)
@@ -274,6 +324,74 @@ func TestIdentityToHosts_TildeExpansion(t *testing.T) {
 	}
 }
 
+func TestIdentityToHosts_CustomHosts(t *testing.T) {
+	identity := config.Identity{
+		Name:       "work",
+		Email:      "work@example.com",
+		SSHKeyPath: "/home/user/.ssh/work_key",
+		Hosts:      []string{"git.company.internal", "gitea.example.com"},
+	}
+
+	result := IdentityToHosts(identity)
+
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 hosts, got %d", len(result))
+	}
+
+	if result[0].Alias != "git.company-work" {
+		t.Errorf("Expected alias 'git.company-work', got %s", result[0].Alias)
+	}
+	if result[0].HostName != "git.company.internal" {
+		t.Errorf("Expected hostname 'git.company.internal', got %s", result[0].HostName)
+	}
+	if result[1].Alias != "gitea.example-work" {
+		t.Errorf("Expected alias 'gitea.example-work', got %s", result[1].Alias)
+	}
+	if result[1].IdentityFile != "/home/user/.ssh/work_key" {
+		t.Errorf("Expected identity file '/home/user/.ssh/work_key', got %s", result[1].IdentityFile)
+	}
+}
+
+func TestHostAliasPrefix(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"github.com", "github"},
+		{"git.company.internal", "git.company"},
+		{"Gitea.Example.COM", "gitea.example"},
+		{"localhost", "localhost"},
+	}
+
+	for _, tt := range tests {
+		if got := hostAliasPrefix(tt.host); got != tt.want {
+			t.Errorf("hostAliasPrefix(%q) = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateConfigBlock_CustomHostsRoundTrip(t *testing.T) {
+	identity := config.Identity{
+		Name:       "work",
+		Email:      "work@example.com",
+		SSHKeyPath: "/home/user/.ssh/work_key",
+		Hosts:      []string{"git.company.internal"},
+	}
+
+	userContent := "Host personal\n    HostName github.com\n    User git\n\n"
+	block := GenerateConfigBlock(IdentityToHosts(identity))
+
+	if !strings.Contains(block, "Host git.company-work\n    HostName git.company.internal\n") {
+		t.Errorf("Expected custom host block, got:\n%s", block)
+	}
+
+	// Removing the managed block must restore the user's content unchanged
+	result := removeManagedBlock(userContent + block)
+	if result != userContent {
+		t.Errorf("Expected round-trip to restore original content, got:\n%q", result)
+	}
+}
+
 func TestSSHConfigPath(t *testing.T) {
 	path, err := SSHConfigPath()
 	if err != nil {
@@ -284,3 +402,291 @@ func TestSSHConfigPath(t *testing.T) {
 		t.Errorf("Expected path to end with '.ssh/config', got: %s", path)
 	}
 }
+
+func TestHasManagedBlock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	managed, err := HasManagedBlock()
+	if err != nil {
+		t.Fatalf("HasManagedBlock() error = %v", err)
+	}
+	if managed {
+		t.Error("expected no managed block when SSH config doesn't exist")
+	}
+
+	if _, err := UpdateSSHConfig(GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})); err != nil {
+		t.Fatalf("UpdateSSHConfig() error = %v", err)
+	}
+
+	managed, err = HasManagedBlock()
+	if err != nil {
+		t.Fatalf("HasManagedBlock() error = %v", err)
+	}
+	if !managed {
+		t.Error("expected managed block after UpdateSSHConfig")
+	}
+
+	if err := os.WriteFile(filepath.Join(home, ".ssh", "config"), []byte("Host example\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if managed, _ := HasManagedBlock(); managed {
+		t.Error("expected no managed block in user-only SSH config")
+	}
+}
+
+func TestRemoveSSHConfigBlock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+
+	removed, err := RemoveSSHConfigBlock()
+	if err != nil || removed {
+		t.Fatalf("RemoveSSHConfigBlock() without config = %v, %v; want false, nil", removed, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte("Host example\n    HostName example.com\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := UpdateSSHConfig(GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})); err != nil {
+		t.Fatalf("UpdateSSHConfig() error = %v", err)
+	}
+
+	removed, err = RemoveSSHConfigBlock()
+	if err != nil || !removed {
+		t.Fatalf("RemoveSSHConfigBlock() = %v, %v; want true, nil", removed, err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "Host example\n    HostName example.com\n" {
+		t.Errorf("config after remove = %q, want user content only", got)
+	}
+
+	backup, err := os.ReadFile(configPath + ".gitch.backup")
+	if err != nil {
+		t.Fatalf("expected backup: %v", err)
+	}
+	if !strings.Contains(string(backup), MarkerStart) {
+		t.Error("expected backup to contain the managed block")
+	}
+
+	removed, err = RemoveSSHConfigBlock()
+	if err != nil || removed {
+		t.Errorf("second RemoveSSHConfigBlock() = %v, %v; want false, nil", removed, err)
+	}
+}
+
+func TestRemoveSSHConfigBlock_Malformed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+
+	content := "Host example\n\n" + MarkerStart + "\nHost github-work\n"
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := RemoveSSHConfigBlock()
+	if !errors.Is(err, ErrMalformedBlock) || removed {
+		t.Fatalf("RemoveSSHConfigBlock() = %v, %v; want false, ErrMalformedBlock", removed, err)
+	}
+
+	data, _ := os.ReadFile(configPath)
+	if string(data) != content {
+		t.Error("malformed config must be left unchanged")
+	}
+}
+
+func TestUpdateSSHConfig_Unchanged(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+	backupPath := configPath + ".gitch.backup"
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+
+	changed, err := UpdateSSHConfig(block)
+	if err != nil || !changed {
+		t.Fatalf("first UpdateSSHConfig() = %v, %v; want true, nil", changed, err)
+	}
+	if _, err := os.Stat(backupPath); !os.IsNotExist(err) {
+		t.Error("expected no backup when the SSH config didn't exist")
+	}
+
+	changed, err = UpdateSSHConfig(block)
+	if err != nil || changed {
+		t.Fatalf("second UpdateSSHConfig() = %v, %v; want false, nil", changed, err)
+	}
+	if _, err := os.Stat(backupPath); !os.IsNotExist(err) {
+		t.Error("expected no backup when the managed block is unchanged")
+	}
+
+	current, err := ReadManagedBlock()
+	if err != nil {
+		t.Fatalf("ReadManagedBlock() error = %v", err)
+	}
+	if current != block {
+		t.Errorf("ReadManagedBlock() = %q, want %q", current, block)
+	}
+
+	newBlock := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_new"}})
+	changed, err = UpdateSSHConfig(newBlock)
+	if err != nil || !changed {
+		t.Fatalf("UpdateSSHConfig() with new block = %v, %v; want true, nil", changed, err)
+	}
+	if _, err := os.Stat(backupPath); err != nil {
+		t.Errorf("expected backup after changing the block: %v", err)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	if diff := UnifiedDiff("a", "b", "same\n", "same\n"); diff != "" {
+		t.Errorf("expected empty diff for equal text, got %q", diff)
+	}
+
+	oldText := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	newText := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n10\n11\n"
+	want := `--- old
++++ new
+@@ -2,9 +2,10 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+ 9
+ 10
++11
+`
+	if got := UnifiedDiff("old", "new", oldText, newText); got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_SeparateHunks(t *testing.T) {
+	oldText := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\n"
+	newText := "A\nb\nc\nd\ne\nf\ng\nh\ni\nj\nK\n"
+	want := `--- old
++++ new
+@@ -1,4 +1,4 @@
+-a
++A
+ b
+ c
+ d
+@@ -8,4 +8,4 @@
+ h
+ i
+ j
+-k
++K
+`
+	if got := UnifiedDiff("old", "new", oldText, newText); got != want {
+		t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestUnifiedDiff_FromEmpty(t *testing.T) {
+	want := "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n"
+	if got := UnifiedDiff("old", "new", "", "a\nb\n"); got != want {
+		t.Errorf("UnifiedDiff() = %q, want %q", got, want)
+	}
+}
+
+func TestHostConfig_String_PortAndProxyJump(t *testing.T) {
+	host := HostConfig{
+		Alias:        "git.corp-work",
+		HostName:     "git.corp.internal",
+		User:         "git",
+		IdentityFile: "/home/user/.ssh/key",
+		Port:         2222,
+		ProxyJump:    "bastion.corp.internal",
+	}
+
+	result := host.String()
+	if !strings.Contains(result, "    HostName git.corp.internal\n    Port 2222\n") {
+		t.Errorf("Expected 'Port 2222' after HostName, got:\n%s", result)
+	}
+	if !strings.Contains(result, "    ProxyJump bastion.corp.internal\n") {
+		t.Errorf("Expected 'ProxyJump bastion.corp.internal', got:\n%s", result)
+	}
+}
+
+func TestHostConfig_String_NoPortOrProxyJumpWhenUnset(t *testing.T) {
+	host := HostConfig{
+		Alias:        "github-work",
+		HostName:     "github.com",
+		User:         "git",
+		IdentityFile: "/home/user/.ssh/key",
+	}
+
+	result := host.String()
+	if strings.Contains(result, "Port") {
+		t.Errorf("Expected no Port line when unset, got:\n%s", result)
+	}
+	if strings.Contains(result, "ProxyJump") {
+		t.Errorf("Expected no ProxyJump line when unset, got:\n%s", result)
+	}
+}
+
+func TestIdentityToHosts_PortAndProxyJump(t *testing.T) {
+	tests := []struct {
+		name  string
+		hosts []string
+	}{
+		{name: "default hosts"},
+		{name: "custom hosts", hosts: []string{"git.corp.internal"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := config.Identity{
+				Name:       "work",
+				Email:      "work@corp.com",
+				SSHKeyPath: "/home/user/.ssh/id_work",
+				Hosts:      tt.hosts,
+				SSHPort:    2222,
+				ProxyJump:  "bastion",
+			}
+
+			for _, host := range IdentityToHosts(identity) {
+				if host.Port != 2222 || host.ProxyJump != "bastion" {
+					t.Errorf("%s: Port = %d, ProxyJump = %q; want 2222, bastion", host.Alias, host.Port, host.ProxyJump)
+				}
+			}
+		})
+	}
+}
+
+func TestIdentityToHosts_MultipleKeys(t *testing.T) {
+	identity := config.Identity{
+		Name:        "work",
+		Email:       "work@corp.com",
+		SSHKeyPath:  "/home/user/.ssh/id_work",
+		SSHKeyPaths: []string{"/home/user/.ssh/id_work", "/home/user/.ssh/id_work_signing"},
+		Hosts:       []string{"git.corp.internal"},
+	}
+
+	hosts := IdentityToHosts(identity)
+	if len(hosts) != 1 {
+		t.Fatalf("expected 1 host, got %d", len(hosts))
+	}
+
+	block := hosts[0].String()
+	want := "    IdentityFile /home/user/.ssh/id_work\n    IdentityFile /home/user/.ssh/id_work_signing\n    IdentitiesOnly yes\n"
+	if !strings.Contains(block, want) {
+		t.Errorf("Host block should list both keys in order, got:\n%s", block)
+	}
+}
@@ -0,0 +1,148 @@
+package ssh
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change
+const diffContext = 3
+
+// diffLine is one line of a line diff; op is ' ' (unchanged), '-' or '+'
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// UnifiedDiff returns a unified diff from oldText to newText with three
+// lines of context, labelled with oldName and newName.
+// Returns an empty string if the texts are equal.
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	for start := 0; start < len(lines); {
+		// Find the next change
+		first := start
+		for first < len(lines) && lines[first].op == ' ' {
+			first++
+		}
+		if first == len(lines) {
+			break
+		}
+
+		// Extend the hunk while changes are close enough to share context
+		last := first
+		for i := first + 1; i < len(lines); i++ {
+			if lines[i].op == ' ' {
+				continue
+			}
+			if i-last > 2*diffContext {
+				break
+			}
+			last = i
+		}
+
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(lines))
+		writeHunk(&sb, lines, from, to)
+		start = to
+	}
+
+	return sb.String()
+}
+
+// writeHunk writes lines[from:to] as a hunk with its @@ header
+func writeHunk(sb *strings.Builder, lines []diffLine, from, to int) {
+	// Line numbers are 1-based positions in the old and new text
+	oldStart, newStart := 1, 1
+	for _, l := range lines[:from] {
+		if l.op != '+' {
+			oldStart++
+		}
+		if l.op != '-' {
+			newStart++
+		}
+	}
+
+	oldCount, newCount := 0, 0
+	for _, l := range lines[from:to] {
+		if l.op != '+' {
+			oldCount++
+		}
+		if l.op != '-' {
+			newCount++
+		}
+	}
+
+	// An empty range starts at the line before it
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+	for _, l := range lines[from:to] {
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		sb.WriteByte('\n')
+	}
+}
+
+// diffLines computes a line diff of a and b from their longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
+
+// splitLines splits text into lines without their trailing newlines
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
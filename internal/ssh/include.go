@@ -0,0 +1,326 @@
+package ssh
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The managed block can live in a file of its own, pulled into the SSH config
+// with an Include directive, for users who keep ~/.ssh/config short and
+// split the rest into ~/.ssh/config.d/.
+
+// NormalizeIncludePath makes path absolute and, if it is inside the home
+// directory, writes it with a leading ~/ as ssh's Include accepts. The path
+// supports ~ expansion.
+func NormalizeIncludePath(path string) (string, error) {
+	expandedPath, err := ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(expandedPath)
+	if err != nil {
+		return "", err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return absPath, nil
+	}
+	if rel, err := filepath.Rel(home, absPath); err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		return "~/" + filepath.ToSlash(rel), nil
+	}
+	return absPath, nil
+}
+
+// ReadManagedBlockFile returns the gitch-managed block in the file at path,
+// or "" if the file or the block doesn't exist.
+func ReadManagedBlockFile(path string) (string, error) {
+	expandedPath, err := ExpandPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(expandedPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return extractManagedBlock(string(data)), nil
+}
+
+// UpdateSSHConfigInclude writes newBlock to the file at includePath (see
+// NormalizeIncludePath) and makes sure the user's SSH config includes it,
+// adding an Include line at the top if no top-level Include already covers
+// the file. An inline managed block in the SSH config is removed, so hosts
+// aren't defined twice. The SSH config is backed up before it is changed.
+// Returns false without touching any file if everything is current.
+//
+// If the include file already exists, only its managed block is replaced;
+// a non-empty file without one is refused rather than overwritten, as is
+// the SSH config itself.
+func UpdateSSHConfigInclude(includePath, newBlock string) (bool, error) {
+	configPath, err := SSHConfigPath()
+	if err != nil {
+		return false, err
+	}
+	sshDir := filepath.Dir(configPath)
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		return false, fmt.Errorf("failed to create .ssh directory: %w", err)
+	}
+
+	includeFile, err := includeFilePath(includePath, configPath)
+	if err != nil {
+		return false, err
+	}
+
+	changed := false
+
+	// Write the block to the include file
+	current, err := os.ReadFile(includeFile)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", includePath, err)
+	}
+	updatedInclude, err := replaceManagedBlock(string(current), newBlock)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", includePath, err)
+	}
+	if updatedInclude != string(current) {
+		if err := os.MkdirAll(filepath.Dir(includeFile), 0700); err != nil {
+			return false, fmt.Errorf("failed to create directory for %s: %w", includePath, err)
+		}
+		if err := writeSSHConfig(includeFile, updatedInclude); err != nil {
+			return false, err
+		}
+		changed = true
+	}
+
+	// Include it from the SSH config, dropping any inline block
+	data, err := os.ReadFile(configPath)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read SSH config: %w", err)
+	}
+	content := string(data)
+
+	updated := content
+	if stripped := removeManagedBlock(content); stripped != content {
+		updated = strings.Trim(stripped, "\n")
+		if updated != "" {
+			updated += "\n"
+		}
+	}
+	if !hasInclude(updated, includeFile, sshDir) {
+		updated = addInclude(updated, includePath)
+	}
+	if updated == content {
+		return changed, nil
+	}
+
+	if err := backupSSHConfig(configPath, data); err != nil {
+		return false, err
+	}
+	if err := writeSSHConfig(configPath, updated); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveSSHConfigInclude removes the managed block from the include file
+// written by UpdateSSHConfigInclude, deleting the file once nothing else is
+// left in it, and then the Include line for it from the user's SSH config,
+// keeping a backup of the config. If the user has added their own hosts to
+// the file, the file and its Include line are kept. Include lines the user
+// wrote, such as globs over a config.d directory, are left alone. Returns
+// whether anything was removed.
+func RemoveSSHConfigInclude(includePath string) (bool, error) {
+	configPath, err := SSHConfigPath()
+	if err != nil {
+		return false, err
+	}
+
+	includeFile, err := includeFilePath(includePath, configPath)
+	if err != nil {
+		return false, err
+	}
+
+	removed := false
+	current, err := os.ReadFile(includeFile)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", includePath, err)
+	}
+	remaining := string(current)
+	if startIdx := strings.Index(remaining, MarkerStart); startIdx != -1 {
+		if !strings.Contains(remaining[startIdx:], MarkerEnd) {
+			return false, fmt.Errorf("%s: %w", includePath, ErrMalformedBlock)
+		}
+		remaining = strings.TrimSpace(removeManagedBlock(remaining))
+		if remaining == "" {
+			if err := os.Remove(includeFile); err != nil {
+				return false, fmt.Errorf("failed to remove %s: %w", includePath, err)
+			}
+		} else if err := writeSSHConfig(includeFile, remaining+"\n"); err != nil {
+			return false, err
+		}
+		removed = true
+	}
+	if strings.TrimSpace(remaining) != "" {
+		// The file is the user's now; keep including it
+		return removed, nil
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return removed, nil
+		}
+		return false, fmt.Errorf("failed to read SSH config: %w", err)
+	}
+
+	updated := removeInclude(string(data), includePath)
+	if updated == string(data) {
+		return removed, nil
+	}
+
+	if err := backupSSHConfig(configPath, data); err != nil {
+		return false, err
+	}
+	if err := writeSSHConfig(configPath, updated); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// IsIncluded reports whether a top-level Include line in the user's SSH
+// config covers the file at includePath
+func IsIncluded(includePath string) (bool, error) {
+	configPath, err := SSHConfigPath()
+	if err != nil {
+		return false, err
+	}
+
+	includeFile, err := ExpandPath(includePath)
+	if err != nil {
+		return false, fmt.Errorf("invalid include path: %w", err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read SSH config: %w", err)
+	}
+
+	return hasInclude(string(data), includeFile, filepath.Dir(configPath)), nil
+}
+
+// includeFilePath expands includePath, refusing the SSH config at
+// configPath itself, which would then include itself
+func includeFilePath(includePath, configPath string) (string, error) {
+	includeFile, err := ExpandPath(includePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid include path: %w", err)
+	}
+	includeFile, err = filepath.Abs(includeFile)
+	if err != nil {
+		return "", fmt.Errorf("invalid include path: %w", err)
+	}
+	if includeFile == filepath.Clean(configPath) {
+		return "", fmt.Errorf("the include file can't be the SSH config itself (%s)", includePath)
+	}
+	return includeFile, nil
+}
+
+// replaceManagedBlock returns content with its managed block replaced by
+// newBlock, leaving everything around the markers as it is. Empty content
+// becomes newBlock alone. Content without a managed block isn't gitch's to
+// overwrite, so it is refused, as is a block missing its end marker.
+func replaceManagedBlock(content, newBlock string) (string, error) {
+	if strings.TrimSpace(content) == "" {
+		return newBlock, nil
+	}
+
+	startIdx := strings.Index(content, MarkerStart)
+	if startIdx == -1 {
+		return "", errors.New("file exists and has no gitch-managed block; choose another include file or empty this one")
+	}
+	endIdx := strings.Index(content[startIdx:], MarkerEnd)
+	if endIdx == -1 {
+		return "", ErrMalformedBlock
+	}
+
+	rest := content[startIdx+endIdx+len(MarkerEnd):]
+	rest = strings.TrimPrefix(rest, "\n")
+	return content[:startIdx] + newBlock + rest, nil
+}
+
+// includeDirective returns the Include line gitch writes for includePath
+func includeDirective(includePath string) string {
+	return "Include " + includePath
+}
+
+// hasInclude reports whether a top-level Include line in content (one before
+// the first Host or Match line) covers the file at target. Relative Include
+// paths are resolved against sshDir, as ssh does for the user config.
+func hasInclude(content, target, sshDir string) bool {
+	home, _ := os.UserHomeDir()
+
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		switch strings.ToLower(fields[0]) {
+		case "host", "match":
+			// Includes below here only apply to that block
+			return false
+		case "include":
+			for _, pattern := range fields[1:] {
+				if strings.HasPrefix(pattern, "~/") && home != "" {
+					pattern = filepath.Join(home, pattern[2:])
+				} else if !filepath.IsAbs(pattern) {
+					pattern = filepath.Join(sshDir, pattern)
+				}
+				if matched, err := filepath.Match(pattern, target); err == nil && matched {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// addInclude puts the Include line for includePath at the top of content,
+// where it applies to every host
+func addInclude(content, includePath string) string {
+	content = strings.TrimLeft(content, "\n")
+	if content == "" {
+		return includeDirective(includePath) + "\n"
+	}
+	return includeDirective(includePath) + "\n\n" + content
+}
+
+// removeInclude removes the Include line addInclude wrote for includePath,
+// and the blank line after it
+func removeInclude(content, includePath string) string {
+	directive := includeDirective(includePath)
+	lines := strings.Split(content, "\n")
+
+	kept := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != directive {
+			kept = append(kept, lines[i])
+			continue
+		}
+		if i+1 < len(lines) && strings.TrimSpace(lines[i+1]) == "" && i+2 < len(lines) {
+			i++
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,276 @@
+package ssh
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeIncludePath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~/.ssh/config.d/gitch", "~/.ssh/config.d/gitch"},
+		{filepath.Join(home, ".ssh", "gitch"), "~/.ssh/gitch"},
+		{"/etc/ssh/gitch", "/etc/ssh/gitch"},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeIncludePath(tt.path)
+		if err != nil {
+			t.Fatalf("NormalizeIncludePath(%q) error = %v", tt.path, err)
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeIncludePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateSSHConfigInclude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+	includeFile := filepath.Join(home, ".ssh", "config.d", "gitch")
+
+	// An existing config with an inline block, which moves to the include file
+	inline := GenerateConfigBlock([]HostConfig{{Alias: "github-old", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_old"}})
+	original := "Host example\n    User me\n\n" + inline
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	changed, err := UpdateSSHConfigInclude("~/.ssh/config.d/gitch", block)
+	if err != nil || !changed {
+		t.Fatalf("UpdateSSHConfigInclude() = %v, %v; want true, nil", changed, err)
+	}
+
+	data, err := os.ReadFile(includeFile)
+	if err != nil {
+		t.Fatalf("failed to read include file: %v", err)
+	}
+	if string(data) != block {
+		t.Errorf("include file = %q, want %q", data, block)
+	}
+
+	data, err = os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Include ~/.ssh/config.d/gitch\n\nHost example\n    User me\n"
+	if string(data) != want {
+		t.Errorf("SSH config = %q, want %q", data, want)
+	}
+	if backup, err := os.ReadFile(configPath + ".gitch.backup"); err != nil || string(backup) != original {
+		t.Errorf("backup = %q, %v; want the original config", backup, err)
+	}
+
+	// Running again changes nothing and adds no second Include line
+	changed, err = UpdateSSHConfigInclude("~/.ssh/config.d/gitch", block)
+	if err != nil || changed {
+		t.Fatalf("second UpdateSSHConfigInclude() = %v, %v; want false, nil", changed, err)
+	}
+	data, _ = os.ReadFile(configPath)
+	if string(data) != want {
+		t.Errorf("SSH config after second update = %q, want %q", data, want)
+	}
+
+	if included, err := IsIncluded("~/.ssh/config.d/gitch"); err != nil || !included {
+		t.Errorf("IsIncluded() = %v, %v; want true, nil", included, err)
+	}
+
+	current, err := ReadManagedBlockFile("~/.ssh/config.d/gitch")
+	if err != nil || current != block {
+		t.Errorf("ReadManagedBlockFile() = %q, %v; want %q", current, err, block)
+	}
+}
+
+func TestUpdateSSHConfigInclude_CoveredByGlob(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+
+	tests := []struct {
+		name    string
+		config  string
+		covered bool
+	}{
+		{"relative glob", "Include config.d/*\n\nHost example\n    User me\n", true},
+		{"home glob", "include ~/.ssh/config.d/*\n", true},
+		{"other directory", "Include ~/.ssh/other/*\n", false},
+		{"inside a Host block", "Host example\n    Include config.d/*\n", false},
+	}
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(configPath, []byte(tt.config), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := UpdateSSHConfigInclude("~/.ssh/config.d/gitch", block); err != nil {
+				t.Fatalf("UpdateSSHConfigInclude() error = %v", err)
+			}
+
+			data, _ := os.ReadFile(configPath)
+			added := strings.HasPrefix(string(data), "Include ~/.ssh/config.d/gitch\n")
+			if added == tt.covered {
+				t.Errorf("SSH config = %q; Include line added = %v, want %v", data, added, !tt.covered)
+			}
+		})
+	}
+}
+
+func TestRemoveSSHConfigInclude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+	includeFile := filepath.Join(home, ".ssh", "config.d", "gitch")
+
+	original := "Host example\n    User me\n"
+	if err := os.MkdirAll(filepath.Dir(configPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, []byte(original), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	if _, err := UpdateSSHConfigInclude("~/.ssh/config.d/gitch", block); err != nil {
+		t.Fatalf("UpdateSSHConfigInclude() error = %v", err)
+	}
+
+	removed, err := RemoveSSHConfigInclude("~/.ssh/config.d/gitch")
+	if err != nil || !removed {
+		t.Fatalf("RemoveSSHConfigInclude() = %v, %v; want true, nil", removed, err)
+	}
+	if _, err := os.Stat(includeFile); !os.IsNotExist(err) {
+		t.Error("expected the include file to be deleted")
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != original {
+		t.Errorf("SSH config = %q, want %q", data, original)
+	}
+
+	removed, err = RemoveSSHConfigInclude("~/.ssh/config.d/gitch")
+	if err != nil || removed {
+		t.Errorf("second RemoveSSHConfigInclude() = %v, %v; want false, nil", removed, err)
+	}
+}
+
+func TestUpdateSSHConfigInclude_ExistingUserFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	includeFile := filepath.Join(home, ".ssh", "config.d", "hosts")
+
+	userHosts := "Host bastion\n    HostName 10.0.0.1\n"
+	if err := os.MkdirAll(filepath.Dir(includeFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(includeFile, []byte(userHosts), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	if _, err := UpdateSSHConfigInclude("~/.ssh/config.d/hosts", block); err == nil {
+		t.Fatal("UpdateSSHConfigInclude() should refuse a file without a gitch block")
+	}
+	if data, _ := os.ReadFile(includeFile); string(data) != userHosts {
+		t.Errorf("include file = %q, want it untouched", data)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".ssh", "config")); !os.IsNotExist(err) {
+		t.Error("expected the SSH config to be left alone")
+	}
+}
+
+func TestUpdateSSHConfigInclude_EditsOnlyTheBlock(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	configPath := filepath.Join(home, ".ssh", "config")
+	includeFile := filepath.Join(home, ".ssh", "config.d", "hosts")
+
+	oldBlock := GenerateConfigBlock([]HostConfig{{Alias: "github-old", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_old"}})
+	before := "Host bastion\n    HostName 10.0.0.1\n\n"
+	after := "\nHost build\n    HostName 10.0.0.2\n"
+	if err := os.MkdirAll(filepath.Dir(includeFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(includeFile, []byte(before+oldBlock+after), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	if _, err := UpdateSSHConfigInclude("~/.ssh/config.d/hosts", block); err != nil {
+		t.Fatalf("UpdateSSHConfigInclude() error = %v", err)
+	}
+	if data, _ := os.ReadFile(includeFile); string(data) != before+block+after {
+		t.Errorf("include file = %q, want %q", data, before+block+after)
+	}
+
+	// Removing keeps the user's hosts, and the Include line they rely on
+	removed, err := RemoveSSHConfigInclude("~/.ssh/config.d/hosts")
+	if err != nil || !removed {
+		t.Fatalf("RemoveSSHConfigInclude() = %v, %v; want true, nil", removed, err)
+	}
+	data, err := os.ReadFile(includeFile)
+	if err != nil {
+		t.Fatalf("expected the include file to be kept: %v", err)
+	}
+	if strings.Contains(string(data), MarkerStart) || !strings.Contains(string(data), "Host bastion") || !strings.Contains(string(data), "Host build") {
+		t.Errorf("include file after remove = %q, want only the user's hosts", data)
+	}
+	if data, _ := os.ReadFile(configPath); !strings.HasPrefix(string(data), "Include ~/.ssh/config.d/hosts\n") {
+		t.Errorf("SSH config = %q, want the Include line kept", data)
+	}
+}
+
+func TestUpdateSSHConfigInclude_Malformed(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	includeFile := filepath.Join(home, ".ssh", "gitch")
+
+	content := MarkerStart + "\nHost github-work\n"
+	if err := os.MkdirAll(filepath.Dir(includeFile), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(includeFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	if _, err := UpdateSSHConfigInclude("~/.ssh/gitch", block); !errors.Is(err, ErrMalformedBlock) {
+		t.Errorf("UpdateSSHConfigInclude() error = %v, want ErrMalformedBlock", err)
+	}
+	if _, err := RemoveSSHConfigInclude("~/.ssh/gitch"); !errors.Is(err, ErrMalformedBlock) {
+		t.Errorf("RemoveSSHConfigInclude() error = %v, want ErrMalformedBlock", err)
+	}
+	if data, _ := os.ReadFile(includeFile); string(data) != content {
+		t.Errorf("include file = %q, want it untouched", data)
+	}
+}
+
+func TestUpdateSSHConfigInclude_RejectsSSHConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	block := GenerateConfigBlock([]HostConfig{{Alias: "github-work", HostName: "github.com", User: "git", IdentityFile: "~/.ssh/id_work"}})
+	for _, path := range []string{"~/.ssh/config", filepath.Join(home, ".ssh", "..", ".ssh", "config")} {
+		if _, err := UpdateSSHConfigInclude(path, block); err == nil {
+			t.Errorf("UpdateSSHConfigInclude(%q) should refuse the SSH config itself", path)
+		}
+		if _, err := RemoveSSHConfigInclude(path); err == nil {
+			t.Errorf("RemoveSSHConfigInclude(%q) should refuse the SSH config itself", path)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package ssh
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/crypto/ssh"
 )
@@ -21,7 +24,8 @@ func GenerateKeyPair(comment string, passphrase []byte) (privateKeyPEM, publicKe
 }
 
 // GenerateKeyPairWithType generates an SSH keypair of the specified type.
-// Supported types: KeyTypeEd25519 (default, modern), KeyTypeRSA (4096-bit, for Azure DevOps).
+// Supported types: KeyTypeEd25519 (default, modern), KeyTypeRSA (4096-bit, for Azure DevOps),
+// KeyTypeECDSA (NIST P-256) and KeyTypeECDSAP384 (NIST P-384).
 // Returns the private key in PEM format and the public key in authorized_keys format.
 // If passphrase is provided, the private key will be encrypted.
 func GenerateKeyPairWithType(keyType KeyType, comment string, passphrase []byte) (privateKeyPEM, publicKey []byte, err error) {
@@ -53,6 +57,22 @@ func GenerateKeyPairWithType(keyType KeyType, comment string, passphrase []byte)
 			return nil, nil, fmt.Errorf("failed to create SSH public key: %w", err)
 		}
 
+	case KeyTypeECDSA, KeyTypeECDSAP384:
+		// Generate ECDSA keypair on the requested NIST curve
+		curve := elliptic.P256()
+		if keyType == KeyTypeECDSAP384 {
+			curve = elliptic.P384()
+		}
+		ecdsaKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate ECDSA keypair: %w", err)
+		}
+		privateKey = ecdsaKey
+		sshPubKey, err = ssh.NewPublicKey(&ecdsaKey.PublicKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create SSH public key: %w", err)
+		}
+
 	default:
 		return nil, nil, fmt.Errorf("unsupported key type: %s", keyType)
 	}
@@ -109,6 +129,93 @@ func WriteKeyFiles(privateKeyPath string, privateKey, publicKey []byte) error {
 	return nil
 }
 
+// BackupKeyFiles renames the keypair at privateKeyPath to {path}.bak and
+// {path}.pub.bak. Earlier backups are never replaced: if either name is
+// taken, {path}-N.bak and {path}.pub-N.bak are used with the first free N.
+// A missing public key is skipped. Returns the backup path of the private key.
+func BackupKeyFiles(privateKeyPath string) (string, error) {
+	if _, err := os.Stat(privateKeyPath); err != nil {
+		return "", fmt.Errorf("failed to back up private key: %w", err)
+	}
+
+	publicKeyPath := privateKeyPath + ".pub"
+	for i := 0; ; i++ {
+		suffix := ".bak"
+		if i > 0 {
+			suffix = fmt.Sprintf("-%d.bak", i)
+		}
+		backupPath, publicBackupPath := privateKeyPath+suffix, publicKeyPath+suffix
+
+		// Claim both names so the pair shares a suffix and nothing is overwritten
+		taken, err := claimBackupPath(backupPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to back up private key: %w", err)
+		}
+		if taken {
+			continue
+		}
+		taken, err = claimBackupPath(publicBackupPath)
+		if err != nil || taken {
+			os.Remove(backupPath)
+			if err != nil {
+				return "", fmt.Errorf("failed to back up public key: %w", err)
+			}
+			continue
+		}
+
+		if err := os.Rename(privateKeyPath, backupPath); err != nil {
+			os.Remove(backupPath)
+			os.Remove(publicBackupPath)
+			return "", fmt.Errorf("failed to back up private key: %w", err)
+		}
+		if err := os.Rename(publicKeyPath, publicBackupPath); err != nil {
+			os.Remove(publicBackupPath)
+			if !os.IsNotExist(err) {
+				// Put the private key back so the pair stays together
+				_ = os.Rename(backupPath, privateKeyPath)
+				return "", fmt.Errorf("failed to back up public key: %w", err)
+			}
+		}
+		return backupPath, nil
+	}
+}
+
+// claimBackupPath creates an empty file at path unless one exists, so a
+// backup can be renamed onto it. Reports whether the path was taken.
+func claimBackupPath(path string) (bool, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if os.IsExist(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return false, file.Close()
+}
+
+// RestoreKeyFiles moves the backups made by BackupKeyFiles, whose private
+// key backup is at backupPath, back to privateKeyPath and {path}.pub,
+// replacing any files written there since. A missing public key backup is
+// skipped.
+func RestoreKeyFiles(privateKeyPath, backupPath string) error {
+	if err := os.Rename(backupPath, privateKeyPath); err != nil {
+		return fmt.Errorf("failed to restore private key: %w", err)
+	}
+
+	publicKeyPath := privateKeyPath + ".pub"
+	publicBackupPath := publicKeyPath + strings.TrimPrefix(backupPath, privateKeyPath)
+	if err := os.Rename(publicBackupPath, publicKeyPath); err != nil {
+		if os.IsNotExist(err) {
+			// No public key was backed up; don't leave a new one next to the old key
+			_ = os.Remove(publicKeyPath)
+			return nil
+		}
+		return fmt.Errorf("failed to restore public key: %w", err)
+	}
+
+	return nil
+}
+
 // GetFingerprint returns the SHA256 fingerprint of an SSH public key.
 // The input should be in authorized_keys format (e.g., "ssh-ed25519 AAAA... comment").
 func GetFingerprint(publicKey []byte) (string, error) {
@@ -4,11 +4,13 @@ package ssh
 import (
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
 	"strings"
 )
 
-// ExpandPath expands ~ and environment variables in a path.
+// ExpandPath expands ~, ~username and environment variables in a path.
+// A ~username prefix naming an unknown user is left unchanged.
 // Returns the cleaned, absolute path.
 func ExpandPath(path string) (string, error) {
 	if path == "" {
@@ -28,6 +30,8 @@ func ExpandPath(path string) (string, error) {
 			path = home
 		} else if strings.HasPrefix(path, "~/") {
 			path = filepath.Join(home, path[2:])
+		} else {
+			path = expandUserHome(path)
 		}
 	}
 
@@ -35,6 +39,17 @@ func ExpandPath(path string) (string, error) {
 	return filepath.Clean(path), nil
 }
 
+// expandUserHome resolves a ~username or ~username/rest prefix to that user's
+// home directory. The path is returned unchanged if the user can't be found.
+func expandUserHome(path string) string {
+	name, rest, _ := strings.Cut(path[1:], "/")
+	u, err := user.Lookup(name)
+	if err != nil || u.HomeDir == "" {
+		return path
+	}
+	return filepath.Join(u.HomeDir, rest)
+}
+
 // DefaultSSHKeyPath returns the default SSH key path for a gitch identity.
 // Format: ~/.ssh/gitch_{identityName}_ed25519
 func DefaultSSHKeyPath(identityName string) string {
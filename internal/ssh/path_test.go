@@ -0,0 +1,87 @@
+package ssh
+
+import (
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Fatalf("failed to get home dir: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "tilde only",
+			input:    "~",
+			expected: home,
+		},
+		{
+			name:     "tilde with path",
+			input:    "~/.ssh/id_ed25519",
+			expected: filepath.Join(home, ".ssh/id_ed25519"),
+		},
+		{
+			name:     "unknown user (not expanded)",
+			input:    "~gitch-nonexistent-user/.ssh/id_rsa",
+			expected: "~gitch-nonexistent-user/.ssh/id_rsa",
+		},
+		{
+			name:     "absolute path",
+			input:    "/etc/ssh/../ssh/key",
+			expected: "/etc/ssh/key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExpandPath(tt.input)
+			if err != nil {
+				t.Fatalf("ExpandPath(%q) error = %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ExpandPath(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandPath_CurrentUser(t *testing.T) {
+	current, err := user.Current()
+	if err != nil || current.HomeDir == "" {
+		t.Skip("current user lookup unavailable")
+	}
+	if _, err := user.Lookup(current.Username); err != nil {
+		t.Skipf("user lookup by name unavailable: %v", err)
+	}
+
+	result, err := ExpandPath("~" + current.Username + "/.ssh/id_rsa")
+	if err != nil {
+		t.Fatalf("ExpandPath() error = %v", err)
+	}
+	expected := filepath.Join(current.HomeDir, ".ssh/id_rsa")
+	if result != expected {
+		t.Errorf("ExpandPath() = %q, want %q", result, expected)
+	}
+
+	result, err = ExpandPath("~" + current.Username)
+	if err != nil {
+		t.Fatalf("ExpandPath() error = %v", err)
+	}
+	if result != filepath.Clean(current.HomeDir) {
+		t.Errorf("ExpandPath() = %q, want %q", result, current.HomeDir)
+	}
+}
+
+func TestExpandPath_Empty(t *testing.T) {
+	if _, err := ExpandPath(""); err == nil {
+		t.Error("ExpandPath(\"\") should return error")
+	}
+}
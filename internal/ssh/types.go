@@ -13,6 +13,10 @@ const (
 	KeyTypeEd25519 KeyType = "ed25519"
 	// KeyTypeRSA represents RSA keys (required for Azure DevOps compatibility).
 	KeyTypeRSA KeyType = "rsa"
+	// KeyTypeECDSA represents ECDSA keys on the NIST P-256 curve (legacy hosts, hardware tokens).
+	KeyTypeECDSA KeyType = "ecdsa"
+	// KeyTypeECDSAP384 represents ECDSA keys on the NIST P-384 curve.
+	KeyTypeECDSAP384 KeyType = "ecdsa-p384"
 )
 
 // String returns the string representation of the key type.
@@ -21,7 +25,7 @@ func (kt KeyType) String() string {
 }
 
 // ParseKeyType parses a string into a KeyType.
-// Accepts "ed25519", "rsa" (case-insensitive).
+// Accepts "ed25519", "rsa", "ecdsa", "ecdsa-p384" (case-insensitive).
 // Returns an error for invalid types.
 func ParseKeyType(s string) (KeyType, error) {
 	switch strings.ToLower(strings.TrimSpace(s)) {
@@ -29,6 +33,10 @@ func ParseKeyType(s string) (KeyType, error) {
 		return KeyTypeEd25519, nil
 	case "rsa":
 		return KeyTypeRSA, nil
+	case "ecdsa":
+		return KeyTypeECDSA, nil
+	case "ecdsa-p384":
+		return KeyTypeECDSAP384, nil
 	default:
 		return "", fmt.Errorf("invalid key type %q: must be one of %v", s, ValidKeyTypes())
 	}
@@ -36,5 +44,5 @@ func ParseKeyType(s string) (KeyType, error) {
 
 // ValidKeyTypes returns a slice of valid key types for help text and validation.
 func ValidKeyTypes() []string {
-	return []string{string(KeyTypeEd25519), string(KeyTypeRSA)}
+	return []string{string(KeyTypeEd25519), string(KeyTypeRSA), string(KeyTypeECDSA), string(KeyTypeECDSAP384)}
 }
@@ -1,18 +1,22 @@
 package ssh
 
 import (
+	"crypto/ecdsa"
 	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rsa"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 
 	"golang.org/x/crypto/ssh"
 )
 
 // ValidateSSHKey validates that the given PEM data is a supported SSH private key.
-// Supported types: Ed25519 and RSA.
+// Supported types: Ed25519, RSA and ECDSA (P-256, P-384).
 // Returns nil if the key is valid (encrypted or not).
 // Returns an error if the key is not a supported type or cannot be parsed.
 func ValidateSSHKey(pemData []byte) error {
@@ -24,22 +28,57 @@ func ValidateSSHKey(pemData []byte) error {
 		if ok {
 			// Key is encrypted - check if it's a supported type via the public key
 			keyType := passErr.PublicKey.Type()
-			if keyType == ssh.KeyAlgoED25519 || keyType == ssh.KeyAlgoRSA {
+			if _, ok := keyTypeFromAlgo(keyType); ok {
 				return nil // Valid encrypted key of supported type
 			}
-			return fmt.Errorf("unsupported key type: %s (supported: ed25519, rsa)", keyType)
+			return fmt.Errorf("unsupported key type: %s (supported: %s)", keyType, strings.Join(ValidKeyTypes(), ", "))
 		}
 		return fmt.Errorf("failed to parse private key: %w", err)
 	}
 
 	// Key parsed successfully - verify it's a supported type
-	switch key.(type) {
+	switch k := key.(type) {
 	case ed25519.PrivateKey, *ed25519.PrivateKey:
 		return nil
 	case *rsa.PrivateKey:
 		return nil
+	case *ecdsa.PrivateKey:
+		if _, ok := ecdsaKeyType(k); ok {
+			return nil
+		}
+		return fmt.Errorf("unsupported ECDSA curve: %s (supported: P-256, P-384)", k.Curve.Params().Name)
+	default:
+		return fmt.Errorf("unsupported key type: %T (supported: %s)", key, strings.Join(ValidKeyTypes(), ", "))
+	}
+}
+
+// keyTypeFromAlgo maps an SSH public key algorithm name to a supported KeyType.
+// Returns false if the algorithm is not supported.
+func keyTypeFromAlgo(algo string) (KeyType, bool) {
+	switch algo {
+	case ssh.KeyAlgoED25519:
+		return KeyTypeEd25519, true
+	case ssh.KeyAlgoRSA:
+		return KeyTypeRSA, true
+	case ssh.KeyAlgoECDSA256:
+		return KeyTypeECDSA, true
+	case ssh.KeyAlgoECDSA384:
+		return KeyTypeECDSAP384, true
+	default:
+		return "", false
+	}
+}
+
+// ecdsaKeyType maps an ECDSA private key to a supported KeyType based on its curve.
+// Returns false for curves other than P-256 and P-384.
+func ecdsaKeyType(key *ecdsa.PrivateKey) (KeyType, bool) {
+	switch key.Curve {
+	case elliptic.P256():
+		return KeyTypeECDSA, true
+	case elliptic.P384():
+		return KeyTypeECDSAP384, true
 	default:
-		return fmt.Errorf("unsupported key type: %T (supported: ed25519, rsa)", key)
+		return "", false
 	}
 }
 
@@ -85,24 +124,25 @@ func GetKeyType(pemData []byte) (KeyType, error) {
 		passErr, ok := err.(*ssh.PassphraseMissingError)
 		if ok {
 			// Key is encrypted - determine type via the public key
-			switch passErr.PublicKey.Type() {
-			case ssh.KeyAlgoED25519:
-				return KeyTypeEd25519, nil
-			case ssh.KeyAlgoRSA:
-				return KeyTypeRSA, nil
-			default:
-				return "", fmt.Errorf("unsupported key type: %s", passErr.PublicKey.Type())
+			if kt, ok := keyTypeFromAlgo(passErr.PublicKey.Type()); ok {
+				return kt, nil
 			}
+			return "", fmt.Errorf("unsupported key type: %s", passErr.PublicKey.Type())
 		}
 		return "", fmt.Errorf("failed to parse private key: %w", err)
 	}
 
 	// Key parsed successfully - determine type
-	switch key.(type) {
+	switch k := key.(type) {
 	case ed25519.PrivateKey, *ed25519.PrivateKey:
 		return KeyTypeEd25519, nil
 	case *rsa.PrivateKey:
 		return KeyTypeRSA, nil
+	case *ecdsa.PrivateKey:
+		if kt, ok := ecdsaKeyType(k); ok {
+			return kt, nil
+		}
+		return "", fmt.Errorf("unsupported key type: ECDSA %s", k.Curve.Params().Name)
 	default:
 		return "", fmt.Errorf("unsupported key type: %T", key)
 	}
@@ -122,7 +162,7 @@ func IsEncrypted(pemData []byte) bool {
 
 // ValidateKeyPath validates an SSH key file at the given path.
 // Expands the path, checks the file exists, validates it's not a .pub file,
-// and verifies it's a supported key type (Ed25519 or RSA).
+// and verifies it's a supported key type (Ed25519, RSA or ECDSA).
 func ValidateKeyPath(path string) error {
 	// Expand path (~ and env vars)
 	expandedPath, err := ExpandPath(path)
@@ -157,3 +197,44 @@ func ValidateKeyPath(path string) error {
 
 	return ValidateSSHKey(data)
 }
+
+// ErrKeyPermissions is returned by CheckKeyPermissions when a private key is
+// accessible by group or others, which makes ssh refuse to use it
+var ErrKeyPermissions = errors.New("private key permissions are too open")
+
+// CheckKeyPermissions reports whether the private key at path is readable
+// only by its owner. Returns an error wrapping ErrKeyPermissions with the
+// current mode when group or other bits are set. Always nil on Windows,
+// where file modes don't reflect ACLs.
+func CheckKeyPermissions(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	expandedPath, err := ExpandPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to expand path: %w", err)
+	}
+
+	info, err := os.Stat(expandedPath)
+	if err != nil {
+		return fmt.Errorf("cannot access key file: %w", err)
+	}
+
+	if mode := info.Mode().Perm(); mode&0077 != 0 {
+		return fmt.Errorf("%w: %s is %04o, should be 0600", ErrKeyPermissions, expandedPath, mode)
+	}
+	return nil
+}
+
+// FixKeyPermissions restricts the private key at path to 0600
+func FixKeyPermissions(path string) error {
+	expandedPath, err := ExpandPath(path)
+	if err != nil {
+		return fmt.Errorf("failed to expand path: %w", err)
+	}
+	if err := os.Chmod(expandedPath, 0600); err != nil {
+		return fmt.Errorf("failed to set key permissions: %w", err)
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/pem"
+	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -300,9 +302,33 @@ func TestValidateSSHKey_AcceptsEncryptedRSA(t *testing.T) {
 	}
 }
 
-func TestValidateSSHKey_RejectsECDSA(t *testing.T) {
-	// Generate an ECDSA key (unsupported)
-	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+func TestValidateSSHKey_AcceptsECDSA(t *testing.T) {
+	privKey, _, err := GenerateKeyPairWithType(KeyTypeECDSA, "test@gitch", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+
+	err = ValidateSSHKey(privKey)
+	if err != nil {
+		t.Errorf("ValidateSSHKey should accept ECDSA key: %v", err)
+	}
+}
+
+func TestValidateSSHKey_AcceptsEncryptedECDSA(t *testing.T) {
+	privKey, _, err := GenerateKeyPairWithType(KeyTypeECDSA, "test@gitch", []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("Failed to generate encrypted ECDSA key: %v", err)
+	}
+
+	err = ValidateSSHKey(privKey)
+	if err != nil {
+		t.Errorf("ValidateSSHKey should accept encrypted ECDSA key: %v", err)
+	}
+}
+
+func TestValidateSSHKey_RejectsECDSAP521(t *testing.T) {
+	// Generate an ECDSA P-521 key (unsupported curve)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	if err != nil {
 		t.Fatalf("Failed to generate ECDSA key: %v", err)
 	}
@@ -315,7 +341,7 @@ func TestValidateSSHKey_RejectsECDSA(t *testing.T) {
 
 	err = ValidateSSHKey(pemData)
 	if err == nil {
-		t.Error("ValidateSSHKey should reject ECDSA key")
+		t.Error("ValidateSSHKey should reject ECDSA P-521 key")
 	}
 	if !strings.Contains(err.Error(), "unsupported") {
 		t.Errorf("Error should mention 'unsupported', got: %v", err)
@@ -391,9 +417,39 @@ func TestGetKeyType_IdentifiesEncryptedRSA(t *testing.T) {
 	}
 }
 
+func TestGetKeyType_IdentifiesECDSA(t *testing.T) {
+	privKey, _, err := GenerateKeyPairWithType(KeyTypeECDSA, "test@gitch", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate ECDSA key: %v", err)
+	}
+
+	kt, err := GetKeyType(privKey)
+	if err != nil {
+		t.Fatalf("GetKeyType failed: %v", err)
+	}
+	if kt != KeyTypeECDSA {
+		t.Errorf("GetKeyType = %v, want %v", kt, KeyTypeECDSA)
+	}
+}
+
+func TestGetKeyType_IdentifiesEncryptedECDSAP384(t *testing.T) {
+	privKey, _, err := GenerateKeyPairWithType(KeyTypeECDSAP384, "test@gitch", []byte("passphrase"))
+	if err != nil {
+		t.Fatalf("Failed to generate encrypted ECDSA P-384 key: %v", err)
+	}
+
+	kt, err := GetKeyType(privKey)
+	if err != nil {
+		t.Fatalf("GetKeyType failed for encrypted key: %v", err)
+	}
+	if kt != KeyTypeECDSAP384 {
+		t.Errorf("GetKeyType = %v, want %v", kt, KeyTypeECDSAP384)
+	}
+}
+
 func TestGetKeyType_RejectsUnsupportedType(t *testing.T) {
-	// Generate an ECDSA key (unsupported)
-	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	// Generate an ECDSA P-521 key (unsupported curve)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	if err != nil {
 		t.Fatalf("Failed to generate ECDSA key: %v", err)
 	}
@@ -446,3 +502,43 @@ func TestValidateKeyPath_AcceptsEncryptedRSAKey(t *testing.T) {
 		t.Errorf("ValidateKeyPath should accept encrypted RSA key: %v", err)
 	}
 }
+
+func TestCheckKeyPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not checked on Windows")
+	}
+
+	privKey, pubKey, err := GenerateKeyPair("test@gitch", nil)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	keyPath := filepath.Join(t.TempDir(), "test_key")
+	if err := WriteKeyFiles(keyPath, privKey, pubKey); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+
+	// WriteKeyFiles uses 0600
+	if err := CheckKeyPermissions(keyPath); err != nil {
+		t.Errorf("CheckKeyPermissions should accept 0600 key: %v", err)
+	}
+
+	// A linked key copied around with default permissions
+	if err := os.Chmod(keyPath, 0644); err != nil {
+		t.Fatalf("Failed to chmod key: %v", err)
+	}
+	err = CheckKeyPermissions(keyPath)
+	if !errors.Is(err, ErrKeyPermissions) {
+		t.Fatalf("CheckKeyPermissions on 0644 key = %v, want ErrKeyPermissions", err)
+	}
+	if !strings.Contains(err.Error(), "0644") {
+		t.Errorf("error should include current mode, got: %v", err)
+	}
+
+	if err := FixKeyPermissions(keyPath); err != nil {
+		t.Fatalf("FixKeyPermissions failed: %v", err)
+	}
+	if err := CheckKeyPermissions(keyPath); err != nil {
+		t.Errorf("CheckKeyPermissions after fix: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package ssh
+
+import (
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VerifyResult is the outcome of testing SSH authentication against one host
+type VerifyResult struct {
+	Host    HostConfig
+	OK      bool
+	Message string // provider greeting or ssh error
+}
+
+// successPatterns match the greetings Git hosts print after a successful
+// 'ssh -T' login (which still exits non-zero since no shell is provided)
+var successPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)successfully authenticated`),             // GitHub, Gitea, Forgejo
+	regexp.MustCompile(`(?i)^welcome to gitlab`),                     // GitLab
+	regexp.MustCompile(`(?i)authenticated via ssh key|logged in as`), // Bitbucket
+	regexp.MustCompile(`(?i)shell access is not supported`),          // Azure DevOps
+}
+
+// VerifyHost runs 'ssh -T' against host using only its IdentityFile and
+// reports whether the provider accepted the key. The host key must already
+// be in known_hosts unless acceptNewHostKey is set, in which case ssh adds
+// an unknown host's key to known_hosts.
+func VerifyHost(host HostConfig, acceptNewHostKey bool) VerifyResult {
+	output, _ := exec.Command("ssh", verifyArgs(host, acceptNewHostKey)...).CombinedOutput()
+	ok, message := parseGreeting(string(output))
+	return VerifyResult{Host: host, OK: ok, Message: message}
+}
+
+// verifyArgs builds the ssh arguments for VerifyHost. Options are passed on
+// the command line and "-F none" skips ~/.ssh/config (unlike /dev/null, it
+// works on Windows too), so the result doesn't depend on the user's config.
+func verifyArgs(host HostConfig, acceptNewHostKey bool) []string {
+	strictHostKeyChecking := "yes"
+	if acceptNewHostKey {
+		strictHostKeyChecking = "accept-new"
+	}
+	args := []string{
+		"-T",
+		"-F", "none",
+		"-o", "IdentitiesOnly=yes",
+		"-o", "IdentityFile=" + host.IdentityFile,
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=10",
+		"-o", "StrictHostKeyChecking=" + strictHostKeyChecking,
+	}
+	for _, file := range host.ExtraIdentityFiles {
+		args = append(args, "-o", "IdentityFile="+file)
+	}
+	if host.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(host.Port))
+	}
+	if host.ProxyJump != "" {
+		args = append(args, "-J", host.ProxyJump)
+	}
+	if host.HostkeyAlgorithms != "" {
+		args = append(args, "-o", "HostkeyAlgorithms="+host.HostkeyAlgorithms)
+	}
+	if host.PubkeyAcceptedAlgorithms != "" {
+		args = append(args, "-o", "PubkeyAcceptedAlgorithms="+host.PubkeyAcceptedAlgorithms)
+	}
+
+	user := host.User
+	if user == "" {
+		user = "git"
+	}
+	return append(args, user+"@"+host.HostName)
+}
+
+// IsHostKeyFailure reports whether a failed VerifyResult was caused by the
+// host key not being in known_hosts (or not matching it)
+func (r VerifyResult) IsHostKeyFailure() bool {
+	return !r.OK && strings.Contains(r.Message, "Host key verification failed")
+}
+
+// parseGreeting inspects 'ssh -T' output. It returns true with the greeting
+// line when a known success message is found, otherwise false with the last
+// non-empty line (usually the ssh error).
+func parseGreeting(output string) (bool, string) {
+	var last string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "remote:"))
+		if line == "" {
+			continue
+		}
+		for _, pattern := range successPatterns {
+			if pattern.MatchString(line) {
+				return true, line
+			}
+		}
+		last = line
+	}
+	if last == "" {
+		last = "no response from host"
+	}
+	return false, last
+}
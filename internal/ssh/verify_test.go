@@ -0,0 +1,126 @@
+package ssh
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseGreeting(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		wantOK  bool
+		wantMsg string
+	}{
+		{
+			name:    "GitHub",
+			output:  "Hi octocat! You've successfully authenticated, but GitHub does not provide shell access.\n",
+			wantOK:  true,
+			wantMsg: "Hi octocat! You've successfully authenticated, but GitHub does not provide shell access.",
+		},
+		{
+			name:    "GitLab",
+			output:  "Welcome to GitLab, @octocat!\n",
+			wantOK:  true,
+			wantMsg: "Welcome to GitLab, @octocat!",
+		},
+		{
+			name:    "Bitbucket",
+			output:  "authenticated via ssh key.\n\nYou can use git to connect to Bitbucket. Shell access is disabled\n",
+			wantOK:  true,
+			wantMsg: "authenticated via ssh key.",
+		},
+		{
+			name:    "Azure DevOps",
+			output:  "remote: Shell access is not supported.\nshell request failed on channel 0\n",
+			wantOK:  true,
+			wantMsg: "Shell access is not supported.",
+		},
+		{
+			name:    "host key warning before greeting",
+			output:  "Warning: Permanently added 'github.com' (ED25519) to the list of known hosts.\nHi octocat! You've successfully authenticated, but GitHub does not provide shell access.\n",
+			wantOK:  true,
+			wantMsg: "Hi octocat! You've successfully authenticated, but GitHub does not provide shell access.",
+		},
+		{
+			name:    "permission denied",
+			output:  "git@github.com: Permission denied (publickey).\n",
+			wantOK:  false,
+			wantMsg: "git@github.com: Permission denied (publickey).",
+		},
+		{
+			name:    "no output",
+			output:  "",
+			wantOK:  false,
+			wantMsg: "no response from host",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, msg := parseGreeting(tt.output)
+			if ok != tt.wantOK || msg != tt.wantMsg {
+				t.Errorf("parseGreeting() = %v, %q; want %v, %q", ok, msg, tt.wantOK, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestVerifyArgs(t *testing.T) {
+	args := verifyArgs(HostConfig{
+		HostName:     "git.corp.internal",
+		User:         "git",
+		IdentityFile: "/home/user/.ssh/id_work",
+		Port:         2222,
+		ProxyJump:    "bastion",
+	}, false)
+
+	for _, want := range []string{"-T", "IdentitiesOnly=yes", "IdentityFile=/home/user/.ssh/id_work", "BatchMode=yes", "StrictHostKeyChecking=yes"} {
+		if !slices.Contains(args, want) {
+			t.Errorf("verifyArgs() missing %q: %v", want, args)
+		}
+	}
+	if i := slices.Index(args, "-F"); i == -1 || args[i+1] != "none" {
+		t.Errorf("verifyArgs() missing -F none: %v", args)
+	}
+	if i := slices.Index(args, "-p"); i == -1 || args[i+1] != "2222" {
+		t.Errorf("verifyArgs() missing -p 2222: %v", args)
+	}
+	if i := slices.Index(args, "-J"); i == -1 || args[i+1] != "bastion" {
+		t.Errorf("verifyArgs() missing -J bastion: %v", args)
+	}
+	if args[len(args)-1] != "git@git.corp.internal" {
+		t.Errorf("verifyArgs() destination = %q, want git@git.corp.internal", args[len(args)-1])
+	}
+}
+
+func TestVerifyArgs_AcceptNewHostKey(t *testing.T) {
+	host := HostConfig{HostName: "github.com", IdentityFile: "/home/user/.ssh/id_work"}
+
+	if args := verifyArgs(host, true); !slices.Contains(args, "StrictHostKeyChecking=accept-new") {
+		t.Errorf("verifyArgs(accept) = %v, want StrictHostKeyChecking=accept-new", args)
+	}
+	if args := verifyArgs(host, false); slices.Contains(args, "StrictHostKeyChecking=accept-new") {
+		t.Errorf("verifyArgs() = %v, want known_hosts left alone", args)
+	}
+}
+
+func TestVerifyResult_IsHostKeyFailure(t *testing.T) {
+	tests := []struct {
+		name   string
+		result VerifyResult
+		want   bool
+	}{
+		{"unknown host", VerifyResult{Message: "Host key verification failed."}, true},
+		{"rejected key", VerifyResult{Message: "git@github.com: Permission denied (publickey)."}, false},
+		{"success", VerifyResult{OK: true, Message: "Hi octocat! You've successfully authenticated"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.IsHostKeyFailure(); got != tt.want {
+				t.Errorf("IsHostKeyFailure() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
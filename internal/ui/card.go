@@ -21,12 +21,18 @@ func buildIndicators(hasSSHKey, hasGPGKey bool) string {
 	return ""
 }
 
+// RenderTags renders identity tags as a dim "tags: a, b" line
+func RenderTags(tags []string) string {
+	return DimStyle.Render("tags: " + strings.Join(tags, ", "))
+}
+
 // RenderIdentityCard renders a single identity as a styled card.
 // If active: uses ActiveCardStyle with checkmark prefix.
 // If not active: uses CardStyle with space prefix.
 // If isDefault: appends "(default)" after the name.
+// If description is set: shows it under the email, followed by any tags.
 // If hasSSHKey or hasGPGKey: shows indicators on a third line.
-func RenderIdentityCard(name, email string, isActive, isDefault, hasSSHKey, hasGPGKey bool) string {
+func RenderIdentityCard(name, email, description string, tags []string, isActive, isDefault, hasSSHKey, hasGPGKey bool) string {
 	var prefix string
 	var style = CardStyle
 
@@ -51,6 +57,14 @@ func RenderIdentityCard(name, email string, isActive, isDefault, hasSSHKey, hasG
 	content.WriteString(nameLine)
 	content.WriteString("\n")
 	content.WriteString(emailLine)
+	if description != "" {
+		content.WriteString("\n  ")
+		content.WriteString(DimStyle.Render(description))
+	}
+	if len(tags) > 0 {
+		content.WriteString("\n  ")
+		content.WriteString(RenderTags(tags))
+	}
 	if indicators := buildIndicators(hasSSHKey, hasGPGKey); indicators != "" {
 		content.WriteString("\n")
 		content.WriteString("  ")
@@ -75,7 +89,7 @@ func RenderIdentityList(identities []config.Identity, activeEmail, defaultName s
 		isDefault := strings.EqualFold(identity.Name, defaultName)
 		hasSSHKey := identity.SSHKeyPath != ""
 		hasGPGKey := identity.GPGKeyID != ""
-		card := RenderIdentityCard(identity.Name, identity.Email, isActive, isDefault, hasSSHKey, hasGPGKey)
+		card := RenderIdentityCard(identity.Name, identity.Email, identity.Description, identity.Tags, isActive, isDefault, hasSSHKey, hasGPGKey)
 		cards = append(cards, card)
 	}
 
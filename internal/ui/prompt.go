@@ -16,6 +16,17 @@ import (
 // ErrNotInteractive is returned when stdin is not a TTY and confirmation is required.
 var ErrNotInteractive = errors.New("stdin is not a terminal; use --yes to skip confirmation")
 
+// IsInteractive reports whether stdin and stdout are both terminals,
+// i.e. whether a TUI or prompt can be shown.
+func IsInteractive() bool {
+	return isTerminal(os.Stdin.Fd()) && isTerminal(os.Stdout.Fd())
+}
+
+// isTerminal reports whether fd is a terminal (including Cygwin terminals)
+func isTerminal(fd uintptr) bool {
+	return isatty.IsTerminal(fd) || isatty.IsCygwinTerminal(fd)
+}
+
 // ConfirmPrompt asks for y/N confirmation.
 // Returns true if user confirms, false otherwise.
 // If stdin is not a TTY and skipConfirm is false, returns ErrNotInteractive.
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/orzazade/gitch/internal/config"
+	"github.com/orzazade/gitch/internal/gpg"
 	"github.com/orzazade/gitch/internal/ui"
 )
 
@@ -15,6 +16,10 @@ type Model struct {
 	cursor      int
 	activeEmail string
 	defaultName string
+	shared      map[string][]string
+	gpgMissing  map[string]bool // identity names whose GPG key is not in the keyring
+	filter      string          // type-to-filter query
+	visible     []int           // indices into identities matching filter; cursor indexes this
 	Selected    *config.Identity
 	Cancelled   bool
 }
@@ -22,12 +27,35 @@ type Model struct {
 // New creates a new selector model.
 // The cursor starts on the currently active identity (for quick re-confirmation).
 func New(identities []config.Identity, activeEmail, defaultName string) Model {
+	visible := make([]int, len(identities))
+	for i := range identities {
+		visible[i] = i
+	}
+
 	return Model{
 		identities:  identities,
 		cursor:      findActiveIndex(identities, activeEmail),
 		activeEmail: activeEmail,
 		defaultName: defaultName,
+		shared:      (&config.Config{Identities: identities}).DuplicateEmails(),
+		gpgMissing:  missingGPGKeys(identities),
+		visible:     visible,
+	}
+}
+
+// missingGPGKeys checks each identity's GPG key against the keyring once,
+// so View doesn't run gpg on every render. Returns nil if gpg isn't installed.
+func missingGPGKeys(identities []config.Identity) map[string]bool {
+	if !gpg.IsGPGAvailable() {
+		return nil
+	}
+	missing := make(map[string]bool)
+	for _, id := range identities {
+		if id.GPGKeyID != "" && gpg.ValidateKeyID(id.GPGKeyID) != nil {
+			missing[id.Name] = true
+		}
 	}
+	return missing
 }
 
 // findActiveIndex returns the index of the active identity, or 0.
@@ -40,6 +68,45 @@ func findActiveIndex(identities []config.Identity, activeEmail string) int {
 	return 0
 }
 
+// matchesFilter reports whether the identity's name, email or one of its
+// tags contains query (case-insensitive).
+func matchesFilter(id config.Identity, query string) bool {
+	query = strings.ToLower(query)
+	if strings.Contains(strings.ToLower(id.Name), query) ||
+		strings.Contains(strings.ToLower(id.Email), query) {
+		return true
+	}
+	for _, tag := range id.Tags {
+		if strings.Contains(strings.ToLower(tag), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// setFilter updates the query and the visible identities. The cursor stays on
+// the same identity if it is still visible, otherwise it moves to the first.
+func (m *Model) setFilter(query string) {
+	current := -1
+	if m.cursor < len(m.visible) {
+		current = m.visible[m.cursor]
+	}
+
+	visible := make([]int, 0, len(m.identities))
+	m.cursor = 0
+	for i, id := range m.identities {
+		if !matchesFilter(id, query) {
+			continue
+		}
+		if i == current {
+			m.cursor = len(visible)
+		}
+		visible = append(visible, i)
+	}
+	m.filter = query
+	m.visible = visible
+}
+
 // Init is the Bubble Tea init function.
 func (m Model) Init() tea.Cmd {
 	return nil
@@ -53,24 +120,50 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyRunes:
+			// Letters type into the filter, so j/k/q no longer navigate or quit
+			if !msg.Alt {
+				m.setFilter(m.filter + string(msg.Runes))
+			}
+			return m, nil
+		case tea.KeyBackspace:
+			if m.filter != "" {
+				runes := []rune(m.filter)
+				m.setFilter(string(runes[:len(runes)-1]))
+			}
+			return m, nil
+		case tea.KeyEsc:
+			// First Esc clears the filter, second one quits
+			if m.filter != "" {
+				m.setFilter("")
+				return m, nil
+			}
+			m.Cancelled = true
+			return m, tea.Quit
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "q", "esc":
+		case "ctrl+c":
 			m.Cancelled = true
 			return m, tea.Quit
-		case "up", "k":
+		case "up":
 			if m.cursor > 0 {
 				m.cursor--
 			}
-		case "down", "j":
-			if m.cursor < len(m.identities)-1 {
+		case "down":
+			if m.cursor < len(m.visible)-1 {
 				m.cursor++
 			}
 		case "home":
 			m.cursor = 0
 		case "end":
-			m.cursor = len(m.identities) - 1
+			m.cursor = max(len(m.visible)-1, 0)
 		case "enter":
-			m.Selected = &m.identities[m.cursor]
+			if len(m.visible) == 0 {
+				return m, nil
+			}
+			m.Selected = &m.identities[m.visible[m.cursor]]
 			return m, tea.Quit
 		}
 	}
@@ -88,37 +181,72 @@ func (m Model) View() string {
 
 	b.WriteString("Select an identity:\n\n")
 
-	for i, identity := range m.identities {
-		isActive := strings.EqualFold(identity.Email, m.activeEmail)
-		isDefault := strings.EqualFold(identity.Name, m.defaultName)
-		hasSSH := identity.SSHKeyPath != ""
-		hasGPG := identity.GPGKeyID != ""
-		isCursor := i == m.cursor
+	if len(m.visible) == 0 {
+		b.WriteString(ui.DimStyle.Render("  No identities match the filter."))
+		b.WriteString("\n")
+	}
 
-		card := renderSelectableCard(identity, isActive, isDefault, hasSSH, hasGPG, isCursor)
+	for i, idx := range m.visible {
+		identity := m.identities[idx]
+		card := renderSelectableCard(identity, cardOptions{
+			active:     strings.EqualFold(identity.Email, m.activeEmail),
+			isDefault:  strings.EqualFold(identity.Name, m.defaultName),
+			cursor:     i == m.cursor,
+			hasSSH:     identity.SSHKeyPath != "",
+			hasGPG:     identity.GPGKeyID != "",
+			gpgMissing: m.gpgMissing[identity.Name],
+			sharedWith: otherNames(m.shared[strings.ToLower(identity.Email)], identity.Name),
+		})
 		b.WriteString(card)
 		b.WriteString("\n")
 	}
 
-	b.WriteString(ui.DimStyle.Render("Up/Down Navigate  Enter Select  q Quit"))
+	if m.filter != "" {
+		b.WriteString("Filter: " + m.filter + "\n")
+		b.WriteString(ui.DimStyle.Render("Up/Down Navigate  Enter Select  Backspace Edit  Esc Clear"))
+	} else {
+		b.WriteString(ui.DimStyle.Render("Type to filter  Up/Down Navigate  Enter Select  Esc Quit"))
+	}
 
 	return b.String()
 }
 
+// otherNames returns names without self
+func otherNames(names []string, self string) []string {
+	var others []string
+	for _, name := range names {
+		if name != self {
+			others = append(others, name)
+		}
+	}
+	return others
+}
+
+// cardOptions describes how an identity card is shown in the selector
+type cardOptions struct {
+	active     bool     // the identity is the one git uses
+	isDefault  bool     // the identity is the default
+	cursor     bool     // the cursor is on the card
+	hasSSH     bool     // an SSH key is configured
+	hasGPG     bool     // a GPG key is configured
+	gpgMissing bool     // the GPG key is not in the keyring
+	sharedWith []string // other identities using the same email
+}
+
 // renderSelectableCard renders an identity card with cursor highlighting.
 // When cursor is on this card, use ActiveCardStyle (green border).
 // When this is the active identity, show checkmark.
 // Both can be true (cursor on active identity).
-func renderSelectableCard(id config.Identity, active, def, ssh, gpg, cursor bool) string {
+func renderSelectableCard(id config.Identity, opts cardOptions) string {
 	// Card style based on cursor position
 	style := ui.CardStyle
-	if cursor {
+	if opts.cursor {
 		style = ui.ActiveCardStyle
 	}
 
 	// Checkmark for active identity (same as card.go)
 	var prefix string
-	if active {
+	if opts.active {
 		prefix = ui.CheckmarkStyle.Render("\u2713 ") // checkmark indicator
 	} else {
 		prefix = "  "
@@ -126,7 +254,7 @@ func renderSelectableCard(id config.Identity, active, def, ssh, gpg, cursor bool
 
 	// Name line with optional default marker
 	nameLine := prefix + ui.NameStyle.Render(id.Name)
-	if def {
+	if opts.isDefault {
 		nameLine += " (default)"
 	}
 
@@ -139,22 +267,47 @@ func renderSelectableCard(id config.Identity, active, def, ssh, gpg, cursor bool
 	content.WriteString("\n")
 	content.WriteString(emailLine)
 
-	// Build indicators line
-	var indicators []string
-	if ssh {
-		indicators = append(indicators, "SSH")
+	// Free-form note telling similar identities apart
+	if id.Description != "" {
+		content.WriteString("\n  ")
+		content.WriteString(ui.DimStyle.Render(id.Description))
+	}
+	if len(id.Tags) > 0 {
+		content.WriteString("\n  ")
+		content.WriteString(ui.RenderTags(id.Tags))
+	}
+
+	// Key indicator lines
+	if opts.hasSSH {
+		content.WriteString("\n  ")
+		content.WriteString(ui.DimStyle.Render("SSH configured"))
 	}
-	if gpg {
-		indicators = append(indicators, "GPG")
+	if opts.hasGPG {
+		content.WriteString("\n  ")
+		if opts.gpgMissing {
+			content.WriteString(ui.WarningStyle.Render("GPG key " + shortKeyID(id.GPGKeyID) + " not in keyring"))
+		} else {
+			content.WriteString(ui.DimStyle.Render("GPG configured (" + shortKeyID(id.GPGKeyID) + ")"))
+		}
 	}
-	if len(indicators) > 0 {
+
+	// Shared email makes switching between these identities ambiguous
+	if len(opts.sharedWith) > 0 {
 		content.WriteString("\n  ")
-		content.WriteString(ui.DimStyle.Render(strings.Join(indicators, " | ") + " configured"))
+		content.WriteString(ui.WarningStyle.Render("shared email with " + strings.Join(opts.sharedWith, ", ")))
 	}
 
 	return style.Render(content.String())
 }
 
+// shortKeyID returns the last 8 characters of a GPG key ID
+func shortKeyID(keyID string) string {
+	if len(keyID) <= 8 {
+		return keyID
+	}
+	return keyID[len(keyID)-8:]
+}
+
 // Run launches the selector and returns the selected identity.
 // Returns nil if cancelled or no selection made.
 func Run(identities []config.Identity, activeEmail, defaultName string) (*config.Identity, error) {
@@ -0,0 +1,130 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/orzazade/gitch/internal/config"
+)
+
+func testIdentities() []config.Identity {
+	return []config.Identity{
+		{Name: "work", Email: "me@company.com"},
+		{Name: "personal", Email: "me@example.com"},
+		{Name: "oss", Email: "oss@Example.com"},
+	}
+}
+
+func typeKeys(m Model, s string) Model {
+	for _, r := range s {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(Model)
+	}
+	return m
+}
+
+func press(m Model, key tea.KeyType) Model {
+	updated, _ := m.Update(tea.KeyMsg{Type: key})
+	return updated.(Model)
+}
+
+func visibleNames(m Model) []string {
+	var names []string
+	for _, idx := range m.visible {
+		names = append(names, m.identities[idx].Name)
+	}
+	return names
+}
+
+func TestFilter_NarrowsByNameAndEmail(t *testing.T) {
+	m := New(testIdentities(), "", "")
+
+	m = typeKeys(m, "EXAMPLE")
+	if got := visibleNames(m); len(got) != 2 || got[0] != "personal" || got[1] != "oss" {
+		t.Errorf("filter EXAMPLE: visible = %v, want [personal oss]", got)
+	}
+
+	m = press(m, tea.KeyEnter)
+	if m.Selected == nil || m.Selected.Name != "personal" {
+		t.Errorf("expected personal selected, got %+v", m.Selected)
+	}
+}
+
+func TestFilter_MatchesTags(t *testing.T) {
+	identities := testIdentities()
+	identities[2].Tags = []string{"Client-A"}
+	m := New(identities, "", "")
+
+	m = typeKeys(m, "client")
+	if got := visibleNames(m); len(got) != 1 || got[0] != "oss" {
+		t.Errorf("filter client: visible = %v, want [oss]", got)
+	}
+}
+
+func TestFilter_KeepsCursorOnVisibleIdentity(t *testing.T) {
+	m := New(testIdentities(), "oss@example.com", "")
+	if m.cursor != 2 {
+		t.Fatalf("initial cursor = %d, want 2 (active identity)", m.cursor)
+	}
+
+	m = typeKeys(m, "o")
+	if m.identities[m.visible[m.cursor]].Name != "oss" {
+		t.Errorf("cursor moved off oss after filtering: %v[%d]", visibleNames(m), m.cursor)
+	}
+
+	m = typeKeys(m, "rk")
+	if got := visibleNames(m); len(got) != 1 || got[0] != "work" || m.cursor != 0 {
+		t.Errorf("filter ork: visible = %v cursor = %d, want [work] at 0", got, m.cursor)
+	}
+}
+
+func TestFilter_BackspaceAndEsc(t *testing.T) {
+	m := New(testIdentities(), "", "")
+
+	m = typeKeys(m, "wx")
+	if len(m.visible) != 0 {
+		t.Errorf("filter wx: visible = %v, want none", visibleNames(m))
+	}
+	if m = press(m, tea.KeyEnter); m.Selected != nil {
+		t.Error("enter with no matches should not select")
+	}
+
+	m = press(m, tea.KeyBackspace)
+	if m.filter != "w" || len(m.visible) != 1 {
+		t.Errorf("after backspace: filter = %q visible = %v", m.filter, visibleNames(m))
+	}
+
+	m = press(m, tea.KeyEsc)
+	if m.Cancelled || m.filter != "" || len(m.visible) != 3 {
+		t.Errorf("first esc should clear the filter: cancelled=%v filter=%q", m.Cancelled, m.filter)
+	}
+
+	m = press(m, tea.KeyEsc)
+	if !m.Cancelled {
+		t.Error("second esc should cancel")
+	}
+}
+
+func TestRenderSelectableCard_Description(t *testing.T) {
+	id := config.Identity{Name: "work-contract", Email: "me@acme.com", Description: "ACME contractor account"}
+
+	card := renderSelectableCard(id, cardOptions{})
+	if !strings.Contains(card, "ACME contractor account") {
+		t.Errorf("expected description on the card, got:\n%s", card)
+	}
+}
+
+func TestRenderSelectableCard_GPG(t *testing.T) {
+	id := config.Identity{Name: "work", Email: "me@company.com", GPGKeyID: "2B0D434EE4548BA1"}
+
+	card := renderSelectableCard(id, cardOptions{hasGPG: true})
+	if !strings.Contains(card, "GPG configured (E4548BA1)") {
+		t.Errorf("expected GPG configured with short key ID, got:\n%s", card)
+	}
+
+	card = renderSelectableCard(id, cardOptions{hasGPG: true, gpgMissing: true})
+	if !strings.Contains(card, "GPG key E4548BA1 not in keyring") {
+		t.Errorf("expected missing key warning, got:\n%s", card)
+	}
+}
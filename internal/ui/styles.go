@@ -1,7 +1,12 @@
 // Package ui provides styled terminal output using lipgloss.
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
 
 // Colors
 var (
@@ -39,3 +44,29 @@ var (
 	SuccessStyle   = lipgloss.NewStyle().Foreground(SuccessColor)
 	DimStyle       = lipgloss.NewStyle().Foreground(InactiveColor)
 )
+
+// ConfigureColor turns styling off when disabled is set (--no-color), when
+// the NO_COLOR environment variable is non-empty, or when stdout is not a
+// terminal. Styles then render plain text.
+func ConfigureColor(disabled bool) {
+	if disabled || !ColorEnabled() {
+		DisableColor()
+	}
+}
+
+// ColorEnabled reports whether the environment allows color: NO_COLOR is
+// unset or empty (see https://no-color.org) and stdout is a terminal.
+func ColorEnabled() bool {
+	return !noColorSet() && isTerminal(os.Stdout.Fd())
+}
+
+// noColorSet reports whether NO_COLOR asks for no color. An empty value
+// doesn't count, as the convention requires.
+func noColorSet() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// DisableColor makes all styles render without colors or text attributes.
+func DisableColor() {
+	lipgloss.SetColorProfile(termenv.Ascii)
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestDisableColor(t *testing.T) {
+	original := lipgloss.ColorProfile()
+	t.Cleanup(func() { lipgloss.SetColorProfile(original) })
+
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	if got := SuccessStyle.Render("ok"); !strings.Contains(got, "\x1b[") {
+		t.Fatalf("expected colored output before DisableColor, got %q", got)
+	}
+
+	DisableColor()
+	for name, style := range map[string]lipgloss.Style{
+		"SuccessStyle": SuccessStyle,
+		"NameStyle":    NameStyle,
+		"DimStyle":     DimStyle,
+	} {
+		if got := style.Render("ok"); got != "ok" {
+			t.Errorf("%s.Render() = %q, want plain %q", name, got, "ok")
+		}
+	}
+}
+
+func TestColorEnabled_NoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled() {
+		t.Error("ColorEnabled() = true with NO_COLOR set, want false")
+	}
+}
+
+func TestNoColorSet(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "1": true, "false": true} {
+		t.Setenv("NO_COLOR", value)
+		if got := noColorSet(); got != want {
+			t.Errorf("noColorSet() with NO_COLOR=%q = %v, want %v", value, got, want)
+		}
+	}
+}
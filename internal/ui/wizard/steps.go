@@ -1,6 +1,16 @@
 // Package wizard provides an interactive setup wizard for creating identities.
 package wizard
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/rules"
+)
+
 // Step constants for the wizard flow
 const (
 	stepName           = 0
@@ -14,6 +24,8 @@ const (
 	stepGPGKeyID       = 8  // New: enter existing GPG key ID
 	stepGPGPassphrase  = 9  // Moved: was 7
 	stepGPGConfirmPass = 10 // Moved: was 8
+	stepRule           = 11 // New: optional auto-switch rule
+	stepGPGKeySelect   = 12 // New: pick an existing GPG key found by email
 )
 
 // sshOptions are the choices for SSH key handling
@@ -60,8 +72,78 @@ const sshKeyTypeEd25519 = 0
 // sshKeyTypeRSA is the index for RSA key type
 const sshKeyTypeRSA = 1
 
+// gpgKeyManualOption is the extra choice after the detected GPG keys
+const gpgKeyManualOption = "Enter key ID manually"
+
+// gpgKeyLabel describes a detected GPG key for the key selection step
+func gpgKeyLabel(k gpgpkg.KeyInfo, now time.Time) string {
+	var details []string
+	if k.Algorithm != "" {
+		details = append(details, k.Algorithm)
+	}
+	if !k.Created.IsZero() {
+		details = append(details, "created "+k.Created.Format("2006-01-02"))
+	}
+	if k.IsExpired(now) {
+		details = append(details, "expired")
+	}
+	if len(details) == 0 {
+		return k.ID
+	}
+	return fmt.Sprintf("%s (%s)", k.ID, strings.Join(details, ", "))
+}
+
+// ruleOption is a choice on the rule step; rule is nil for "no rule"
+type ruleOption struct {
+	label string
+	rule  *rules.Rule
+}
+
+// buildRuleOptions returns the rule choices for cwd and remoteURL, and the
+// index selected by default: the remote rule when a remote was detected,
+// otherwise "no rule".
+func buildRuleOptions(cwd, remoteURL string) ([]ruleOption, int) {
+	var options []ruleOption
+
+	if remoteURL != "" {
+		if remote, err := rules.ParseRemote(remoteURL); err == nil {
+			if pattern := remote.OrgPattern(); pattern != "" {
+				options = append(options, ruleOption{
+					label: "Use for remotes matching " + pattern,
+					rule:  &rules.Rule{Type: rules.RemoteRule, Pattern: pattern},
+				})
+			}
+		}
+	}
+
+	if cwd != "" {
+		pattern := rules.DirectoryPattern(cwd)
+		options = append(options, ruleOption{
+			label: "Use in " + pattern,
+			rule:  &rules.Rule{Type: rules.DirectoryRule, Pattern: pattern},
+		})
+
+		if parent := filepath.Dir(cwd); parent != cwd && parent != filepath.Dir(parent) {
+			pattern := rules.DirectoryPattern(parent)
+			options = append(options, ruleOption{
+				label: "Use in " + pattern,
+				rule:  &rules.Rule{Type: rules.DirectoryRule, Pattern: pattern},
+			})
+		}
+	}
+
+	options = append(options, ruleOption{label: "Don't create a rule"})
+
+	defaultChoice := len(options) - 1
+	if options[0].rule != nil && options[0].rule.Type == rules.RemoteRule {
+		defaultChoice = 0
+	}
+	return options, defaultChoice
+}
+
 // getTotalSteps returns the total number of steps based on SSH and GPG choices.
-func getTotalSteps(sshChoice, gpgChoice int, sshPassphraseEmpty, gpgPassphraseEmpty bool) int {
+// existingGPGKeySteps is the number of steps for choosing an existing GPG key.
+func getTotalSteps(sshChoice, gpgChoice int, sshPassphraseEmpty, gpgPassphraseEmpty bool, existingGPGKeySteps int) int {
 	total := 3 // name, email, ssh choice
 
 	// Add SSH steps based on choice
@@ -89,9 +171,12 @@ func getTotalSteps(sshChoice, gpgChoice int, sshPassphraseEmpty, gpgPassphraseEm
 			total += 2 // passphrase + confirm
 		}
 	case gpgChoiceUseExisting:
-		total++ // key ID step
+		total += existingGPGKeySteps // key list and/or key ID
 	}
 
+	// Always add rule step
+	total++
+
 	return total
 }
 
@@ -120,6 +205,10 @@ func getStepTitle(step int) string {
 		return "Enter a passphrase for your GPG key (optional, press Enter to skip)"
 	case stepGPGConfirmPass:
 		return "Confirm your GPG passphrase"
+	case stepRule:
+		return "Would you like to use this identity automatically here?"
+	case stepGPGKeySelect:
+		return "Select your existing GPG key"
 	default:
 		return ""
 	}
@@ -150,6 +239,10 @@ func getStepHint(step int) string {
 		return "Leave empty for no passphrase"
 	case stepGPGConfirmPass:
 		return "Type your passphrase again to confirm"
+	case stepRule:
+		return "Creates a rule so 'gitch auto' and the pre-commit hook pick this identity"
+	case stepGPGKeySelect:
+		return "Secret keys in your keyring matching this email"
 	default:
 		return ""
 	}
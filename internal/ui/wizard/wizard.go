@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -14,6 +15,7 @@ import (
 	"github.com/orzazade/gitch/internal/config"
 	gitpkg "github.com/orzazade/gitch/internal/git"
 	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/rules"
 	sshpkg "github.com/orzazade/gitch/internal/ssh"
 	"github.com/orzazade/gitch/internal/ui"
 )
@@ -29,6 +31,7 @@ type WizardResult struct {
 	GPGKeyID       string
 	GenerateGPG    bool
 	UseExistingGPG bool
+	Rule           *rules.Rule // optional auto-switch rule bound to Name; nil if declined
 }
 
 // Model is the Bubble Tea model for the setup wizard
@@ -61,6 +64,13 @@ type Model struct {
 	generatedGPGKeyID    string // track GPG result for later
 	existingSSHKeyPath   string // track existing SSH key path
 	existingGPGKeyID     string // track existing GPG key ID
+	gpgGenerated         bool   // GPG key was generated (for buildResult)
+	gpgExisting          bool   // existing GPG key was chosen (for buildResult)
+	ruleOptions          []ruleOption
+	ruleChoice           int
+	gpgKeys              []gpgpkg.KeyInfo // existing keys matching the email
+	gpgKeyChoice         int              // index into gpgKeys; len(gpgKeys) = manual entry
+	gpgKeyManual         bool             // manual entry was chosen from the detected keys
 }
 
 // titleStyle is the style for the wizard header
@@ -162,12 +172,17 @@ func New() Model {
 	)
 
 	// Detect Azure DevOps remote for key type default
-	isAzureDevOps, _ := gitpkg.GetCurrentRemoteType()
+	isAzureDevOps := gitpkg.IsAzureDevOps()
 	sshKeyTypeDefault := sshKeyTypeEd25519
 	if isAzureDevOps {
 		sshKeyTypeDefault = sshKeyTypeRSA
 	}
 
+	// Offer rules for the current directory and remote
+	cwd, _ := os.Getwd()
+	remoteURL, _ := rules.GetGitRemoteURL()
+	ruleOptions, ruleChoice := buildRuleOptions(cwd, remoteURL)
+
 	return Model{
 		step:               stepName,
 		nameInput:          nameInput,
@@ -184,6 +199,8 @@ func New() Model {
 		gpgConfirmInput:    gpgConfirmInput,
 		spinner:            s,
 		progress:           p,
+		ruleOptions:        ruleOptions,
+		ruleChoice:         ruleChoice,
 	}
 }
 
@@ -213,10 +230,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case gpgKeyGenerated:
 		m.loading = false
 		m.generatedGPGKeyID = msg.keyID
-		// GPG generation complete, finish wizard
-		m.result = m.buildResult(true, false)
-		m.done = true
-		return m, tea.Quit
+		m.gpgGenerated = true
+		// GPG generation complete, move to rule step
+		m.step = stepRule
+		return m, nil
 
 	case gpgKeyError:
 		m.loading = false
@@ -242,9 +259,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Cancelled = true
 				return m, tea.Quit
 			}
-			// Go back to previous step
+			// Go back to previous step, clearing a half-typed confirmation
 			m.err = nil
 			m.warning = ""
+			switch m.step {
+			case stepSSHConfirmPass:
+				m.sshConfirmInput.Reset()
+			case stepGPGConfirmPass:
+				m.gpgConfirmInput.Reset()
+			}
 			m.step = m.getPreviousStep()
 			return m, m.focusCurrentInput()
 
@@ -270,6 +293,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+			if m.step == stepRule {
+				if m.ruleChoice > 0 {
+					m.ruleChoice--
+				}
+				return m, nil
+			}
+			if m.step == stepGPGKeySelect {
+				if m.gpgKeyChoice > 0 {
+					m.gpgKeyChoice--
+				}
+				return m, nil
+			}
 
 		case "down", "j":
 			if m.step == stepSSH {
@@ -290,6 +325,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+			if m.step == stepRule {
+				if m.ruleChoice < len(m.ruleOptions)-1 {
+					m.ruleChoice++
+				}
+				return m, nil
+			}
+			if m.step == stepGPGKeySelect {
+				if m.gpgKeyChoice < len(m.gpgKeys) {
+					m.gpgKeyChoice++
+				}
+				return m, nil
+			}
 		}
 	}
 
@@ -330,13 +377,12 @@ func (m Model) getPreviousStep() int {
 	case stepSSHPassphrase:
 		return stepSSHKeyType
 	case stepSSHConfirmPass:
-		m.sshConfirmInput.Reset()
 		return stepSSHPassphrase
 	case stepGPG:
 		// Go back based on SSH choice
 		switch m.sshChoice {
 		case sshChoiceGenerate:
-			if m.sshPassphraseInput.Value() == "" {
+			if !m.hasSSHConfirmStep() {
 				return stepSSHPassphrase
 			}
 			return stepSSHConfirmPass
@@ -346,12 +392,25 @@ func (m Model) getPreviousStep() int {
 			return stepSSH
 		}
 	case stepGPGKeyID:
+		if len(m.gpgKeys) > 0 {
+			return stepGPGKeySelect
+		}
+		return stepGPG
+	case stepGPGKeySelect:
 		return stepGPG
 	case stepGPGPassphrase:
 		return stepGPG
 	case stepGPGConfirmPass:
-		m.gpgConfirmInput.Reset()
 		return stepGPGPassphrase
+	case stepRule:
+		// Go back based on GPG choice
+		if m.gpgChoice == gpgChoiceUseExisting {
+			if m.gpgKeyChoice < len(m.gpgKeys) {
+				return stepGPGKeySelect
+			}
+			return stepGPGKeyID
+		}
+		return stepGPG
 	default:
 		return m.step - 1
 	}
@@ -461,21 +520,41 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 	case stepGPG:
 		m.err = nil
 		m.warning = ""
+		m.gpgGenerated = false
+		m.gpgExisting = false
+		m.gpgKeyManual = false
 		switch m.gpgChoice {
 		case gpgChoiceSkip:
-			// Skip GPG, complete the wizard
-			m.result = m.buildResult(false, false)
-			m.done = true
-			return m, tea.Quit
+			// Skip GPG, continue to rule step
+			m.step = stepRule
+			return m, nil
 		case gpgChoiceUseExisting:
 			// Use existing GPG key, go to key ID input
 			if !gpgpkg.IsGPGAvailable() {
 				m.err = fmt.Errorf("gpg command not found - install GPG to use GPG features")
 				return m, nil
 			}
+			// Offer keys matching the email; fall back to manual entry
+			keys, _ := gpgpkg.FindKeyByEmail(strings.TrimSpace(m.emailInput.Value()))
+			m.gpgKeys = keys
+			m.gpgKeyChoice = 0
+			if len(keys) == 1 && m.gpgKeyIDInput.Value() == "" {
+				m.gpgKeyIDInput.SetValue(keys[0].ID)
+			}
+			if len(keys) > 0 {
+				m.step = stepGPGKeySelect
+				return m, nil
+			}
 			m.step = stepGPGKeyID
 			return m, m.gpgKeyIDInput.Focus()
 		default:
+			// Reuse a key generated before going back, rather than leaving
+			// it orphaned in the keyring
+			if m.generatedGPGKeyID != "" {
+				m.gpgGenerated = true
+				m.step = stepRule
+				return m, nil
+			}
 			// Generate new key
 			// Check if GPG is available
 			if !gpgpkg.IsGPGAvailable() {
@@ -498,12 +577,24 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 			m.err = err
 			return m, nil
 		}
-		// Store the key ID and complete wizard
+		// Store the key ID and continue to rule step
 		m.existingGPGKeyID = keyID
+		m.gpgExisting = true
 		m.err = nil
-		m.result = m.buildResult(false, true)
-		m.done = true
-		return m, tea.Quit
+		m.step = stepRule
+		return m, nil
+
+	case stepGPGKeySelect:
+		m.err = nil
+		m.gpgKeyManual = m.gpgKeyChoice == len(m.gpgKeys)
+		if m.gpgKeyManual {
+			m.step = stepGPGKeyID
+			return m, m.gpgKeyIDInput.Focus()
+		}
+		m.existingGPGKeyID = m.gpgKeys[m.gpgKeyChoice].ID
+		m.gpgExisting = true
+		m.step = stepRule
+		return m, nil
 
 	case stepGPGPassphrase:
 		passphrase := m.gpgPassphraseInput.Value()
@@ -528,6 +619,13 @@ func (m Model) handleEnter() (tea.Model, tea.Cmd) {
 		}
 		m.err = nil
 		return m.startGPGKeyGeneration()
+
+	case stepRule:
+		// Complete the wizard
+		m.err = nil
+		m.result = m.buildResult(m.gpgGenerated, m.gpgExisting)
+		m.done = true
+		return m, tea.Quit
 	}
 
 	return m, nil
@@ -747,6 +845,11 @@ func (m Model) View() string {
 			}
 			b.WriteString("\n")
 		}
+		if m.generatedGPGKeyID != "" {
+			b.WriteString("\n  ")
+			b.WriteString(ui.DimStyle.Render(fmt.Sprintf("(GPG key %s already generated - choosing Generate reuses it)", m.generatedGPGKeyID)))
+			b.WriteString("\n")
+		}
 
 	case stepGPGKeyID:
 		b.WriteString("  > ")
@@ -762,6 +865,35 @@ func (m Model) View() string {
 		b.WriteString("  > ")
 		b.WriteString(m.gpgConfirmInput.View())
 		b.WriteString("\n")
+
+	case stepGPGKeySelect:
+		now := time.Now()
+		for i := 0; i <= len(m.gpgKeys); i++ {
+			option := gpgKeyManualOption
+			if i < len(m.gpgKeys) {
+				option = gpgKeyLabel(m.gpgKeys[i], now)
+			}
+			if i == m.gpgKeyChoice {
+				b.WriteString("  ")
+				b.WriteString(ui.SuccessStyle.Render("> " + option))
+			} else {
+				b.WriteString("    ")
+				b.WriteString(ui.DimStyle.Render(option))
+			}
+			b.WriteString("\n")
+		}
+
+	case stepRule:
+		for i, option := range m.ruleOptions {
+			if i == m.ruleChoice {
+				b.WriteString("  ")
+				b.WriteString(ui.SuccessStyle.Render("> " + option.label))
+			} else {
+				b.WriteString("    ")
+				b.WriteString(ui.DimStyle.Render(option.label))
+			}
+			b.WriteString("\n")
+		}
 	}
 
 	// Error message
@@ -781,9 +913,7 @@ func (m Model) View() string {
 
 // renderProgress renders the progress bar with step indicator
 func (m Model) renderProgress() string {
-	sshPassEmpty := m.sshPassphraseInput.Value() == ""
-	gpgPassEmpty := m.gpgPassphraseInput.Value() == ""
-	total := getTotalSteps(m.sshChoice, m.gpgChoice, sshPassEmpty, gpgPassEmpty)
+	total := getTotalSteps(m.sshChoice, m.gpgChoice, !m.hasSSHConfirmStep(), !m.hasGPGConfirmStep(), m.existingGPGKeySteps())
 
 	// Calculate current step number for display
 	displayStep := m.getDisplayStep()
@@ -818,12 +948,16 @@ func (m Model) getDisplayStep() int {
 		return 6
 	case stepGPG:
 		return m.getGPGBaseStep()
-	case stepGPGKeyID:
+	case stepGPGKeySelect:
 		return m.getGPGBaseStep() + 1
+	case stepGPGKeyID:
+		return m.getGPGBaseStep() + m.existingGPGKeySteps()
 	case stepGPGPassphrase:
 		return m.getGPGBaseStep() + 1
 	case stepGPGConfirmPass:
 		return m.getGPGBaseStep() + 2
+	case stepRule:
+		return m.getRuleStep()
 	default:
 		return m.step + 1
 	}
@@ -834,12 +968,12 @@ func (m Model) getGPGBaseStep() int {
 	base := 3 // name, email, ssh choice
 	switch m.sshChoice {
 	case sshChoiceSkip:
-		base++ // just ssh choice
+		base++ // gpg choice
 	case sshChoiceUseExisting:
-		base += 2 // ssh choice + key path
+		base += 2 // key path + gpg choice
 	case sshChoiceGenerate:
-		base++ // key type step
-		if m.sshPassphraseInput.Value() == "" {
+		base += 2 // key type + gpg choice
+		if !m.hasSSHConfirmStep() {
 			base++ // passphrase only
 		} else {
 			base += 2 // passphrase + confirm
@@ -848,6 +982,45 @@ func (m Model) getGPGBaseStep() int {
 	return base
 }
 
+// hasSSHConfirmStep reports whether the flow includes the SSH passphrase
+// confirmation. It uses the submitted passphrase rather than the live input
+// so the step count doesn't change while the user is typing.
+func (m Model) hasSSHConfirmStep() bool {
+	return len(m.sshPassphrase) > 0
+}
+
+// hasGPGConfirmStep reports whether the flow includes the GPG passphrase
+// confirmation, based on the submitted passphrase.
+func (m Model) hasGPGConfirmStep() bool {
+	return len(m.gpgPassphrase) > 0
+}
+
+// existingGPGKeySteps returns the number of steps for choosing an existing
+// GPG key: the detected key list or manual entry, or both when manual entry
+// is chosen from the list. Like the confirm steps, it uses the submitted
+// choice so moving through the list doesn't change the step count.
+func (m Model) existingGPGKeySteps() int {
+	if len(m.gpgKeys) > 0 && m.gpgKeyManual {
+		return 2
+	}
+	return 1
+}
+
+// getRuleStep returns the step number for the rule step
+func (m Model) getRuleStep() int {
+	step := m.getGPGBaseStep() + 1
+	switch m.gpgChoice {
+	case gpgChoiceUseExisting:
+		step += m.existingGPGKeySteps() // key list and/or key ID
+	case gpgChoiceGenerate:
+		step++ // passphrase step
+		if m.hasGPGConfirmStep() {
+			step++ // confirm step
+		}
+	}
+	return step
+}
+
 // getSSHKeyTypeString returns the key type as a string for the result
 func (m Model) getSSHKeyTypeString() string {
 	if m.sshChoice == sshChoiceSkip || m.sshChoice == sshChoiceUseExisting {
@@ -877,6 +1050,14 @@ func (m Model) buildResult(gpgGenerated, gpgExisting bool) *WizardResult {
 		gpgKeyID = m.existingGPGKeyID
 	}
 
+	// Bind the chosen rule to the new identity
+	var rule *rules.Rule
+	if m.ruleChoice < len(m.ruleOptions) && m.ruleOptions[m.ruleChoice].rule != nil {
+		r := *m.ruleOptions[m.ruleChoice].rule
+		r.Identity = strings.TrimSpace(m.nameInput.Value())
+		rule = &r
+	}
+
 	return &WizardResult{
 		Name:           strings.TrimSpace(m.nameInput.Value()),
 		Email:          strings.TrimSpace(m.emailInput.Value()),
@@ -887,6 +1068,7 @@ func (m Model) buildResult(gpgGenerated, gpgExisting bool) *WizardResult {
 		GPGKeyID:       gpgKeyID,
 		GenerateGPG:    gpgGenerated,
 		UseExistingGPG: gpgExisting,
+		Rule:           rule,
 	}
 }
 
@@ -896,7 +1078,7 @@ func (m Model) renderHints() string {
 	switch m.step {
 	case stepName:
 		hints = "Enter Continue  Esc Quit"
-	case stepSSH, stepSSHKeyType, stepGPG:
+	case stepSSH, stepSSHKeyType, stepGPG, stepGPGKeySelect, stepRule:
 		hints = "Up/Down Select  Enter Confirm  Esc Back"
 	default:
 		hints = "Enter Continue  Esc Back"
@@ -0,0 +1,350 @@
+package wizard
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	gpgpkg "github.com/orzazade/gitch/internal/gpg"
+	"github.com/orzazade/gitch/internal/gpg/gpgtest"
+)
+
+// Ways of choosing an existing GPG key
+const (
+	gpgKeysNone       = iota // no keys detected, the ID is typed
+	gpgKeysPicked            // a detected key is picked from the list
+	gpgKeysThenManual        // manual entry is chosen from the list
+)
+
+// wizardPath returns the steps visited for a combination of choices
+func wizardPath(sshChoice, gpgChoice int, sshPass, gpgPass bool, gpgKeys int) []int {
+	path := []int{stepName, stepEmail, stepSSH}
+	switch sshChoice {
+	case sshChoiceGenerate:
+		path = append(path, stepSSHKeyType, stepSSHPassphrase)
+		if sshPass {
+			path = append(path, stepSSHConfirmPass)
+		}
+	case sshChoiceUseExisting:
+		path = append(path, stepSSHKeyPath)
+	}
+
+	path = append(path, stepGPG)
+	switch gpgChoice {
+	case gpgChoiceGenerate:
+		path = append(path, stepGPGPassphrase)
+		if gpgPass {
+			path = append(path, stepGPGConfirmPass)
+		}
+	case gpgChoiceUseExisting:
+		switch gpgKeys {
+		case gpgKeysNone:
+			path = append(path, stepGPGKeyID)
+		case gpgKeysPicked:
+			path = append(path, stepGPGKeySelect)
+		case gpgKeysThenManual:
+			path = append(path, stepGPGKeySelect, stepGPGKeyID)
+		}
+	}
+
+	return append(path, stepRule)
+}
+
+func TestWizardProgress_AllBranches(t *testing.T) {
+	for _, sshChoice := range []int{sshChoiceGenerate, sshChoiceUseExisting, sshChoiceSkip} {
+		for _, gpgChoice := range []int{gpgChoiceGenerate, gpgChoiceUseExisting, gpgChoiceSkip} {
+			for _, sshPass := range []bool{false, true} {
+				for _, gpgPass := range []bool{false, true} {
+					for _, gpgKeys := range []int{gpgKeysNone, gpgKeysPicked, gpgKeysThenManual} {
+						name := fmt.Sprintf("ssh=%d/gpg=%d/sshPass=%v/gpgPass=%v/gpgKeys=%d", sshChoice, gpgChoice, sshPass, gpgPass, gpgKeys)
+						t.Run(name, func(t *testing.T) {
+							m := New()
+							m.sshChoice = sshChoice
+							m.gpgChoice = gpgChoice
+							if sshPass {
+								m.sshPassphrase = []byte("secret")
+							}
+							if gpgPass {
+								m.gpgPassphrase = []byte("secret")
+							}
+							if gpgKeys != gpgKeysNone {
+								m.gpgKeys = []gpgpkg.KeyInfo{{ID: "ABCD1234EFGH5678"}}
+							}
+							if gpgKeys == gpgKeysThenManual {
+								m.gpgKeyChoice = len(m.gpgKeys)
+								m.gpgKeyManual = true
+							}
+
+							path := wizardPath(sshChoice, gpgChoice, sshPass, gpgPass, gpgKeys)
+							total := getTotalSteps(m.sshChoice, m.gpgChoice, !m.hasSSHConfirmStep(), !m.hasGPGConfirmStep(), m.existingGPGKeySteps())
+							if total != len(path) {
+								t.Fatalf("getTotalSteps() = %d, want %d", total, len(path))
+							}
+
+							// Forward: each step is numbered one higher than the last
+							for i, step := range path {
+								m.step = step
+								if got := m.getDisplayStep(); got != i+1 {
+									t.Errorf("step %d: getDisplayStep() = %d, want %d", step, got, i+1)
+								}
+							}
+
+							// Backward: numbers only go down and stay within the total
+							prev := m.getDisplayStep()
+							for m.step != stepName {
+								m.step = m.getPreviousStep()
+								got := m.getDisplayStep()
+								if got > total {
+									t.Errorf("step %d: getDisplayStep() = %d exceeds total %d", m.step, got, total)
+								}
+								if got >= prev {
+									t.Errorf("step %d: going back moved display from %d to %d", m.step, prev, got)
+								}
+								prev = got
+							}
+						})
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestWizardProgress_StableWhileTyping(t *testing.T) {
+	m := New()
+	m.sshChoice = sshChoiceGenerate
+	m.step = stepSSHPassphrase
+
+	before := m.renderProgress()
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	if after := updated.(Model).renderProgress(); after != before {
+		t.Errorf("progress changed while typing a passphrase:\nbefore: %q\nafter:  %q", before, after)
+	}
+}
+
+func TestWizardBack_ClearsConfirmInput(t *testing.T) {
+	m := New()
+	m.sshChoice = sshChoiceGenerate
+	m.sshPassphraseInput.SetValue("secret")
+	m.sshPassphrase = []byte("secret")
+	m.sshConfirmInput.SetValue("sec")
+	m.step = stepSSHConfirmPass
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	got := updated.(Model)
+	if got.step != stepSSHPassphrase {
+		t.Errorf("step = %d, want %d", got.step, stepSSHPassphrase)
+	}
+	if got.sshConfirmInput.Value() != "" {
+		t.Errorf("confirm input = %q, want it cleared", got.sshConfirmInput.Value())
+	}
+	if got.sshPassphraseInput.Value() != "secret" {
+		t.Errorf("passphrase input = %q, want it kept", got.sshPassphraseInput.Value())
+	}
+}
+
+func TestWizardBack_ReusesGeneratedGPGKey(t *testing.T) {
+	m := New()
+	m.gpgChoice = gpgChoiceGenerate
+	m.step = stepGPGPassphrase
+
+	updated, _ := m.Update(gpgKeyGenerated{keyID: "ABCD1234EFGH5678"})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := updated.(Model).step; got != stepGPG {
+		t.Fatalf("step after going back = %d, want %d", got, stepGPG)
+	}
+
+	// Choosing Generate again moves on without generating a second key
+	updated, cmd := updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+	got := updated.(Model)
+	if got.step != stepRule || got.loading || cmd != nil {
+		t.Fatalf("step = %d, loading = %v; want the rule step without generating", got.step, got.loading)
+	}
+	if result := got.buildResult(got.gpgGenerated, got.gpgExisting); result.GPGKeyID != "ABCD1234EFGH5678" || !result.GenerateGPG {
+		t.Errorf("result GPG key = %q (generated %v), want the generated key", result.GPGKeyID, result.GenerateGPG)
+	}
+}
+
+func TestBuildRuleOptions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		name        string
+		cwd         string
+		remoteURL   string
+		wantRules   []string // type:pattern of each option, "" for no rule
+		wantDefault int
+	}{
+		{
+			name:        "remote detected",
+			cwd:         "/srv/work/app",
+			remoteURL:   "git@github.com:acme/app.git",
+			wantRules:   []string{"remote:github.com/acme/*", "directory:/srv/work/app/**", "directory:/srv/work/**", ""},
+			wantDefault: 0,
+		},
+		{
+			name:        "no remote",
+			cwd:         filepath.Join(home, "projects", "app"),
+			wantRules:   []string{"directory:~/projects/app/**", "directory:~/projects/**", ""},
+			wantDefault: 2,
+		},
+		{
+			name:        "unparsable remote",
+			cwd:         "/srv/work/app",
+			remoteURL:   "not a remote",
+			wantRules:   []string{"directory:/srv/work/app/**", "directory:/srv/work/**", ""},
+			wantDefault: 2,
+		},
+		{
+			name:        "no parent rule for the root",
+			cwd:         "/srv",
+			wantRules:   []string{"directory:/srv/**", ""},
+			wantDefault: 1,
+		},
+		{
+			name:        "no directory",
+			wantRules:   []string{""},
+			wantDefault: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options, choice := buildRuleOptions(tt.cwd, tt.remoteURL)
+
+			var got []string
+			for _, option := range options {
+				if option.rule == nil {
+					got = append(got, "")
+					continue
+				}
+				got = append(got, string(option.rule.Type)+":"+option.rule.Pattern)
+			}
+			if !slices.Equal(got, tt.wantRules) {
+				t.Errorf("options = %q, want %q", got, tt.wantRules)
+			}
+			if choice != tt.wantDefault {
+				t.Errorf("default choice = %d, want %d", choice, tt.wantDefault)
+			}
+		})
+	}
+}
+
+func TestGPGKeyLabel(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		key  gpgpkg.KeyInfo
+		want string
+	}{
+		{"ID only", gpgpkg.KeyInfo{ID: "ABCD1234EFGH5678"}, "ABCD1234EFGH5678"},
+		{"details", gpgpkg.KeyInfo{ID: "ABCD1234EFGH5678", Algorithm: "ed25519", Created: created}, "ABCD1234EFGH5678 (ed25519, created 2025-01-02)"},
+		{"not yet expired", gpgpkg.KeyInfo{ID: "ABCD1234EFGH5678", Algorithm: "ed25519", Expires: &future}, "ABCD1234EFGH5678 (ed25519)"},
+		{"expired", gpgpkg.KeyInfo{ID: "ABCD1234EFGH5678", Created: created, Expires: &past}, "ABCD1234EFGH5678 (created 2025-01-02, expired)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gpgKeyLabel(tt.key, now); got != tt.want {
+				t.Errorf("gpgKeyLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// pressKeys sends keys to the wizard, returning the updated model
+func pressKeys(m Model, keys ...tea.KeyType) Model {
+	for _, key := range keys {
+		updated, _ := m.Update(tea.KeyMsg{Type: key})
+		m = updated.(Model)
+	}
+	return m
+}
+
+// useExistingGPGKey returns a wizard on the GPG step with "Use existing
+// GPG key" highlighted for email
+func useExistingGPGKey(email string) Model {
+	m := New()
+	m.emailInput.SetValue(email)
+	m.sshChoice = sshChoiceSkip
+	m.gpgChoice = gpgChoiceUseExisting
+	m.step = stepGPG
+	return m
+}
+
+func TestWizardGPGKeySelect_SingleKey(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+	key, err := gpgpkg.GenerateKey("Work", "work@example.com", nil)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	// The only matching key is listed, selected and pre-filled for manual entry
+	m := pressKeys(useExistingGPGKey("work@example.com"), tea.KeyEnter)
+	if m.step != stepGPGKeySelect {
+		t.Fatalf("step = %d, want the key list", m.step)
+	}
+	if len(m.gpgKeys) != 1 || m.gpgKeys[0].ID != key.ID || m.gpgKeyChoice != 0 {
+		t.Fatalf("keys = %+v, choice %d; want %s selected", m.gpgKeys, m.gpgKeyChoice, key.ID)
+	}
+	if got := m.gpgKeyIDInput.Value(); got != key.ID {
+		t.Errorf("key ID input = %q, want %q", got, key.ID)
+	}
+
+	m = pressKeys(m, tea.KeyEnter)
+	if m.step != stepRule {
+		t.Fatalf("step = %d, want the rule step", m.step)
+	}
+	if result := m.buildResult(m.gpgGenerated, m.gpgExisting); result.GPGKeyID != key.ID || !result.UseExistingGPG {
+		t.Errorf("result GPG key = %q (existing %v), want %s", result.GPGKeyID, result.UseExistingGPG, key.ID)
+	}
+
+	// Esc goes back to the list, not to manual entry
+	if m = pressKeys(m, tea.KeyEsc); m.step != stepGPGKeySelect {
+		t.Errorf("step after Esc = %d, want the key list", m.step)
+	}
+}
+
+func TestWizardGPGKeySelect_NoKeys(t *testing.T) {
+	gpgtest.UseTempKeyring(t)
+
+	m := pressKeys(useExistingGPGKey("nobody@example.com"), tea.KeyEnter)
+	if m.step != stepGPGKeyID {
+		t.Fatalf("step = %d, want manual key entry", m.step)
+	}
+	if m = pressKeys(m, tea.KeyEsc); m.step != stepGPG {
+		t.Errorf("step after Esc = %d, want the GPG step", m.step)
+	}
+}
+
+func TestWizardGPGKeySelect_ManualEntry(t *testing.T) {
+	m := useExistingGPGKey("work@example.com")
+	m.gpgKeys = []gpgpkg.KeyInfo{{ID: "ABCD1234EFGH5678"}, {ID: "1234ABCD5678EFGH"}}
+	m.step = stepGPGKeySelect
+
+	// Down past both keys reaches manual entry and stops there
+	m = pressKeys(m, tea.KeyDown, tea.KeyDown, tea.KeyDown)
+	if m.gpgKeyChoice != len(m.gpgKeys) {
+		t.Fatalf("choice = %d, want manual entry (%d)", m.gpgKeyChoice, len(m.gpgKeys))
+	}
+	listStep := m.getDisplayStep()
+
+	m = pressKeys(m, tea.KeyEnter)
+	if m.step != stepGPGKeyID {
+		t.Fatalf("step = %d, want manual key entry", m.step)
+	}
+	if got := m.getDisplayStep(); got != listStep+1 {
+		t.Errorf("manual entry is step %d, want %d after the list", got, listStep+1)
+	}
+	if m = pressKeys(m, tea.KeyEsc); m.step != stepGPGKeySelect {
+		t.Errorf("step after Esc = %d, want the key list", m.step)
+	}
+}
@@ -0,0 +1,18 @@
+// Package xdgtest provides helpers for tests that use XDG directories.
+package xdgtest
+
+import (
+	"testing"
+
+	"github.com/adrg/xdg"
+)
+
+// Setenv sets an XDG environment variable such as XDG_CACHE_HOME for the
+// duration of the test. xdg reads the environment once at startup, so it is
+// reloaded now and again after the test.
+func Setenv(t testing.TB, key, value string) {
+	t.Helper()
+	t.Setenv(key, value)
+	xdg.Reload()
+	t.Cleanup(xdg.Reload)
+}